@@ -0,0 +1,95 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Authentication errors
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid bearer token")
+	ErrExpiredToken = errors.New("bearer token has expired")
+)
+
+// Authenticator resolves the user making an HTTP request
+type Authenticator interface {
+	// Authenticate returns the caller's user ID, or an error if the request is not authenticated
+	Authenticate(r *http.Request) (string, error)
+}
+
+// tokenAuthenticator implements the Authenticator interface with HMAC-signed bearer tokens
+// of the form "<userID>.<expiryUnix>.<hex HMAC-SHA256 of userID.expiryUnix>"
+type tokenAuthenticator struct {
+	secret []byte
+}
+
+// NewTokenAuthenticator creates an authenticator accepting tokens signed with the given secret
+func NewTokenAuthenticator(secret []byte) Authenticator {
+	return &tokenAuthenticator{
+		secret: secret,
+	}
+}
+
+// IssueToken creates a bearer token for a user, signed with the given secret and valid until expiry
+func IssueToken(secret []byte, userID string, expiry time.Time) string {
+	payload := userID + "." + strconv.FormatInt(expiry.Unix(), 10)
+	return payload + "." + signTokenPayload(secret, payload)
+}
+
+// Authenticate implements Authenticator.Authenticate
+// Browsers cannot set headers on WebSocket upgrades, so the token may also be passed
+// as the access_token query parameter.
+func (a *tokenAuthenticator) Authenticate(r *http.Request) (string, error) {
+	// 1. Extract the token
+	token := ""
+	if header := r.Header.Get("Authorization"); header != "" {
+		if !strings.HasPrefix(header, "Bearer ") {
+			return "", ErrInvalidToken
+		}
+		token = strings.TrimPrefix(header, "Bearer ")
+	} else {
+		token = r.URL.Query().Get("access_token")
+	}
+	if token == "" {
+		return "", ErrMissingToken
+	}
+
+	// 2. Split into user ID, expiry and signature
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", ErrInvalidToken
+	}
+
+	// 3. Verify the signature
+	payload := parts[0] + "." + parts[1]
+	expected := signTokenPayload(a.secret, payload)
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return "", ErrInvalidToken
+	}
+
+	// 4. Check the expiry
+	expiryUnix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	if time.Now().Unix() >= expiryUnix {
+		return "", fmt.Errorf("%w: expired at %s", ErrExpiredToken, time.Unix(expiryUnix, 0).UTC().Format(time.RFC3339))
+	}
+
+	return parts[0], nil
+}
+
+// signTokenPayload returns the hex HMAC-SHA256 of a token payload
+func signTokenPayload(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
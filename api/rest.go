@@ -0,0 +1,210 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/hashperp/hashperp"
+)
+
+// restParamsBuilder turns a REST request into the params of the JSON-RPC method it maps onto
+type restParamsBuilder func(r *http.Request) (map[string]interface{}, error)
+
+// setupRESTRoutes registers RESTful routes for curl and browser clients. Each route is
+// served by the same handler as its JSON-RPC method, so both accept and return the same shapes.
+func (s *Server) setupRESTRoutes(router *mux.Router) {
+	// Contracts
+	router.HandleFunc("/contracts", s.restHandler("createContract", http.StatusCreated, bodyParams)).Methods(http.MethodPost)
+	router.HandleFunc("/contracts/{id}", s.restHandler("getContract", http.StatusOK, pathParam("contract_id"))).Methods(http.MethodGet)
+	router.HandleFunc("/contracts/{id}/settle", s.restHandler("settleContract", http.StatusOK, settleParams)).Methods(http.MethodPost)
+	router.HandleFunc("/contracts/{id}/settlement", s.restHandler("getSettlementResult", http.StatusOK, pathParam("contract_id"))).Methods(http.MethodGet)
+	router.HandleFunc("/contracts/{id}/settlement/preview", s.restHandler("previewSettlement", http.StatusOK, pathParam("contract_id"))).Methods(http.MethodGet)
+
+	// Orders
+	router.HandleFunc("/orders", s.restHandler("placeOrder", http.StatusCreated, bodyParams)).Methods(http.MethodPost)
+	router.HandleFunc("/orders/{id}", s.restHandler("getOrder", http.StatusOK, pathParam("order_id"))).Methods(http.MethodGet)
+	router.HandleFunc("/orders/{id}", s.restHandler("cancelOrder", http.StatusOK, cancelOrderParams)).Methods(http.MethodDelete)
+	router.HandleFunc("/orderbook", s.restHandler("getOrderBook", http.StatusOK, marketParams)).Methods(http.MethodGet)
+	router.HandleFunc("/orderbook/depth", s.restHandler("getOrderBookDepth", http.StatusOK, marketParams)).Methods(http.MethodGet)
+
+	// VTXOs and transactions
+	router.HandleFunc("/vtxos/{id}", s.restHandler("getVTXO", http.StatusOK, pathParam("vtxo_id"))).Methods(http.MethodGet)
+	router.HandleFunc("/transactions/{id}", s.restHandler("getTransaction", http.StatusOK, pathParam("transaction_id"))).Methods(http.MethodGet)
+	router.HandleFunc("/transactions/{id}/status", s.restHandler("getTransactionStatus", http.StatusOK, pathParam("transaction_id"))).Methods(http.MethodGet)
+
+	// Market data
+	router.HandleFunc("/hashrate", s.restHandler("getCurrentHashRate", http.StatusOK, noParams)).Methods(http.MethodGet)
+	router.HandleFunc("/blockheight", s.restHandler("getCurrentBlockHeight", http.StatusOK, noParams)).Methods(http.MethodGet)
+}
+
+// restHandler serves a REST route by executing the JSON-RPC method it maps onto
+func (s *Server) restHandler(rpcMethod string, successStatus int, buildParams restParamsBuilder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Build the method params from the path, query and body
+		params, err := buildParams(r)
+		if err != nil {
+			writeRESTError(w, &RPCError{
+				Code:    -32602,
+				Message: "Invalid params",
+				Data:    err.Error(),
+			})
+			return
+		}
+
+		rawParams, err := json.Marshal(params)
+		if err != nil {
+			writeRESTError(w, err)
+			return
+		}
+
+		// 2. Execute the method
+		result, err := s.executeRPCMethod(r.Context(), rpcMethod, rawParams)
+		if err != nil {
+			writeRESTError(w, err)
+			return
+		}
+
+		writeRESTResult(w, successStatus, result)
+	}
+}
+
+// noParams is the params builder of routes whose method takes no params
+func noParams(r *http.Request) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+// bodyParams uses the JSON request body as the method params
+func bodyParams(r *http.Request) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	return params, nil
+}
+
+// pathParam passes the {id} path variable as the named method param
+func pathParam(key string) restParamsBuilder {
+	return func(r *http.Request) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			key: mux.Vars(r)["id"],
+		}, nil
+	}
+}
+
+// cancelOrderParams builds the params of DELETE /orders/{id}. The user defaults to the caller.
+func cancelOrderParams(r *http.Request) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"order_id": mux.Vars(r)["id"],
+		"user_id":  r.URL.Query().Get("user_id"),
+	}, nil
+}
+
+// settleParams builds the params of POST /contracts/{id}/settle. The fee_priority query parameter is optional.
+func settleParams(r *http.Request) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"contract_id":  mux.Vars(r)["id"],
+		"fee_priority": r.URL.Query().Get("fee_priority"),
+	}, nil
+}
+
+// marketParams builds the params of market routes from the contract_type and expiry query parameters
+func marketParams(r *http.Request) (map[string]interface{}, error) {
+	query := r.URL.Query()
+
+	expiry, err := strconv.ParseUint(query.Get("expiry"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiry %q: %w", query.Get("expiry"), err)
+	}
+
+	return map[string]interface{}{
+		"contract_type":       query.Get("contract_type"),
+		"expiry_block_height": expiry,
+	}, nil
+}
+
+// restStatus maps an error returned by an RPC method to an HTTP status code
+func restStatus(err error) int {
+	var rpcError *RPCError
+	if errors.As(err, &rpcError) && rpcError.Code == -32602 {
+		return http.StatusBadRequest
+	}
+
+	switch {
+	case errors.Is(err, hashperp.ErrUnauthorized):
+		return http.StatusForbidden
+	case errors.Is(err, hashperp.ErrContractNotFound),
+		errors.Is(err, hashperp.ErrVTXONotFound),
+		errors.Is(err, hashperp.ErrSettlementNotFound),
+		errors.Is(err, hashperp.ErrWebhookNotFound),
+		errors.Is(err, hashperp.ErrTransactionNotFound),
+		errors.Is(err, hashperp.ErrUserKeyNotFound),
+		errors.Is(err, hashperp.ErrOrderNotFound),
+		errors.Is(err, hashperp.ErrTemplateNotFound),
+		errors.Is(err, hashperp.ErrPreSignedExitNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, hashperp.ErrInvalidParameters),
+		errors.Is(err, hashperp.ErrInvalidBlockHeight),
+		errors.Is(err, hashperp.ErrInvalidLeverage),
+		errors.Is(err, hashperp.ErrInvalidWebhookURL),
+		errors.Is(err, hashperp.ErrEmptyID),
+		errors.Is(err, hashperp.ErrInvalidID),
+		errors.Is(err, hashperp.ErrEmptyUserID),
+		errors.Is(err, hashperp.ErrInvalidUserID),
+		errors.Is(err, hashperp.ErrNegativeAmount),
+		errors.Is(err, hashperp.ErrInvalidRate),
+		errors.Is(err, hashperp.ErrMissingSignature),
+		errors.Is(err, hashperp.ErrInvalidSignature),
+		errors.Is(err, hashperp.ErrInvalidSwapSignature),
+		errors.Is(err, hashperp.ErrInvalidTimeRange),
+		errors.Is(err, hashperp.ErrInvalidBlockRange),
+		errors.Is(err, hashperp.ErrInvalidIdempotencyKey),
+		errors.Is(err, hashperp.ErrInvalidCursor),
+		errors.Is(err, hashperp.ErrInvalidPageLimit),
+		errors.Is(err, hashperp.ErrInvalidFeePriority),
+		errors.Is(err, hashperp.ErrInvalidPublicKey),
+		errors.Is(err, hashperp.ErrSelfContract),
+		errors.Is(err, hashperp.ErrPriceBandExceeded):
+		return http.StatusBadRequest
+	case errors.Is(err, hashperp.ErrInvalidContractStatus),
+		errors.Is(err, hashperp.ErrInvalidOrderStatus),
+		errors.Is(err, hashperp.ErrInvalidSwapOfferStatus),
+		errors.Is(err, hashperp.ErrClientOrderIDConflict),
+		errors.Is(err, hashperp.ErrIdempotencyKeyConflict),
+		errors.Is(err, hashperp.ErrDisputeWindowOpen),
+		errors.Is(err, hashperp.ErrDisputeWindowClosed),
+		errors.Is(err, hashperp.ErrBlockNotFinal),
+		errors.Is(err, hashperp.ErrTransactionNotBroadcast),
+		errors.Is(err, hashperp.ErrInsufficientFunds),
+		errors.Is(err, hashperp.ErrPreSignedExitUsed),
+		errors.Is(err, hashperp.ErrOpenOrderLimit),
+		errors.Is(err, hashperp.ErrOpenSwapOfferLimit),
+		errors.Is(err, hashperp.ErrConcurrentModification),
+		errors.Is(err, hashperp.ErrPositionNotLiquidatable),
+		errors.Is(err, hashperp.ErrSweepNotAllowed),
+		errors.Is(err, hashperp.ErrDynamicJoinRejected):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeRESTResult writes a successful REST response
+func writeRESTResult(w http.ResponseWriter, status int, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeRESTError writes a REST error response, carrying the same error object as JSON-RPC
+func writeRESTError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(restStatus(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": toRPCError(err),
+	})
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,22 +12,81 @@ import (
 	"github.com/hashperp/hashperp"
 )
 
-// executeRPCMethod executes an RPC method with the given parameters
-func (s *Server) executeRPCMethod(ctx context.Context, method string, params json.RawMessage) (interface{}, error) {
+// executeRPCMethod executes an RPC method with the given parameters and records its latency
+func (s *Server) executeRPCMethod(ctx context.Context, method string, params json.RawMessage) (result interface{}, err error) {
+	start := time.Now()
+	defer func() {
+		label := method
+		var rpcError *RPCError
+		if errors.As(err, &rpcError) && rpcError.Code == -32601 {
+			label = "unknown" // Keep arbitrary method names out of the metric labels
+		}
+		s.metrics.RPCCall(label, hashperp.MetricOutcome(err), time.Since(start))
+	}()
+
+	return s.dispatchRPCMethod(ctx, method, params)
+}
+
+// dispatchRPCMethod calls the handler of an RPC method
+func (s *Server) dispatchRPCMethod(ctx context.Context, method string, params json.RawMessage) (interface{}, error) {
 	switch method {
 	// Contract methods
 	case "createContract":
 		return s.rpcCreateContract(ctx, params)
+	case "createContractFromTemplate":
+		return s.rpcCreateContractFromTemplate(ctx, params)
+	case "createContractTemplate":
+		return s.rpcCreateContractTemplate(ctx, params)
+	case "getContractTemplates":
+		return s.rpcGetContractTemplates(ctx, params)
 	case "getContract":
 		return s.rpcGetContract(ctx, params)
 	case "getContractsByUser":
 		return s.rpcGetContractsByUser(ctx, params)
+	case "searchContracts":
+		return s.rpcSearchContracts(ctx, params)
+	case "getContractsByExpiryRange":
+		return s.rpcGetContractsByExpiryRange(ctx, params)
+	case "getContractsNearingExpiry":
+		return s.rpcGetContractsNearingExpiry(ctx, params)
+	case "getSettlementHistory":
+		return s.rpcGetSettlementHistory(ctx, params)
+	case "getContractsRequiringSettlement":
+		return s.rpcGetContractsRequiringSettlement(ctx, params)
+	case "autoSettleExpired":
+		return s.rpcAutoSettleExpired(ctx, params)
+	case "getOpenInterest":
+		return s.rpcGetOpenInterest(ctx, params)
+	case "getContractPnLHistory":
+		return s.rpcGetContractPnLHistory(ctx, params)
+	case "getUserPositionSummary":
+		return s.rpcGetUserPositionSummary(ctx, params)
+	case "reconcileContract":
+		return s.rpcReconcileContract(ctx, params)
+	case "fundContract":
+		return s.rpcFundContract(ctx, params)
+	case "cancelContract":
+		return s.rpcCancelContract(ctx, params)
+	case "getContractScripts":
+		return s.rpcGetContractScripts(ctx, params)
 	case "settleContract":
 		return s.rpcSettleContract(ctx, params)
+	case "getSettlementResult":
+		return s.rpcGetSettlementResult(ctx, params)
+	case "previewSettlement":
+		return s.rpcPreviewSettlement(ctx, params)
+	case "confirmSettlement":
+		return s.rpcConfirmSettlement(ctx, params)
+	case "disputeSettlement":
+		return s.rpcDisputeSettlement(ctx, params)
 	case "exitContract":
 		return s.rpcExitContract(ctx, params)
 	case "rolloverContract":
 		return s.rpcRolloverContract(ctx, params)
+	case "joinContractSide":
+		return s.rpcJoinContractSide(ctx, params)
+	case "requestDynamicJoin":
+		return s.rpcRequestDynamicJoin(ctx, params)
 	case "executeExitPath":
 		return s.rpcExecuteExitPath(ctx, params)
 
@@ -39,18 +99,32 @@ func (s *Server) executeRPCMethod(ctx context.Context, method string, params jso
 		return s.rpcGetVTXOsByContract(ctx, params)
 	case "getVTXOsByUser":
 		return s.rpcGetVTXOsByUser(ctx, params)
+	case "getUserVTXOInContract":
+		return s.rpcGetUserVTXOInContract(ctx, params)
 	case "swapVTXO":
 		return s.rpcSwapVTXO(ctx, params)
+	case "splitVTXO":
+		return s.rpcSplitVTXO(ctx, params)
 	case "createPresignedExitTransaction":
 		return s.rpcCreatePresignedExitTransaction(ctx, params)
+	case "getPreSignedExits":
+		return s.rpcGetPreSignedExits(ctx, params)
+	case "broadcastPreSignedExit":
+		return s.rpcBroadcastPreSignedExit(ctx, params)
 	case "executeVTXOSweep":
 		return s.rpcExecuteVTXOSweep(ctx, params)
+	case "sweepAllVTXOs":
+		return s.rpcSweepAllVTXOs(ctx, params)
 
 	// Order methods
 	case "placeOrder":
 		return s.rpcPlaceOrder(ctx, params)
 	case "cancelOrder":
 		return s.rpcCancelOrder(ctx, params)
+	case "reduceOrder":
+		return s.rpcReduceOrder(ctx, params)
+	case "modifyOrder":
+		return s.rpcModifyOrder(ctx, params)
 	case "getOrder":
 		return s.rpcGetOrder(ctx, params)
 	case "getOrdersByUser":
@@ -59,6 +133,18 @@ func (s *Server) executeRPCMethod(ctx context.Context, method string, params jso
 		return s.rpcGetOrderBook(ctx, params)
 	case "matchOrders":
 		return s.rpcMatchOrders(ctx, params)
+	case "getMarketView":
+		return s.rpcGetMarketView(ctx, params)
+	case "getOrderBookDepth":
+		return s.rpcGetOrderBookDepth(ctx, params)
+	case "getQuote":
+		return s.rpcGetQuote(ctx, params)
+	case "getMarketSummary":
+		return s.rpcGetMarketSummary(ctx, params)
+	case "getStats":
+		return s.rpcGetStats(ctx, params)
+	case "getAuditTrail":
+		return s.rpcGetAuditTrail(ctx, params)
 
 	// Swap offer methods
 	case "createSwapOffer":
@@ -73,6 +159,12 @@ func (s *Server) executeRPCMethod(ctx context.Context, method string, params jso
 		return s.rpcGetSwapOffersByUser(ctx, params)
 	case "getSwapOffersByContract":
 		return s.rpcGetSwapOffersByContract(ctx, params)
+	case "getBestSwapOffer":
+		return s.rpcGetBestSwapOffer(ctx, params)
+	case "autoAcceptBestOffer":
+		return s.rpcAutoAcceptBestOffer(ctx, params)
+	case "invalidateOffersForVTXO":
+		return s.rpcInvalidateOffersForVTXO(ctx, params)
 
 	// Market data methods
 	case "getCurrentHashRate":
@@ -87,10 +179,32 @@ func (s *Server) executeRPCMethod(ctx context.Context, method string, params jso
 	// Transaction methods
 	case "getTransaction":
 		return s.rpcGetTransaction(ctx, params)
+	case "getTransactionStatus":
+		return s.rpcGetTransactionStatus(ctx, params)
 	case "getTransactionsByUser":
 		return s.rpcGetTransactionsByUser(ctx, params)
 	case "getTransactionsByContract":
 		return s.rpcGetTransactionsByContract(ctx, params)
+	case "getTransactionsByType":
+		return s.rpcGetTransactionsByType(ctx, params)
+	case "getTransactionsByTimeRange":
+		return s.rpcGetTransactionsByTimeRange(ctx, params)
+	case "getTransactionsByBlockRange":
+		return s.rpcGetTransactionsByBlockRange(ctx, params)
+
+	// Webhook methods
+	case "registerWebhook":
+		return s.rpcRegisterWebhook(ctx, params)
+	case "getWebhooksByUser":
+		return s.rpcGetWebhooksByUser(ctx, params)
+	case "deleteWebhook":
+		return s.rpcDeleteWebhook(ctx, params)
+
+	// User methods
+	case "registerUserKey":
+		return s.rpcRegisterUserKey(ctx, params)
+	case "getUserKey":
+		return s.rpcGetUserKey(ctx, params)
 
 	// Utility methods
 	case "getCurrentBlockHeight":
@@ -109,15 +223,19 @@ func (s *Server) executeRPCMethod(ctx context.Context, method string, params jso
 
 // Contract RPC Methods
 
-// rpcCreateContract creates a new contract
+// rpcCreateContract creates a new contract between two parties, operators only
 func (s *Server) rpcCreateContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		BuyerID           string  `json:"buyer_id"`
-		SellerID          string  `json:"seller_id"`
-		ContractType      string  `json:"contract_type"`
-		StrikeRate        float64 `json:"strike_rate"`
-		ExpiryBlockHeight uint64  `json:"expiry_block_height"`
-		Size              float64 `json:"size"`
+		BuyerID           string   `json:"buyer_id"`
+		SellerID          string   `json:"seller_id"`
+		ContractType      string   `json:"contract_type"`
+		StrikeRate        float64  `json:"strike_rate"`
+		ExpiryBlockHeight uint64   `json:"expiry_block_height"`
+		Size              float64  `json:"size"`
+		Leverage          float64  `json:"leverage,omitempty"`
+		SettlementMethod  string   `json:"settlement_method,omitempty"`
+		ExitFeeRate       *float64 `json:"exit_fee_rate,omitempty"` // Omitted uses the configured default
+		IdempotencyKey    string   `json:"idempotency_key,omitempty"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -136,6 +254,10 @@ func (s *Server) rpcCreateContract(ctx context.Context, params json.RawMessage)
 		req.StrikeRate,
 		req.ExpiryBlockHeight,
 		req.Size,
+		req.Leverage,
+		hashperp.SettlementMethod(req.SettlementMethod),
+		req.ExitFeeRate,
+		req.IdempotencyKey,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create contract: %w", err)
@@ -144,10 +266,13 @@ func (s *Server) rpcCreateContract(ctx context.Context, params json.RawMessage)
 	return contract, nil
 }
 
-// rpcGetContract retrieves a contract by ID
-func (s *Server) rpcGetContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcCreateContractFromTemplate creates a new contract from a contract template, operators only
+func (s *Server) rpcCreateContractFromTemplate(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		ContractID string `json:"contract_id"`
+		TemplateID string  `json:"template_id"`
+		BuyerID    string  `json:"buyer_id"`
+		SellerID   string  `json:"seller_id"`
+		StrikeRate float64 `json:"strike_rate"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -158,19 +283,27 @@ func (s *Server) rpcGetContract(ctx context.Context, params json.RawMessage) (in
 		}
 	}
 
-	contract, err := s.service.GetContract(ctx, req.ContractID)
+	contract, err := s.service.CreateContractFromTemplate(ctx, req.TemplateID, req.BuyerID, req.SellerID, req.StrikeRate)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get contract: %w", err)
+		return nil, fmt.Errorf("failed to create contract from template: %w", err)
 	}
 
 	return contract, nil
 }
 
-// rpcGetContractsByUser retrieves contracts for a user
-func (s *Server) rpcGetContractsByUser(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcCreateContractTemplate publishes a new contract template
+func (s *Server) rpcCreateContractTemplate(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		UserID string   `json:"user_id"`
-		Status []string `json:"status,omitempty"`
+		Name             string   `json:"name"`
+		ContractType     string   `json:"contract_type"`
+		MinStrikeRate    float64  `json:"min_strike_rate"`
+		MaxStrikeRate    float64  `json:"max_strike_rate"`
+		ExpiryOffsets    []uint64 `json:"expiry_offsets"`
+		MinSize          float64  `json:"min_size"`
+		MaxSize          float64  `json:"max_size"`
+		DefaultSize      float64  `json:"default_size,omitempty"`
+		Leverage         float64  `json:"leverage,omitempty"`
+		SettlementMethod string   `json:"settlement_method,omitempty"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -181,24 +314,42 @@ func (s *Server) rpcGetContractsByUser(ctx context.Context, params json.RawMessa
 		}
 	}
 
-	// Convert string statuses to ContractStatus
-	var statuses []hashperp.ContractStatus
-	for _, status := range req.Status {
-		statuses = append(statuses, hashperp.ContractStatus(status))
+	template, err := s.service.CreateContractTemplate(ctx, &hashperp.ContractTemplate{
+		Name:             req.Name,
+		ContractType:     hashperp.ContractType(req.ContractType),
+		MinStrikeRate:    req.MinStrikeRate,
+		MaxStrikeRate:    req.MaxStrikeRate,
+		ExpiryOffsets:    req.ExpiryOffsets,
+		MinSize:          req.MinSize,
+		MaxSize:          req.MaxSize,
+		DefaultSize:      req.DefaultSize,
+		Leverage:         req.Leverage,
+		SettlementMethod: hashperp.SettlementMethod(req.SettlementMethod),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create contract template: %w", err)
 	}
 
-	contracts, err := s.service.GetContractsByUser(ctx, req.UserID, statuses)
+	return template, nil
+}
+
+// rpcGetContractTemplates lists the published contract templates
+func (s *Server) rpcGetContractTemplates(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	templates, err := s.service.GetContractTemplates(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get contracts by user: %w", err)
+		return nil, fmt.Errorf("failed to get contract templates: %w", err)
 	}
 
-	return contracts, nil
+	return map[string]interface{}{
+		"templates": templates,
+	}, nil
 }
 
-// rpcSettleContract settles a contract
-func (s *Server) rpcSettleContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetContract retrieves a contract by ID
+func (s *Server) rpcGetContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		ContractID string `json:"contract_id"`
+		ContractID string   `json:"contract_id"`
+		Include    []string `json:"include,omitempty"` // Related entities to return with the contract: vtxos, transactions, offers
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -209,19 +360,69 @@ func (s *Server) rpcSettleContract(ctx context.Context, params json.RawMessage)
 		}
 	}
 
-	tx, err := s.service.SettleContract(ctx, req.ContractID)
+	include := make(map[string]bool, len(req.Include))
+	for _, entity := range req.Include {
+		switch entity {
+		case "vtxos", "transactions", "offers":
+			include[entity] = true
+		default:
+			return nil, &RPCError{
+				Code:    -32602,
+				Message: "Invalid params",
+				Data:    fmt.Sprintf("unknown include %q, expected vtxos, transactions or offers", entity),
+			}
+		}
+	}
+
+	contract, err := s.service.GetContract(ctx, req.ContractID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to settle contract: %w", err)
+		return nil, fmt.Errorf("failed to get contract: %w", err)
 	}
 
-	return tx, nil
+	// Without includes the response is the contract alone
+	if len(include) == 0 {
+		return contract, nil
+	}
+
+	response := struct {
+		*hashperp.Contract
+		VTXOs        []*hashperp.VTXO        `json:"vtxos,omitempty"`
+		Transactions []*hashperp.Transaction `json:"transactions,omitempty"`
+		Offers       []*hashperp.SwapOffer   `json:"offers,omitempty"`
+	}{Contract: contract}
+
+	if include["vtxos"] {
+		response.VTXOs, err = s.service.GetVTXOsByContract(ctx, contract.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get VTXOs by contract: %w", err)
+		}
+	}
+
+	if include["transactions"] {
+		response.Transactions, err = s.service.GetTransactionsByContract(ctx, contract.ID, nil, hashperp.SORT_ASCENDING)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transactions by contract: %w", err)
+		}
+	}
+
+	if include["offers"] {
+		response.Offers, _, err = s.service.GetSwapOffersByContract(ctx, contract.ID, nil, hashperp.PageRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get swap offers by contract: %w", err)
+		}
+	}
+
+	return response, nil
 }
 
-// rpcExitContract exits a contract
-func (s *Server) rpcExitContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetContractsByUser retrieves contracts for a user
+func (s *Server) rpcGetContractsByUser(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		ContractID string `json:"contract_id"`
-		UserID     string `json:"user_id"`
+		UserID          string   `json:"user_id"`
+		Status          []string `json:"status,omitempty"`
+		IncludeArchived bool     `json:"include_archived,omitempty"`
+		Limit           int      `json:"limit,omitempty"`
+		Cursor          string   `json:"cursor,omitempty"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -232,19 +433,33 @@ func (s *Server) rpcExitContract(ctx context.Context, params json.RawMessage) (i
 		}
 	}
 
-	tx, err := s.service.ExitContract(ctx, req.ContractID, req.UserID)
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	// Convert string statuses to ContractStatus
+	var statuses []hashperp.ContractStatus
+	for _, status := range req.Status {
+		statuses = append(statuses, hashperp.ContractStatus(status))
+	}
+
+	page := hashperp.PageRequest{Limit: req.Limit, Cursor: req.Cursor}
+	contracts, nextCursor, err := s.service.GetContractsByUser(ctx, req.UserID, statuses, req.IncludeArchived, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to exit contract: %w", err)
+		return nil, fmt.Errorf("failed to get contracts by user: %w", err)
 	}
 
-	return tx, nil
+	return map[string]interface{}{
+		"contracts":   contracts,
+		"next_cursor": nextCursor,
+	}, nil
 }
 
-// rpcRolloverContract rolls over a contract
-func (s *Server) rpcRolloverContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcSearchContracts retrieves contracts matching a filter
+func (s *Server) rpcSearchContracts(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		ContractID          string `json:"contract_id"`
-		NewExpiryBlockHeight uint64 `json:"new_expiry_block_height"`
+		hashperp.ContractFilter
+		Limit  int    `json:"limit,omitempty"`
+		Cursor string `json:"cursor,omitempty"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -255,23 +470,26 @@ func (s *Server) rpcRolloverContract(ctx context.Context, params json.RawMessage
 		}
 	}
 
-	contract, tx, err := s.service.RolloverContract(ctx, req.ContractID, req.NewExpiryBlockHeight)
+	// Default to the authenticated caller, the service rejects searching anyone else's contracts
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	page := hashperp.PageRequest{Limit: req.Limit, Cursor: req.Cursor}
+	contracts, nextCursor, err := s.service.SearchContracts(ctx, req.ContractFilter, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to rollover contract: %w", err)
+		return nil, fmt.Errorf("failed to search contracts: %w", err)
 	}
 
 	return map[string]interface{}{
-		"contract":    contract,
-		"transaction": tx,
+		"contracts":   contracts,
+		"next_cursor": nextCursor,
 	}, nil
 }
 
-// rpcExecuteExitPath executes an exit path
-func (s *Server) rpcExecuteExitPath(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetContractsByExpiryRange retrieves active contracts expiring within a block height range
+func (s *Server) rpcGetContractsByExpiryRange(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		ContractID   string `json:"contract_id"`
-		UserID       string `json:"user_id"`
-		ExitPathType string `json:"exit_path_type"`
+		FromHeight uint64 `json:"from_height"`
+		ToHeight   uint64 `json:"to_height"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -282,24 +500,20 @@ func (s *Server) rpcExecuteExitPath(ctx context.Context, params json.RawMessage)
 		}
 	}
 
-	tx, err := s.service.ExecuteExitPath(ctx, req.ContractID, req.UserID, req.ExitPathType)
+	contracts, err := s.service.GetContractsByExpiryRange(ctx, req.FromHeight, req.ToHeight)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute exit path: %w", err)
+		return nil, fmt.Errorf("failed to get contracts by expiry range: %w", err)
 	}
 
-	return tx, nil
+	return map[string]interface{}{
+		"contracts": contracts,
+	}, nil
 }
 
-// VTXO RPC Methods
-
-// rpcCreateVTXO creates a new VTXO
-func (s *Server) rpcCreateVTXO(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetContractsNearingExpiry retrieves active contracts expiring within a number of blocks
+func (s *Server) rpcGetContractsNearingExpiry(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		ContractID    string  `json:"contract_id"`
-		OwnerID       string  `json:"owner_id"`
-		Amount        float64 `json:"amount"`
-		ScriptPath    string  `json:"script_path"`
-		SignatureData string  `json:"signature_data"` // Base64 encoded
+		WithinBlocks uint64 `json:"within_blocks"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -310,44 +524,22 @@ func (s *Server) rpcCreateVTXO(ctx context.Context, params json.RawMessage) (int
 		}
 	}
 
-	// Decode signature data
-	var signatureData []byte
-	if req.SignatureData != "" {
-		var err error
-		signatureData, err = decodeBase64(req.SignatureData)
-		if err != nil {
-			return nil, &RPCError{
-				Code:    -32602,
-				Message: "Invalid signature data",
-				Data:    err.Error(),
-			}
-		}
-	}
-
-	vtxo, err := s.service.CreateVTXO(
-		ctx,
-		req.ContractID,
-		req.OwnerID,
-		req.Amount,
-		req.ScriptPath,
-		signatureData,
-	)
+	contracts, err := s.service.GetContractsNearingExpiry(ctx, req.WithinBlocks)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create VTXO: %w", err)
+		return nil, fmt.Errorf("failed to get contracts nearing expiry: %w", err)
 	}
 
-	return vtxo, nil
+	return map[string]interface{}{
+		"contracts": contracts,
+	}, nil
 }
 
-// Additional RPC method implementations would follow a similar pattern.
-// For brevity, I'm not including all method implementations here.
-
-// decodeBase64 decodes a base64 string
-
-// rpcGetVTXO retrieves a VTXO by ID
-func (s *Server) rpcGetVTXO(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetSettlementHistory retrieves the settlement rates of past contracts per expiry block
+func (s *Server) rpcGetSettlementHistory(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		VTXOID string `json:"vtxo_id"`
+		ContractType string `json:"contract_type,omitempty"` // CALL or PUT, empty for both
+		FromHeight   uint64 `json:"from_height"`
+		ToHeight     uint64 `json:"to_height"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -358,18 +550,20 @@ func (s *Server) rpcGetVTXO(ctx context.Context, params json.RawMessage) (interf
 		}
 	}
 
-	vtxo, err := s.service.GetVTXO(ctx, req.VTXOID)
+	history, err := s.service.GetSettlementHistory(ctx, hashperp.ContractType(req.ContractType), req.FromHeight, req.ToHeight)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get VTXO: %w", err)
+		return nil, fmt.Errorf("failed to get settlement history: %w", err)
 	}
 
-	return vtxo, nil
+	return map[string]interface{}{
+		"history": history,
+	}, nil
 }
 
-// rpcGetVTXOsByContract retrieves all VTXOs for a specific contract
-func (s *Server) rpcGetVTXOsByContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetOpenInterest retrieves the open interest of active contracts per expiry and strike
+func (s *Server) rpcGetOpenInterest(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		ContractID string `json:"contract_id"`
+		ContractType string `json:"contract_type,omitempty"` // CALL or PUT, empty for both
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -380,19 +574,21 @@ func (s *Server) rpcGetVTXOsByContract(ctx context.Context, params json.RawMessa
 		}
 	}
 
-	vtxos, err := s.service.GetVTXOsByContract(ctx, req.ContractID)
+	buckets, err := s.service.GetOpenInterest(ctx, hashperp.ContractType(req.ContractType))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get VTXOs by contract: %w", err)
+		return nil, fmt.Errorf("failed to get open interest: %w", err)
 	}
 
-	return vtxos, nil
+	return map[string]interface{}{
+		"open_interest": buckets,
+	}, nil
 }
 
-// rpcGetVTXOsByUser retrieves all VTXOs for a specific user
-func (s *Server) rpcGetVTXOsByUser(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetContractPnLHistory retrieves the unrealized PnL of a contract over its life for charting
+func (s *Server) rpcGetContractPnLHistory(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		UserID     string `json:"user_id"`
-		OnlyActive bool   `json:"only_active,omitempty"`
+		ContractID     string `json:"contract_id"`
+		IntervalBlocks uint64 `json:"interval_blocks"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -403,20 +599,20 @@ func (s *Server) rpcGetVTXOsByUser(ctx context.Context, params json.RawMessage)
 		}
 	}
 
-	vtxos, err := s.service.GetVTXOsByUser(ctx, req.UserID, req.OnlyActive)
+	history, err := s.service.GetContractPnLHistory(ctx, req.ContractID, req.IntervalBlocks)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get VTXOs by user: %w", err)
+		return nil, fmt.Errorf("failed to get contract PnL history: %w", err)
 	}
 
-	return vtxos, nil
+	return map[string]interface{}{
+		"history": history,
+	}, nil
 }
 
-// rpcSwapVTXO swaps a VTXO between users
-func (s *Server) rpcSwapVTXO(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetUserPositionSummary retrieves a user's positions and net exposure across active contracts
+func (s *Server) rpcGetUserPositionSummary(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		VTXOID          string `json:"vtxo_id"`
-		NewOwnerID      string `json:"new_owner_id"`
-		NewSignatureData string `json:"new_signature_data"` // Base64 encoded
+		UserID string `json:"user_id"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -427,32 +623,68 @@ func (s *Server) rpcSwapVTXO(ctx context.Context, params json.RawMessage) (inter
 		}
 	}
 
-	// Decode the signature data
-	signatureData, err := decodeBase64(req.NewSignatureData)
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	summary, err := s.service.GetUserPositionSummary(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// rpcGetContractsRequiringSettlement retrieves active contracts past their expiry block
+func (s *Server) rpcGetContractsRequiringSettlement(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	contracts, err := s.service.GetContractsRequiringSettlement(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contracts requiring settlement: %w", err)
+	}
+
+	return map[string]interface{}{
+		"contracts": contracts,
+	}, nil
+}
+
+// rpcAutoSettleExpired settles every contract past its expiry block
+func (s *Server) rpcAutoSettleExpired(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	summary, err := s.service.AutoSettleExpired(ctx)
 	if err != nil {
+		return nil, fmt.Errorf("failed to settle expired contracts: %w", err)
+	}
+
+	return summary, nil
+}
+
+// rpcReconcileContract reports where a contract's records differ from the chain
+func (s *Server) rpcReconcileContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID  string `json:"contract_id"`
+		AutoCorrect bool   `json:"auto_correct"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, &RPCError{
 			Code:    -32602,
-			Message: "Invalid signature data",
+			Message: "Invalid params",
 			Data:    err.Error(),
 		}
 	}
 
-	vtxo, tx, err := s.service.SwapVTXO(ctx, req.VTXOID, req.NewOwnerID, signatureData)
+	report, err := s.service.ReconcileContract(ctx, req.ContractID, req.AutoCorrect)
 	if err != nil {
-		return nil, fmt.Errorf("failed to swap VTXO: %w", err)
+		return nil, fmt.Errorf("failed to reconcile contract: %w", err)
 	}
 
-	return map[string]interface{}{
-		"vtxo":        vtxo,
-		"transaction": tx,
-	}, nil
+	return report, nil
 }
 
-// rpcCreatePresignedExitTransaction creates a pre-signed exit transaction for a VTXO
-func (s *Server) rpcCreatePresignedExitTransaction(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcFundContract broadcasts a pending contract's setup transaction once both parties have signed it
+func (s *Server) rpcFundContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		VTXOID        string `json:"vtxo_id"`
-		SignatureData string `json:"signature_data"` // Base64 encoded
+		ContractID      string `json:"contract_id"`
+		BuyerSignature  string `json:"buyer_signature"`  // Base64 signature over the fund message of <contract>, <buyer>, <vtxo>
+		SellerSignature string `json:"seller_signature"` // Base64 signature over the fund message of <contract>, <seller>, <vtxo>
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -463,8 +695,15 @@ func (s *Server) rpcCreatePresignedExitTransaction(ctx context.Context, params j
 		}
 	}
 
-	// Decode the signature data
-	signatureData, err := decodeBase64(req.SignatureData)
+	buyerSignature, err := decodeBase64(req.BuyerSignature)
+	if err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid signature data",
+			Data:    err.Error(),
+		}
+	}
+	sellerSignature, err := decodeBase64(req.SellerSignature)
 	if err != nil {
 		return nil, &RPCError{
 			Code:    -32602,
@@ -473,20 +712,19 @@ func (s *Server) rpcCreatePresignedExitTransaction(ctx context.Context, params j
 		}
 	}
 
-	txID, err := s.service.CreatePresignedExitTransaction(ctx, req.VTXOID, signatureData)
+	tx, err := s.service.FundContract(ctx, req.ContractID, buyerSignature, sellerSignature)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create pre-signed exit transaction: %w", err)
+		return nil, fmt.Errorf("failed to fund contract: %w", err)
 	}
 
-	return map[string]interface{}{
-		"transaction_id": txID,
-	}, nil
+	return tx, nil
 }
 
-// rpcExecuteVTXOSweep executes a VTXO sweep
-func (s *Server) rpcExecuteVTXOSweep(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcCancelContract cancels a pending contract that was never funded
+func (s *Server) rpcCancelContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		VTXOID string `json:"vtxo_id"`
+		ContractID string `json:"contract_id"`
+		UserID     string `json:"user_id"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -497,23 +735,21 @@ func (s *Server) rpcExecuteVTXOSweep(ctx context.Context, params json.RawMessage
 		}
 	}
 
-	tx, err := s.service.ExecuteVTXOSweep(ctx, req.VTXOID)
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	tx, err := s.service.CancelContract(ctx, req.ContractID, req.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute VTXO sweep: %w", err)
+		return nil, fmt.Errorf("failed to cancel contract: %w", err)
 	}
 
 	return tx, nil
 }
 
-// rpcPlaceOrder places a new order in the order book
-func (s *Server) rpcPlaceOrder(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetContractScripts retrieves the scripts generated for a contract
+func (s *Server) rpcGetContractScripts(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		UserID           string  `json:"user_id"`
-		OrderType        string  `json:"order_type"`
-		ContractType     string  `json:"contract_type"`
-		StrikeRate       float64 `json:"strike_rate"`
-		ExpiryBlockHeight uint64  `json:"expiry_block_height"`
-		Size             float64 `json:"size"`
+		ContractID string `json:"contract_id"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -524,27 +760,19 @@ func (s *Server) rpcPlaceOrder(ctx context.Context, params json.RawMessage) (int
 		}
 	}
 
-	order, err := s.service.PlaceOrder(
-		ctx,
-		req.UserID,
-		hashperp.OrderType(req.OrderType),
-		hashperp.ContractType(req.ContractType),
-		req.StrikeRate,
-		req.ExpiryBlockHeight,
-		req.Size,
-	)
+	scripts, err := s.service.GetContractScripts(ctx, req.ContractID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to place order: %w", err)
+		return nil, fmt.Errorf("failed to get contract scripts: %w", err)
 	}
 
-	return order, nil
+	return scripts, nil
 }
 
-// rpcCancelOrder cancels an existing order
-func (s *Server) rpcCancelOrder(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcSettleContract settles a contract
+func (s *Server) rpcSettleContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		OrderID string `json:"order_id"`
-		UserID  string `json:"user_id"`
+		ContractID  string               `json:"contract_id"`
+		FeePriority hashperp.FeePriority `json:"fee_priority"` // slow, normal or fast, defaults to normal
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -555,20 +783,27 @@ func (s *Server) rpcCancelOrder(ctx context.Context, params json.RawMessage) (in
 		}
 	}
 
-	err := s.service.CancelOrder(ctx, req.OrderID, req.UserID)
+	tx, err := s.service.SettleContract(ctx, req.ContractID, req.FeePriority)
 	if err != nil {
-		return nil, fmt.Errorf("failed to cancel order: %w", err)
+		return nil, fmt.Errorf("failed to settle contract: %w", err)
 	}
 
-	return map[string]interface{}{
-		"success": true,
-	}, nil
+	// A finalized settlement also reports each side's payout, a proposal has none yet
+	var settlement *hashperp.SettlementResult
+	if tx.Type == hashperp.CONTRACT_SETTLEMENT {
+		settlement = hashperp.SettlementResultFromTransaction(tx)
+	}
+
+	return struct {
+		*hashperp.Transaction
+		Settlement *hashperp.SettlementResult `json:"settlement,omitempty"`
+	}{tx, settlement}, nil
 }
 
-// rpcGetOrder retrieves an order by ID
-func (s *Server) rpcGetOrder(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetSettlementResult retrieves the payouts recorded for a settled contract
+func (s *Server) rpcGetSettlementResult(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		OrderID string `json:"order_id"`
+		ContractID string `json:"contract_id"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -579,9 +814,769 @@ func (s *Server) rpcGetOrder(ctx context.Context, params json.RawMessage) (inter
 		}
 	}
 
-	order, err := s.service.GetOrder(ctx, req.OrderID)
+	result, err := s.service.GetSettlementResult(ctx, req.ContractID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get order: %w", err)
+		return nil, fmt.Errorf("failed to get settlement result: %w", err)
+	}
+
+	return result, nil
+}
+
+// rpcPreviewSettlement computes the outcome a contract would settle with, without settling it
+func (s *Server) rpcPreviewSettlement(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID string `json:"contract_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	preview, err := s.service.PreviewSettlement(ctx, req.ContractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to preview settlement: %w", err)
+	}
+
+	return preview, nil
+}
+
+// rpcConfirmSettlement accepts a proposed settlement before its dispute window elapses
+func (s *Server) rpcConfirmSettlement(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID string `json:"contract_id"`
+		UserID     string `json:"user_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	tx, err := s.service.ConfirmSettlement(ctx, req.ContractID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm settlement: %w", err)
+	}
+
+	return tx, nil
+}
+
+// rpcDisputeSettlement disputes a proposed settlement through the dispute resolution exit path
+func (s *Server) rpcDisputeSettlement(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID string `json:"contract_id"`
+		UserID     string `json:"user_id"`
+		Evidence   string `json:"evidence"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	tx, err := s.service.DisputeSettlement(ctx, req.ContractID, req.UserID, req.Evidence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dispute settlement: %w", err)
+	}
+
+	return tx, nil
+}
+
+// rpcExitContract exits a contract
+func (s *Server) rpcExitContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID string `json:"contract_id"`
+		UserID     string `json:"user_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	tx, err := s.service.ExitContract(ctx, req.ContractID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exit contract: %w", err)
+	}
+
+	return tx, nil
+}
+
+// rpcRolloverContract rolls over a contract
+func (s *Server) rpcRolloverContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID          string `json:"contract_id"`
+		NewExpiryBlockHeight uint64 `json:"new_expiry_block_height"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	contract, tx, err := s.service.RolloverContract(ctx, req.ContractID, req.NewExpiryBlockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rollover contract: %w", err)
+	}
+
+	return map[string]interface{}{
+		"contract":    contract,
+		"transaction": tx,
+	}, nil
+}
+
+// rpcJoinContractSide adds the caller to the pool of one side of a contract
+func (s *Server) rpcJoinContractSide(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID    string  `json:"contract_id"`
+		UserID        string  `json:"user_id"`
+		Side          string  `json:"side"`
+		Amount        float64 `json:"amount"`
+		SignatureData string  `json:"signature_data"` // Base64 encoded
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	// Decode the signature data
+	signatureData, err := decodeBase64(req.SignatureData)
+	if err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid signature data",
+			Data:    err.Error(),
+		}
+	}
+
+	contract, tx, err := s.service.JoinContractSide(
+		ctx, req.ContractID, req.UserID, hashperp.PositionSide(req.Side), req.Amount, signatureData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join contract side: %w", err)
+	}
+
+	return map[string]interface{}{
+		"contract":    contract,
+		"transaction": tx,
+	}, nil
+}
+
+// rpcRequestDynamicJoin lets the caller take the open side of a contract
+func (s *Server) rpcRequestDynamicJoin(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID    string `json:"contract_id"`
+		UserID        string `json:"user_id"`
+		Side          string `json:"side"`
+		SignatureData string `json:"signature_data"` // Base64 encoded
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	// Decode the signature data
+	signatureData, err := decodeBase64(req.SignatureData)
+	if err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid signature data",
+			Data:    err.Error(),
+		}
+	}
+
+	contract, tx, err := s.service.RequestDynamicJoin(
+		ctx, req.ContractID, req.UserID, hashperp.PositionSide(req.Side), signatureData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join contract: %w", err)
+	}
+
+	return map[string]interface{}{
+		"contract":    contract,
+		"transaction": tx,
+	}, nil
+}
+
+func (s *Server) rpcExecuteExitPath(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID   string               `json:"contract_id"`
+		UserID       string               `json:"user_id"`
+		ExitPathType string               `json:"exit_path_type"`
+		FeePriority  hashperp.FeePriority `json:"fee_priority"` // slow, normal or fast, defaults to normal
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	tx, err := s.service.ExecuteExitPath(ctx, req.ContractID, req.UserID, req.ExitPathType, req.FeePriority)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute exit path: %w", err)
+	}
+
+	return tx, nil
+}
+
+// VTXO RPC Methods
+
+// rpcCreateVTXO creates a new VTXO
+func (s *Server) rpcCreateVTXO(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID    string  `json:"contract_id"`
+		OwnerID       string  `json:"owner_id"`
+		Amount        float64 `json:"amount"`
+		ScriptPath    string  `json:"script_path"`
+		SignatureData string  `json:"signature_data"` // Base64 encoded
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.OwnerID = callerUserID(ctx, req.OwnerID)
+
+	// Decode signature data
+	var signatureData []byte
+	if req.SignatureData != "" {
+		var err error
+		signatureData, err = decodeBase64(req.SignatureData)
+		if err != nil {
+			return nil, &RPCError{
+				Code:    -32602,
+				Message: "Invalid signature data",
+				Data:    err.Error(),
+			}
+		}
+	}
+
+	vtxo, err := s.service.CreateVTXO(
+		ctx,
+		req.ContractID,
+		req.OwnerID,
+		req.Amount,
+		req.ScriptPath,
+		signatureData,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create VTXO: %w", err)
+	}
+
+	return vtxo, nil
+}
+
+// Additional RPC method implementations would follow a similar pattern.
+// For brevity, I'm not including all method implementations here.
+
+// decodeBase64 decodes a base64 string
+
+// rpcGetVTXO retrieves a VTXO by ID
+func (s *Server) rpcGetVTXO(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		VTXOID string `json:"vtxo_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	vtxo, err := s.service.GetVTXO(ctx, req.VTXOID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VTXO: %w", err)
+	}
+
+	return vtxo, nil
+}
+
+// rpcGetVTXOsByContract retrieves all VTXOs for a specific contract
+func (s *Server) rpcGetVTXOsByContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID string `json:"contract_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	vtxos, err := s.service.GetVTXOsByContract(ctx, req.ContractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VTXOs by contract: %w", err)
+	}
+
+	return vtxos, nil
+}
+
+// rpcGetUserVTXOInContract retrieves the active VTXO a user holds in a contract
+func (s *Server) rpcGetUserVTXOInContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractID string `json:"contract_id"`
+		UserID     string `json:"user_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	vtxo, err := s.service.GetUserVTXOInContract(ctx, req.ContractID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user VTXO in contract: %w", err)
+	}
+
+	return vtxo, nil
+}
+
+// rpcGetVTXOsByUser retrieves all VTXOs for a specific user
+func (s *Server) rpcGetVTXOsByUser(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		UserID          string `json:"user_id"`
+		OnlyActive      bool   `json:"only_active,omitempty"`
+		IncludeArchived bool   `json:"include_archived,omitempty"`
+		Limit           int    `json:"limit,omitempty"`
+		Cursor          string `json:"cursor,omitempty"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	page := hashperp.PageRequest{Limit: req.Limit, Cursor: req.Cursor}
+	vtxos, nextCursor, err := s.service.GetVTXOsByUser(ctx, req.UserID, req.OnlyActive, req.IncludeArchived, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VTXOs by user: %w", err)
+	}
+
+	return map[string]interface{}{
+		"vtxos":       vtxos,
+		"next_cursor": nextCursor,
+	}, nil
+}
+
+// rpcSwapVTXO swaps a VTXO between users
+func (s *Server) rpcSwapVTXO(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		VTXOID          string `json:"vtxo_id"`
+		NewOwnerID      string `json:"new_owner_id"`
+		NewSignatureData string `json:"new_signature_data"` // Base64 encoded
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Decode the signature data
+	signatureData, err := decodeBase64(req.NewSignatureData)
+	if err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid signature data",
+			Data:    err.Error(),
+		}
+	}
+
+	vtxo, tx, err := s.service.SwapVTXO(ctx, req.VTXOID, req.NewOwnerID, signatureData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to swap VTXO: %w", err)
+	}
+
+	return map[string]interface{}{
+		"vtxo":        vtxo,
+		"transaction": tx,
+	}, nil
+}
+
+// rpcSplitVTXO splits a VTXO into child VTXOs owned by several users
+func (s *Server) rpcSplitVTXO(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		VTXOID   string `json:"vtxo_id"`
+		Children []struct {
+			OwnerID       string  `json:"owner_id"`
+			Amount        float64 `json:"amount"`
+			SignatureData string  `json:"signature_data"` // Base64 encoded
+		} `json:"children"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	amounts := make([]float64, len(req.Children))
+	owners := make([]string, len(req.Children))
+	signatures := make([][]byte, len(req.Children))
+	for i, child := range req.Children {
+		signatureData, err := decodeBase64(child.SignatureData)
+		if err != nil {
+			return nil, &RPCError{
+				Code:    -32602,
+				Message: "Invalid signature data",
+				Data:    err.Error(),
+			}
+		}
+		amounts[i], owners[i], signatures[i] = child.Amount, child.OwnerID, signatureData
+	}
+
+	vtxos, tx, err := s.service.SplitVTXO(ctx, req.VTXOID, amounts, owners, signatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split VTXO: %w", err)
+	}
+
+	return map[string]interface{}{
+		"vtxos":       vtxos,
+		"transaction": tx,
+	}, nil
+}
+
+// rpcCreatePresignedExitTransaction creates a pre-signed exit transaction for a VTXO
+func (s *Server) rpcCreatePresignedExitTransaction(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		VTXOID        string `json:"vtxo_id"`
+		SignatureData string `json:"signature_data"` // Base64 encoded
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Decode the signature data
+	signatureData, err := decodeBase64(req.SignatureData)
+	if err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid signature data",
+			Data:    err.Error(),
+		}
+	}
+
+	txID, err := s.service.CreatePresignedExitTransaction(ctx, req.VTXOID, signatureData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pre-signed exit transaction: %w", err)
+	}
+
+	return map[string]interface{}{
+		"transaction_id": txID,
+	}, nil
+}
+
+// rpcGetPreSignedExits retrieves the pre-signed exits of a VTXO
+func (s *Server) rpcGetPreSignedExits(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		VTXOID string `json:"vtxo_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	exits, err := s.service.GetPreSignedExits(ctx, req.VTXOID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pre-signed exits: %w", err)
+	}
+
+	return map[string]interface{}{
+		"pre_signed_exits": exits,
+	}, nil
+}
+
+// rpcBroadcastPreSignedExit broadcasts a pre-signed exit transaction
+func (s *Server) rpcBroadcastPreSignedExit(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		PreSignedExitID string `json:"pre_signed_exit_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	tx, err := s.service.BroadcastPreSignedExit(ctx, req.PreSignedExitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to broadcast pre-signed exit: %w", err)
+	}
+
+	return tx, nil
+}
+
+// rpcExecuteVTXOSweep executes a VTXO sweep
+func (s *Server) rpcExecuteVTXOSweep(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		VTXOID string `json:"vtxo_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	tx, err := s.service.ExecuteVTXOSweep(ctx, req.VTXOID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute VTXO sweep: %w", err)
+	}
+
+	return tx, nil
+}
+
+// rpcSweepAllVTXOs sweeps every active VTXO of a user
+func (s *Server) rpcSweepAllVTXOs(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	summary, err := s.service.SweepAllVTXOs(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sweep VTXOs: %w", err)
+	}
+
+	return summary, nil
+}
+
+// rpcPlaceOrder places a new order in the order book
+func (s *Server) rpcPlaceOrder(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		UserID           string  `json:"user_id"`
+		OrderType        string  `json:"order_type"`
+		ContractType     string  `json:"contract_type"`
+		StrikeRate       float64 `json:"strike_rate"`
+		ExpiryBlockHeight uint64  `json:"expiry_block_height"`
+		Size             float64 `json:"size"`
+		ClientOrderID    string  `json:"client_order_id,omitempty"`
+		IdempotencyKey   string  `json:"idempotency_key,omitempty"`
+		SelfTradePolicy  string  `json:"self_trade_policy,omitempty"` // skip, cancel_oldest or cancel_newest, empty for the default
+		TimeInForce      string  `json:"time_in_force,omitempty"`     // GTC, IOC or FOK, empty for GTC
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	// An idempotency key dedups orders the same way a client order ID does
+	if req.ClientOrderID == "" {
+		req.ClientOrderID = req.IdempotencyKey
+	}
+
+	order, err := s.service.PlaceOrder(
+		ctx,
+		req.UserID,
+		hashperp.OrderType(req.OrderType),
+		hashperp.ContractType(req.ContractType),
+		req.StrikeRate,
+		req.ExpiryBlockHeight,
+		req.Size,
+		req.ClientOrderID,
+		hashperp.SelfTradePolicy(req.SelfTradePolicy),
+		hashperp.TimeInForce(req.TimeInForce),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	return order, nil
+}
+
+// rpcCancelOrder cancels an existing order
+func (s *Server) rpcCancelOrder(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		OrderID string `json:"order_id"`
+		UserID  string `json:"user_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	err := s.service.CancelOrder(ctx, req.OrderID, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	return map[string]interface{}{
+		"success": true,
+	}, nil
+}
+
+// rpcReduceOrder lowers the size of an open order
+func (s *Server) rpcReduceOrder(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		OrderID string  `json:"order_id"`
+		UserID  string  `json:"user_id"`
+		NewSize float64 `json:"new_size"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	order, err := s.service.ReduceOrder(ctx, req.OrderID, req.UserID, req.NewSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reduce order: %w", err)
+	}
+
+	return order, nil
+}
+
+// rpcModifyOrder changes the strike rate and size of an open order
+func (s *Server) rpcModifyOrder(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		OrderID       string  `json:"order_id"`
+		UserID        string  `json:"user_id"`
+		NewStrikeRate float64 `json:"new_strike_rate"`
+		NewSize       float64 `json:"new_size"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	order, err := s.service.ModifyOrder(ctx, req.OrderID, req.UserID, req.NewStrikeRate, req.NewSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify order: %w", err)
+	}
+
+	return order, nil
+}
+
+// rpcGetOrder retrieves an order by ID
+func (s *Server) rpcGetOrder(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		OrderID string `json:"order_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	order, err := s.service.GetOrder(ctx, req.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
 
 	return order, nil
@@ -602,6 +1597,9 @@ func (s *Server) rpcGetOrdersByUser(ctx context.Context, params json.RawMessage)
 		}
 	}
 
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
 	// Convert string statuses to OrderStatus
 	var statuses []hashperp.OrderStatus
 	for _, status := range req.Status {
@@ -631,7 +1629,7 @@ func (s *Server) rpcGetOrderBook(ctx context.Context, params json.RawMessage) (i
 		}
 	}
 
-	orders, err := s.service.GetOrderBook(
+	snapshot, err := s.service.GetOrderBook(
 		ctx,
 		hashperp.ContractType(req.ContractType),
 		req.ExpiryBlockHeight,
@@ -640,11 +1638,12 @@ func (s *Server) rpcGetOrderBook(ctx context.Context, params json.RawMessage) (i
 		return nil, fmt.Errorf("failed to get order book: %w", err)
 	}
 
-	// Group orders by type for easier client-side rendering
+	// Group orders by type for easier client-side rendering; each side keeps
+	// the price-time priority order returned by the service
 	buyOrders := []*hashperp.Order{}
 	sellOrders := []*hashperp.Order{}
 	
-	for _, order := range orders {
+	for _, order := range snapshot.Orders {
 		if order.OrderType == hashperp.BUY {
 			buyOrders = append(buyOrders, order)
 		} else {
@@ -655,6 +1654,7 @@ func (s *Server) rpcGetOrderBook(ctx context.Context, params json.RawMessage) (i
 	return map[string]interface{}{
 		"buy_orders":  buyOrders,
 		"sell_orders": sellOrders,
+		"sequence":    snapshot.Sequence,
 	}, nil
 }
 
@@ -671,6 +1671,132 @@ func (s *Server) rpcMatchOrders(ctx context.Context, params json.RawMessage) (in
 	}, nil
 }
 
+// rpcGetMarketView retrieves a consistent snapshot of the order book and contract data for a market
+func (s *Server) rpcGetMarketView(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractType      string `json:"contract_type"`
+		ExpiryBlockHeight uint64 `json:"expiry_block_height"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	view, err := s.service.GetMarketView(
+		ctx,
+		hashperp.ContractType(req.ContractType),
+		req.ExpiryBlockHeight,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market view: %w", err)
+	}
+
+	return view, nil
+}
+
+// rpcGetMarketSummary retrieves open interest, settled volume and rates across every market
+func (s *Server) rpcGetMarketSummary(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	summary, err := s.service.GetMarketSummary(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// rpcGetStats retrieves the number of active contracts, open orders and open swap offers
+func (s *Server) rpcGetStats(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	stats, err := s.service.GetPlatformStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get platform stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// rpcGetAuditTrail retrieves the status changes recorded for a contract, order, swap offer or VTXO
+func (s *Server) rpcGetAuditTrail(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		EntityType string `json:"entity_type"` // contract, order, swap_offer or vtxo
+		EntityID   string `json:"entity_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	changes, err := s.service.GetAuditTrail(ctx, hashperp.AuditEntityType(req.EntityType), req.EntityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit trail: %w", err)
+	}
+
+	return map[string]interface{}{
+		"status_changes": changes,
+	}, nil
+}
+
+// rpcGetOrderBookDepth retrieves the order book for a market aggregated into price levels
+func (s *Server) rpcGetOrderBookDepth(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractType      string `json:"contract_type"`
+		ExpiryBlockHeight uint64 `json:"expiry_block_height"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	depth, err := s.service.GetOrderBookDepth(
+		ctx,
+		hashperp.ContractType(req.ContractType),
+		req.ExpiryBlockHeight,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book depth: %w", err)
+	}
+
+	return depth, nil
+}
+
+// rpcGetQuote retrieves the best bid and ask of a market
+func (s *Server) rpcGetQuote(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ContractType      string `json:"contract_type"`
+		ExpiryBlockHeight uint64 `json:"expiry_block_height"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	quote, err := s.service.GetQuote(
+		ctx,
+		hashperp.ContractType(req.ContractType),
+		req.ExpiryBlockHeight,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	return quote, nil
+}
+
 // rpcCreateSwapOffer creates a new swap offer
 func (s *Server) rpcCreateSwapOffer(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
@@ -688,6 +1814,9 @@ func (s *Server) rpcCreateSwapOffer(ctx context.Context, params json.RawMessage)
 		}
 	}
 
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.OfferorID = callerUserID(ctx, req.OfferorID)
+
 	// Calculate expiry time
 	expiryTime := time.Now().UTC().Add(time.Duration(req.ExpiryHours) * time.Hour)
 
@@ -699,17 +1828,105 @@ func (s *Server) rpcCreateSwapOffer(ctx context.Context, params json.RawMessage)
 		expiryTime,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create swap offer: %w", err)
+		return nil, fmt.Errorf("failed to create swap offer: %w", err)
+	}
+
+	return offer, nil
+}
+
+// rpcAcceptSwapOffer accepts a swap offer
+func (s *Server) rpcAcceptSwapOffer(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		OfferID       string `json:"offer_id"`
+		AcceptorID    string `json:"acceptor_id"`
+		SignatureData string `json:"signature_data"` // Base64 encoded
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.AcceptorID = callerUserID(ctx, req.AcceptorID)
+
+	// Decode the signature data
+	signatureData, err := decodeBase64(req.SignatureData)
+	if err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid signature data",
+			Data:    err.Error(),
+		}
+	}
+
+	tx, err := s.service.AcceptSwapOffer(ctx, req.OfferID, req.AcceptorID, signatureData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept swap offer: %w", err)
+	}
+
+	return tx, nil
+}
+
+// rpcCancelSwapOffer cancels a swap offer
+func (s *Server) rpcCancelSwapOffer(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		OfferID   string `json:"offer_id"`
+		OfferorID string `json:"offeror_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.OfferorID = callerUserID(ctx, req.OfferorID)
+
+	err := s.service.CancelSwapOffer(ctx, req.OfferID, req.OfferorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel swap offer: %w", err)
+	}
+
+	return map[string]interface{}{
+		"success": true,
+	}, nil
+}
+
+// rpcGetSwapOffer retrieves a swap offer by ID
+func (s *Server) rpcGetSwapOffer(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		OfferID string `json:"offer_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	offer, err := s.service.GetSwapOffer(ctx, req.OfferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swap offer: %w", err)
 	}
 
 	return offer, nil
 }
 
-// rpcAcceptSwapOffer accepts a swap offer
-func (s *Server) rpcAcceptSwapOffer(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetSwapOffersByUser retrieves all swap offers for a specific user
+func (s *Server) rpcGetSwapOffersByUser(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		OfferID    string `json:"offer_id"`
-		AcceptorID string `json:"acceptor_id"`
+		UserID    string   `json:"user_id"`
+		IsOfferor bool     `json:"is_offeror"`
+		Status    []string `json:"status,omitempty"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -720,19 +1937,33 @@ func (s *Server) rpcAcceptSwapOffer(ctx context.Context, params json.RawMessage)
 		}
 	}
 
-	tx, err := s.service.AcceptSwapOffer(ctx, req.OfferID, req.AcceptorID)
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	offers, err := s.service.GetSwapOffersByUser(ctx, req.UserID, req.IsOfferor, swapOfferStatuses(req.Status))
 	if err != nil {
-		return nil, fmt.Errorf("failed to accept swap offer: %w", err)
+		return nil, fmt.Errorf("failed to get swap offers by user: %w", err)
 	}
 
-	return tx, nil
+	return offers, nil
 }
 
-// rpcCancelSwapOffer cancels a swap offer
-func (s *Server) rpcCancelSwapOffer(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// swapOfferStatuses converts string statuses to SwapOfferStatus
+func swapOfferStatuses(status []string) []hashperp.SwapOfferStatus {
+	var statuses []hashperp.SwapOfferStatus
+	for _, s := range status {
+		statuses = append(statuses, hashperp.SwapOfferStatus(s))
+	}
+	return statuses
+}
+
+// rpcGetSwapOffersByContract retrieves all swap offers for a specific contract
+func (s *Server) rpcGetSwapOffersByContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		OfferID   string `json:"offer_id"`
-		OfferorID string `json:"offeror_id"`
+		ContractID string   `json:"contract_id"`
+		Status     []string `json:"status,omitempty"`
+		Limit      int      `json:"limit,omitempty"`
+		Cursor     string   `json:"cursor,omitempty"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -743,20 +1974,23 @@ func (s *Server) rpcCancelSwapOffer(ctx context.Context, params json.RawMessage)
 		}
 	}
 
-	err := s.service.CancelSwapOffer(ctx, req.OfferID, req.OfferorID)
+	page := hashperp.PageRequest{Limit: req.Limit, Cursor: req.Cursor}
+	offers, nextCursor, err := s.service.GetSwapOffersByContract(ctx, req.ContractID, swapOfferStatuses(req.Status), page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to cancel swap offer: %w", err)
+		return nil, fmt.Errorf("failed to get swap offers by contract: %w", err)
 	}
 
 	return map[string]interface{}{
-		"success": true,
+		"offers":      offers,
+		"next_cursor": nextCursor,
 	}, nil
 }
 
-// rpcGetSwapOffer retrieves a swap offer by ID
-func (s *Server) rpcGetSwapOffer(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcGetBestSwapOffer retrieves the lowest-rate open offer for one side of a contract
+func (s *Server) rpcGetBestSwapOffer(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		OfferID string `json:"offer_id"`
+		ContractID string `json:"contract_id"`
+		Side       string `json:"side"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -767,19 +2001,21 @@ func (s *Server) rpcGetSwapOffer(ctx context.Context, params json.RawMessage) (i
 		}
 	}
 
-	offer, err := s.service.GetSwapOffer(ctx, req.OfferID)
+	offer, err := s.service.GetBestSwapOffer(ctx, req.ContractID, hashperp.PositionSide(req.Side))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get swap offer: %w", err)
+		return nil, fmt.Errorf("failed to get best swap offer: %w", err)
 	}
 
 	return offer, nil
 }
 
-// rpcGetSwapOffersByUser retrieves all swap offers for a specific user
-func (s *Server) rpcGetSwapOffersByUser(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcAutoAcceptBestOffer accepts the lowest-rate open offer on a VTXO within a rate limit
+func (s *Server) rpcAutoAcceptBestOffer(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		UserID    string `json:"user_id"`
-		IsOfferor bool   `json:"is_offeror"`
+		VTXOID        string  `json:"vtxo_id"`
+		AcceptorID    string  `json:"acceptor_id"`
+		MaxRate       float64 `json:"max_rate"`
+		SignatureData string  `json:"signature_data"` // Base64 encoded
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -790,18 +2026,34 @@ func (s *Server) rpcGetSwapOffersByUser(ctx context.Context, params json.RawMess
 		}
 	}
 
-	offers, err := s.service.GetSwapOffersByUser(ctx, req.UserID, req.IsOfferor)
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.AcceptorID = callerUserID(ctx, req.AcceptorID)
+
+	// Decode the signature data
+	signatureData, err := decodeBase64(req.SignatureData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get swap offers by user: %w", err)
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid signature data",
+			Data:    err.Error(),
+		}
 	}
 
-	return offers, nil
+	offer, tx, err := s.service.AutoAcceptBestOffer(ctx, req.VTXOID, req.AcceptorID, req.MaxRate, signatureData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to auto-accept swap offer: %w", err)
+	}
+
+	return map[string]interface{}{
+		"offer":       offer,
+		"transaction": tx,
+	}, nil
 }
 
-// rpcGetSwapOffersByContract retrieves all swap offers for a specific contract
-func (s *Server) rpcGetSwapOffersByContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
+// rpcInvalidateOffersForVTXO force-cancels the open offers on a VTXO that is no longer active
+func (s *Server) rpcInvalidateOffersForVTXO(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		ContractID string `json:"contract_id"`
+		VTXOID string `json:"vtxo_id"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -812,12 +2064,14 @@ func (s *Server) rpcGetSwapOffersByContract(ctx context.Context, params json.Raw
 		}
 	}
 
-	offers, err := s.service.GetSwapOffersByContract(ctx, req.ContractID)
+	canceled, err := s.service.InvalidateOffersForVTXO(ctx, req.VTXOID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get swap offers by contract: %w", err)
+		return nil, fmt.Errorf("failed to invalidate swap offers: %w", err)
 	}
 
-	return offers, nil
+	return map[string]interface{}{
+		"canceled": canceled,
+	}, nil
 }
 
 // rpcGetCurrentHashRate retrieves the current hash rate
@@ -896,11 +2150,11 @@ func (s *Server) rpcCalculateBTCPerPHPerDay(ctx context.Context, params json.Raw
 	// If block height is not provided, use current block height
 	blockHeight := req.BlockHeight
 	if blockHeight == 0 {
-		var err error
-		blockHeight, err = s.service.GetCurrentBlockHeight(ctx)
+		reading, err := s.service.GetBlockHeight(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get current block height: %w", err)
 		}
+		blockHeight = reading.BlockHeight
 	}
 
 	btcPerPHPerDay, err := s.service.CalculateBTCPerPHPerDay(ctx, req.HashRate, blockHeight)
@@ -935,11 +2189,35 @@ func (s *Server) rpcGetTransaction(ctx context.Context, params json.RawMessage)
 	return tx, nil
 }
 
+// rpcGetTransactionStatus reports whether a broadcast transaction is pending, confirmed or failed
+func (s *Server) rpcGetTransactionStatus(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		TransactionID string `json:"transaction_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	status, err := s.service.GetTransactionStatus(ctx, req.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction status: %w", err)
+	}
+
+	return status, nil
+}
+
 // rpcGetTransactionsByUser retrieves all transactions for a specific user
 func (s *Server) rpcGetTransactionsByUser(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
 		UserID string   `json:"user_id"`
 		Types  []string `json:"types,omitempty"`
+		Limit  int      `json:"limit,omitempty"`
+		Cursor string   `json:"cursor,omitempty"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -950,24 +2228,33 @@ func (s *Server) rpcGetTransactionsByUser(ctx context.Context, params json.RawMe
 		}
 	}
 
+	// Default to the authenticated caller, the service rejects acting for anyone else
+	req.UserID = callerUserID(ctx, req.UserID)
+
 	// Convert string types to TransactionType
 	var types []hashperp.TransactionType
 	for _, t := range req.Types {
 		types = append(types, hashperp.TransactionType(t))
 	}
 
-	txs, err := s.service.GetTransactionsByUser(ctx, req.UserID, types)
+	page := hashperp.PageRequest{Limit: req.Limit, Cursor: req.Cursor}
+	txs, nextCursor, err := s.service.GetTransactionsByUser(ctx, req.UserID, types, page)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions by user: %w", err)
 	}
 
-	return txs, nil
+	return map[string]interface{}{
+		"transactions": txs,
+		"next_cursor":  nextCursor,
+	}, nil
 }
 
 // rpcGetTransactionsByContract retrieves all transactions for a specific contract
 func (s *Server) rpcGetTransactionsByContract(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
-		ContractID string `json:"contract_id"`
+		ContractID string   `json:"contract_id"`
+		Types      []string `json:"types,omitempty"`
+		Order      string   `json:"order,omitempty"` // asc or desc, defaults to asc
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
@@ -978,7 +2265,12 @@ func (s *Server) rpcGetTransactionsByContract(ctx context.Context, params json.R
 		}
 	}
 
-	txs, err := s.service.GetTransactionsByContract(ctx, req.ContractID)
+	var txTypes []hashperp.TransactionType
+	for _, t := range req.Types {
+		txTypes = append(txTypes, hashperp.TransactionType(t))
+	}
+
+	txs, err := s.service.GetTransactionsByContract(ctx, req.ContractID, txTypes, hashperp.SortOrder(req.Order))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions by contract: %w", err)
 	}
@@ -986,18 +2278,249 @@ func (s *Server) rpcGetTransactionsByContract(ctx context.Context, params json.R
 	return txs, nil
 }
 
-// rpcGetCurrentBlockHeight retrieves the current block height
-func (s *Server) rpcGetCurrentBlockHeight(ctx context.Context, params json.RawMessage) (interface{}, error) {
-	blockHeight, err := s.service.GetCurrentBlockHeight(ctx)
+// rpcGetTransactionsByType retrieves every transaction of a specific type
+func (s *Server) rpcGetTransactionsByType(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	txs, err := s.service.GetTransactionsByType(ctx, hashperp.TransactionType(req.Type))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current block height: %w", err)
+		return nil, fmt.Errorf("failed to get transactions by type: %w", err)
+	}
+
+	return txs, nil
+}
+
+// rpcGetTransactionsByTimeRange retrieves the transactions recorded within a time range
+func (s *Server) rpcGetTransactionsByTimeRange(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		StartTime int64 `json:"start_time"` // Unix timestamp
+		EndTime   int64 `json:"end_time"`   // Unix timestamp
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	startTime := time.Unix(req.StartTime, 0).UTC()
+	endTime := time.Unix(req.EndTime, 0).UTC()
+
+	txs, err := s.service.GetTransactionsByTimeRange(ctx, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions by time range: %w", err)
+	}
+
+	return txs, nil
+}
+
+// rpcGetTransactionsByBlockRange retrieves the transactions recorded within an inclusive
+// block height range
+func (s *Server) rpcGetTransactionsByBlockRange(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		FromHeight uint64 `json:"from_height"`
+		ToHeight   uint64 `json:"to_height"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+
+	txs, err := s.service.GetTransactionsByBlockRange(ctx, req.FromHeight, req.ToHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions by block range: %w", err)
+	}
+
+	return txs, nil
+}
+
+// Webhook RPC Methods
+
+// rpcRegisterWebhook registers a callback URL for a user's transactions
+func (s *Server) rpcRegisterWebhook(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		UserID     string   `json:"user_id"`
+		URL        string   `json:"url"`
+		EventTypes []string `json:"event_types,omitempty"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	var eventTypes []hashperp.TransactionType
+	for _, eventType := range req.EventTypes {
+		eventTypes = append(eventTypes, hashperp.TransactionType(eventType))
+	}
+
+	subscription, err := s.service.RegisterWebhook(ctx, req.UserID, req.URL, eventTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register webhook: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// rpcGetWebhooksByUser retrieves a user's webhook subscriptions
+func (s *Server) rpcGetWebhooksByUser(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	subscriptions, err := s.service.GetWebhooksByUser(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks by user: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// rpcDeleteWebhook removes a webhook subscription
+func (s *Server) rpcDeleteWebhook(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		WebhookID string `json:"webhook_id"`
+		UserID    string `json:"user_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	if err := s.service.DeleteWebhook(ctx, req.WebhookID, req.UserID); err != nil {
+		return nil, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return map[string]interface{}{
+		"success": true,
+	}, nil
+}
+
+// rpcRegisterUserKey registers or rotates the caller's public key
+func (s *Server) rpcRegisterUserKey(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		UserID              string `json:"user_id"`
+		PublicKey           string `json:"public_key"`                      // Hex encoded
+		Signature           string `json:"signature"`                       // Hex encoded, by the new key
+		CurrentKeySignature string `json:"current_key_signature,omitempty"` // Hex encoded, required when rotating
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	pubKey, err := hex.DecodeString(req.PublicKey)
+	if err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid public key",
+			Data:    err.Error(),
+		}
+	}
+
+	signature, err := hex.DecodeString(req.Signature)
+	if err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid signature",
+			Data:    err.Error(),
+		}
+	}
+	currentKeySignature, err := hex.DecodeString(req.CurrentKeySignature)
+	if err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid current key signature",
+			Data:    err.Error(),
+		}
+	}
+
+	if err := s.service.RegisterUserKey(ctx, req.UserID, pubKey, signature, currentKeySignature); err != nil {
+		return nil, fmt.Errorf("failed to register user key: %w", err)
+	}
+
+	return map[string]interface{}{
+		"user_id":    req.UserID,
+		"public_key": hex.EncodeToString(pubKey),
+	}, nil
+}
+
+// rpcGetUserKey retrieves a user's registered public key
+func (s *Server) rpcGetUserKey(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &RPCError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+	req.UserID = callerUserID(ctx, req.UserID)
+
+	pubKey, err := s.service.GetUserKey(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user key: %w", err)
 	}
 
 	return map[string]interface{}{
-		"block_height": blockHeight,
+		"user_id":    req.UserID,
+		"public_key": hex.EncodeToString(pubKey),
 	}, nil
 }
 
+// rpcGetCurrentBlockHeight retrieves the current block height, the last known height flagged
+// as stale while the node is unreachable
+func (s *Server) rpcGetCurrentBlockHeight(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	reading, err := s.service.GetBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	return reading, nil
+}
+
 // rpcValidateContractParameters validates contract parameters
 func (s *Server) rpcValidateContractParameters(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
@@ -1047,3 +2570,13 @@ func decodeBase64(encoded string) ([]byte, error) {
 	
 	return decoded, nil
 }
+
+// callerUserID returns the user ID given in a request, defaulting to the authenticated caller
+func callerUserID(ctx context.Context, requested string) string {
+	if requested == "" {
+		if userID, ok := hashperp.AuthenticatedUserID(ctx); ok {
+			return userID
+		}
+	}
+	return requested
+}
@@ -1,12 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -16,19 +18,36 @@ import (
 
 // Server represents the API server
 type Server struct {
-	router     *mux.Router
-	service    hashperp.HashPerpService
-	upgrader   websocket.Upgrader
-	httpServer *http.Server
+	router        *mux.Router
+	service       hashperp.HashPerpService
+	upgrader      websocket.Upgrader
+	httpServer    *http.Server
+	wsWriteMu     sync.Map      // *websocket.Conn -> *sync.Mutex, serializes writes per connection
+	authenticator Authenticator // Resolves the caller of RPC and WebSocket requests, nil trusts every request
+	metrics       hashperp.MetricsRecorder // Records RPC call latency
+	metricsHandler http.Handler            // Serves /metrics, nil disables the endpoint
 }
 
-// NewServer creates a new API server
-func NewServer(service hashperp.HashPerpService) *Server {
+// NewServer creates a new API server. A nil authenticator runs every RPC and WebSocket
+// request as an internal caller, for development only. A nil metrics handler disables /metrics.
+func NewServer(
+	service hashperp.HashPerpService,
+	authenticator Authenticator,
+	metrics hashperp.MetricsRecorder,
+	metricsHandler http.Handler,
+) *Server {
 	router := mux.NewRouter()
 	
+	if metrics == nil {
+		metrics = hashperp.NewNoopMetricsRecorder()
+	}
+	
 	server := &Server{
-		router:  router,
-		service: service,
+		router:         router,
+		service:        service,
+		authenticator:  authenticator,
+		metrics:        metrics,
+		metricsHandler: metricsHandler,
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -50,11 +69,51 @@ func (s *Server) setupRoutes() {
 	// Health check endpoint
 	s.router.HandleFunc("/health", s.handleHealthCheck).Methods(http.MethodGet)
 	
+	// Metrics endpoint, scraped without authentication
+	if s.metricsHandler != nil {
+		s.router.Handle("/metrics", s.metricsHandler).Methods(http.MethodGet)
+	}
+	
+	// Authenticated endpoints
+	authenticated := s.router.NewRoute().Subrouter()
+	if s.authenticator != nil {
+		authenticated.Use(s.authMiddleware)
+	} else {
+		authenticated.Use(s.insecureDevMiddleware)
+	}
+	
 	// JSONRPC endpoint
-	s.router.HandleFunc("/rpc", s.handleRPC).Methods(http.MethodPost)
+	authenticated.HandleFunc("/rpc", s.handleRPC).Methods(http.MethodPost)
 	
 	// WebSocket endpoint
-	s.router.HandleFunc("/ws", s.handleWebSocket)
+	authenticated.HandleFunc("/ws", s.handleWebSocket)
+
+	// REST endpoints
+	s.setupRESTRoutes(authenticated)
+}
+
+// authMiddleware rejects unauthenticated requests and injects the caller's user ID into the request context
+func (s *Server) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := s.authenticator.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		
+		ctx := hashperp.ContextWithAuthenticatedUser(r.Context(), userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// insecureDevMiddleware runs every request as an internal caller, used only when the server
+// is started without an authenticator for development
+func (s *Server) insecureDevMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := hashperp.ContextWithInternalCaller(r.Context())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
 
 // Start starts the API server
@@ -73,17 +132,19 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.httpServer.Shutdown(ctx)
 }
 
-// handleHealthCheck handles health check requests
+// handleHealthCheck reports the status of each dependency, responding 503 if any is down
+// so load balancers can take the instance out of rotation
 func (s *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
-	err := s.service.Healthcheck(r.Context())
-	if err != nil {
-		http.Error(w, "Service Unhealthy", http.StatusServiceUnavailable)
-		return
+	report := s.service.Healthcheck(r.Context())
+	
+	status := http.StatusOK
+	if report.Status != hashperp.HEALTH_HEALTHY {
+		status = http.StatusServiceUnavailable
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
 }
 
 // RPCRequest represents a JSON-RPC request
@@ -109,16 +170,61 @@ type RPCError struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// Error implements the error interface so RPC errors can be returned from handlers
+func (e *RPCError) Error() string {
+	if e.Data != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Data)
+	}
+	return e.Message
+}
+
 // WebSocketMessage represents a WebSocket message
 type WebSocketMessage struct {
 	Type    string          `json:"type"`
 	Payload json.RawMessage `json:"payload"`
 }
 
-// handleRPC handles JSON-RPC requests
+// maxRPCBatchSize limits the number of calls accepted in a single batch request
+const maxRPCBatchSize = 100
+
+// handleRPC handles JSON-RPC requests, including JSON-RPC 2.0 batch requests
 func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeRPCError(w, &RPCError{
+			Code:    -32700,
+			Message: "Parse error",
+			Data:    err.Error(),
+		}, nil)
+		return
+	}
+	
+	// A JSON array body is a batch request
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		s.handleRPCBatch(w, r, trimmed)
+		return
+	}
+	
 	var req RPCRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeRPCError(w, &RPCError{
+			Code:    -32600,
+			Message: "Invalid Request",
+			Data:    err.Error(),
+		}, nil)
+		return
+	}
+	
+	response := s.dispatchRPCRequest(r.Context(), &req)
+	writeRPCResponse(w, response)
+}
+
+// handleRPCBatch dispatches each call in a batch independently and returns the
+// responses as an array, so a failing call does not abort the rest of the batch
+func (s *Server) handleRPCBatch(w http.ResponseWriter, r *http.Request, body json.RawMessage) {
+	var rawRequests []json.RawMessage
+	if err := json.Unmarshal(body, &rawRequests); err != nil {
 		writeRPCError(w, &RPCError{
 			Code:    -32700,
 			Message: "Parse error",
@@ -127,32 +233,98 @@ func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// Ensure JSON-RPC version is 2.0
-	if req.JSONRPC != "2.0" {
+	if len(rawRequests) == 0 {
 		writeRPCError(w, &RPCError{
 			Code:    -32600,
 			Message: "Invalid Request",
-			Data:    "JSONRPC version must be 2.0",
-		}, req.ID)
+			Data:    "batch must contain at least one request",
+		}, nil)
 		return
 	}
 	
-	result, err := s.executeRPCMethod(r.Context(), req.Method, req.Params)
+	if len(rawRequests) > maxRPCBatchSize {
+		writeRPCError(w, &RPCError{
+			Code:    -32600,
+			Message: "Invalid Request",
+			Data:    fmt.Sprintf("batch exceeds maximum of %d requests", maxRPCBatchSize),
+		}, nil)
+		return
+	}
+	
+	responses := make([]RPCResponse, 0, len(rawRequests))
+	for _, raw := range rawRequests {
+		var req RPCRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			responses = append(responses, RPCResponse{
+				JSONRPC: "2.0",
+				Error: &RPCError{
+					Code:    -32600,
+					Message: "Invalid Request",
+					Data:    err.Error(),
+				},
+			})
+			continue
+		}
+		
+		responses = append(responses, s.dispatchRPCRequest(r.Context(), &req))
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responses)
+}
+
+// dispatchRPCRequest validates and executes a single JSON-RPC request
+func (s *Server) dispatchRPCRequest(ctx context.Context, req *RPCRequest) RPCResponse {
+	// Ensure JSON-RPC version is 2.0
+	if req.JSONRPC != "2.0" {
+		return RPCResponse{
+			JSONRPC: "2.0",
+			Error: &RPCError{
+				Code:    -32600,
+				Message: "Invalid Request",
+				Data:    "JSONRPC version must be 2.0",
+			},
+			ID: req.ID,
+		}
+	}
+	
+	result, err := s.executeRPCMethod(ctx, req.Method, req.Params)
 	if err != nil {
-		var rpcError *RPCError
-		if errors.As(err, &rpcError) {
-			writeRPCError(w, rpcError, req.ID)
-		} else {
-			writeRPCError(w, &RPCError{
-				Code:    -32603,
-				Message: "Internal error",
-				Data:    err.Error(),
-			}, req.ID)
+		return RPCResponse{
+			JSONRPC: "2.0",
+			Error:   toRPCError(err),
+			ID:      req.ID,
 		}
-		return
 	}
 	
-	writeRPCResult(w, result, req.ID)
+	return RPCResponse{
+		JSONRPC: "2.0",
+		Result:  result,
+		ID:      req.ID,
+	}
+}
+
+// toRPCError maps an error returned by an RPC method to a JSON-RPC error
+func toRPCError(err error) *RPCError {
+	var rpcError *RPCError
+	if errors.As(err, &rpcError) {
+		return rpcError
+	}
+	
+	if errors.Is(err, hashperp.ErrUnauthorized) {
+		return &RPCError{
+			Code:    -32001,
+			Message: "Unauthorized",
+			Data:    err.Error(),
+		}
+	}
+	
+	return &RPCError{
+		Code:    -32603,
+		Message: "Internal error",
+		Data:    err.Error(),
+	}
 }
 
 // handleWebSocket handles WebSocket connections
@@ -164,12 +336,17 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 	defer conn.Close()
 	
+	defer s.wsWriteMu.Delete(conn)
+	
 	// Create a context that will be canceled when the connection closes
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
 	
-	// Handle incoming messages
-	go s.handleWebSocketMessages(ctx, conn)
+	// Handle incoming messages, canceling the context once the client disconnects
+	go func() {
+		s.handleWebSocketMessages(ctx, conn)
+		cancel()
+	}()
 	
 	// Keep the connection alive with pings
 	go s.keepWebSocketAlive(ctx, conn)
@@ -180,18 +357,40 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 // handleWebSocketMessages handles incoming WebSocket messages
 func (s *Server) handleWebSocketMessages(ctx context.Context, conn *websocket.Conn) {
+	// Subscriptions live as long as the connection
+	subs := newWSSubscriptions()
+	defer subs.closeAll()
+	
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			var msg WebSocketMessage
-			err := conn.ReadJSON(&msg)
+			_, data, err := conn.ReadMessage()
 			if err != nil {
 				log.Printf("Error reading WebSocket message: %v", err)
 				return
 			}
 			
+			var msg WebSocketMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				s.sendWebSocketError(conn, "error", &RPCError{
+					Code:    -32700,
+					Message: "Parse error",
+					Data:    err.Error(),
+				}, nil)
+				continue
+			}
+			
+			if msg.Type == "" {
+				// Bare subscription commands, e.g. {"subscribe":"contract","id":"..."}
+				var cmd WebSocketCommand
+				if err := json.Unmarshal(data, &cmd); err == nil && (cmd.Subscribe != "" || cmd.Unsubscribe != "") {
+					s.handleWebSocketCommand(ctx, conn, subs, cmd)
+					continue
+				}
+			}
+			
 			if msg.Type == "rpc" {
 				var rpcReq RPCRequest
 				if err := json.Unmarshal(msg.Payload, &rpcReq); err != nil {
@@ -205,16 +404,7 @@ func (s *Server) handleWebSocketMessages(ctx context.Context, conn *websocket.Co
 				
 				result, err := s.executeRPCMethod(ctx, rpcReq.Method, rpcReq.Params)
 				if err != nil {
-					var rpcError *RPCError
-					if errors.As(err, &rpcError) {
-						s.sendWebSocketError(conn, "rpc_error", rpcError, rpcReq.ID)
-					} else {
-						s.sendWebSocketError(conn, "rpc_error", &RPCError{
-							Code:    -32603,
-							Message: "Internal error",
-							Data:    err.Error(),
-						}, rpcReq.ID)
-					}
+					s.sendWebSocketError(conn, "rpc_error", toRPCError(err), rpcReq.ID)
 					continue
 				}
 				
@@ -319,7 +509,7 @@ func (s *Server) subscribeToContracts(ctx context.Context, conn *websocket.Conn,
 			return
 		case <-ticker.C:
 			// Fetch current contracts for the user
-			contracts, err := s.service.GetContractsByUser(ctx, contractParams.UserID, nil)
+			contracts, _, err := s.service.GetContractsByUser(ctx, contractParams.UserID, nil, false, hashperp.PageRequest{})
 			if err != nil {
 				log.Printf("Error fetching contracts: %v", err)
 				continue
@@ -360,11 +550,20 @@ func (s *Server) sendWebSocketMessage(conn *websocket.Conn, msgType string, payl
 	}
 	msg.Payload = payloadBytes
 	
-	if err := conn.WriteJSON(msg); err != nil {
+	if err := s.writeWebSocketJSON(conn, msg); err != nil {
 		log.Printf("Error writing WebSocket message: %v", err)
 	}
 }
 
+// writeWebSocketJSON writes a JSON message, serializing concurrent writers on the same connection
+func (s *Server) writeWebSocketJSON(conn *websocket.Conn, v interface{}) error {
+	mu, _ := s.wsWriteMu.LoadOrStore(conn, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	defer mu.(*sync.Mutex).Unlock()
+	
+	return conn.WriteJSON(v)
+}
+
 // sendWebSocketResult sends a WebSocket RPC result
 func (s *Server) sendWebSocketResult(conn *websocket.Conn, msgType string, result interface{}, id interface{}) {
 	rpcResponse := RPCResponse{
@@ -387,6 +586,13 @@ func (s *Server) sendWebSocketError(conn *websocket.Conn, msgType string, err *R
 	s.sendWebSocketMessage(conn, msgType, rpcResponse)
 }
 
+// writeRPCResponse writes a prepared JSON-RPC response
+func writeRPCResponse(w http.ResponseWriter, response RPCResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
 // writeRPCResult writes a JSON-RPC result response
 func writeRPCResult(w http.ResponseWriter, result interface{}, id interface{}) {
 	response := RPCResponse{
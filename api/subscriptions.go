@@ -0,0 +1,300 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashperp/hashperp"
+)
+
+// WebSocket subscription topics
+const (
+	topicContract  = "contract"
+	topicHashRate  = "hashrate"
+	topicOrderBook = "orderbook"
+)
+
+// WebSocketCommand is a subscription command sent by a WebSocket client, e.g.
+// {"subscribe":"contract","id":"<contractID>"}, {"unsubscribe":"contract","id":"<contractID>"}
+// {"subscribe":"hashrate"} or {"subscribe":"orderbook","contract_type":"CALL","expiry_block_height":850000}
+type WebSocketCommand struct {
+	Subscribe         string `json:"subscribe,omitempty"`
+	Unsubscribe       string `json:"unsubscribe,omitempty"`
+	ID                string `json:"id,omitempty"`
+	ContractType      string `json:"contract_type,omitempty"`       // Market of an orderbook subscription
+	ExpiryBlockHeight uint64 `json:"expiry_block_height,omitempty"` // Market of an orderbook subscription
+}
+
+// wsSubscriptions tracks the subscriptions held by a single WebSocket connection,
+// keyed by contract ID for contract subscriptions, by market for order book
+// subscriptions and by topic otherwise
+type wsSubscriptions struct {
+	mu      sync.Mutex
+	cancels map[string]func()
+}
+
+// newWSSubscriptions creates an empty subscription set
+func newWSSubscriptions() *wsSubscriptions {
+	return &wsSubscriptions{
+		cancels: make(map[string]func()),
+	}
+}
+
+// add registers a subscription, returning false if the key is already subscribed
+func (c *wsSubscriptions) add(key string, cancel func()) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.cancels[key]; exists {
+		return false
+	}
+	c.cancels[key] = cancel
+	return true
+}
+
+// remove cancels a subscription, returning false if the key was not subscribed
+func (c *wsSubscriptions) remove(key string) bool {
+	c.mu.Lock()
+	cancel, exists := c.cancels[key]
+	delete(c.cancels, key)
+	c.mu.Unlock()
+
+	if !exists {
+		return false
+	}
+	cancel()
+	return true
+}
+
+// closeAll cancels every subscription, used when the connection goes away
+func (c *wsSubscriptions) closeAll() {
+	c.mu.Lock()
+	cancels := c.cancels
+	c.cancels = make(map[string]func())
+	c.mu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
+
+// handleWebSocketCommand processes a subscribe or unsubscribe command
+func (s *Server) handleWebSocketCommand(
+	ctx context.Context,
+	conn *websocket.Conn,
+	subs *wsSubscriptions,
+	cmd WebSocketCommand,
+) {
+	topic := cmd.Subscribe
+	if topic == "" {
+		topic = cmd.Unsubscribe
+	}
+
+	// Contract subscriptions are keyed by contract ID, other topics by name
+	key := topic
+	switch topic {
+	case topicContract:
+		if err := hashperp.ValidateUUID(cmd.ID); err != nil {
+			s.sendWebSocketError(conn, "subscription_error", &RPCError{
+				Code:    -32602,
+				Message: "Invalid params",
+				Data:    err.Error(),
+			}, nil)
+			return
+		}
+		key = cmd.ID
+	case topicHashRate:
+		// A single shared stream, no ID needed
+	case topicOrderBook:
+		if err := hashperp.ValidateContractType(hashperp.ContractType(cmd.ContractType)); err != nil {
+			s.sendWebSocketError(conn, "subscription_error", &RPCError{
+				Code:    -32602,
+				Message: "Invalid params",
+				Data:    err.Error(),
+			}, nil)
+			return
+		}
+		key = fmt.Sprintf("%s:%s-%d", topicOrderBook, cmd.ContractType, cmd.ExpiryBlockHeight)
+	default:
+		s.sendWebSocketError(conn, "subscription_error", &RPCError{
+			Code:    -32601,
+			Message: "Unknown subscription topic",
+			Data:    topic,
+		}, nil)
+		return
+	}
+
+	if cmd.Unsubscribe != "" {
+		if !subs.remove(key) {
+			s.sendWebSocketError(conn, "subscription_error", &RPCError{
+				Code:    -32602,
+				Message: "Not subscribed",
+				Data:    key,
+			}, nil)
+			return
+		}
+		s.sendWebSocketMessage(conn, "unsubscribed", map[string]string{
+			"topic": topic,
+			"id":    cmd.ID,
+		})
+		return
+	}
+
+	if topic == topicHashRate {
+		s.subscribeToHashRateTopic(ctx, conn, subs)
+		return
+	}
+	if topic == topicOrderBook {
+		s.subscribeToOrderBook(ctx, conn, subs, key, hashperp.ContractType(cmd.ContractType), cmd.ExpiryBlockHeight)
+		return
+	}
+	s.subscribeToContract(ctx, conn, subs, cmd.ID)
+}
+
+// subscribeToContract starts pushing status changes for a single contract to the connection
+func (s *Server) subscribeToContract(
+	ctx context.Context,
+	conn *websocket.Conn,
+	subs *wsSubscriptions,
+	contractID string,
+) {
+	// 1. Make sure the contract exists before subscribing
+	contract, err := s.service.GetContract(ctx, contractID)
+	if err != nil {
+		s.sendWebSocketError(conn, "subscription_error", &RPCError{
+			Code:    -32603,
+			Message: "Internal error",
+			Data:    err.Error(),
+		}, nil)
+		return
+	}
+
+	// 2. Only the contract's parties may follow it
+	if err := hashperp.AuthorizeUser(ctx, contract.BuyerID, contract.SellerID); err != nil {
+		s.sendWebSocketError(conn, "subscription_error", toRPCError(err), nil)
+		return
+	}
+
+	// 3. Register with the event bus
+	events, cancel := s.service.SubscribeToContract(contractID)
+	if !subs.add(contractID, cancel) {
+		cancel()
+		s.sendWebSocketError(conn, "subscription_error", &RPCError{
+			Code:    -32602,
+			Message: "Already subscribed",
+			Data:    contractID,
+		}, nil)
+		return
+	}
+
+	// 4. Confirm with the current status so the client has a starting point
+	s.sendWebSocketMessage(conn, "subscribed", map[string]string{
+		"topic":  topicContract,
+		"id":     contractID,
+		"status": string(contract.Status),
+	})
+
+	// 5. Forward events until unsubscribed or disconnected
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				s.sendWebSocketMessage(conn, "contract_status", event)
+			}
+		}
+	}()
+}
+
+// subscribeToHashRateTopic starts pushing hash rate data for each new block to the connection
+func (s *Server) subscribeToHashRateTopic(ctx context.Context, conn *websocket.Conn, subs *wsSubscriptions) {
+	// 1. Register with the shared poller
+	updates, cancel := s.service.SubscribeToHashRate()
+	if !subs.add(topicHashRate, cancel) {
+		cancel()
+		s.sendWebSocketError(conn, "subscription_error", &RPCError{
+			Code:    -32602,
+			Message: "Already subscribed",
+			Data:    topicHashRate,
+		}, nil)
+		return
+	}
+
+	// 2. Confirm the subscription
+	s.sendWebSocketMessage(conn, "subscribed", map[string]string{
+		"topic": topicHashRate,
+	})
+
+	// 3. Forward updates until unsubscribed or disconnected
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-updates:
+				if !ok {
+					return
+				}
+				s.sendWebSocketMessage(conn, "hashrate", data)
+			}
+		}
+	}()
+}
+
+// subscribeToOrderBook starts pushing sequenced order book changes for a market to the
+// connection, preceded by a snapshot to apply them to
+func (s *Server) subscribeToOrderBook(
+	ctx context.Context,
+	conn *websocket.Conn,
+	subs *wsSubscriptions,
+	key string,
+	contractType hashperp.ContractType,
+	expiryBlockHeight uint64,
+) {
+	// 1. Register before taking the snapshot so no change falls between the two
+	updates, cancel := s.service.SubscribeToOrderBook(contractType, expiryBlockHeight)
+	if !subs.add(key, cancel) {
+		cancel()
+		s.sendWebSocketError(conn, "subscription_error", &RPCError{
+			Code:    -32602,
+			Message: "Already subscribed",
+			Data:    key,
+		}, nil)
+		return
+	}
+
+	// 2. Send the snapshot, the client applies the updates above its sequence
+	snapshot, err := s.service.GetOrderBook(ctx, contractType, expiryBlockHeight)
+	if err != nil {
+		subs.remove(key)
+		s.sendWebSocketError(conn, "subscription_error", &RPCError{
+			Code:    -32603,
+			Message: "Internal error",
+			Data:    err.Error(),
+		}, nil)
+		return
+	}
+	s.sendWebSocketMessage(conn, "orderbook_snapshot", snapshot)
+
+	// 3. Forward updates until unsubscribed or disconnected. A client that sees a gap in the
+	// sequence, e.g. after updates were dropped for a slow connection, re-subscribes.
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+				s.sendWebSocketMessage(conn, "orderbook_update", update)
+			}
+		}
+	}()
+}
@@ -17,6 +17,8 @@ import (
 	"math/big"
 	
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
 )
 )
 
@@ -25,10 +27,54 @@ import (
 
 // BitcoinClientImpl implements the BitcoinClient interface
 type BitcoinClientImpl struct {
-	rpcURL      string
-	rpcUser     string
-	rpcPassword string
-	httpClient  *http.Client
+	rpcURL         string
+	rpcUser        string
+	rpcPassword    string
+	httpClient     *http.Client
+	defaultTimeout time.Duration            // Timeout of RPC methods without their own
+	methodTimeouts map[string]time.Duration // Per-method timeouts, by RPC method name
+	retry          RetryPolicy              // Retries of transient call failures
+	network        hashperp.Network         // Network the node must be on, selects address encodings
+}
+
+// RetryPolicy controls how transient RPC failures are retried. The backoff doubles after each
+// attempt up to MaxBackoff. MaxRetries of 0 disables retries.
+type RetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	}
+}
+
+// Validate checks that the policy's bounds are usable
+func (p RetryPolicy) Validate() error {
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("max retries must not be negative, got %d", p.MaxRetries)
+	}
+	if p.MaxRetries > 0 && (p.InitialBackoff <= 0 || p.MaxBackoff < p.InitialBackoff) {
+		return fmt.Errorf("backoff bounds [%s, %s] are invalid", p.InitialBackoff, p.MaxBackoff)
+	}
+	return nil
+}
+
+// defaultRPCTimeout bounds an RPC call whose method has no timeout of its own
+const defaultRPCTimeout = 30 * time.Second
+
+// defaultMethodTimeouts gives cheap lookups a short timeout so a stalled node is noticed
+// quickly, and slow scans a long one so they are not cut off
+var defaultMethodTimeouts = map[string]time.Duration{
+	"getblockcount":  5 * time.Second,
+	"getblockhash":   10 * time.Second,
+	"getblockheader": 10 * time.Second,
+	"verifychain":    10 * time.Minute,
 }
 
 // RPCRequest represents a Bitcoin JSON-RPC request
@@ -53,22 +99,127 @@ type RPCError struct {
 	Message string `json:"message"`
 }
 
-// NewBitcoinClient creates a new Bitcoin client
-func NewBitcoinClient(rpcURL, rpcUser, rpcPassword string) (hashperp.BitcoinClient, error) {
+// Error implements the error interface
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC error [%d]: %s", e.Code, e.Message)
+}
+
+// rpcInvalidAddressOrKey is the Bitcoin Core error code for an unknown transaction or address
+const rpcInvalidAddressOrKey = -5
+
+// NewBitcoinClient creates a new Bitcoin client for a node on the given network
+func NewBitcoinClient(rpcURL, rpcUser, rpcPassword string, network hashperp.Network) (hashperp.BitcoinClient, error) {
 	if rpcURL == "" {
 		return nil, errors.New("Bitcoin RPC URL is required")
 	}
+	if _, err := hashperp.ParseNetwork(string(network)); err != nil {
+		return nil, err
+	}
+
+	methodTimeouts := make(map[string]time.Duration, len(defaultMethodTimeouts))
+	for method, timeout := range defaultMethodTimeouts {
+		methodTimeouts[method] = timeout
+	}
 
 	return &BitcoinClientImpl{
 		rpcURL:      rpcURL,
 		rpcUser:     rpcUser,
 		rpcPassword: rpcPassword,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		// Calls are bounded by their context, see timeoutFor
+		httpClient:     &http.Client{},
+		defaultTimeout: defaultRPCTimeout,
+		methodTimeouts: methodTimeouts,
+		retry:          DefaultRetryPolicy(),
+		network:        network,
 	}, nil
 }
 
+// chainParams returns the consensus and address parameters of the configured network
+func (c *BitcoinClientImpl) chainParams() *chaincfg.Params {
+	switch c.network {
+	case hashperp.NETWORK_TESTNET:
+		return &chaincfg.TestNet3Params
+	case hashperp.NETWORK_REGTEST:
+		return &chaincfg.RegressionNetParams
+	case hashperp.NETWORK_SIGNET:
+		return &chaincfg.SigNetParams
+	}
+	return &chaincfg.MainNetParams
+}
+
+// VerifyNetwork checks that the node is on the configured network, so a testnet deployment
+// can never settle against mainnet or the other way round. Call it once at startup.
+func (c *BitcoinClientImpl) VerifyNetwork(ctx context.Context) error {
+	var chainInfo struct {
+		Chain string `json:"chain"`
+	}
+	if err := c.call(ctx, "getblockchaininfo", []interface{}{}, &chainInfo); err != nil {
+		return fmt.Errorf("failed to get blockchain info: %w", err)
+	}
+
+	if chainInfo.Chain != c.network.ChainName() {
+		return fmt.Errorf("%w: configured for %s but the node reports chain %q",
+			hashperp.ErrNetworkMismatch, c.network, chainInfo.Chain)
+	}
+	return nil
+}
+
+// pubKeyAddress derives the P2PKH address of a public key on the configured network, the
+// address type verifymessage checks signatures against
+func (c *BitcoinClientImpl) pubKeyAddress(pubKey []byte) (string, error) {
+	address, err := btcutil.NewAddressPubKey(pubKey, c.chainParams())
+	if err != nil {
+		return "", err
+	}
+	return address.AddressPubKeyHash().EncodeAddress(), nil
+}
+
+// SetRetryPolicy configures how transient call failures are retried
+func (c *BitcoinClientImpl) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// SetCallTimeouts configures the timeout of RPC methods without their own and overrides the
+// timeouts of individual methods. A zero default keeps the current one.
+func (c *BitcoinClientImpl) SetCallTimeouts(defaultTimeout time.Duration, methodTimeouts map[string]time.Duration) {
+	if defaultTimeout > 0 {
+		c.defaultTimeout = defaultTimeout
+	}
+	for method, timeout := range methodTimeouts {
+		c.methodTimeouts[method] = timeout
+	}
+}
+
+// timeoutFor returns how long a call to an RPC method may take
+func (c *BitcoinClientImpl) timeoutFor(method string) time.Duration {
+	if timeout, ok := c.methodTimeouts[method]; ok && timeout > 0 {
+		return timeout
+	}
+	return c.defaultTimeout
+}
+
+// ParseMethodTimeouts parses per-method timeouts given as "method=duration" pairs separated
+// by commas, such as "verifychain=15m,getblockcount=3s"
+func ParseMethodTimeouts(spec string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		method, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(method) == "" {
+			return nil, fmt.Errorf("invalid method timeout %q, expected method=duration", pair)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout for method %s: %q", method, value)
+		}
+		timeouts[strings.TrimSpace(method)] = timeout
+	}
+	return timeouts, nil
+}
+
 // GetCurrentBlockHeight implements BitcoinClient.GetCurrentBlockHeight
 func (c *BitcoinClientImpl) GetCurrentBlockHeight(ctx context.Context) (uint64, error) {
 	// Call Bitcoin RPC getblockcount
@@ -82,7 +233,7 @@ func (c *BitcoinClientImpl) GetCurrentBlockHeight(ctx context.Context) (uint64,
 }
 
 // GetBlockHashRate implements BitcoinClient.GetBlockHashRate
-func (c *BitcoinClientImpl) GetBlockHashRate(ctx context.Context, blockHeight uint64) (float64, error) {
+func (c *BitcoinClientImpl) GetBlockHashRate(ctx context.Context, blockHeight uint64, minConfirmations uint64) (float64, error) {
 	// In a real implementation, this would fetch the hash rate at the specific block height
 	// For this example, we'll use the network hash rate
 	
@@ -95,14 +246,20 @@ func (c *BitcoinClientImpl) GetBlockHashRate(ctx context.Context, blockHeight ui
 	
 	// Get block details
 	var blockDetails struct {
-		Difficulty float64 `json:"difficulty"`
-		Time       int64   `json:"time"`
+		Difficulty    float64 `json:"difficulty"`
+		Time          int64   `json:"time"`
+		Confirmations int64   `json:"confirmations"`
 	}
 	err = c.call(ctx, "getblock", []interface{}{blockHash}, &blockDetails)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get block details: %w", err)
 	}
 	
+	// Refuse to report a hash rate from a block that could still be reorged out
+	if err := checkConfirmations(blockHeight, blockDetails.Confirmations, minConfirmations); err != nil {
+		return 0, err
+	}
+	
 	// Calculate estimated hash rate from difficulty
 	// Bitcoin network targeting 600 seconds (10 minutes) per block
 	// Hash rate (in hashes per second) = difficulty * 2^32 / 600
@@ -114,16 +271,82 @@ func (c *BitcoinClientImpl) GetBlockHashRate(ctx context.Context, blockHeight ui
 	return petaHashRate, nil
 }
 
+// GetNetworkHashRate implements BitcoinClient.GetNetworkHashRate
+func (c *BitcoinClientImpl) GetNetworkHashRate(ctx context.Context, windowBlocks uint64, endBlockHeight uint64, minConfirmations uint64) (float64, error) {
+	// Refuse to average over blocks that could still be reorged out
+	if _, err := c.EnsureBlockConfirmed(ctx, endBlockHeight, minConfirmations); err != nil {
+		return 0, err
+	}
+
+	// The node derives the hash rate from the work and timestamps of the whole window in one call
+	var hashRate float64
+	err := c.call(ctx, "getnetworkhashps", []interface{}{windowBlocks, endBlockHeight}, &hashRate)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get network hash rate: %w", err)
+	}
+
+	// Convert hashes per second to petahashes per second (PH/s)
+	return hashRate / 1e15, nil
+}
+
+// EnsureBlockConfirmed implements BitcoinClient.EnsureBlockConfirmed
+func (c *BitcoinClientImpl) EnsureBlockConfirmed(ctx context.Context, blockHeight uint64, minConfirmations uint64) (string, error) {
+	// Get block hash for the height on the current best chain
+	var blockHash string
+	err := c.call(ctx, "getblockhash", []interface{}{blockHeight}, &blockHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get block hash: %w", err)
+	}
+	
+	// Get the header, which reports confirmations relative to the chain tip
+	var header struct {
+		Confirmations int64 `json:"confirmations"`
+	}
+	err = c.call(ctx, "getblockheader", []interface{}{blockHash, true}, &header)
+	if err != nil {
+		return "", fmt.Errorf("failed to get block header: %w", err)
+	}
+	
+	if err := checkConfirmations(blockHeight, header.Confirmations, minConfirmations); err != nil {
+		return "", err
+	}
+	
+	return blockHash, nil
+}
+
+// checkConfirmations fails with ErrBlockNotFinal when a block has fewer than minConfirmations
+// confirmations. Bitcoin Core reports -1 confirmations for blocks off the best chain.
+func checkConfirmations(blockHeight uint64, confirmations int64, minConfirmations uint64) error {
+	if confirmations < 0 || uint64(confirmations) < minConfirmations {
+		return fmt.Errorf("%w: block %d has %d of %d required confirmations",
+			hashperp.ErrBlockNotFinal, blockHeight, confirmations, minConfirmations)
+	}
+	return nil
+}
+
 // BroadcastTransaction implements BitcoinClient.BroadcastTransaction
+// A transaction the node already has, because an earlier attempt reached it, counts as
+// broadcast so retried broadcasts are idempotent.
 func (c *BitcoinClientImpl) BroadcastTransaction(ctx context.Context, txHex string) (string, error) {
 	// Call Bitcoin RPC sendrawtransaction
 	var txid string
 	err := c.call(ctx, "sendrawtransaction", []interface{}{txHex}, &txid)
-	if err != nil {
+	if err == nil {
+		return txid, nil
+	}
+	if !isAlreadyBroadcast(err) {
 		return "", fmt.Errorf("failed to broadcast transaction: %w", err)
 	}
 
-	return txid, nil
+	// The node does not return the txid of a rejected transaction, decode it instead
+	var decoded struct {
+		TxID string `json:"txid"`
+	}
+	if decodeErr := c.call(ctx, "decoderawtransaction", []interface{}{txHex}, &decoded); decodeErr != nil {
+		return "", fmt.Errorf("transaction was already broadcast but its txid could not be decoded: %w", decodeErr)
+	}
+
+	return decoded.TxID, nil
 }
 
 // ValidateSignature implements BitcoinClient.ValidateSignature
@@ -134,11 +357,14 @@ func (c *BitcoinClientImpl) ValidateSignature(ctx context.Context, message []byt
 	// Convert the binary data to hex strings for Bitcoin RPC
 	messageHex := fmt.Sprintf("%x", message)
 	signatureHex := fmt.Sprintf("%x", signature)
-	pubKeyHex := fmt.Sprintf("%x", pubKey)
+	address, err := c.pubKeyAddress(pubKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert public key to address: %w", err)
+	}
 	
 	// Call Bitcoin RPC verifymessage
 	var result bool
-	err := c.call(ctx, "verifymessage", []interface{}{pubKeyHex, signatureHex, messageHex}, &result)
+	err = c.call(ctx, "verifymessage", []interface{}{address, signatureHex, messageHex}, &result)
 	if err != nil {
 		return false, fmt.Errorf("failed to verify signature: %w", err)
 	}
@@ -177,8 +403,36 @@ func (c *BitcoinClientImpl) EstimateNetworkDifficulty(ctx context.Context) (floa
 	return difficulty, nil
 }
 
-// call makes a Bitcoin JSON-RPC call
+// call makes a Bitcoin JSON-RPC call, retrying transient failures with exponential backoff
+// according to the client's retry policy. Deterministic RPC errors are returned at once.
 func (c *BitcoinClientImpl) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	backoff := c.retry.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.callOnce(ctx, method, params, result)
+		if err == nil || attempt >= c.retry.MaxRetries || !isTransient(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s call aborted: %w", method, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > c.retry.MaxBackoff {
+			backoff = c.retry.MaxBackoff
+		}
+	}
+}
+
+// callOnce makes a single Bitcoin JSON-RPC call. The call is aborted when ctx is cancelled or
+// when the method's timeout elapses, in which case it fails with ErrNodeTimeout.
+func (c *BitcoinClientImpl) callOnce(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	timeout := c.timeoutFor(method)
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// Create RPC request
 	rpcReq := RPCRequest{
 		JSONRPC: "1.0",
@@ -194,7 +448,7 @@ func (c *BitcoinClientImpl) call(ctx context.Context, method string, params []in
 	}
 	
 	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.rpcURL, strings.NewReader(string(reqBody)))
+	req, err := http.NewRequestWithContext(callCtx, http.MethodPost, c.rpcURL, strings.NewReader(string(reqBody)))
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %w", err)
 	}
@@ -203,27 +457,34 @@ func (c *BitcoinClientImpl) call(ctx context.Context, method string, params []in
 	req.Header.Set("Content-Type", "application/json")
 	req.SetBasicAuth(c.rpcUser, c.rpcPassword)
 	
-	// Send request
+	// Send request, failing to reach the node at all is transient
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send HTTP request: %w", err)
+		return callError(ctx, callCtx, method, timeout, &transientError{fmt.Errorf("failed to send HTTP request: %w", err)})
 	}
 	defer resp.Body.Close()
 	
-	// Check response status code
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected HTTP status code: %d", resp.StatusCode)
-	}
-	
-	// Decode response
+	// Decode response. Bitcoin Core reports RPC errors with a non-200 status and an error body.
 	var rpcResp RPCResponse
-	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
-		return fmt.Errorf("failed to decode RPC response: %w", err)
-	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&rpcResp)
 	
 	// Check for RPC error
 	if rpcResp.Error != nil {
-		return fmt.Errorf("RPC error [%d]: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+		return rpcResp.Error
+	}
+	
+	// Check response status code, server errors without an RPC error come from an overloaded
+	// node or a proxy in front of it
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("unexpected HTTP status code: %d", resp.StatusCode)
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return &transientError{err}
+		}
+		return err
+	}
+	
+	if decodeErr != nil {
+		return callError(ctx, callCtx, method, timeout, fmt.Errorf("failed to decode RPC response: %w", decodeErr))
 	}
 	
 	// Unmarshal result
@@ -233,6 +494,78 @@ func (c *BitcoinClientImpl) call(ctx context.Context, method string, params []in
 	
 	return nil
 }
+
+// callError tells apart a call that hit its method timeout, which callers may retry, from one
+// aborted by its caller's context
+func callError(ctx, callCtx context.Context, method string, timeout time.Duration, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s call aborted: %w", method, ctx.Err())
+	}
+	if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %s did not answer within %s", hashperp.ErrNodeTimeout, method, timeout)
+	}
+	return err
+}
+
+// transientError marks a call failure that may succeed if the call is made again
+type transientError struct {
+	err error
+}
+
+// Error implements the error interface
+func (e *transientError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying failure
+func (e *transientError) Unwrap() error {
+	return e.err
+}
+
+// Bitcoin Core error codes classified by the client
+const (
+	rpcInWarmup             = -28 // The node is still starting
+	rpcVerifyRejected       = -26 // The transaction was rejected by mempool policy
+	rpcVerifyAlreadyInChain = -27 // The transaction is already confirmed
+)
+
+// alreadyKnownRejections are the reject reasons of a transaction the mempool already holds
+var alreadyKnownRejections = []string{"txn-already-known", "txn-already-in-mempool"}
+
+// isAlreadyBroadcast reports whether sendrawtransaction failed only because the node already
+// has the transaction, in the chain or in its mempool
+func isAlreadyBroadcast(err error) bool {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+
+	switch rpcErr.Code {
+	case rpcVerifyAlreadyInChain:
+		return true
+	case rpcVerifyRejected:
+		for _, reason := range alreadyKnownRejections {
+			if strings.Contains(rpcErr.Message, reason) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isTransient reports whether a failed call may succeed if it is made again. RPC errors are
+// deterministic, the same call would fail the same way, except while the node warms up.
+func isTransient(err error) bool {
+	if errors.Is(err, hashperp.ErrNodeTimeout) {
+		return true
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == rpcInWarmup
+	}
+	var transient *transientError
+	return errors.As(err, &transient)
+}
 import (
 	"context"
 	"crypto/hmac"
@@ -253,7 +586,8 @@ func (c *BitcoinClientImpl) CreateAndBroadcastTx(
 	destinationAddress string,
 ) (string, error) {
 	// 1. Convert the amount from BTC to satoshis
-	amountSatoshis := uint64(amount * 100000000) // 1 BTC = 100,000,000 satoshis
+	// Rounded, truncating would turn 0.3 BTC into 29999999 satoshis
+	amountSatoshis := uint64(hashperp.BTCToSats(amount))
 	
 	// 2. Create a raw transaction with the script
 	// Find appropriate UTXOs to use as inputs
@@ -437,13 +771,11 @@ func (c *BitcoinClientImpl) ValidateSignature(
 	// Convert the binary data to hex strings for Bitcoin RPC
 	messageHex := hex.EncodeToString(message)
 	signatureHex := hex.EncodeToString(signature)
-	pubKeyHex := hex.EncodeToString(pubKey)
 	
 	// Call Bitcoin RPC verifymessage
 	// verifymessage expects address, signature, and message
-	// First, convert the public key to an address
-	var address string
-	err := c.call(ctx, "getaddressfrompubkey", []interface{}{pubKeyHex}, &address)
+	// First, convert the public key to an address on the configured network
+	address, err := c.pubKeyAddress(pubKey)
 	if err != nil {
 		return false, fmt.Errorf("failed to convert public key to address: %w", err)
 	}
@@ -466,6 +798,10 @@ func (c *BitcoinClientImpl) GetTransactionConfirmations(
 	var txData map[string]interface{}
 	err := c.call(ctx, "gettransaction", []interface{}{txHash}, &txData)
 	if err != nil {
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) && rpcErr.Code == rpcInvalidAddressOrKey {
+			return 0, fmt.Errorf("%w: %s", hashperp.ErrTransactionUnknown, txHash)
+		}
 		return 0, fmt.Errorf("failed to get transaction data: %w", err)
 	}
 	
@@ -474,6 +810,11 @@ func (c *BitcoinClientImpl) GetTransactionConfirmations(
 		return 0, fmt.Errorf("invalid transaction data format")
 	}
 	
+	// Bitcoin Core reports negative confirmations for transactions that conflict with the chain
+	if confirmations < 0 {
+		return 0, fmt.Errorf("%w: %s", hashperp.ErrTransactionConflicted, txHash)
+	}
+	
 	return uint64(confirmations), nil
 }
 
@@ -628,18 +969,26 @@ func (c *BitcoinClientImpl) CreateRawTransaction(
 	return rawTx, nil
 }
 
-// VerifyChainTip ensures the blockchain is in a valid state and returns the current chain height
-func (c *BitcoinClientImpl) VerifyChainTip(ctx context.Context) (uint64, error) {
-	// First, check if the blockchain is in a valid state
+// IsInitialBlockDownload implements BitcoinClient.IsInitialBlockDownload
+func (c *BitcoinClientImpl) IsInitialBlockDownload(ctx context.Context) (bool, error) {
 	var chainInfo map[string]interface{}
 	err := c.call(ctx, "getblockchaininfo", []interface{}{}, &chainInfo)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get blockchain info: %w", err)
+		return false, fmt.Errorf("failed to get blockchain info: %w", err)
 	}
 	
-	// Check if the chain is in an initial block download state
 	ibd, ok := chainInfo["initialblockdownload"].(bool)
-	if ok && ibd {
+	return ok && ibd, nil
+}
+
+// VerifyChainTip ensures the blockchain is in a valid state and returns the current chain height
+func (c *BitcoinClientImpl) VerifyChainTip(ctx context.Context) (uint64, error) {
+	// First, check if the chain is in an initial block download state
+	ibd, err := c.IsInitialBlockDownload(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if ibd {
 		return 0, fmt.Errorf("blockchain is still in initial block download mode")
 	}
 	
@@ -690,22 +1039,10 @@ func (c *BitcoinClientImpl) ValidateSignature(
 	signatureHex := hex.EncodeToString(signature)
 	pubKeyHex := hex.EncodeToString(pubKey)
 	
-	// 2. First convert the public key to an address
-	var address string
-	err := c.call(ctx, "getaddressfromkey", []interface{}{pubKeyHex}, &address)
+	// 2. First convert the public key to an address on the configured network
+	address, err := c.pubKeyAddress(pubKey)
 	if err != nil {
-		// Fallback to creating address with descriptor if getaddressfromkey is not supported
-		var addressInfo map[string]interface{}
-		descriptorParam := fmt.Sprintf("addr(%s)", pubKeyHex)
-		err = c.call(ctx, "deriveaddresses", []interface{}{descriptorParam}, &addressInfo)
-		if err != nil {
-			return verifySignatureWithCrypto(message, signature, pubKey)
-		}
-		addresses, ok := addressInfo["addresses"].([]interface{})
-		if !ok || len(addresses) == 0 {
-			return verifySignatureWithCrypto(message, signature, pubKey)
-		}
-		address = addresses[0].(string)
+		return verifySignatureWithCrypto(message, signature, pubKey)
 	}
 	
 	// 3. Add message prefix according to Bitcoin signed message format
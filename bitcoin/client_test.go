@@ -0,0 +1,99 @@
+package bitcoin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashperp/hashperp"
+)
+
+func TestIsAlreadyBroadcast(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"already in the chain", &RPCError{Code: rpcVerifyAlreadyInChain, Message: "Transaction already in block chain"}, true},
+		{"outputs already in the UTXO set", &RPCError{Code: rpcVerifyAlreadyInChain, Message: "Transaction outputs already in utxo set"}, true},
+		{"already known to the mempool", &RPCError{Code: rpcVerifyRejected, Message: "txn-already-known"}, true},
+		{"already in the mempool", &RPCError{Code: rpcVerifyRejected, Message: "txn-already-in-mempool"}, true},
+		{"wrapped", fmt.Errorf("broadcast: %w", &RPCError{Code: rpcVerifyRejected, Message: "txn-already-known"}), true},
+		{"rejected for another reason", &RPCError{Code: rpcVerifyRejected, Message: "min relay fee not met"}, false},
+		{"conflicting spend", &RPCError{Code: rpcVerifyRejected, Message: "txn-mempool-conflict"}, false},
+		{"missing inputs", &RPCError{Code: -25, Message: "bad-txns-inputs-missingorspent"}, false},
+		{"reason under another code", &RPCError{Code: -25, Message: "txn-already-known"}, false},
+		{"not an RPC error", errors.New("txn-already-known"), false},
+		{"no error", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAlreadyBroadcast(tt.err); got != tt.want {
+				t.Errorf("isAlreadyBroadcast(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBroadcastTransactionAlreadyBroadcast(t *testing.T) {
+	const txid = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+
+	tests := []struct {
+		name     string
+		sendErr  *RPCError
+		wantTxID string
+		wantErr  bool
+	}{
+		{"accepted", nil, txid, false},
+		{"already in the chain", &RPCError{Code: rpcVerifyAlreadyInChain, Message: "Transaction already in block chain"}, txid, false},
+		{"already in the mempool", &RPCError{Code: rpcVerifyRejected, Message: "txn-already-in-mempool"}, txid, false},
+		{"rejected", &RPCError{Code: rpcVerifyRejected, Message: "min relay fee not met"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req RPCRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+					return
+				}
+
+				resp := RPCResponse{ID: req.ID}
+				switch req.Method {
+				case "sendrawtransaction":
+					if tt.sendErr != nil {
+						resp.Error = tt.sendErr
+						w.WriteHeader(http.StatusInternalServerError)
+					} else {
+						resp.Result, _ = json.Marshal(txid)
+					}
+				case "decoderawtransaction":
+					resp.Result, _ = json.Marshal(map[string]string{"txid": txid})
+				default:
+					t.Errorf("unexpected method %s", req.Method)
+				}
+				_ = json.NewEncoder(w).Encode(resp)
+			}))
+			defer server.Close()
+
+			client, err := NewBitcoinClient(server.URL, "user", "password", hashperp.NETWORK_REGTEST)
+			if err != nil {
+				t.Fatalf("NewBitcoinClient() error = %v", err)
+			}
+
+			gotTxID, err := client.BroadcastTransaction(context.Background(), "0100")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BroadcastTransaction() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if gotTxID != tt.wantTxID {
+				t.Errorf("BroadcastTransaction() = %q, want %q", gotTxID, tt.wantTxID)
+			}
+		})
+	}
+}
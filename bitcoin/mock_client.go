@@ -0,0 +1,236 @@
+package bitcoin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/hashperp/hashperp"
+)
+
+const (
+	// mockGenesisTime is the timestamp of block 0 on the mock chain, every later block
+	// follows at exactly the target interval
+	mockGenesisTime = 1231006505
+
+	// mockBlockInterval is the target block interval in seconds
+	mockBlockInterval = 600
+
+	// mockBaseHashRate is the hash rate in PH/s the default hash rate function oscillates around
+	mockBaseHashRate = 500000.0
+)
+
+// MockBroadcast is a transaction broadcast to the mock chain
+type MockBroadcast struct {
+	TxHash      string
+	TxHex       string
+	BlockHeight uint64 // Chain tip when the transaction was broadcast, it confirms in the next block
+}
+
+// MockBitcoinClient is an in-memory BitcoinClient for local development and tests. The chain
+// only advances when the block height is set, hash rates are a deterministic function of the
+// block height and broadcast transactions are kept in a log.
+type MockBitcoinClient struct {
+	// HashRateFunc returns the hash rate in PH/s at a block height, set it before use to
+	// script specific settlement outcomes
+	HashRateFunc func(blockHeight uint64) float64
+
+	mu          sync.Mutex
+	blockHeight uint64
+	broadcasts  []MockBroadcast
+	byHash      map[string]int // Index into broadcasts
+}
+
+// NewMockBitcoinClient creates a mock client whose chain tip is at blockHeight
+func NewMockBitcoinClient(blockHeight uint64) *MockBitcoinClient {
+	return &MockBitcoinClient{
+		HashRateFunc: defaultMockHashRate,
+		blockHeight:  blockHeight,
+		byHash:       make(map[string]int),
+	}
+}
+
+// defaultMockHashRate varies the hash rate by up to 10% over each difficulty period so
+// contracts struck at the base rate settle both ways
+func defaultMockHashRate(blockHeight uint64) float64 {
+	phase := 2 * math.Pi * float64(blockHeight%2016) / 2016
+	return mockBaseHashRate * (1 + 0.1*math.Sin(phase))
+}
+
+// SetBlockHeight moves the chain tip to blockHeight
+func (c *MockBitcoinClient) SetBlockHeight(blockHeight uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blockHeight = blockHeight
+}
+
+// MineBlocks advances the chain tip by n blocks
+func (c *MockBitcoinClient) MineBlocks(n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blockHeight += n
+}
+
+// Broadcasts returns a copy of the broadcast log in broadcast order
+func (c *MockBitcoinClient) Broadcasts() []MockBroadcast {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]MockBroadcast(nil), c.broadcasts...)
+}
+
+// tip returns the current chain tip
+func (c *MockBitcoinClient) tip() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.blockHeight
+}
+
+// confirmations returns how many confirmations a block has, failing with ErrBlockNotFinal when
+// it has fewer than minConfirmations
+func (c *MockBitcoinClient) confirmations(blockHeight uint64, minConfirmations uint64) error {
+	tip := c.tip()
+	var confirmations int64 = -1
+	if blockHeight <= tip {
+		confirmations = int64(tip-blockHeight) + 1
+	}
+	return checkConfirmations(blockHeight, confirmations, minConfirmations)
+}
+
+// mockBlockHash derives a stable block hash from a height
+func mockBlockHash(height uint64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("mock-block-%d", height)))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetCurrentBlockHeight implements BitcoinClient.GetCurrentBlockHeight
+func (c *MockBitcoinClient) GetCurrentBlockHeight(ctx context.Context) (uint64, error) {
+	return c.tip(), nil
+}
+
+// GetBlockHashRate implements BitcoinClient.GetBlockHashRate
+func (c *MockBitcoinClient) GetBlockHashRate(ctx context.Context, blockHeight uint64, minConfirmations uint64) (float64, error) {
+	if err := c.confirmations(blockHeight, minConfirmations); err != nil {
+		return 0, err
+	}
+	return c.HashRateFunc(blockHeight), nil
+}
+
+// GetNetworkHashRate implements BitcoinClient.GetNetworkHashRate
+func (c *MockBitcoinClient) GetNetworkHashRate(ctx context.Context, windowBlocks uint64, endBlockHeight uint64, minConfirmations uint64) (float64, error) {
+	if err := c.confirmations(endBlockHeight, minConfirmations); err != nil {
+		return 0, err
+	}
+	if windowBlocks == 0 || windowBlocks > endBlockHeight+1 {
+		return 0, fmt.Errorf("failed to get network hash rate: window of %d blocks ending at block %d", windowBlocks, endBlockHeight)
+	}
+
+	total := 0.0
+	for height := endBlockHeight + 1 - windowBlocks; height <= endBlockHeight; height++ {
+		total += c.HashRateFunc(height)
+	}
+	return total / float64(windowBlocks), nil
+}
+
+// EnsureBlockConfirmed implements BitcoinClient.EnsureBlockConfirmed
+func (c *MockBitcoinClient) EnsureBlockConfirmed(ctx context.Context, blockHeight uint64, minConfirmations uint64) (string, error) {
+	if err := c.confirmations(blockHeight, minConfirmations); err != nil {
+		return "", err
+	}
+	return mockBlockHash(blockHeight), nil
+}
+
+// BroadcastTransaction implements BitcoinClient.BroadcastTransaction
+// Broadcasting the same transaction again returns its hash without logging it twice.
+func (c *MockBitcoinClient) BroadcastTransaction(ctx context.Context, txHex string) (string, error) {
+	if txHex == "" {
+		return "", errors.New("failed to broadcast transaction: empty transaction")
+	}
+
+	sum := sha256.Sum256([]byte(txHex))
+	txHash := hex.EncodeToString(sum[:])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.byHash[txHash]; !ok {
+		c.byHash[txHash] = len(c.broadcasts)
+		c.broadcasts = append(c.broadcasts, MockBroadcast{
+			TxHash:      txHash,
+			TxHex:       txHex,
+			BlockHeight: c.blockHeight,
+		})
+	}
+	return txHash, nil
+}
+
+// ValidateSignature implements BitcoinClient.ValidateSignature
+// The mock accepts any non-empty signature, it must never back a production deployment.
+func (c *MockBitcoinClient) ValidateSignature(ctx context.Context, message []byte, signature []byte, pubKey []byte) (bool, error) {
+	return len(signature) > 0 && len(pubKey) > 0, nil
+}
+
+// GetBlockByHeight implements BitcoinClient.GetBlockByHeight
+// Numeric fields are float64, as they are when decoded from a node's JSON response.
+func (c *MockBitcoinClient) GetBlockByHeight(ctx context.Context, height uint64) (map[string]interface{}, error) {
+	tip := c.tip()
+	if height > tip {
+		return nil, fmt.Errorf("failed to get block hash: block %d is above the chain tip %d", height, tip)
+	}
+
+	return map[string]interface{}{
+		"hash":          mockBlockHash(height),
+		"height":        float64(height),
+		"time":          float64(mockGenesisTime + height*mockBlockInterval),
+		"confirmations": float64(tip - height + 1),
+		"difficulty":    mockDifficulty(c.HashRateFunc(height)),
+	}, nil
+}
+
+// mockDifficulty inverts the hash rate estimate of the node client,
+// hash rate = difficulty * 2^32 / 600
+func mockDifficulty(petaHashRate float64) float64 {
+	return petaHashRate * 1e15 * mockBlockInterval / 4294967296
+}
+
+// EstimateNetworkDifficulty implements BitcoinClient.EstimateNetworkDifficulty
+func (c *MockBitcoinClient) EstimateNetworkDifficulty(ctx context.Context) (float64, error) {
+	return mockDifficulty(c.HashRateFunc(c.tip())), nil
+}
+
+// IsInitialBlockDownload implements BitcoinClient.IsInitialBlockDownload
+func (c *MockBitcoinClient) IsInitialBlockDownload(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+// GetNetworkFeeEstimate implements BitcoinClient.GetNetworkFeeEstimate
+// Faster targets pay more, down to the 1 sat/byte minimum.
+func (c *MockBitcoinClient) GetNetworkFeeEstimate(ctx context.Context, targetConfirmations int) (uint64, error) {
+	if targetConfirmations < 1 {
+		targetConfirmations = 1
+	}
+	satoshisPerByte := uint64(20 / targetConfirmations)
+	if satoshisPerByte < 1 {
+		satoshisPerByte = 1
+	}
+	return satoshisPerByte, nil
+}
+
+// GetTransactionConfirmations implements BitcoinClient.GetTransactionConfirmations
+// A broadcast transaction is mined in the block after its broadcast.
+func (c *MockBitcoinClient) GetTransactionConfirmations(ctx context.Context, txHash string) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i, ok := c.byHash[txHash]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", hashperp.ErrTransactionUnknown, txHash)
+	}
+	// The tip may have been moved back below the broadcast
+	if c.blockHeight <= c.broadcasts[i].BlockHeight {
+		return 0, nil
+	}
+	return c.blockHeight - c.broadcasts[i].BlockHeight, nil
+}
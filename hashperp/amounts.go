@@ -0,0 +1,66 @@
+package hashperp
+
+import (
+	"math"
+	"math/big"
+)
+
+// Amounts are held in BTC throughout the API for compatibility, but BTC floats cannot
+// represent most satoshi amounts exactly. Collateral splits and payouts are computed in
+// integer satoshis so the parts always sum back to the whole, and storage keeps a satoshi
+// copy of every amount.
+
+// BTCToSats converts a BTC amount to satoshis, rounding to the nearest satoshi
+func BTCToSats(btc float64) int64 {
+	return int64(math.Round(btc * satoshisPerBTC))
+}
+
+// SatsToBTC converts a satoshi amount to BTC
+func SatsToBTC(sats int64) float64 {
+	return float64(sats) / satoshisPerBTC
+}
+
+// collateralSats returns the collateral in satoshis the buyer posts for a contract size at
+// a leverage, the smaller side of splitCollateralSats
+func collateralSats(sizeSats int64, leverage float64) int64 {
+	buyerSats, _ := splitCollateralSats(sizeSats, leverage)
+	return buyerSats
+}
+
+// splitCollateralSats splits the collateral for a contract size at a leverage between the
+// buyer and the seller. The seller absorbs the odd satoshi, so at 1x the two sides sum to
+// the size exactly and at higher leverage they never sum to more than size/leverage.
+func splitCollateralSats(sizeSats int64, leverage float64) (buyerSats, sellerSats int64) {
+	if leverage <= 0 {
+		leverage = defaultLeverage
+	}
+	potSats := sizeSats
+	if leverage != 1 {
+		potSats = int64(math.Floor(float64(sizeSats) / leverage))
+	}
+	buyerSats = potSats / 2
+	return buyerSats, potSats - buyerSats
+}
+
+// splitSatsProRata splits total across weights in proportion to each weight. Each share is
+// rounded down and the remainder goes to the first share, so the shares sum to total exactly.
+func splitSatsProRata(total int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+	var weightSum int64
+	for _, weight := range weights {
+		weightSum += weight
+	}
+	if weightSum <= 0 || len(weights) == 0 {
+		return shares
+	}
+
+	remainder := total
+	for i, weight := range weights {
+		// total * weight can overflow an int64 for large amounts
+		share := new(big.Int).Mul(big.NewInt(total), big.NewInt(weight))
+		shares[i] = share.Quo(share, big.NewInt(weightSum)).Int64()
+		remainder -= shares[i]
+	}
+	shares[0] += remainder
+	return shares
+}
@@ -0,0 +1,92 @@
+package hashperp
+
+import "testing"
+
+func TestSatsRoundTrip(t *testing.T) {
+	tests := []int64{0, 1, 3, 546, 99999999, 100000001, 123456789, 2100000000000000}
+
+	for _, sats := range tests {
+		if got := BTCToSats(SatsToBTC(sats)); got != sats {
+			t.Errorf("BTCToSats(SatsToBTC(%d)) = %d", sats, got)
+		}
+	}
+}
+
+func TestSplitCollateralSats(t *testing.T) {
+	tests := []struct {
+		name       string
+		sizeSats   int64
+		leverage   float64
+		wantBuyer  int64
+		wantSeller int64
+	}{
+		{"even size", 100000000, 1, 50000000, 50000000},
+		{"odd size", 100000001, 1, 50000000, 50000001},
+		{"one satoshi", 1, 1, 0, 1},
+		{"unset leverage", 100000001, 0, 50000000, 50000001},
+		{"leveraged", 100000000, 2, 25000000, 25000000},
+		{"leveraged, rounded down", 100000000, 3, 16666666, 16666667},
+		{"maximum leverage", 100000007, 10, 5000000, 5000000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buyerSats, sellerSats := splitCollateralSats(tt.sizeSats, tt.leverage)
+			if buyerSats != tt.wantBuyer || sellerSats != tt.wantSeller {
+				t.Errorf("splitCollateralSats(%d, %v) = %d, %d, want %d, %d",
+					tt.sizeSats, tt.leverage, buyerSats, sellerSats, tt.wantBuyer, tt.wantSeller)
+			}
+		})
+	}
+}
+
+func TestSideCollateralSumsToSize(t *testing.T) {
+	for _, size := range []float64{1, 0.00000001, 0.12345679, 1.00000001, 21} {
+		contract := &Contract{Size: size, Leverage: 1}
+		sumSats := BTCToSats(sideCollateral(contract, true)) + BTCToSats(sideCollateral(contract, false))
+		if sumSats != BTCToSats(size) {
+			t.Errorf("collateral of a %.8f BTC contract sums to %d sats, want %d", size, sumSats, BTCToSats(size))
+		}
+	}
+}
+
+func TestSettlementPayoutsSumToCollateral(t *testing.T) {
+	const strike = 0.001
+
+	tests := []struct {
+		name         string
+		contractType ContractType
+		size         float64
+		leverage     float64
+		rate         float64
+	}{
+		{"CALL in the money", CALL, 1.00000001, 1, 0.00123456},
+		{"CALL out of the money", CALL, 1.00000001, 1, 0.00087654},
+		{"PUT in the money", PUT, 0.12345679, 1, 0.00087654},
+		{"PUT at the strike", PUT, 0.12345679, 1, strike},
+		{"leveraged CALL", CALL, 0.33333333, 3, 0.00111111},
+		{"leveraged PUT liquidated", PUT, 0.33333333, 10, 0.002},
+		{"profit capped at the counterparty", CALL, 2.00000003, 1, 0.005},
+	}
+
+	for _, tt := range tests {
+		for _, policy := range []PayoutPolicy{{RoundingSats: 1}, DefaultPayoutPolicy(), {RoundingSats: 1000, MinPayoutSats: 546}} {
+			contract := &Contract{
+				ContractType: tt.contractType,
+				StrikeRate:   strike,
+				Size:         tt.size,
+				Leverage:     tt.leverage,
+				BuyerID:      "buyer",
+				SellerID:     "seller",
+			}
+
+			outcome := computeSettlementOutcome(contract, tt.rate, policy, 0)
+			collateralSats := BTCToSats(sideCollateral(contract, true)) + BTCToSats(sideCollateral(contract, false))
+			payoutSats := BTCToSats(outcome.buyerPayout) + BTCToSats(outcome.sellerPayout)
+			if payoutSats != collateralSats {
+				t.Errorf("%s with rounding %d: payouts sum to %d sats, want the collateral %d",
+					tt.name, policy.RoundingSats, payoutSats, collateralSats)
+			}
+		}
+	}
+}
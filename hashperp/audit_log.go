@@ -0,0 +1,349 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AuditEntityType names the kind of entity a status change was recorded for
+type AuditEntityType string
+
+const (
+	AUDIT_CONTRACT   AuditEntityType = "contract"
+	AUDIT_ORDER      AuditEntityType = "order"
+	AUDIT_SWAP_OFFER AuditEntityType = "swap_offer"
+	AUDIT_VTXO       AuditEntityType = "vtxo"
+)
+
+// VTXO statuses recorded in the audit trail, a VTXO's status is its IsActive flag
+const (
+	auditVTXOActive   = "ACTIVE"
+	auditVTXOInactive = "INACTIVE"
+)
+
+// auditActorSystem is recorded as the actor of changes made without an authenticated caller,
+// by background jobs such as settlement and order expiry
+const auditActorSystem = "system"
+
+// StatusChange is an audit record of an entity moving from one status to another. Creating
+// an entity is recorded as a change from the empty status.
+type StatusChange struct {
+	ID         string          `json:"id"`
+	EntityType AuditEntityType `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	OldStatus  string          `json:"old_status,omitempty"`
+	NewStatus  string          `json:"new_status"`
+	ActorID    string          `json:"actor_id"` // Authenticated caller that made the change, "system" for background jobs
+	Timestamp  time.Time       `json:"timestamp"`
+}
+
+// ValidateAuditEntityType validates the entity type of an audit trail query
+func ValidateAuditEntityType(entityType AuditEntityType) error {
+	switch entityType {
+	case AUDIT_CONTRACT, AUDIT_ORDER, AUDIT_SWAP_OFFER, AUDIT_VTXO:
+		return nil
+	}
+	return fmt.Errorf("%w: entity type must be %q, %q, %q or %q", ErrInvalidParameters,
+		AUDIT_CONTRACT, AUDIT_ORDER, AUDIT_SWAP_OFFER, AUDIT_VTXO)
+}
+
+// vtxoAuditStatus is the status recorded for a VTXO in the audit trail
+func vtxoAuditStatus(vtxo *VTXO) string {
+	if vtxo.IsActive {
+		return auditVTXOActive
+	}
+	return auditVTXOInactive
+}
+
+// statusAuditLog records status changes for the audited repositories
+type statusAuditLog struct {
+	repo           StatusChangeRepository
+	ids            IDGenerator
+	clock          Clock
+	eventPublisher EventPublisher
+}
+
+// newStatusAuditLog creates an audit log writing to repo
+func newStatusAuditLog(repo StatusChangeRepository) *statusAuditLog {
+	return &statusAuditLog{
+		repo:           repo,
+		ids:            NewUUIDGenerator(),
+		clock:          NewSystemClock(),
+		eventPublisher: NewNoopEventPublisher(),
+	}
+}
+
+// record writes a status change if the status actually changed. Failures are published rather
+// than returned, the change itself has already been persisted.
+func (l *statusAuditLog) record(ctx context.Context, entityType AuditEntityType, entityID, oldStatus, newStatus string) {
+	if oldStatus == newStatus {
+		return
+	}
+
+	actorID, ok := AuthenticatedUserID(ctx)
+	if !ok {
+		actorID = auditActorSystem
+	}
+
+	change := &StatusChange{
+		ID:         l.ids.NewID(),
+		EntityType: entityType,
+		EntityID:   entityID,
+		OldStatus:  oldStatus,
+		NewStatus:  newStatus,
+		ActorID:    actorID,
+		Timestamp:  l.clock.Now().UTC(),
+	}
+	if err := l.repo.Create(ctx, change); err != nil {
+		publishEvent(ctx, l.eventPublisher, Event{
+			Type:      EVENT_OPERATION_FAILED,
+			Operation: "record_status_change",
+			Error:     err.Error(),
+			Attributes: map[string]string{
+				"entity_type": string(entityType),
+				"entity_id":   entityID,
+				"old_status":  oldStatus,
+				"new_status":  newStatus,
+			},
+		})
+	}
+}
+
+// auditedContractRepository records every contract status change in the audit trail
+type auditedContractRepository struct {
+	ContractRepository
+	log *statusAuditLog
+}
+
+// NewAuditedContractRepository wraps a contract repository so that every status a contract
+// is created with or moved to is recorded in the audit trail
+func NewAuditedContractRepository(repo ContractRepository, auditRepo StatusChangeRepository) ContractRepository {
+	return &auditedContractRepository{
+		ContractRepository: repo,
+		log:                newStatusAuditLog(auditRepo),
+	}
+}
+
+// SetEventPublisher sets the publisher that receives failures to record a contract status change
+func (r *auditedContractRepository) SetEventPublisher(publisher EventPublisher) {
+	r.log.eventPublisher = publisher
+}
+
+// Create implements ContractRepository.Create
+func (r *auditedContractRepository) Create(ctx context.Context, contract *Contract) error {
+	if err := r.ContractRepository.Create(ctx, contract); err != nil {
+		return err
+	}
+
+	r.log.record(ctx, AUDIT_CONTRACT, contract.ID, "", string(contract.Status))
+	return nil
+}
+
+// Update implements ContractRepository.Update
+func (r *auditedContractRepository) Update(ctx context.Context, contract *Contract) error {
+	current, err := r.ContractRepository.FindByID(ctx, contract.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get contract: %w", err)
+	}
+	if err := r.ContractRepository.Update(ctx, contract); err != nil {
+		return err
+	}
+
+	if current != nil {
+		r.log.record(ctx, AUDIT_CONTRACT, contract.ID, string(current.Status), string(contract.Status))
+	}
+	return nil
+}
+
+// auditedOrderRepository records every order status change in the audit trail
+type auditedOrderRepository struct {
+	OrderRepository
+	log *statusAuditLog
+}
+
+// NewAuditedOrderRepository wraps an order repository so that every status an order is
+// created with or moved to is recorded in the audit trail
+func NewAuditedOrderRepository(repo OrderRepository, auditRepo StatusChangeRepository) OrderRepository {
+	return &auditedOrderRepository{
+		OrderRepository: repo,
+		log:             newStatusAuditLog(auditRepo),
+	}
+}
+
+// SetEventPublisher sets the publisher that receives failures to record a order status change
+func (r *auditedOrderRepository) SetEventPublisher(publisher EventPublisher) {
+	r.log.eventPublisher = publisher
+}
+
+// Create implements OrderRepository.Create
+func (r *auditedOrderRepository) Create(ctx context.Context, order *Order) error {
+	if err := r.OrderRepository.Create(ctx, order); err != nil {
+		return err
+	}
+
+	r.log.record(ctx, AUDIT_ORDER, order.ID, "", string(order.Status))
+	return nil
+}
+
+// Update implements OrderRepository.Update
+func (r *auditedOrderRepository) Update(ctx context.Context, order *Order) error {
+	current, err := r.OrderRepository.FindByID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+	if err := r.OrderRepository.Update(ctx, order); err != nil {
+		return err
+	}
+
+	if current != nil {
+		r.log.record(ctx, AUDIT_ORDER, order.ID, string(current.Status), string(order.Status))
+	}
+	return nil
+}
+
+// auditedSwapOfferRepository records every swap offer status change in the audit trail
+type auditedSwapOfferRepository struct {
+	SwapOfferRepository
+	log *statusAuditLog
+}
+
+// NewAuditedSwapOfferRepository wraps a swap offer repository so that every status an offer
+// is created with or moved to is recorded in the audit trail
+func NewAuditedSwapOfferRepository(repo SwapOfferRepository, auditRepo StatusChangeRepository) SwapOfferRepository {
+	return &auditedSwapOfferRepository{
+		SwapOfferRepository: repo,
+		log:                 newStatusAuditLog(auditRepo),
+	}
+}
+
+// SetEventPublisher sets the publisher that receives failures to record a swap offer status change
+func (r *auditedSwapOfferRepository) SetEventPublisher(publisher EventPublisher) {
+	r.log.eventPublisher = publisher
+}
+
+// Create implements SwapOfferRepository.Create
+func (r *auditedSwapOfferRepository) Create(ctx context.Context, offer *SwapOffer) error {
+	if err := r.SwapOfferRepository.Create(ctx, offer); err != nil {
+		return err
+	}
+
+	r.log.record(ctx, AUDIT_SWAP_OFFER, offer.ID, "", offer.Status)
+	return nil
+}
+
+// Update implements SwapOfferRepository.Update
+func (r *auditedSwapOfferRepository) Update(ctx context.Context, offer *SwapOffer) error {
+	current, err := r.SwapOfferRepository.FindByID(ctx, offer.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get swap offer: %w", err)
+	}
+	if err := r.SwapOfferRepository.Update(ctx, offer); err != nil {
+		return err
+	}
+
+	if current != nil {
+		r.log.record(ctx, AUDIT_SWAP_OFFER, offer.ID, current.Status, offer.Status)
+	}
+	return nil
+}
+
+// auditedVTXORepository records every VTXO activation and deactivation in the audit trail
+type auditedVTXORepository struct {
+	VTXORepository
+	log *statusAuditLog
+}
+
+// NewAuditedVTXORepository wraps a VTXO repository so that every VTXO's creation and each
+// change of its active flag is recorded in the audit trail
+func NewAuditedVTXORepository(repo VTXORepository, auditRepo StatusChangeRepository) VTXORepository {
+	return &auditedVTXORepository{
+		VTXORepository: repo,
+		log:            newStatusAuditLog(auditRepo),
+	}
+}
+
+// SetEventPublisher sets the publisher that receives failures to record a VTXO status change
+func (r *auditedVTXORepository) SetEventPublisher(publisher EventPublisher) {
+	r.log.eventPublisher = publisher
+}
+
+// Create implements VTXORepository.Create
+func (r *auditedVTXORepository) Create(ctx context.Context, vtxo *VTXO) error {
+	if err := r.VTXORepository.Create(ctx, vtxo); err != nil {
+		return err
+	}
+
+	r.log.record(ctx, AUDIT_VTXO, vtxo.ID, "", vtxoAuditStatus(vtxo))
+	return nil
+}
+
+// Update implements VTXORepository.Update
+func (r *auditedVTXORepository) Update(ctx context.Context, vtxo *VTXO) error {
+	current, err := r.VTXORepository.FindByID(ctx, vtxo.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get VTXO: %w", err)
+	}
+	if err := r.VTXORepository.Update(ctx, vtxo); err != nil {
+		return err
+	}
+
+	if current != nil {
+		r.log.record(ctx, AUDIT_VTXO, vtxo.ID, vtxoAuditStatus(current), vtxoAuditStatus(vtxo))
+	}
+	return nil
+}
+
+// GetAuditTrail implements HashPerpService.GetAuditTrail
+func (s *hashPerpService) GetAuditTrail(ctx context.Context, entityType AuditEntityType, entityID string) ([]*StatusChange, error) {
+	if err := ValidateAuditEntityType(entityType); err != nil {
+		return nil, err
+	}
+	if err := ValidateUUID(entityID); err != nil {
+		return nil, fmt.Errorf("invalid entity ID: %w", err)
+	}
+	if s.statusChangeRepo == nil {
+		return nil, fmt.Errorf("no status change repository configured")
+	}
+
+	// 1. Only the parties to an entity may read its audit trail
+	if err := s.authorizeAuditEntity(ctx, entityType, entityID); err != nil {
+		return nil, err
+	}
+
+	// 2. Get the recorded changes, oldest first
+	changes, err := s.statusChangeRepo.FindByEntity(ctx, entityType, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit trail: %w", err)
+	}
+
+	return changes, nil
+}
+
+// authorizeAuditEntity checks that the authenticated caller is a party to the entity whose
+// audit trail they read
+func (s *hashPerpService) authorizeAuditEntity(ctx context.Context, entityType AuditEntityType, entityID string) error {
+	if isInternalCaller(ctx) {
+		return nil
+	}
+
+	switch entityType {
+	case AUDIT_CONTRACT:
+		return s.authorizeContractParty(ctx, entityID)
+	case AUDIT_VTXO:
+		return s.authorizeVTXOOwner(ctx, entityID)
+	case AUDIT_ORDER:
+		order, err := s.orderBookManager.GetOrder(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		return authorizeUser(ctx, order.UserID)
+	case AUDIT_SWAP_OFFER:
+		offer, err := s.swapOfferManager.GetSwapOffer(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		return authorizeUser(ctx, offer.OfferorID, offer.AcceptorID)
+	}
+	return nil
+}
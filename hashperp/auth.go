@@ -0,0 +1,81 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnauthorized is returned when the authenticated caller acts for another user
+var ErrUnauthorized = errors.New("caller is not authorized to act for this user")
+
+// authenticatedUserKey is the context key holding the authenticated caller's user ID
+type authenticatedUserKey struct{}
+
+// internalCallerKey is the context key marking a trusted internal caller
+type internalCallerKey struct{}
+
+// ContextWithAuthenticatedUser returns a context carrying the authenticated caller's user ID
+func ContextWithAuthenticatedUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, authenticatedUserKey{}, userID)
+}
+
+// AuthenticatedUserID returns the authenticated caller's user ID, if the context has one
+func AuthenticatedUserID(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(authenticatedUserKey{}).(string)
+	return userID, ok && userID != ""
+}
+
+// ContextWithInternalCaller returns a context for a trusted internal caller, which may act for
+// any user and run operator methods. Only background jobs and an API server explicitly started
+// without authentication for development use it.
+func ContextWithInternalCaller(ctx context.Context) context.Context {
+	return context.WithValue(ctx, internalCallerKey{}, true)
+}
+
+// isInternalCaller reports whether a context comes from a trusted internal caller
+func isInternalCaller(ctx context.Context) bool {
+	internal, _ := ctx.Value(internalCallerKey{}).(bool)
+	return internal
+}
+
+// AuthorizeUser checks that the caller may act for one of the given users, for callers outside
+// the service such as the API's subscriptions
+func AuthorizeUser(ctx context.Context, userIDs ...string) error {
+	return authorizeUser(ctx, userIDs...)
+}
+
+// authorizeUser checks that the authenticated caller is one of the given users. Internal
+// callers are allowed, any other context without an authenticated caller is refused.
+func authorizeUser(ctx context.Context, userIDs ...string) error {
+	if isInternalCaller(ctx) {
+		return nil
+	}
+	callerID, ok := AuthenticatedUserID(ctx)
+	if !ok {
+		return fmt.Errorf("%w: no authenticated caller", ErrUnauthorized)
+	}
+
+	for _, userID := range userIDs {
+		if userID == callerID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: caller %s", ErrUnauthorized, callerID)
+}
+
+// authorizeOperator checks that the caller is one of the operators, or an internal caller
+func authorizeOperator(ctx context.Context, operators map[string]bool) error {
+	if isInternalCaller(ctx) {
+		return nil
+	}
+	callerID, ok := AuthenticatedUserID(ctx)
+	if !ok {
+		return fmt.Errorf("%w: no authenticated caller", ErrUnauthorized)
+	}
+	if !operators[callerID] {
+		return fmt.Errorf("%w: caller %s is not an operator", ErrUnauthorized, callerID)
+	}
+	return nil
+}
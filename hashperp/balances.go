@@ -0,0 +1,62 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// lockCollateral deducts collateral from a user's available balance before it is locked
+// into a VTXO. Balances are not tracked when no user repository is configured.
+func lockCollateral(ctx context.Context, userRepo UserRepository, userID string, amount float64) error {
+	if userRepo == nil || amount <= 0 {
+		return nil
+	}
+
+	if err := userRepo.DebitBalance(ctx, userID, amount); err != nil {
+		return fmt.Errorf("failed to lock collateral for user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// releaseCollateral credits a payout or returned collateral to a user's available balance
+func releaseCollateral(ctx context.Context, userRepo UserRepository, userID string, amount float64) error {
+	if userRepo == nil || amount <= 0 {
+		return nil
+	}
+
+	if err := userRepo.CreditBalance(ctx, userID, amount); err != nil {
+		return fmt.Errorf("failed to credit user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// sidePayouts keys each side's payout by user, splitting it across the side's pool if it has one
+func sidePayouts(contract *Contract, buyerPayout, sellerPayout float64) map[string]float64 {
+	if len(contract.BuyerPool) > 0 || len(contract.SellerPool) > 0 {
+		return poolPayouts(contract, buyerPayout, sellerPayout)
+	}
+	return map[string]float64{contract.BuyerID: buyerPayout, contract.SellerID: sellerPayout}
+}
+
+// releasePayouts credits each user's payout, publishing a failure event for any credit that
+// fails so the remaining payouts still go out
+func releasePayouts(
+	ctx context.Context,
+	userRepo UserRepository,
+	publisher EventPublisher,
+	contractID string,
+	operation string,
+	payouts map[string]float64,
+) {
+	for userID, payout := range payouts {
+		if err := releaseCollateral(ctx, userRepo, userID, payout); err != nil {
+			publishEvent(ctx, publisher, Event{
+				Type:       EVENT_OPERATION_FAILED,
+				ContractID: contractID,
+				UserIDs:    []string{userID},
+				Operation:  operation,
+				Error:      err.Error(),
+			})
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultMaxBlockHeightStaleness is how long the last known block height may stand in for the
+// node's, about six blocks
+const defaultMaxBlockHeightStaleness = time.Hour
+
+// BlockHeightCacheConfig sets how long read paths may keep serving the last block height the
+// node reported once it becomes unreachable, and how long a height refreshed by the block
+// height poller is served without calling the node at all
+type BlockHeightCacheConfig struct {
+	MaxStaleness    time.Duration // Age after which the cached height is no longer served
+	RefreshInterval time.Duration // Age up to which the cached height counts as current, 0 always calls the node
+}
+
+// DefaultBlockHeightCacheConfig returns the block height cache config used when none is configured
+func DefaultBlockHeightCacheConfig() BlockHeightCacheConfig {
+	return BlockHeightCacheConfig{MaxStaleness: defaultMaxBlockHeightStaleness}
+}
+
+// Validate checks that the staleness bound is positive and covers the refresh interval
+func (c BlockHeightCacheConfig) Validate() error {
+	if c.MaxStaleness <= 0 {
+		return fmt.Errorf("%w: block height max staleness must be positive", ErrInvalidParameters)
+	}
+	if c.RefreshInterval < 0 || c.RefreshInterval > c.MaxStaleness {
+		return fmt.Errorf("%w: block height refresh interval must be between 0 and the max staleness",
+			ErrInvalidParameters)
+	}
+	return nil
+}
+
+// BlockHeightReading is the block height a read ran at. While the node is unreachable it is
+// the last height the node reported, flagged as stale.
+type BlockHeightReading struct {
+	BlockHeight uint64    `json:"block_height"`
+	ObservedAt  time.Time `json:"observed_at"` // When the node reported this height
+	Stale       bool      `json:"stale"`
+}
+
+// blockHeightCachingClient remembers the last block height the node reported, so that read
+// paths can keep serving while the node is down for maintenance
+type blockHeightCachingClient struct {
+	BitcoinClient
+	config BlockHeightCacheConfig
+	clock  Clock
+
+	mu         sync.RWMutex
+	height     uint64
+	observedAt time.Time
+}
+
+// NewBlockHeightCachingClient wraps a Bitcoin client so that every block height it returns is
+// cached. Write and settlement paths still call the node and fail while it is unreachable,
+// only read paths fall back to the cached height.
+func NewBlockHeightCachingClient(client BitcoinClient, config BlockHeightCacheConfig) BitcoinClient {
+	return &blockHeightCachingClient{
+		BitcoinClient: client,
+		config:        config,
+		clock:         NewSystemClock(),
+	}
+}
+
+// GetCurrentBlockHeight implements BitcoinClient.GetCurrentBlockHeight
+func (c *blockHeightCachingClient) GetCurrentBlockHeight(ctx context.Context) (uint64, error) {
+	height, err := c.BitcoinClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.height = height
+	c.observedAt = c.clock.Now().UTC()
+	c.mu.Unlock()
+	return height, nil
+}
+
+// lastBlockHeight returns the last block height the node reported, if it is recent enough to serve
+func (c *blockHeightCachingClient) lastBlockHeight() (uint64, time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.observedAt.IsZero() || c.clock.Now().Sub(c.observedAt) > c.config.MaxStaleness {
+		return 0, time.Time{}, false
+	}
+	return c.height, c.observedAt, true
+}
+
+// recentBlockHeight returns the cached block height if it was refreshed within the refresh
+// interval, recent enough to use as the current height
+func (c *blockHeightCachingClient) recentBlockHeight() (uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.config.RefreshInterval <= 0 || c.observedAt.IsZero() ||
+		c.clock.Now().Sub(c.observedAt) > c.config.RefreshInterval {
+		return 0, false
+	}
+	return c.height, true
+}
+
+// cachedBlockHeight gets the current block height, from the cache the block height poller
+// keeps refreshed when it is recent and from the node otherwise. A node failure is returned,
+// callers needing the height to validate or match never run on a stale one.
+func cachedBlockHeight(ctx context.Context, btcClient BitcoinClient) (uint64, error) {
+	if cache, ok := btcClient.(interface{ recentBlockHeight() (uint64, bool) }); ok {
+		if height, ok := cache.recentBlockHeight(); ok {
+			return height, nil
+		}
+	}
+
+	height, err := btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current block height: %w", err)
+	}
+	return height, nil
+}
+
+// readBlockHeight gets the current block height for a read path. When the node is unreachable
+// it falls back to the last height the client cached, flagged as stale. Paths that write or
+// settle must use cachedBlockHeight or GetCurrentBlockHeight so they never act on a stale height.
+func readBlockHeight(ctx context.Context, btcClient BitcoinClient, clock Clock) (*BlockHeightReading, error) {
+	height, err := cachedBlockHeight(ctx, btcClient)
+	if err == nil {
+		return &BlockHeightReading{BlockHeight: height, ObservedAt: clock.Now().UTC()}, nil
+	}
+
+	cache, ok := btcClient.(interface {
+		lastBlockHeight() (uint64, time.Time, bool)
+	})
+	if !ok {
+		return nil, err
+	}
+	cachedHeight, observedAt, ok := cache.lastBlockHeight()
+	if !ok {
+		return nil, err
+	}
+
+	return &BlockHeightReading{BlockHeight: cachedHeight, ObservedAt: observedAt, Stale: true}, nil
+}
+
+// recordedBlockHeight gets the block height a transaction is recorded at. The height only
+// annotates an operation that already took effect, so the last known height is used while the
+// node is unreachable, and 0 when there is none.
+func recordedBlockHeight(ctx context.Context, btcClient BitcoinClient, clock Clock) uint64 {
+	reading, err := readBlockHeight(ctx, btcClient, clock)
+	if err != nil {
+		return 0
+	}
+	return reading.BlockHeight
+}
+
+// GetBlockHeight implements HashPerpService.GetBlockHeight
+func (s *hashPerpService) GetBlockHeight(ctx context.Context) (*BlockHeightReading, error) {
+	return readBlockHeight(ctx, s.btcClient, s.clock)
+}
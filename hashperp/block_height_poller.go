@@ -0,0 +1,122 @@
+package hashperp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BlockHeightPoller keeps the shared block height cache refreshed in the background, so that
+// order placement and contract validation read a recent height instead of each calling the node
+type BlockHeightPoller interface {
+	// Start refreshes the block height once and then on every poll interval
+	Start()
+
+	// Stop cancels polling and waits for a refresh in progress to finish
+	Stop()
+
+	// OnNewBlock registers a listener called from the polling goroutine with each new block
+	// height the poller observes. It must be called before Start.
+	OnNewBlock(listener func(ctx context.Context, blockHeight uint64))
+}
+
+// blockHeightPoller implements the BlockHeightPoller interface
+type blockHeightPoller struct {
+	btcClient      BitcoinClient
+	interval       time.Duration
+	eventPublisher EventPublisher
+	listeners      []func(ctx context.Context, blockHeight uint64)
+	lastHeight     uint64 // Last height passed to the listeners, only touched by the polling goroutine
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // Non-nil while polling
+	wg     sync.WaitGroup
+}
+
+// NewBlockHeightPoller creates a poller refreshing the block height of a client wrapped by
+// NewBlockHeightCachingClient every interval. A zero interval disables polling.
+func NewBlockHeightPoller(btcClient BitcoinClient, interval time.Duration) BlockHeightPoller {
+	return &blockHeightPoller{
+		btcClient:      btcClient,
+		interval:       interval,
+		eventPublisher: NewNoopEventPublisher(),
+	}
+}
+
+// SetEventPublisher sets the publisher that receives failed polls. It must be called before Start.
+func (p *blockHeightPoller) SetEventPublisher(publisher EventPublisher) {
+	p.eventPublisher = publisher
+}
+
+// Start implements BlockHeightPoller.Start
+func (p *blockHeightPoller) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil || p.interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		p.refresh(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.refresh(ctx)
+			}
+		}
+	}()
+}
+
+// Stop implements BlockHeightPoller.Stop
+func (p *blockHeightPoller) Stop() {
+	p.mu.Lock()
+	cancel := p.cancel
+	p.cancel = nil
+	p.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	p.wg.Wait()
+}
+
+// OnNewBlock implements BlockHeightPoller.OnNewBlock
+func (p *blockHeightPoller) OnNewBlock(listener func(ctx context.Context, blockHeight uint64)) {
+	p.listeners = append(p.listeners, listener)
+}
+
+// refresh reads the block height from the node, the caching client records it. The listeners
+// are told about a height they have not seen yet.
+func (p *blockHeightPoller) refresh(ctx context.Context) {
+	height, err := p.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		publishEvent(ctx, p.eventPublisher, Event{
+			Type:      EVENT_OPERATION_FAILED,
+			Operation: "poll_block_height",
+			Error:     err.Error(),
+		})
+		return
+	}
+
+	if height == p.lastHeight {
+		return
+	}
+	p.lastHeight = height
+	for _, listener := range p.listeners {
+		listener(ctx, height)
+	}
+}
@@ -0,0 +1,110 @@
+package hashperp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultBlockInterval is the target block interval, used when block timestamps are unavailable
+	defaultBlockInterval = 10 * time.Minute
+
+	// blockIntervalWindow is how many recent blocks the observed block interval is averaged
+	// over. One difficulty period smooths out both variance and timestamp skew.
+	blockIntervalWindow = 2016
+
+	// Observed intervals outside these bounds point at bad block data rather than the network
+	minObservedBlockInterval = 1 * time.Minute
+	maxObservedBlockInterval = 60 * time.Minute
+)
+
+// blockIntervalEstimator projects block heights to wall-clock times using the average block
+// interval observed over recent blocks. The average is recomputed at most once per block.
+type blockIntervalEstimator struct {
+	btcClient BitcoinClient
+	clock     Clock // Time expiry dates are projected from
+
+	mu       sync.Mutex
+	tip      uint64        // Block height the cached interval was measured at
+	interval time.Duration // Cached average interval, zero until first measured
+}
+
+// newBlockIntervalEstimator creates an estimator reading block timestamps from btcClient
+func newBlockIntervalEstimator(btcClient BitcoinClient) *blockIntervalEstimator {
+	return &blockIntervalEstimator{btcClient: btcClient, clock: NewSystemClock()}
+}
+
+// setClock configures the time expiry dates are projected from
+func (e *blockIntervalEstimator) setClock(clock Clock) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.clock = clock
+}
+
+// blockTimestamp reads the header time of a block returned by BitcoinClient.GetBlockByHeight
+func blockTimestamp(block map[string]interface{}) (time.Time, bool) {
+	seconds, ok := block["time"].(float64)
+	if !ok || seconds <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0).UTC(), true
+}
+
+// averageInterval returns the average block interval over the blockIntervalWindow blocks
+// ending at currentBlockHeight, falling back to defaultBlockInterval when the timestamps
+// cannot be read or are implausible
+func (e *blockIntervalEstimator) averageInterval(ctx context.Context, currentBlockHeight uint64) time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.interval > 0 && e.tip == currentBlockHeight {
+		return e.interval
+	}
+
+	interval := e.measure(ctx, currentBlockHeight)
+	e.tip = currentBlockHeight
+	e.interval = interval
+	return interval
+}
+
+// measure reads the timestamps at both ends of the window from the node
+func (e *blockIntervalEstimator) measure(ctx context.Context, currentBlockHeight uint64) time.Duration {
+	if currentBlockHeight < blockIntervalWindow {
+		return defaultBlockInterval
+	}
+
+	endBlock, err := e.btcClient.GetBlockByHeight(ctx, currentBlockHeight)
+	if err != nil {
+		return defaultBlockInterval
+	}
+	startBlock, err := e.btcClient.GetBlockByHeight(ctx, currentBlockHeight-blockIntervalWindow)
+	if err != nil {
+		return defaultBlockInterval
+	}
+
+	endTime, ok := blockTimestamp(endBlock)
+	if !ok {
+		return defaultBlockInterval
+	}
+	startTime, ok := blockTimestamp(startBlock)
+	if !ok {
+		return defaultBlockInterval
+	}
+
+	interval := endTime.Sub(startTime) / blockIntervalWindow
+	if interval < minObservedBlockInterval || interval > maxObservedBlockInterval {
+		return defaultBlockInterval
+	}
+	return interval
+}
+
+// expiryDate estimates when expiryBlockHeight will be mined at the observed block interval
+func (e *blockIntervalEstimator) expiryDate(ctx context.Context, expiryBlockHeight, currentBlockHeight uint64) time.Time {
+	interval := e.averageInterval(ctx, currentBlockHeight)
+
+	e.mu.Lock()
+	now := e.clock.Now()
+	e.mu.Unlock()
+	return calculateExpiryDate(now, expiryBlockHeight, currentBlockHeight, interval)
+}
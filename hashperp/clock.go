@@ -0,0 +1,157 @@
+package hashperp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator produces the IDs of newly created entities
+type IDGenerator interface {
+	// NewID returns a new unique ID
+	NewID() string
+}
+
+// uuidGenerator implements the IDGenerator interface with random UUIDs
+type uuidGenerator struct{}
+
+// NewUUIDGenerator creates an ID generator producing random (version 4) UUIDs, the default
+func NewUUIDGenerator() IDGenerator {
+	return uuidGenerator{}
+}
+
+// NewID implements IDGenerator.NewID
+func (uuidGenerator) NewID() string {
+	return uuid.New().String()
+}
+
+// sequentialIDGenerator implements the IDGenerator interface with predictable UUIDs
+type sequentialIDGenerator struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+// NewSequentialIDGenerator creates an ID generator producing the UUIDs
+// 00000000-0000-0000-0000-000000000001, ...0002 and so on, for reproducible tests
+func NewSequentialIDGenerator() IDGenerator {
+	return &sequentialIDGenerator{}
+}
+
+// NewID implements IDGenerator.NewID
+func (g *sequentialIDGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.next++
+	return fmt.Sprintf("00000000-0000-0000-0000-%012x", g.next)
+}
+
+// Clock reports the current time to the managers
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+}
+
+// systemClock implements the Clock interface with the system time
+type systemClock struct{}
+
+// NewSystemClock creates a clock reading the system time, the default
+func NewSystemClock() Clock {
+	return systemClock{}
+}
+
+// Now implements Clock.Now
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock implements the Clock interface with a time that only moves when told to,
+// for reproducible tests
+type FixedClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFixedClock creates a clock stopped at the given time
+func NewFixedClock(now time.Time) *FixedClock {
+	return &FixedClock{now: now}
+}
+
+// Now implements Clock.Now
+func (c *FixedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *FixedClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// SetIDGenerator configures how the IDs of new contracts, VTXOs and transactions are generated
+func (s *contractService) SetIDGenerator(ids IDGenerator) {
+	s.ids = ids
+}
+
+// SetClock configures the clock timestamps and projected expiry dates are taken from
+func (s *contractService) SetClock(clock Clock) {
+	s.clock = clock
+	s.blockTimes.setClock(clock)
+}
+
+// SetIDGenerator configures how the IDs of new VTXOs and transactions are generated
+func (s *vtxoService) SetIDGenerator(ids IDGenerator) {
+	s.ids = ids
+}
+
+// SetClock configures the clock timestamps are taken from
+func (s *vtxoService) SetClock(clock Clock) {
+	s.clock = clock
+}
+
+// SetIDGenerator configures how the IDs of new swap offers and transactions are generated
+func (s *swapOfferService) SetIDGenerator(ids IDGenerator) {
+	s.ids = ids
+}
+
+// SetClock configures the clock timestamps and offer expiries are taken from
+func (s *swapOfferService) SetClock(clock Clock) {
+	s.clock = clock
+}
+
+// SetClock configures the clock order timestamps and projected expiry dates are taken from
+func (s *orderBookService) SetClock(clock Clock) {
+	s.clock = clock
+	s.blockTimes.setClock(clock)
+}
+
+// SetClock configures the clock hash rate observations are stamped with
+func (s *marketDataService) SetClock(clock Clock) {
+	s.clock = clock
+}
+
+// SetClock configures the clock recorded transactions are stamped with
+func (s *transactionService) SetClock(clock Clock) {
+	s.clock = clock
+}
+
+// SetIDGenerator configures how the IDs of new webhook subscriptions and deliveries are generated
+func (s *webhookService) SetIDGenerator(ids IDGenerator) {
+	s.ids = ids
+}
+
+// SetClock configures the clock webhook deliveries are scheduled and signed with
+func (s *webhookService) SetClock(clock Clock) {
+	s.clock = clock
+}
+
+// SetClock configures the clock health reports, market summaries and swap offer expiry
+// bounds are taken from
+func (s *hashPerpService) SetClock(clock Clock) {
+	s.clock = clock
+}
@@ -27,6 +27,7 @@ type ContractStatus string
 
 const (
 	PENDING            ContractStatus = "PENDING"      // Contract created but not yet active
+	FUNDING            ContractStatus = "FUNDING"      // Setup transaction stored, broadcast until the contract is active
 	ACTIVE             ContractStatus = "ACTIVE"       // Contract is currently active
 	SETTLED            ContractStatus = "SETTLED"      // Contract has been settled
 	EXITED             ContractStatus = "EXITED"       // Contract was exited before expiration
@@ -35,6 +36,7 @@ const (
 	SETTLEMENT_IN_PROGRESS ContractStatus = "SETTLEMENT_IN_PROGRESS" // Settlement is in progress
 	COMPLETED          ContractStatus = "COMPLETED"    // Contract is fully completed
 	CLOSE_TO_EXPIRY    ContractStatus = "CLOSE_TO_EXPIRY" // Contract is close to expiration
+	CONTRACT_CANCELED  ContractStatus = "CANCELED"     // Contract was canceled before it was funded
 )
 
 // Contract represents a hash rate perpetual futures contract
@@ -50,8 +52,11 @@ type Contract struct {
 	BuyerID            string         `json:"buyer_id"`
 	SellerID           string         `json:"seller_id"`
 	Size               float64        `json:"size"`            // Contract size in BTC
+	SizeSats           int64          `json:"size_sats"`       // Contract size in satoshis
 	BuyerVTXO          string         `json:"buyer_vtxo"`      // VTXO identifier for buyer
 	SellerVTXO         string         `json:"seller_vtxo"`     // VTXO identifier for seller
+	SetupTx            string         `json:"setup_tx,omitempty"`      // Signed setup transaction, stored before it is broadcast
+	SetupTxID          string         `json:"setup_tx_id,omitempty"`   // Setup transaction ID once funded on-chain
 	SettlementTx       string         `json:"settlement_tx,omitempty"` // Settlement transaction ID if settled
 	RolledOverToID     string         `json:"rolled_over_to_id,omitempty"` // ID of contract this rolled into
 	CompletionTimestamp time.Time     `json:"completion_timestamp,omitempty"` // When the contract was completed
@@ -59,14 +64,66 @@ type Contract struct {
 	SellerExited       bool           `json:"seller_exited,omitempty"` // Whether the seller has exited
 	BuyerExitTxHash    string         `json:"buyer_exit_tx_hash,omitempty"` // Exit transaction hash for buyer
 	SellerExitTxHash   string         `json:"seller_exit_tx_hash,omitempty"` // Exit transaction hash for seller
+	Leverage           float64        `json:"leverage"`        // Position leverage, collateral per side is Size/2/Leverage
+	BuyerLiquidated    bool           `json:"buyer_liquidated,omitempty"` // Whether the buyer's losses exceeded their collateral
+	SellerLiquidated   bool           `json:"seller_liquidated,omitempty"` // Whether the seller's losses exceeded their collateral
+	RolloverCount      int            `json:"rollover_count,omitempty"` // Number of rollovers preceding this contract
+	OriginBlockHeight  uint64         `json:"origin_block_height,omitempty"` // Block height at which the rollover chain started
+	ProposedWinnerID   string         `json:"proposed_winner_id,omitempty"` // Winner proposed while settlement is pending
+	SettlementProposedHeight uint64   `json:"settlement_proposed_height,omitempty"` // Block height at which the settlement was proposed
+	SettlementDisputeDeadline uint64  `json:"settlement_dispute_deadline,omitempty"` // Block height at which the proposed settlement can no longer be disputed
+	SettlementBlockHash string        `json:"settlement_block_hash,omitempty"` // Hash of the expiry block the settlement rate was read from
+	IdempotencyKey     string         `json:"idempotency_key,omitempty"` // Caller-supplied key used to dedup retried creations
+	SettlementMethod   SettlementMethod `json:"settlement_method"` // How the settlement rate is read at expiry
+	ExitFeeRate        float64        `json:"exit_fee_rate"`   // Fraction of the exiting side's collateral charged to a party exiting early
+	TimeoutExitBlocks  uint64         `json:"timeout_exit_blocks"` // Blocks after expiry before the timeout exit path opens
+	SweepWindowBlocks  uint64         `json:"sweep_window_blocks"` // Blocks before expiry from which VTXOs may be swept
+	BuyerPool          []PoolMember   `json:"buyer_pool,omitempty"`  // Users sharing the buyer side, the buyer holds the rest
+	SellerPool         []PoolMember   `json:"seller_pool,omitempty"` // Users sharing the seller side, the seller holds the rest
+	Version            uint64         `json:"version"` // Incremented by every update, guards against concurrent writes
 }
 
+// PositionSide is the buyer or seller side of a contract
+type PositionSide string
+
+const (
+	SIDE_BUYER  PositionSide = "BUYER"
+	SIDE_SELLER PositionSide = "SELLER"
+)
+
+// PoolMember is a user holding a share of one side of a contract with their own VTXO.
+// The side's founding party (BuyerID or SellerID) holds whatever share the members do not.
+type PoolMember struct {
+	UserID   string    `json:"user_id"`
+	VTXOID   string    `json:"vtxo_id"`
+	Size     float64   `json:"size"`      // Share of the side's contract size in BTC
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// SettlementMethod determines how the settlement rate of a contract is derived
+type SettlementMethod string
+
+const (
+	SETTLEMENT_SPOT SettlementMethod = "SPOT" // Hash rate of the expiry block alone
+	SETTLEMENT_TWAP SettlementMethod = "TWAP" // Hash rate averaged over the blocks leading up to expiry
+)
+
+// FeePriority selects how quickly a broadcast transaction should confirm, trading off fees
+type FeePriority string
+
+const (
+	FEE_PRIORITY_SLOW   FeePriority = "slow"   // Confirm within about a day
+	FEE_PRIORITY_NORMAL FeePriority = "normal" // Confirm within about an hour
+	FEE_PRIORITY_FAST   FeePriority = "fast"   // Confirm within the next couple of blocks
+)
+
 // VTXO represents a Virtual Transaction Output used in the contract system
 type VTXO struct {
 	ID                string    `json:"id"`
 	ContractID        string    `json:"contract_id"`
 	OwnerID           string    `json:"owner_id"`
 	Amount            float64   `json:"amount"`            // Amount in BTC
+	AmountSats        int64     `json:"amount_sats"`       // Amount in satoshis
 	ScriptPath        string    `json:"script_path"`       // The Taproot script path
 	CreationTimestamp time.Time `json:"creation_timestamp"`
 	SignatureData     []byte    `json:"signature_data"`    // Signature data for the VTXO
@@ -76,8 +133,17 @@ type VTXO struct {
 	IsActive          bool      `json:"is_active"`
 	ExitTxHash        string    `json:"exit_tx_hash,omitempty"` // Exit transaction hash if exited
 	ExitTimestamp     time.Time `json:"exit_timestamp,omitempty"` // When the VTXO was exited
+	Version           uint64    `json:"version"` // Incremented by every update, guards against concurrent writes
 }
 
+// VTXOHistoryDirection selects how much of a VTXO's lineage is returned
+type VTXOHistoryDirection string
+
+const (
+	HISTORY_ANCESTORS    VTXOHistoryDirection = "ANCESTORS"    // The VTXO and everything it was swapped or rolled from
+	HISTORY_FULL_LINEAGE VTXOHistoryDirection = "FULL_LINEAGE" // Ancestors plus the VTXOs it was rolled into
+)
+
 // OrderType represents whether an order is a buy or sell order
 type OrderType string
 
@@ -110,6 +176,9 @@ type Order struct {
 	CreationTime       time.Time   `json:"creation_time"`
 	MatchedOrderID     string      `json:"matched_order_id,omitempty"`
 	ResultingContractID string     `json:"resulting_contract_id,omitempty"`
+	ClientOrderID      string      `json:"client_order_id,omitempty"` // Caller-supplied ID used to dedup retried submissions
+	SelfTradePolicy    SelfTradePolicy `json:"self_trade_policy,omitempty"` // Handling of a match with the user's own order, empty for the default
+	TimeInForce        TimeInForce `json:"time_in_force,omitempty"` // GTC, IOC or FOK, see TimeInForce
 }
 
 // SwapOfferStatus represents the current status of a swap offer
@@ -144,11 +213,21 @@ type TransactionType string
 
 const (
 	CONTRACT_CREATION   TransactionType = "CONTRACT_CREATION"
+	CONTRACT_FUNDING    TransactionType = "CONTRACT_FUNDING" // The setup transaction was broadcast
+	CONTRACT_CANCELLATION TransactionType = "CONTRACT_CANCELLATION" // A PENDING contract was canceled before funding
+	FEE_COLLECTION      TransactionType = "FEE_COLLECTION" // An exit or settlement fee was credited to the treasury
 	CONTRACT_SETTLEMENT TransactionType = "CONTRACT_SETTLEMENT"
 	VTXO_SWAP           TransactionType = "VTXO_SWAP"
+	VTXO_SPLIT          TransactionType = "VTXO_SPLIT" // A VTXO was split into child VTXOs
 	VTXO_ROLLOVER       TransactionType = "VTXO_ROLLOVER"
 	CONTRACT_ROLLOVER   TransactionType = "CONTRACT_ROLLOVER"
 	EXIT_PATH_EXECUTION TransactionType = "EXIT_PATH_EXECUTION"
+	LIQUIDATION         TransactionType = "LIQUIDATION"
+	SETTLEMENT_PROPOSAL TransactionType = "SETTLEMENT_PROPOSAL"
+	VTXO_SWEEP          TransactionType = "VTXO_SWEEP"
+	POOL_JOIN           TransactionType = "POOL_JOIN"
+	DYNAMIC_JOIN        TransactionType = "DYNAMIC_JOIN"
+	EXPIRY_NOTICE       TransactionType = "EXPIRY_NOTICE" // A contract came within the expiry notice window
 )
 
 // Transaction represents a transaction in the system
@@ -160,12 +239,39 @@ type Transaction struct {
 	UserIDs         []string         `json:"user_ids"`
 	TxHash          string           `json:"tx_hash,omitempty"`    // On-chain transaction hash, if applicable
 	Amount          float64          `json:"amount"`              // Amount in BTC
+	AmountSats      int64            `json:"amount_sats"`         // Amount in satoshis
 	BTCPerPHPerDay  float64          `json:"btc_ph_day,omitempty"`// Rate at transaction time
 	BlockHeight     uint64           `json:"block_height,omitempty"`
 	Status          string           `json:"status,omitempty"`    // Transaction status
 	RelatedEntities map[string]string `json:"related_entities,omitempty"` // Related VTXOs, contracts, etc.
 }
 
+// Transaction.Status values
+const (
+	TX_STATUS_PENDING   = "PENDING"   // Still being processed by the operation that recorded it
+	TX_STATUS_COMPLETED = "COMPLETED" // Processed, awaiting confirmation if broadcast
+	TX_STATUS_CONFIRMED = "CONFIRMED" // Reached the required number of confirmations
+	TX_STATUS_FAILED    = "FAILED"    // Conflicted with a transaction that was mined instead
+)
+
+// ConfirmationStatus is the on-chain state of a broadcast transaction
+type ConfirmationStatus string
+
+const (
+	CONFIRMATION_PENDING   ConfirmationStatus = "pending"
+	CONFIRMATION_CONFIRMED ConfirmationStatus = "confirmed"
+	CONFIRMATION_FAILED    ConfirmationStatus = "failed"
+)
+
+// TransactionStatus reports how far a broadcast transaction has confirmed
+type TransactionStatus struct {
+	TransactionID         string             `json:"transaction_id"`
+	TxHash                string             `json:"tx_hash"`
+	Status                ConfirmationStatus `json:"status"`
+	Confirmations         uint64             `json:"confirmations"`
+	RequiredConfirmations uint64             `json:"required_confirmations"`
+}
+
 // SwapOfferMarketData represents aggregated market data for swap offers
 type SwapOfferMarketData struct {
 	ContractID      string    `json:"contract_id"`
@@ -180,6 +286,267 @@ type SwapOfferMarketData struct {
 	SellerOffers    int       `json:"seller_offers"`
 }
 
+// SettlementResult summarizes the outcome of a settled contract
+type SettlementResult struct {
+	ContractID       string    `json:"contract_id"`
+	TransactionID    string    `json:"transaction_id"`
+	TxHash           string    `json:"tx_hash"`
+	SettlementRate   float64   `json:"settlement_rate"`   // BTC/PH/day at the expiry block
+	BlockHeight      uint64    `json:"block_height"`
+	Timestamp        time.Time `json:"timestamp"`
+	WinnerID         string    `json:"winner_id"`         // Empty when the contract settled at the money
+	BuyerPayout      float64   `json:"buyer_payout"`      // In BTC
+	SellerPayout     float64   `json:"seller_payout"`     // In BTC
+	FiatCurrency     string    `json:"fiat_currency,omitempty"`
+	BTCFiatPrice     float64   `json:"btc_fiat_price,omitempty"` // BTC price captured at settlement
+	BuyerPayoutFiat  float64   `json:"buyer_payout_fiat,omitempty"`
+	SellerPayoutFiat float64   `json:"seller_payout_fiat,omitempty"`
+}
+
+// Health statuses
+const (
+	HEALTH_HEALTHY   = "healthy"
+	HEALTH_UNHEALTHY = "unhealthy"
+)
+
+// DependencyHealth is the result of checking one dependency of the service
+type DependencyHealth struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HealthReport is the readiness of the service and each of its dependencies
+type HealthReport struct {
+	Status               string             `json:"status"` // Healthy only if every dependency is healthy
+	BlockHeight          uint64             `json:"block_height"`
+	InitialBlockDownload bool               `json:"initial_block_download"`
+	Dependencies         []DependencyHealth `json:"dependencies"`
+	CheckedAt            time.Time          `json:"checked_at"`
+}
+
+// SettlementPreview is the outcome a contract would settle with at its expiry block,
+// computed without broadcasting or recording anything
+type SettlementPreview struct {
+	ContractID       string  `json:"contract_id"`
+	SettlementRate   float64 `json:"settlement_rate"` // BTC/PH/day at the expiry block
+	BlockHeight      uint64  `json:"block_height"`
+	WinnerID         string  `json:"winner_id"`       // Empty when the contract would settle at the money
+	BuyerPayout      float64 `json:"buyer_payout"`    // In BTC
+	SellerPayout     float64 `json:"seller_payout"`   // In BTC
+	BuyerLiquidated  bool    `json:"buyer_liquidated"`
+	SellerLiquidated bool    `json:"seller_liquidated"`
+	SettlementFee    float64 `json:"settlement_fee,omitempty"` // Charged on the winning side's profit, in BTC
+	Payouts          map[string]float64 `json:"payouts,omitempty"` // Per user, when either side is pooled
+}
+
+// MarketView is a consistent snapshot of a single market (contract type and expiry),
+// captured at one block height and within one database read
+type MarketView struct {
+	ContractType      ContractType `json:"contract_type"`
+	ExpiryBlockHeight uint64       `json:"expiry_block_height"`
+	BlockHeight       uint64       `json:"block_height"`    // Block height the view was captured at
+	BlockHeightStale  bool         `json:"block_height_stale,omitempty"` // The node was unreachable, BlockHeight is the last known height
+	Timestamp         time.Time    `json:"timestamp"`
+	Bids              []*Order     `json:"bids"`            // Open buy orders, best price first
+	Asks              []*Order     `json:"asks"`            // Open sell orders, best price first
+	OpenInterest      float64      `json:"open_interest"`   // Total size of active contracts in BTC
+	ActiveContracts   int          `json:"active_contracts"`
+	ImpliedRate       float64      `json:"implied_rate"`    // Mid of best bid and ask in BTC/PH/day
+	IndexRate         float64      `json:"index_rate"`      // Current BTC/PH/day from network hash rate
+}
+
+// ContractTotals is the number and total size of a group of contracts
+type ContractTotals struct {
+	Count int     `json:"count"`
+	Size  float64 `json:"size"` // Total size in BTC
+}
+
+// ContractFilter selects contracts for SearchContracts. Zero-valued fields do not filter,
+// ranges are inclusive and either bound may be left open.
+type ContractFilter struct {
+	ContractType     ContractType     `json:"contract_type,omitempty"`
+	Statuses         []ContractStatus `json:"statuses,omitempty"`
+	MinStrikeRate    float64          `json:"min_strike_rate,omitempty"`
+	MaxStrikeRate    float64          `json:"max_strike_rate,omitempty"`
+	MinSize          float64          `json:"min_size,omitempty"`
+	MaxSize          float64          `json:"max_size,omitempty"`
+	FromExpiryHeight uint64           `json:"from_expiry_height,omitempty"`
+	ToExpiryHeight   uint64           `json:"to_expiry_height,omitempty"`
+	UserID           string           `json:"user_id,omitempty"`         // Buyer or seller
+	CounterpartyID   string           `json:"counterparty_id,omitempty"` // The other party of UserID, or either party without one
+}
+
+// SettlementHistoryPoint aggregates the contracts settled at one expiry block
+type SettlementHistoryPoint struct {
+	ExpiryBlockHeight uint64  `json:"expiry_block_height"`
+	ContractCount     int     `json:"contract_count"`
+	TotalSize         float64 `json:"total_size"`      // Total size of the settled contracts in BTC
+	SettlementRate    float64 `json:"settlement_rate"` // Size-weighted average settlement rate in BTC/PH/day
+	MinRate           float64 `json:"min_rate"`        // Settlement rates differ within a block only across settlement methods
+	MaxRate           float64 `json:"max_rate"`
+}
+
+// PnLPoint is the unrealized PnL of both sides of a contract at one recorded hash rate
+type PnLPoint struct {
+	BlockHeight    uint64    `json:"block_height"`
+	Timestamp      time.Time `json:"timestamp"`
+	BTCPerPHPerDay float64   `json:"btc_ph_day"`
+	BuyerPnL       float64   `json:"buyer_pnl"`  // Capped at the collateral posted, like the settlement payout
+	SellerPnL      float64   `json:"seller_pnl"`
+}
+
+// PositionSummary is a user's share of one side of an active contract, valued at the current rate
+type PositionSummary struct {
+	ContractID        string       `json:"contract_id"`
+	ContractType      ContractType `json:"contract_type"`
+	Side              PositionSide `json:"side"`
+	ExpiryBlockHeight uint64       `json:"expiry_block_height"`
+	StrikeRate        float64      `json:"strike_rate"`
+	Size              float64      `json:"size"`       // The user's share of the side's size in BTC
+	Collateral        float64      `json:"collateral"` // Collateral backing the user's share in BTC
+	PnL               float64      `json:"pnl"`        // Capped at the collateral posted, like the settlement payout
+}
+
+// TypeExposure is the notional a user holds on each side of one contract type
+type TypeExposure struct {
+	Long  float64 `json:"long"`  // Buyer notional in BTC
+	Short float64 `json:"short"` // Seller notional in BTC
+}
+
+// UserPositionSummary is a user's net exposure across every active contract
+type UserPositionSummary struct {
+	UserID          string                         `json:"user_id"`
+	Timestamp       time.Time                      `json:"timestamp"`
+	BlockHeight     uint64                         `json:"block_height"`
+	BTCPerPHPerDay  float64                        `json:"btc_ph_day"` // Rate the positions are valued at
+	Positions       []*PositionSummary             `json:"positions"`
+	ExposureByType  map[ContractType]*TypeExposure `json:"exposure_by_type"`
+	NetExposure     float64                        `json:"net_exposure"`     // Notional gaining as the rate rises, less notional gaining as it falls
+	TotalCollateral float64                        `json:"total_collateral"` // Collateral locked across every position in BTC
+	TotalPnL        float64                        `json:"total_pnl"`
+}
+
+// OpenInterestBucket aggregates the active contracts at one expiry block and strike rate
+type OpenInterestBucket struct {
+	ExpiryBlockHeight uint64  `json:"expiry_block_height"`
+	StrikeRate        float64 `json:"strike_rate"` // Strike rate in BTC/PH/day
+	ContractCount     int     `json:"contract_count"`
+	TotalSize         float64 `json:"total_size"` // Total size of the contracts in BTC
+}
+
+// MarketSummary is a high-level view of every market for dashboards
+type MarketSummary struct {
+	Timestamp             time.Time            `json:"timestamp"`
+	BlockHeight           uint64               `json:"block_height"`
+	OpenInterest          float64              `json:"open_interest"`            // Total size of active contracts in BTC
+	ActiveContracts       int                  `json:"active_contracts"`
+	ActiveContractsByType map[ContractType]int `json:"active_contracts_by_type"`
+	SettledVolume24h      float64              `json:"settled_volume_24h"`       // Size of contracts settled in the last 24 hours in BTC
+	Settlements24h        int                  `json:"settlements_24h"`
+	HashRate              float64              `json:"hash_rate"`                // Current hash rate in PH/s
+	BTCPerPHPerDay        float64              `json:"btc_ph_day"`               // Current BTC per PetaHash per Day rate
+}
+
+// PlatformStats is a count of the live entities across every market
+type PlatformStats struct {
+	Timestamp       time.Time `json:"timestamp"`
+	ActiveContracts int       `json:"active_contracts"` // Including those close to expiry
+	OpenOrders      int       `json:"open_orders"`
+	OpenSwapOffers  int       `json:"open_swap_offers"`
+}
+
+// OrderBookLevel is the total open size resting at one strike rate
+type OrderBookLevel struct {
+	StrikeRate     float64 `json:"strike_rate"`
+	Size           float64 `json:"size"`            // Open size at this rate in BTC
+	CumulativeSize float64 `json:"cumulative_size"` // Open size at this rate and every better rate in BTC
+	OrderCount     int     `json:"order_count"`
+}
+
+// OrderBookDepth is the order book of a market aggregated into price levels
+type OrderBookDepth struct {
+	ContractType      ContractType      `json:"contract_type"`
+	ExpiryBlockHeight uint64            `json:"expiry_block_height"`
+	Bids              []*OrderBookLevel `json:"bids"`     // Buy levels, best (highest) rate first
+	Asks              []*OrderBookLevel `json:"asks"`     // Sell levels, best (lowest) rate first
+	BestBid           float64           `json:"best_bid"` // Zero when there are no bids
+	BestAsk           float64           `json:"best_ask"` // Zero when there are no asks
+	Spread            float64           `json:"spread"`   // Best ask minus best bid, zero unless both sides are quoted
+}
+
+// Quote is the top of a market's order book, the best bid and ask with the size resting at each
+type Quote struct {
+	ContractType      ContractType `json:"contract_type"`
+	ExpiryBlockHeight uint64       `json:"expiry_block_height"`
+	BestBid           float64      `json:"best_bid"`      // Zero when there are no bids
+	BestBidSize       float64      `json:"best_bid_size"` // Open size at the best bid in BTC
+	BestAsk           float64      `json:"best_ask"`      // Zero when there are no asks
+	BestAskSize       float64      `json:"best_ask_size"` // Open size at the best ask in BTC
+	MidPrice          float64      `json:"mid_price"`     // Mean of best bid and ask, zero unless both sides are quoted
+	Timestamp         time.Time    `json:"timestamp"`
+}
+
+// ContractTemplate is a named product preset that contracts can be created from
+type ContractTemplate struct {
+	ID               string           `json:"id"`
+	Name             string           `json:"name"`
+	ContractType     ContractType     `json:"contract_type"`
+	MinStrikeRate    float64          `json:"min_strike_rate"`
+	MaxStrikeRate    float64          `json:"max_strike_rate"`
+	ExpiryOffsets    []uint64         `json:"expiry_offsets"` // Blocks after creation, the first is the default
+	MinSize          float64          `json:"min_size"`
+	MaxSize          float64          `json:"max_size"`
+	DefaultSize      float64          `json:"default_size"`
+	Leverage         float64          `json:"leverage"`
+	SettlementMethod SettlementMethod `json:"settlement_method"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+// ContractScripts are the scripts generated for a contract, kept so clients can build
+// pre-signed exits and verify the spending conditions of their position after creation
+type ContractScripts struct {
+	ContractID       string            `json:"contract_id"`
+	BuyerScriptPath  string            `json:"buyer_script_path"`
+	SellerScriptPath string            `json:"seller_script_path"`
+	ExitScripts      map[string]string `json:"exit_scripts"` // Every other generated script, keyed by the generator's name
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// WebhookSubscription is a user's callback URL for transaction notifications
+type WebhookSubscription struct {
+	ID         string            `json:"id"`
+	UserID     string            `json:"user_id"`
+	URL        string            `json:"url"`
+	EventTypes []TransactionType `json:"event_types,omitempty"` // Transaction types to deliver, empty for all
+	Secret     string            `json:"secret,omitempty"`      // HMAC signing key, only returned at registration
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// WebhookDeliveryStatus represents the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	DELIVERY_PENDING   WebhookDeliveryStatus = "PENDING"
+	DELIVERY_DELIVERED WebhookDeliveryStatus = "DELIVERED"
+	DELIVERY_FAILED    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookDelivery is one transaction notification queued for a webhook subscription
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	SubscriptionID string                `json:"subscription_id"`
+	TransactionID  string                `json:"transaction_id"`
+	Payload        []byte                `json:"-"` // Signed request body, identical on every attempt
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	LastError      string                `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	DeliveredAt    time.Time             `json:"delivered_at,omitempty"`
+}
+
 
 // =============================================================================
 // CONTRACT MANAGEMENT API INTERFACES
@@ -187,27 +554,139 @@ type SwapOfferMarketData struct {
 
 // ContractManager handles the lifecycle of contracts
 type ContractManager interface {
-	// CreateContract creates a new contract between two parties
+	// CreateContract creates a new contract between two parties. A leverage of 0 defaults to 1x
+	// and an empty settlement method defaults to SPOT.
+	// A non-empty idempotency key that was already used returns the contract created with it.
+	// It locks both parties' collateral, so only order matching and operators may call it.
 	CreateContract(ctx context.Context, buyerID, sellerID string, contractType ContractType, 
-		strikeRate float64, expiryBlockHeight uint64, size float64) (*Contract, error)
+		strikeRate float64, expiryBlockHeight uint64, size float64, leverage float64,
+		settlementMethod SettlementMethod, exitFeeRate *float64, idempotencyKey string) (*Contract, error)
 	
 	// GetContract retrieves a contract by ID
 	GetContract(ctx context.Context, contractID string) (*Contract, error)
 	
-	// GetContractsByUser retrieves all contracts for a specific user
-	GetContractsByUser(ctx context.Context, userID string, status []ContractStatus) ([]*Contract, error)
+	// GetContractsByUser retrieves a page of contracts for a specific user and the cursor of the next page.
+	// Contracts archived after a failed creation are only included if includeArchived is set.
+	GetContractsByUser(ctx context.Context, userID string, status []ContractStatus, includeArchived bool, page PageRequest) ([]*Contract, string, error)
+	
+	// SearchContracts retrieves a page of contracts matching every criterion of a filter,
+	// returning the cursor of the next page. The filter must select by a party or a bounded
+	// expiry range, broader searches fail with ErrInvalidParameters.
+	SearchContracts(ctx context.Context, filter ContractFilter, page PageRequest) ([]*Contract, string, error)
+	
+	// GetContractsByExpiryRange retrieves active contracts expiring between two block heights, inclusive.
+	// The range may span at most maxExpiryRangeSpan blocks.
+	GetContractsByExpiryRange(ctx context.Context, fromHeight, toHeight uint64) ([]*Contract, error)
+	
+	// GetContractsNearingExpiry retrieves active contracts expiring within the next withinBlocks blocks
+	GetContractsNearingExpiry(ctx context.Context, withinBlocks uint64) ([]*Contract, error)
+	
+	// GetSettlementHistory retrieves the settlement rates of SETTLED contracts aggregated per
+	// expiry block, oldest first. An empty contract type includes both CALL and PUT contracts.
+	// The range may span at most maxSettlementHistorySpan blocks.
+	GetSettlementHistory(ctx context.Context, contractType ContractType, fromHeight, toHeight uint64) ([]*SettlementHistoryPoint, error)
+	
+	// GetOpenInterest retrieves the open interest of active contracts grouped by expiry block
+	// and strike rate. An empty contract type includes both CALL and PUT contracts.
+	GetOpenInterest(ctx context.Context, contractType ContractType) ([]*OpenInterestBucket, error)
+	
+	// GetContractPnLHistory retrieves the unrealized PnL of a contract from its creation until
+	// now or its expiry, sampled every intervalBlocks blocks from the recorded hash rates
+	GetContractPnLHistory(ctx context.Context, contractID string, intervalBlocks uint64) ([]*PnLPoint, error)
+	
+	// GetUserPositionSummary values a user's positions in every active contract, as a founding
+	// party or pool member, at the current rate and aggregates their exposure by contract type
+	GetUserPositionSummary(ctx context.Context, userID string) (*UserPositionSummary, error)
+	
+	// FundContract verifies both parties' signatures over the fund message, encodeSigningMessage
+	// with domain "fund" and fields contract ID, user ID and the party's VTXO ID, stores the contract's setup transaction as FUNDING,
+	// broadcasts it and moves the contract to ACTIVE. A FUNDING contract whose broadcast failed
+	// is retried by calling it again, which rebroadcasts the stored transaction.
+	FundContract(ctx context.Context, contractID string, buyerSignature, sellerSignature []byte) (*Transaction, error)
+	
+	// CancelContract cancels a PENDING contract that was never funded on behalf of one of its
+	// parties, deactivating its VTXOs and returning their collateral
+	CancelContract(ctx context.Context, contractID string, userID string) (*Transaction, error)
+	
+	// GetContractScripts retrieves the scripts generated for a contract, regenerating and
+	// storing them if they were never stored
+	GetContractScripts(ctx context.Context, contractID string) (*ContractScripts, error)
 	
-	// SettleContract settles a contract based on the current hash rate data
-	SettleContract(ctx context.Context, contractID string) (*Transaction, error)
+	// SettleContract settles a contract based on the current hash rate data. The fee priority
+	// sets the confirmation target of the broadcast transactions, empty means normal.
+	SettleContract(ctx context.Context, contractID string, feePriority FeePriority) (*Transaction, error)
 	
 	// ExitContract allows a user to exit a contract before expiration
 	ExitContract(ctx context.Context, contractID string, userID string) (*Transaction, error)
 	
-	// RolloverContract rolls over a contract to a new expiration
+	// RolloverContract rolls over a contract to a new expiration. The new contract is PENDING
+	// until both parties fund it with FundContract.
 	RolloverContract(ctx context.Context, contractID string, newExpiryBlockHeight uint64) (*Contract, *Transaction, error)
 	
-	// ExecuteExitPath handles non-cooperative settlement via an exit path
-	ExecuteExitPath(ctx context.Context, contractID string, userID string, exitPathType string) (*Transaction, error)
+	// JoinContractSide adds a user to the pool of one side of an active contract, taking amount
+	// BTC of the side's size from its founding party along with the collateral backing it
+	JoinContractSide(ctx context.Context, contractID string, userID string, side PositionSide, amount float64, signature []byte) (*Contract, *Transaction, error)
+	
+	// RequestDynamicJoin lets a user take the open side of an active contract, rejecting the
+	// join with ErrDynamicJoinRejected when it violates the contract's rules
+	RequestDynamicJoin(ctx context.Context, contractID string, userID string, side PositionSide, signature []byte) (*Contract, *Transaction, error)
+	
+	// CreateContractTemplate validates and publishes a contract template
+	CreateContractTemplate(ctx context.Context, template *ContractTemplate) (*ContractTemplate, error)
+	
+	// GetContractTemplates retrieves all published contract templates
+	GetContractTemplates(ctx context.Context) ([]*ContractTemplate, error)
+	
+	// CreateContractFromTemplate creates a contract with the template's type, default size,
+	// leverage, settlement method and first expiry offset, validating the strike against
+	// the template's strike range. Like CreateContract only operators may call it.
+	CreateContractFromTemplate(ctx context.Context, templateID string, buyerID, sellerID string, strikeRate float64) (*Contract, error)
+	
+	// ExecuteExitPath handles non-cooperative settlement via an exit path. The fee priority
+	// sets the confirmation target of the exit transaction, empty means normal. Liquidation
+	// is not a requestable path, only CheckLiquidations force-settles positions.
+	ExecuteExitPath(ctx context.Context, contractID string, userID string, exitPathType string, feePriority FeePriority) (*Transaction, error)
+	
+	// CheckLiquidations force-settles active positions whose losses breach the maintenance margin
+	CheckLiquidations(ctx context.Context) ([]*Transaction, error)
+	
+	// SettleExpiredContracts settles every contract whose expiry block has been reached,
+	// including pending settlements whose dispute window has elapsed
+	SettleExpiredContracts(ctx context.Context) ([]*Transaction, error)
+	
+	// GetContractsRequiringSettlement retrieves active contracts whose expiry block has been
+	// reached, the settlement backlog
+	GetContractsRequiringSettlement(ctx context.Context) ([]*Contract, error)
+	
+	// AutoSettleExpired settles every contract requiring settlement, continuing past failures,
+	// and reports which contracts were settled, deferred and failed
+	AutoSettleExpired(ctx context.Context) (*AutoSettleSummary, error)
+	
+	// NotifyContractsNearingExpiry moves ACTIVE contracts within the expiry notice window to
+	// CLOSE_TO_EXPIRY, notifying their parties, and returns the contracts it moved
+	NotifyContractsNearingExpiry(ctx context.Context) ([]*Contract, error)
+	
+	// ReconcileContract compares a contract's recorded transactions, exits and VTXO states
+	// with the chain and reports every discrepancy. With autoCorrect, records whose correct
+	// state is unambiguous are updated to match the chain.
+	ReconcileContract(ctx context.Context, contractID string, autoCorrect bool) (*ReconciliationReport, error)
+	
+	// GetSettlementResult retrieves the payouts recorded when a contract was settled
+	GetSettlementResult(ctx context.Context, contractID string) (*SettlementResult, error)
+	
+	// PreviewSettlement computes the winner and payouts a contract would settle with,
+	// without broadcasting or recording anything
+	PreviewSettlement(ctx context.Context, contractID string) (*SettlementPreview, error)
+	
+	// ConfirmSettlement lets the proposed loser waive the dispute window and finalize settlement
+	ConfirmSettlement(ctx context.Context, contractID string, userID string) (*Transaction, error)
+	
+	// DisputeSettlement lets the proposed loser challenge a pending settlement through the dispute
+	// resolution exit path before its dispute deadline
+	DisputeSettlement(ctx context.Context, contractID string, userID string, evidence string) (*Transaction, error)
+	
+	// SubscribeToContract streams status changes for a contract until the returned cancel function is called
+	SubscribeToContract(contractID string) (<-chan ContractStatusEvent, func())
 }
 
 // =============================================================================
@@ -226,17 +705,49 @@ type VTXOManager interface {
 	// GetVTXOsByContract retrieves all VTXOs for a specific contract
 	GetVTXOsByContract(ctx context.Context, contractID string) ([]*VTXO, error)
 	
-	// GetVTXOsByUser retrieves all VTXOs for a specific user
-	GetVTXOsByUser(ctx context.Context, userID string, onlyActive bool) ([]*VTXO, error)
+	// GetUserVTXOInContract retrieves the active VTXO a user holds in a contract, or nil if
+	// the user holds none
+	GetUserVTXOInContract(ctx context.Context, contractID string, userID string) (*VTXO, error)
 	
-	// SwapVTXO swaps a VTXO between two users (off-chain)
+	// GetVTXOsByUser retrieves a page of VTXOs for a specific user and the cursor of the next page.
+	// VTXOs archived after a failed operation are only included if includeArchived is set.
+	GetVTXOsByUser(ctx context.Context, userID string, onlyActive bool, includeArchived bool, page PageRequest) ([]*VTXO, string, error)
+	
+	// SwapVTXO swaps a VTXO between two users (off-chain). The new owner signs the swap message,
+	// encodeSigningMessage with domain "swap" and fields VTXO ID, new owner ID and contract ID,
+	// with their registered key.
 	SwapVTXO(ctx context.Context, vtxoID string, newOwnerID string, newSignatureData []byte) (*VTXO, *Transaction, error)
 	
+	// SplitVTXO splits a VTXO into child VTXOs, one per owner, whose amounts sum exactly to
+	// the parent's. Each owner signs the split message, encodeSigningMessage with domain "split"
+	// and fields parent VTXO ID, owner ID and the owner's amount in satoshis.
+	// The first child takes the parent's place in the contract and the others join its side's pool.
+	SplitVTXO(ctx context.Context, vtxoID string, amounts []float64, owners []string, signatures [][]byte) ([]*VTXO, *Transaction, error)
+	
 	// CreatePresignedExitTransaction creates a pre-signed exit transaction for a VTXO
 	CreatePresignedExitTransaction(ctx context.Context, vtxoID string, signatureData []byte) (string, error)
 	
-	// ExecuteVTXOSweep executes a VTXO sweep in case of failure or non-cooperation
+	// GetPreSignedExit retrieves a pre-signed exit by ID
+	GetPreSignedExit(ctx context.Context, preSignedExitID string) (*PreSignedExit, error)
+	
+	// GetPreSignedExits retrieves the pre-signed exits of a VTXO, used or not
+	GetPreSignedExits(ctx context.Context, vtxoID string) ([]*PreSignedExit, error)
+	
+	// BroadcastPreSignedExit broadcasts a pre-signed exit, marks it used and records the
+	// exit, giving a user a unilateral exit that needs no cooperation from the operator
+	BroadcastPreSignedExit(ctx context.Context, preSignedExitID string) (*Transaction, error)
+	
+	// ExecuteVTXOSweep executes a VTXO sweep in case of failure or non-cooperation.
+	// Sweeping an already swept VTXO returns its existing sweep transaction.
 	ExecuteVTXOSweep(ctx context.Context, vtxoID string) (*Transaction, error)
+	
+	// SweepAllVTXOs sweeps every active VTXO of a user, skipping those not yet eligible and
+	// continuing past individual failures, and reports the outcome of each VTXO
+	SweepAllVTXOs(ctx context.Context, userID string) (*SweepSummary, error)
+	
+	// ReconcileSweeps finishes sweeps left PENDING by a failure after they were recorded
+	// and returns how many were finalized or failed
+	ReconcileSweeps(ctx context.Context) (int, error)
 }
 
 // =============================================================================
@@ -245,24 +756,57 @@ type VTXOManager interface {
 
 // OrderBookManager handles the order book functionality
 type OrderBookManager interface {
-	// PlaceOrder places a new order in the order book
+	// PlaceOrder places a new order in the order book. A non-empty clientOrderID makes
+	// the call idempotent per user. The self-trade policy decides what happens when the
+	// order would match the user's own order, empty uses the operator's default. IOC and
+	// FOK orders that do not match on placement are canceled instead of resting in the book.
 	PlaceOrder(ctx context.Context, userID string, orderType OrderType, contractType ContractType, 
-		strikeRate float64, expiryBlockHeight uint64, size float64) (*Order, error)
+		strikeRate float64, expiryBlockHeight uint64, size float64, clientOrderID string,
+		selfTradePolicy SelfTradePolicy, timeInForce TimeInForce) (*Order, error)
 	
 	// CancelOrder cancels an existing order
 	CancelOrder(ctx context.Context, orderID string, userID string) error
 	
+	// ReduceOrder lowers the size of an open order to newSize, leaving it open with its
+	// time priority. The new size must be positive and smaller than the current size.
+	ReduceOrder(ctx context.Context, orderID string, userID string, newSize float64) (*Order, error)
+	
+	// ModifyOrder changes the strike rate and size of an open order in place and matches it
+	// immediately if it now crosses the book. A price change, or a size increase, resets the
+	// order's time priority, a size decrease keeps it.
+	ModifyOrder(ctx context.Context, orderID string, userID string, newStrikeRate float64, newSize float64) (*Order, error)
+	
 	// GetOrder retrieves an order by ID
 	GetOrder(ctx context.Context, orderID string) (*Order, error)
 	
 	// GetOrdersByUser retrieves all orders for a specific user
 	GetOrdersByUser(ctx context.Context, userID string, status []OrderStatus) ([]*Order, error)
 	
-	// GetOrderBook retrieves the current order book for a given contract type and parameters
-	GetOrderBook(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) ([]*Order, error)
+	// GetOrderBook retrieves the open orders for a given contract type and parameters,
+	// sorted best price first on each side with time priority at equal prices, with the
+	// sequence number of the last change they reflect. A streaming client applies the
+	// updates above that sequence; updates already reflected apply idempotently by order ID.
+	GetOrderBook(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) (*OrderBookSnapshot, error)
+	
+	// SubscribeToOrderBook streams sequenced changes to a market's order book until the
+	// returned cancel function is called
+	SubscribeToOrderBook(contractType ContractType, expiryBlockHeight uint64) (<-chan OrderBookUpdate, func())
 	
 	// MatchOrders attempts to match buy and sell orders
 	MatchOrders(ctx context.Context) ([]*Contract, error)
+	
+	// ExpireStaleOrders moves open orders whose expiry block has been reached to EXPIRED,
+	// returning the number of orders expired
+	ExpireStaleOrders(ctx context.Context) (int, error)
+	
+	// GetMarketView retrieves the order book, open interest and rates for a market as one consistent snapshot
+	GetMarketView(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) (*MarketView, error)
+	
+	// GetOrderBookDepth retrieves the open orders for a market aggregated into price levels
+	GetOrderBookDepth(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) (*OrderBookDepth, error)
+	
+	// GetQuote retrieves the best bid and ask of a market, a lighter call than the full book or depth
+	GetQuote(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) (*Quote, error)
 }
 
 // =============================================================================
@@ -275,8 +819,10 @@ type SwapOfferManager interface {
 	CreateSwapOffer(ctx context.Context, offerorID string, vtxoID string, offeredRate float64, 
 		expiryTime time.Time) (*SwapOffer, error)
 	
-	// AcceptSwapOffer accepts a swap offer
-	AcceptSwapOffer(ctx context.Context, offerID string, acceptorID string) (*Transaction, error)
+	// AcceptSwapOffer accepts a swap offer. The acceptor signs the swap message,
+	// encodeSigningMessage with domain "swap" and fields VTXO ID, acceptor ID and contract ID,
+	// with their registered key.
+	AcceptSwapOffer(ctx context.Context, offerID string, acceptorID string, signatureData []byte) (*Transaction, error)
 	
 	// CancelSwapOffer cancels a swap offer
 	CancelSwapOffer(ctx context.Context, offerID string, offerorID string) error
@@ -284,11 +830,26 @@ type SwapOfferManager interface {
 	// GetSwapOffer retrieves a swap offer by ID
 	GetSwapOffer(ctx context.Context, offerID string) (*SwapOffer, error)
 	
-	// GetSwapOffersByUser retrieves all swap offers for a specific user
-	GetSwapOffersByUser(ctx context.Context, userID string, isOfferor bool) ([]*SwapOffer, error)
+	// GetSwapOffersByUser retrieves the swap offers for a specific user in the given statuses,
+	// or in any status if none are given
+	GetSwapOffersByUser(ctx context.Context, userID string, isOfferor bool, status []SwapOfferStatus) ([]*SwapOffer, error)
+	
+	// GetSwapOffersByContract retrieves a page of swap offers for a specific contract in the given
+	// statuses, or in any status if none are given, and the cursor of the next page
+	GetSwapOffersByContract(ctx context.Context, contractID string, status []SwapOfferStatus, page PageRequest) ([]*SwapOffer, string, error)
+	
+	// GetBestSwapOffer retrieves the open public offer with the lowest rate for one side of a contract
+	GetBestSwapOffer(ctx context.Context, contractID string, side PositionSide) (*SwapOffer, error)
 	
-	// GetSwapOffersByContract retrieves all swap offers for a specific contract
-	GetSwapOffersByContract(ctx context.Context, contractID string) ([]*SwapOffer, error)
+	// AutoAcceptBestOffer accepts the lowest-rate open offer on a VTXO at or below maxRate,
+	// falling back to the next best if another acceptor takes it first. The signature is the
+	// same one AcceptSwapOffer takes for the VTXO.
+	AutoAcceptBestOffer(ctx context.Context, vtxoID string, acceptorID string, maxRate float64,
+		signatureData []byte) (*SwapOffer, *Transaction, error)
+	
+	// InvalidateOffersForVTXO cancels every open offer on a VTXO that is no longer active,
+	// returning the number of offers canceled
+	InvalidateOffersForVTXO(ctx context.Context, vtxoID string) (int, error)
 }
 
 // =============================================================================
@@ -308,6 +869,12 @@ type MarketDataManager interface {
 	
 	// CalculateBTCPerPHPerDay calculates the BTC per PetaHash per Day rate
 	CalculateBTCPerPHPerDay(ctx context.Context, hashRate float64, blockHeight uint64) (float64, error)
+	
+	// GetAverageHashRate averages the network hash rate over the windowBlocks blocks ending at endBlockHeight
+	GetAverageHashRate(ctx context.Context, endBlockHeight uint64, windowBlocks uint64) (float64, error)
+	
+	// SubscribeToHashRate streams hash rate data for each new block until the returned cancel function is called
+	SubscribeToHashRate() (<-chan *HashRateData, func())
 }
 
 // =============================================================================
@@ -316,7 +883,8 @@ type MarketDataManager interface {
 
 // TransactionManager handles transaction recording and retrieval
 type TransactionManager interface {
-	// RecordTransaction records a new transaction
+	// RecordTransaction records a new transaction, returning the existing record instead if
+	// txHash was already recorded
 	RecordTransaction(ctx context.Context, transactionType TransactionType, contractID string, 
 		userIDs []string, txHash string, amount float64, btcPerPHPerDay float64, 
 		blockHeight uint64, relatedEntities map[string]string) (*Transaction, error)
@@ -324,11 +892,45 @@ type TransactionManager interface {
 	// GetTransaction retrieves a transaction by ID
 	GetTransaction(ctx context.Context, transactionID string) (*Transaction, error)
 	
-	// GetTransactionsByUser retrieves all transactions for a specific user
-	GetTransactionsByUser(ctx context.Context, userID string, transactionTypes []TransactionType) ([]*Transaction, error)
+	// GetTransactionsByUser retrieves a page of transactions for a specific user and the cursor of the next page
+	GetTransactionsByUser(ctx context.Context, userID string, transactionTypes []TransactionType, page PageRequest) ([]*Transaction, string, error)
+	
+	// GetTransactionsByContract retrieves the transactions of a specific contract ordered by
+	// timestamp, oldest first unless order is SORT_DESCENDING. An empty types list returns every type.
+	GetTransactionsByContract(ctx context.Context, contractID string, transactionTypes []TransactionType, order SortOrder) ([]*Transaction, error)
 	
-	// GetTransactionsByContract retrieves all transactions for a specific contract
-	GetTransactionsByContract(ctx context.Context, contractID string) ([]*Transaction, error)
+	// GetTransactionsByType retrieves every transaction of a specific type
+	GetTransactionsByType(ctx context.Context, transactionType TransactionType) ([]*Transaction, error)
+	
+	// GetTransactionsByTimeRange retrieves the transactions recorded within a time range
+	GetTransactionsByTimeRange(ctx context.Context, startTime, endTime time.Time) ([]*Transaction, error)
+	
+	// GetTransactionsByBlockRange retrieves the transactions recorded at a block height within
+	// an inclusive range, ordered by block height, for reconciling on-chain activity
+	GetTransactionsByBlockRange(ctx context.Context, fromHeight, toHeight uint64) ([]*Transaction, error)
+}
+
+// =============================================================================
+// WEBHOOK API INTERFACES
+// =============================================================================
+
+// WebhookManager notifies users of their transactions through signed webhook callbacks
+type WebhookManager interface {
+	// RegisterWebhook registers an HTTPS callback URL for a user's transactions of the given
+	// types (all types if empty). The returned subscription carries the signing secret.
+	RegisterWebhook(ctx context.Context, userID string, url string, eventTypes []TransactionType) (*WebhookSubscription, error)
+	
+	// GetWebhooksByUser retrieves a user's webhook subscriptions, without their secrets
+	GetWebhooksByUser(ctx context.Context, userID string) ([]*WebhookSubscription, error)
+	
+	// DeleteWebhook removes a user's webhook subscription
+	DeleteWebhook(ctx context.Context, webhookID string, userID string) error
+	
+	// NotifyTransaction queues a delivery to every subscription of the users involved in a transaction
+	NotifyTransaction(ctx context.Context, tx *Transaction)
+	
+	// DeliverPendingWebhooks attempts every delivery that is due, returning the number delivered
+	DeliverPendingWebhooks(ctx context.Context) (int, error)
 }
 
 // =============================================================================
@@ -340,17 +942,22 @@ type ScriptGenerator interface {
 	// GenerateContractScripts generates all necessary scripts for a contract
 	GenerateContractScripts(ctx context.Context, contract *Contract) (map[string]string, error)
 	
-	// GenerateSetupTransaction generates the setup transaction script for a contract
-	GenerateSetupTransaction(ctx context.Context, contract *Contract, buyerVTXO, sellerVTXO *VTXO) (string, error)
+	// GenerateSetupTransaction generates the setup transaction script for a contract,
+	// reserving a fee at feeRate satoshis per byte
+	GenerateSetupTransaction(ctx context.Context, contract *Contract, buyerVTXO, sellerVTXO *VTXO, feeRate uint64) (string, error)
 	
-	// GenerateFinalTransaction generates the final transaction script for a contract
-	GenerateFinalTransaction(ctx context.Context, contract *Contract, setupTxID string) (string, error)
+	// GenerateFinalTransaction generates the final transaction script for a contract,
+	// reserving a fee at feeRate satoshis per byte
+	GenerateFinalTransaction(ctx context.Context, contract *Contract, setupTxID string, feeRate uint64) (string, error)
 	
-	// GenerateSettlementTransaction generates the settlement transaction script for a contract
-	GenerateSettlementTransaction(ctx context.Context, contract *Contract, finalTxID string, winnerID string) (string, error)
+	// GenerateSettlementTransaction generates the settlement transaction script for a contract,
+	// reserving a fee at feeRate satoshis per byte.
+	// An empty winnerID means the contract settled at the money and both parties are refunded.
+	GenerateSettlementTransaction(ctx context.Context, contract *Contract, finalTxID string, winnerID string, feeRate uint64) (string, error)
 	
-	// GenerateExitPathScripts generates scripts for all exit paths
-	GenerateExitPathScripts(ctx context.Context, contract *Contract) (map[string]string, error)
+	// GenerateExitPathScripts generates scripts for all exit paths, reserving a fee at
+	// feeRate satoshis per byte
+	GenerateExitPathScripts(ctx context.Context, contract *Contract, feeRate uint64) (map[string]string, error)
 }
 
 // =============================================================================
@@ -365,14 +972,44 @@ type HashPerpService interface {
 	SwapOfferManager
 	MarketDataManager
 	TransactionManager
+	WebhookManager
 	ScriptGenerator
 	
-	// Healthcheck verifies the service is functioning properly
-	Healthcheck(ctx context.Context) error
+	// Healthcheck checks each dependency of the service and reports its status and latency
+	Healthcheck(ctx context.Context) *HealthReport
+	
+	// GetTransactionStatus reports the confirmations of a broadcast transaction and whether
+	// it is pending, confirmed or failed
+	GetTransactionStatus(ctx context.Context, transactionID string) (*TransactionStatus, error)
+	
+	// RegisterUserKey registers or rotates the secp256k1 public key a user's signatures are
+	// verified against. The new key signs the register key message, encodeSigningMessage with
+	// domain "register_key" and fields user ID and the hex public key, and when rotating the
+	// currently registered key signs it too.
+	RegisterUserKey(ctx context.Context, userID string, pubKey, signature, currentKeySignature []byte) error
+	
+	// GetUserKey retrieves a user's registered public key
+	GetUserKey(ctx context.Context, userID string) ([]byte, error)
+	
+	// GetMarketSummary aggregates open interest, active contracts, settled volume over the
+	// last 24 hours and the current rates across every market
+	GetMarketSummary(ctx context.Context) (*MarketSummary, error)
+	
+	// GetPlatformStats counts active contracts, open orders and open swap offers across
+	// every market
+	GetPlatformStats(ctx context.Context) (*PlatformStats, error)
+	
+	// GetAuditTrail retrieves every status change recorded for a contract, order, swap offer
+	// or VTXO, oldest first
+	GetAuditTrail(ctx context.Context, entityType AuditEntityType, entityID string) ([]*StatusChange, error)
 	
 	// GetCurrentBlockHeight retrieves the current Bitcoin block height
 	GetCurrentBlockHeight(ctx context.Context) (uint64, error)
 	
+	// GetBlockHeight retrieves the current Bitcoin block height for reads, the last known
+	// height flagged as stale while the node is unreachable
+	GetBlockHeight(ctx context.Context) (*BlockHeightReading, error)
+	
 	// ValidateContractParameters validates that contract parameters are valid
 	ValidateContractParameters(ctx context.Context, contractType ContractType, 
 		strikeRate float64, expiryBlockHeight uint64, size float64) error
@@ -0,0 +1,95 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// CancelContract implements ContractManager.CancelContract
+// The contract is marked CANCELED before its VTXOs are touched, so a concurrent FundContract
+// fails on the contract's version instead of activating a contract whose collateral was returned.
+func (s *contractService) CancelContract(ctx context.Context, contractID string, userID string) (*Transaction, error) {
+	// 1. Only a PENDING contract can be canceled, and only by one of its parties
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, ErrContractNotFound
+	}
+	if err := s.validateUserIsContractParty(contract, userID); err != nil {
+		return nil, err
+	}
+	if contract.Status != PENDING {
+		return nil, fmt.Errorf("%w: contract %s is %s, only PENDING contracts can be canceled",
+			ErrInvalidContractStatus, contractID, contract.Status)
+	}
+
+	// 2. Mark the contract as canceled
+	contract.Status = CONTRACT_CANCELED
+	if err := s.updateContract(ctx, contract, PENDING); err != nil {
+		return nil, fmt.Errorf("failed to cancel contract: %w", err)
+	}
+
+	// 3. Deactivate the VTXOs created for the contract and return their collateral
+	vtxos, err := s.vtxoRepo.FindByContract(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract VTXOs: %w", err)
+	}
+
+	released := make(map[string]float64, len(vtxos))
+	var releasedSats int64
+	for _, vtxo := range vtxos {
+		if !vtxo.IsActive {
+			continue
+		}
+		vtxo.IsActive = false
+		if err := s.vtxoRepo.Update(ctx, vtxo); err != nil {
+			return nil, fmt.Errorf("failed to deactivate VTXO %s: %w", vtxo.ID, err)
+		}
+		released[vtxo.OwnerID] = SatsToBTC(BTCToSats(released[vtxo.OwnerID]) + BTCToSats(vtxo.Amount))
+		releasedSats += BTCToSats(vtxo.Amount)
+	}
+
+	releasePayouts(ctx, s.userRepo, s.eventPublisher, contractID, "release_canceled_collateral", released)
+
+	// 4. Record the cancellation
+	tx := &Transaction{
+		ID:          s.ids.NewID(),
+		Type:        CONTRACT_CANCELLATION,
+		Timestamp:   s.clock.Now().UTC(),
+		ContractID:  contract.ID,
+		UserIDs:     []string{contract.BuyerID, contract.SellerID},
+		Amount:      SatsToBTC(releasedSats),
+		AmountSats:  releasedSats,
+		BlockHeight: recordedBlockHeight(ctx, s.btcClient, s.clock),
+		RelatedEntities: map[string]string{
+			"buyer_vtxo":  contract.BuyerVTXO,
+			"seller_vtxo": contract.SellerVTXO,
+			"canceled_by": userID,
+		},
+		Status: TX_STATUS_COMPLETED,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		// The contract is already canceled and its collateral returned, only the record is missing
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: contract.ID,
+			Operation:  "record_cancellation_transaction",
+			Error:      err.Error(),
+		})
+	}
+
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:          EVENT_CONTRACT_CANCELED,
+		ContractID:    contract.ID,
+		UserIDs:       tx.UserIDs,
+		TransactionID: tx.ID,
+		Attributes: map[string]string{
+			"canceled_by": userID,
+		},
+	})
+
+	return tx, nil
+}
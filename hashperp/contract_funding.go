@@ -0,0 +1,140 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// FundContract implements ContractManager.FundContract
+// The signed setup transaction is stored and the contract moved to FUNDING before anything is
+// broadcast. A failed broadcast leaves it FUNDING, and a retry rebroadcasts the stored
+// transaction instead of generating a new one that would spend the same VTXOs.
+func (s *contractService) FundContract(
+	ctx context.Context,
+	contractID string,
+	buyerSignature []byte,
+	sellerSignature []byte,
+) (*Transaction, error) {
+	// 1. Only a PENDING contract can be funded, or a FUNDING one whose broadcast is retried
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, ErrContractNotFound
+	}
+	if contract.Status != PENDING && contract.Status != FUNDING {
+		return nil, fmt.Errorf("%w: contract %s is %s, only PENDING or FUNDING contracts can be funded",
+			ErrInvalidContractStatus, contractID, contract.Status)
+	}
+
+	// 2. Get the VTXOs the setup transaction funds
+	buyerVTXO, err := s.vtxoRepo.FindByID(ctx, contract.BuyerVTXO)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get buyer VTXO: %w", err)
+	}
+	sellerVTXO, err := s.vtxoRepo.FindByID(ctx, contract.SellerVTXO)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seller VTXO: %w", err)
+	}
+	if buyerVTXO == nil || sellerVTXO == nil {
+		return nil, ErrVTXONotFound
+	}
+
+	// 3. Both parties must sign their side of the setup
+	if err := s.verifyUserSignature(ctx, contract.BuyerID,
+		buildFundMessage(contract.ID, contract.BuyerID, buyerVTXO.ID), buyerSignature); err != nil {
+		return nil, fmt.Errorf("buyer: %w", err)
+	}
+	if err := s.verifyUserSignature(ctx, contract.SellerID,
+		buildFundMessage(contract.ID, contract.SellerID, sellerVTXO.ID), sellerSignature); err != nil {
+		return nil, fmt.Errorf("seller: %w", err)
+	}
+
+	// 4. Generate the setup transaction and store it before it is broadcast
+	var feeRate uint64
+	if contract.Status == PENDING {
+		feeRate, err = s.estimateFeeRate(ctx, FEE_PRIORITY_NORMAL)
+		if err != nil {
+			return nil, err
+		}
+
+		buyerVTXO.SignatureData = buyerSignature
+		sellerVTXO.SignatureData = sellerSignature
+		setupTx, err := s.scriptGen.GenerateSetupTransaction(ctx, contract, buyerVTXO, sellerVTXO, feeRate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate setup transaction: %w", err)
+		}
+
+		// Keep the signatures with the VTXOs they commit
+		if err := s.vtxoRepo.Update(ctx, buyerVTXO); err != nil {
+			return nil, fmt.Errorf("failed to update buyer VTXO: %w", err)
+		}
+		if err := s.vtxoRepo.Update(ctx, sellerVTXO); err != nil {
+			return nil, fmt.Errorf("failed to update seller VTXO: %w", err)
+		}
+
+		contract.SetupTx = setupTx
+		contract.Status = FUNDING
+		if err := s.updateContract(ctx, contract, PENDING); err != nil {
+			return nil, fmt.Errorf("failed to store setup transaction: %w", err)
+		}
+	}
+
+	// 5. Broadcast the stored setup transaction, the node accepts a rebroadcast it already holds
+	setupTxID, err := s.btcClient.BroadcastTransaction(ctx, contract.SetupTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to broadcast setup transaction, retry to rebroadcast it: %w", err)
+	}
+
+	// 6. The contract is live once funded
+	contract.SetupTxID = setupTxID
+	contract.Status = ACTIVE
+	if err := s.updateContract(ctx, contract, FUNDING); err != nil {
+		return nil, fmt.Errorf("failed to activate funded contract: %w", err)
+	}
+
+	// 7. Record the funding
+	tx := &Transaction{
+		ID:          s.ids.NewID(),
+		Type:        CONTRACT_FUNDING,
+		Timestamp:   s.clock.Now().UTC(),
+		ContractID:  contract.ID,
+		UserIDs:     []string{contract.BuyerID, contract.SellerID},
+		TxHash:      setupTxID,
+		Amount:      buyerVTXO.Amount + sellerVTXO.Amount,
+		BlockHeight: recordedBlockHeight(ctx, s.btcClient, s.clock),
+		RelatedEntities: map[string]string{
+			"buyer_vtxo":  buyerVTXO.ID,
+			"seller_vtxo": sellerVTXO.ID,
+			"setup_tx":    setupTxID,
+		},
+		Status: TX_STATUS_COMPLETED,
+	}
+	if feeRate > 0 {
+		// Unknown when a retry rebroadcasts a setup stored by an earlier call
+		tx.RelatedEntities["fee_rate"] = fmt.Sprintf("%d", feeRate)
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		// The contract is already funded and active, only the record is missing
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: contract.ID,
+			Operation:  "record_funding_transaction",
+			Error:      err.Error(),
+		})
+	}
+
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:          EVENT_CONTRACT_FUNDED,
+		ContractID:    contract.ID,
+		UserIDs:       tx.UserIDs,
+		TransactionID: tx.ID,
+		Attributes: map[string]string{
+			"setup_tx": setupTxID,
+		},
+	})
+
+	return tx, nil
+}
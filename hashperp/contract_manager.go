@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,8 +29,165 @@ var (
 	ErrUserNotInContract       = errors.New("user is not a participant in this contract")
 	ErrVTXONotActive           = errors.New("VTXO is not active")
 	ErrDynamicJoinRejected     = errors.New("dynamic join request was rejected")
+	ErrRolloverLimitExceeded   = errors.New("rollover chain limit exceeded")
+	ErrInvalidLeverage         = errors.New("invalid contract leverage")
+	ErrIdempotencyKeyConflict  = errors.New("idempotency key already used with different parameters")
+	ErrSettlementNotFound      = errors.New("settlement transaction not found")
+	ErrDisputeWindowOpen       = errors.New("settlement dispute window is still open")
+	ErrDisputeWindowClosed     = errors.New("settlement dispute window has closed")
+	ErrBlockNotFinal           = errors.New("block does not have enough confirmations to be final")
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrInvalidWebhookURL       = errors.New("webhook URL must be an absolute https URL of a public host")
+	ErrInvalidOrderStatus      = errors.New("order cannot move to the requested status")
+	ErrInvalidSwapOfferStatus  = errors.New("swap offer cannot move to the requested status")
+	ErrInvalidFeePriority      = errors.New("fee priority must be slow, normal or fast")
+	ErrTransactionNotFound     = errors.New("transaction not found")
+	ErrTransactionNotBroadcast = errors.New("transaction was not broadcast on chain")
+	ErrTransactionConflicted   = errors.New("transaction conflicts with a transaction in the chain")
+	ErrTransactionUnknown      = errors.New("transaction is not known to the node")
+	ErrNodeTimeout             = errors.New("Bitcoin node call timed out")
+	ErrTemplateNotFound        = errors.New("contract template not found")
+	ErrTemplateViolation       = errors.New("contract parameters are outside the template bounds")
+	ErrUserKeyNotFound         = errors.New("no public key registered for user")
+	ErrPreSignedExitNotFound   = errors.New("pre-signed exit not found")
+	ErrPreSignedExitUsed       = errors.New("pre-signed exit has already been broadcast")
+	ErrSweepNotAllowed         = errors.New("VTXO sweep is not allowed by the sweep policy")
+	ErrSelfContract            = errors.New("buyer and seller must be different users")
+	ErrNetworkMismatch         = errors.New("Bitcoin node is on a different network than configured")
+	ErrOpenOrderLimit          = errors.New("user has reached the maximum number of open orders")
+	ErrOpenSwapOfferLimit      = errors.New("user has reached the maximum number of open swap offers")
+	ErrConcurrentModification  = errors.New("record was modified concurrently, reload it and retry")
+	ErrPositionNotLiquidatable = errors.New("position is not below its maintenance margin")
+	ErrPriceBandExceeded       = errors.New("order strike rate is outside the allowed price band")
 )
 
+// Contract duration limits. The minimum and maximum durations are defaults, see DurationConfig.
+const (
+	minBlockDuration = 100   // ~16 hours at 10 min per block
+	maxBlockDuration = 52560 // ~1 year at 10 min per block
+
+	// maxRolloverChainLength caps how many times a position can be rolled forward
+	maxRolloverChainLength = 12
+	// maxRolloverTotalDuration caps the total span of a rollover chain, measured
+	// from the block height at which the first contract in the chain was created
+	maxRolloverTotalDuration = 2 * maxBlockDuration // ~2 years
+
+	// maxExpiryRangeSpan caps the block range of an expiry query so it cannot scan every contract
+	maxExpiryRangeSpan = 4320 // ~30 days at 10 min per block
+
+	// maxSettlementHistorySpan caps the block range of a settlement history query
+	maxSettlementHistorySpan = 52560 // ~1 year at 10 min per block
+)
+
+// DurationConfig bounds how far ahead of the current block a contract may expire. Contract
+// creation, rollover and order placement must share one config so they agree on valid expiries.
+type DurationConfig struct {
+	MinContractBlocks uint64 // Shortest allowed contract duration
+	MaxContractBlocks uint64 // Longest allowed contract duration
+}
+
+// DefaultDurationConfig returns the duration bounds used when none are configured
+func DefaultDurationConfig() DurationConfig {
+	return DurationConfig{
+		MinContractBlocks: minBlockDuration,
+		MaxContractBlocks: maxBlockDuration,
+	}
+}
+
+// Validate checks that the bounds describe a non-empty range
+func (c DurationConfig) Validate() error {
+	if c.MinContractBlocks == 0 || c.MaxContractBlocks < c.MinContractBlocks {
+		return fmt.Errorf("%w: contract duration bounds [%d, %d] are invalid",
+			ErrInvalidParameters, c.MinContractBlocks, c.MaxContractBlocks)
+	}
+	return nil
+}
+
+// validateExpiry checks that an expiry block is within the duration bounds of the current block
+func (c DurationConfig) validateExpiry(expiryBlockHeight, currentBlockHeight uint64) error {
+	if expiryBlockHeight <= currentBlockHeight {
+		return fmt.Errorf("%w: expiry block height must be in the future", ErrInvalidBlockHeight)
+	}
+	if expiryBlockHeight < currentBlockHeight+c.MinContractBlocks {
+		return fmt.Errorf("%w: contract duration too short, minimum %d blocks", ErrInvalidParameters, c.MinContractBlocks)
+	}
+	if expiryBlockHeight > currentBlockHeight+c.MaxContractBlocks {
+		return fmt.Errorf("%w: contract duration too long, maximum %d blocks", ErrInvalidParameters, c.MaxContractBlocks)
+	}
+	return nil
+}
+
+// twapSettlementWindow is the number of blocks, ending at the expiry block, over which the
+// network hash rate is averaged when a contract settles with the TWAP method
+const twapSettlementWindow = blocksPerDay
+
+// defaultSettlementConfirmations is how deeply the expiry block must be buried before
+// settlement reads its hash rate, guarding against the settlement value changing in a reorg
+const defaultSettlementConfirmations = 6
+
+// feePriorityTargetBlocks maps each fee priority onto the confirmation target used to estimate its fee rate
+var feePriorityTargetBlocks = map[FeePriority]int{
+	FEE_PRIORITY_SLOW:   144,
+	FEE_PRIORITY_NORMAL: 6,
+	FEE_PRIORITY_FAST:   2,
+}
+
+// Leverage and margin policy
+const (
+	defaultLeverage = 1.0  // Fully collateralized, each side posts half the contract size
+	minLeverage     = 1.0
+	maxLeverage     = 10.0
+
+	// maintenanceMarginRatio is the minimum collateral, as a fraction of contract size,
+	// each side must hold for a position to remain open
+	maintenanceMarginRatio = 0.05
+
+	// minCollateral prevents dust VTXOs on highly leveraged small contracts
+	minCollateral = 0.0001 // BTC
+)
+
+// Early exit fee policy, as a fraction of the exiting side's collateral
+const (
+	defaultExitFeeRate = 0.05
+	maxExitFeeRate     = 0.5 // At most half the exiting side's collateral, whatever the leverage
+)
+
+// Exit timeout policy, in blocks. The windows are defaults, see ExitTimeoutConfig.
+const (
+	defaultTimeoutExitBlocks = blocksPerDay
+	defaultSweepWindowBlocks = blocksPerDay
+	maxExitTimeoutBlocks     = 4320 // ~30 days at 10 min per block
+)
+
+// ExitTimeoutConfig sets the unilateral exit windows given to new contracts. Each contract
+// keeps the windows it was created with, so reconfiguring does not move the exits of open
+// contracts.
+type ExitTimeoutConfig struct {
+	TimeoutExitBlocks uint64 // Blocks after expiry before the timeout exit path opens
+	SweepWindowBlocks uint64 // Blocks before expiry from which VTXOs may be swept
+}
+
+// DefaultExitTimeoutConfig returns the exit windows used when none are configured
+func DefaultExitTimeoutConfig() ExitTimeoutConfig {
+	return ExitTimeoutConfig{
+		TimeoutExitBlocks: defaultTimeoutExitBlocks,
+		SweepWindowBlocks: defaultSweepWindowBlocks,
+	}
+}
+
+// Validate checks that both windows are set and bounded
+func (c ExitTimeoutConfig) Validate() error {
+	if c.TimeoutExitBlocks == 0 || c.TimeoutExitBlocks > maxExitTimeoutBlocks {
+		return fmt.Errorf("%w: timeout exit window must be between 1 and %d blocks",
+			ErrInvalidParameters, maxExitTimeoutBlocks)
+	}
+	if c.SweepWindowBlocks == 0 || c.SweepWindowBlocks > maxExitTimeoutBlocks {
+		return fmt.Errorf("%w: sweep window must be between 1 and %d blocks",
+			ErrInvalidParameters, maxExitTimeoutBlocks)
+	}
+	return nil
+}
+
 // contractService implements the ContractManager interface
 type contractService struct {
 	contractRepo    ContractRepository
@@ -37,8 +195,28 @@ type contractService struct {
 	transactionRepo TransactionRepository
 	scriptGen       ScriptGenerator
 	btcClient       BitcoinClient
-	blockHeight     uint64 // Current block height, regularly updated
 	swapManager     SwapOfferManager // For handling VTXO swaps
+	priceProvider   PriceProvider // Fiat reference prices captured at settlement
+	eventBus        ContractEventBus // Contract status change notifications
+	eventPublisher  EventPublisher   // Lifecycle events for downstream integrations
+	fiatCurrency    string        // Currency for fiat reference prices, empty for BTC-only
+	settlementDisputeWindow uint64 // Blocks a proposed settlement can be disputed, 0 settles immediately
+	settlementConfirmations uint64 // Confirmations the expiry block needs before it can be settled against
+	defaultExitFeeRate      float64 // Exit fee rate of contracts created without one
+	metrics                 MetricsRecorder // Operational metrics
+	userRepo                UserRepository  // Public keys for verifying pool join signatures
+	templateRepo            ContractTemplateRepository // Published contract templates
+	scriptRepo              ContractScriptRepository   // Scripts generated for contracts, nil skips storing them
+	expiryNoticeBlocks      uint64 // Blocks before expiry a contract is flagged CLOSE_TO_EXPIRY, 0 disables
+	durations               DurationConfig // Bounds on contract duration, shared with order placement
+	exitTimeouts            ExitTimeoutConfig // Exit windows given to new contracts
+	payoutPolicy            PayoutPolicy      // Rounding of settlement, exit and liquidation payouts
+	treasury                TreasuryConfig    // Account exit and settlement fees are credited to, unset disables fee collection
+	allowSelfContracts      bool // Whether a user may be both buyer and seller of a contract
+	blockTimes              *blockIntervalEstimator // Projects expiry blocks to dates
+	hashRateRepo            HashRateRepository // Recorded hash rates for PnL history, nil disables it
+	ids                     IDGenerator // IDs of new contracts, VTXOs and transactions
+	clock                   Clock       // Source of timestamps
 }
 
 // Below are additional helper methods that would typically be part of a complete implementation
@@ -57,9 +235,6 @@ func (s *contractService) GetCurrentBlockHeight(ctx context.Context) (uint64, er
 	if err != nil {
 		return 0, fmt.Errorf("failed to get current block height: %w", err)
 	}
-	
-	// Update cached block height
-	s.blockHeight = currentBlockHeight
 	return currentBlockHeight, nil
 }
 
@@ -71,16 +246,41 @@ func (s *contractService) ValidateContractParameters(
 	expiryBlockHeight uint64, 
 	size float64,
 ) error {
-	return s.validateContractParameters(ctx, contractType, strikeRate, expiryBlockHeight, size)
+	return s.validateContractParameters(ctx, contractType, strikeRate, expiryBlockHeight, size, s.defaultExitFeeRate)
 }
 
+// liquidationExitPath is the exit path liquidations force-settle through. It is internal to
+// CheckLiquidations, parties cannot request it.
+const liquidationExitPath = "liquidation"
+
 // ExecuteExitPath implements ContractManager.ExecuteExitPath
 func (s *contractService) ExecuteExitPath(
 	ctx context.Context,
 	contractID string,
 	userID string,
 	exitPathType string,
+	feePriority FeePriority,
+) (*Transaction, error) {
+	if exitPathType == liquidationExitPath {
+		return nil, fmt.Errorf("%w: liquidation is not a requestable exit path", ErrInvalidParameters)
+	}
+	return s.executeExitPath(ctx, contractID, userID, exitPathType, feePriority)
+}
+
+// executeExitPath executes any exit path, including the liquidation path only
+// liquidatePosition may take
+func (s *contractService) executeExitPath(
+	ctx context.Context,
+	contractID string,
+	userID string,
+	exitPathType string,
+	feePriority FeePriority,
 ) (*Transaction, error) {
+	feePriority, err := resolveFeePriority(feePriority)
+	if err != nil {
+		return nil, err
+	}
+
 	// 1. Get the contract
 	contract, err := s.contractRepo.FindByID(ctx, contractID)
 	if err != nil {
@@ -95,8 +295,9 @@ func (s *contractService) ExecuteExitPath(
 		return nil, ErrUserNotInContract
 	}
 
-	// 3. Validate contract status
-	if contract.Status != ACTIVE {
+	// 3. Validate contract status, a proposed settlement can only be disputed
+	if !isLive(contract.Status) &&
+		!(contract.Status == SETTLEMENT_PENDING && exitPathType == "dispute_resolution") {
 		return nil, ErrInvalidContractStatus
 	}
 
@@ -105,7 +306,6 @@ func (s *contractService) ExecuteExitPath(
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current block height: %w", err)
 	}
-	s.blockHeight = currentBlockHeight // Update cached block height
 
 	// 5. Determine counterparty
 	var counterpartyID string
@@ -126,7 +326,12 @@ func (s *contractService) ExecuteExitPath(
 		return nil, fmt.Errorf("failed to get seller VTXO: %w", err)
 	}
 
-	// 7. Execute the appropriate exit path based on type
+	// 7. Execute the appropriate exit path based on type, reserving a fee for the priority
+	feeRate, err := s.estimateFeeRate(ctx, feePriority)
+	if err != nil {
+		return nil, err
+	}
+
 	var exitTxHex, exitTxID string
 	var relatedEntities map[string]string = make(map[string]string)
 
@@ -134,7 +339,7 @@ func (s *contractService) ExecuteExitPath(
 	case "early_exit":
 		// Voluntary early exit (typically with a fee)
 		// Generate exit transaction
-		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract)
+		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract, feeRate)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate exit scripts: %w", err)
 		}
@@ -145,7 +350,7 @@ func (s *contractService) ExecuteExitPath(
 	case "mutual_agreement":
 		// Both parties agree to exit (often with no fee)
 		// Check if both parties have signed agreement (in a real system, would verify signatures)
-		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract)
+		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract, feeRate)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate exit scripts: %w", err)
 		}
@@ -159,13 +364,12 @@ func (s *contractService) ExecuteExitPath(
 			return nil, fmt.Errorf("cannot use timeout exit path before contract expiry")
 		}
 		
-		// Ensure sufficient time has passed since expiry (e.g., 144 blocks / 1 day)
-		const timeoutBuffer = 144 // blocks
-		if currentBlockHeight < contract.ExpiryBlockHeight + timeoutBuffer {
-			return nil, fmt.Errorf("timeout exit path requires waiting %d blocks after expiry", timeoutBuffer)
+		// Ensure the contract's timeout window has passed since expiry
+		if currentBlockHeight < contract.ExpiryBlockHeight + contract.TimeoutExitBlocks {
+			return nil, fmt.Errorf("timeout exit path requires waiting %d blocks after expiry", contract.TimeoutExitBlocks)
 		}
 		
-		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract)
+		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract, feeRate)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate exit scripts: %w", err)
 		}
@@ -180,7 +384,7 @@ func (s *contractService) ExecuteExitPath(
 			return nil, fmt.Errorf("cannot force settlement before contract expiry")
 		}
 		
-		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract)
+		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract, feeRate)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate exit scripts: %w", err)
 		}
@@ -191,7 +395,7 @@ func (s *contractService) ExecuteExitPath(
 		
 	case "dispute_resolution":
 		// Use third-party oracle or arbiter for dispute resolution
-		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract)
+		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract, feeRate)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate exit scripts: %w", err)
 		}
@@ -202,7 +406,7 @@ func (s *contractService) ExecuteExitPath(
 		
 	case "emergency_exit":
 		// Used for security measures or protocol emergencies
-		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract)
+		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract, feeRate)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate exit scripts: %w", err)
 		}
@@ -210,6 +414,30 @@ func (s *contractService) ExecuteExitPath(
 		exitTxHex = scripts["emergency"]
 		relatedEntities["exit_reason"] = "emergency_protocol_action"
 		
+	case liquidationExitPath:
+		// Position breached its maintenance margin and is force-settled before expiry. The
+		// margin is re-checked at the current rate, it may have recovered since the scan.
+		if currentBlockHeight >= contract.ExpiryBlockHeight {
+			return nil, fmt.Errorf("%w: expired contracts are settled, not liquidated", ErrPositionNotLiquidatable)
+		}
+		hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current hash rate: %w", err)
+		}
+		isBuyer := userID == contract.BuyerID
+		if !belowMaintenanceMargin(contract, isBuyer, calculateBTCPerPHPerDay(hashRate, currentBlockHeight)) {
+			return nil, ErrPositionNotLiquidatable
+		}
+
+		scripts, err := s.scriptGen.GenerateExitPathScripts(ctx, contract, feeRate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate exit scripts: %w", err)
+		}
+		
+		exitTxHex = scripts["forced_settlement"]
+		relatedEntities["exit_reason"] = "liquidation"
+		relatedEntities["liquidated_user"] = userID
+		
 	default:
 		return nil, fmt.Errorf("unknown exit path type: %s", exitPathType)
 	}
@@ -221,8 +449,9 @@ func (s *contractService) ExecuteExitPath(
 	}
 
 	// 9. Update contract status to EXITED
+	previousStatus := contract.Status
 	contract.Status = EXITED
-	if err := s.contractRepo.Update(ctx, contract); err != nil {
+	if err := s.updateContract(ctx, contract, previousStatus); err != nil {
 		return nil, fmt.Errorf("failed to update contract status: %w", err)
 	}
 
@@ -239,12 +468,12 @@ func (s *contractService) ExecuteExitPath(
 	}
 
 	// 11. Get current hash rate for transaction record
-	hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight)
+	hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight, 0)
 	if err != nil {
 		// Non-critical error, can continue with settlement
 		hashRate = 0 // Default value if hash rate retrieval fails
 	}
-	currentBTCPerPHPerDay := calculateBTCPerPHPerDay(hashRate)
+	currentBTCPerPHPerDay := calculateBTCPerPHPerDay(hashRate, currentBlockHeight)
 
 	// 12. Add core related entities
 	relatedEntities["exit_path_type"] = exitPathType
@@ -252,12 +481,13 @@ func (s *contractService) ExecuteExitPath(
 	relatedEntities["seller_vtxo"] = sellerVTXO.ID
 	relatedEntities["initiated_by"] = userID
 	relatedEntities["counterparty"] = counterpartyID
+	recordFee(relatedEntities, feePriority, feeRate)
 
 	// 13. Record the exit transaction
 	tx := &Transaction{
-		ID:              generateUniqueID(),
+		ID:              s.ids.NewID(),
 		Type:            EXIT_PATH_EXECUTION,
-		Timestamp:       time.Now().UTC(),
+		Timestamp:       s.clock.Now().UTC(),
 		ContractID:      contractID,
 		UserIDs:         []string{contract.BuyerID, contract.SellerID},
 		TxHash:          exitTxID,
@@ -271,6 +501,18 @@ func (s *contractService) ExecuteExitPath(
 		return nil, fmt.Errorf("failed to record exit transaction: %w", err)
 	}
 
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:          EVENT_EXIT_EXECUTED,
+		ContractID:    contractID,
+		UserIDs:       tx.UserIDs,
+		TransactionID: tx.ID,
+		Attributes: map[string]string{
+			"exit_path_type": exitPathType,
+			"initiated_by":   userID,
+			"tx_hash":        exitTxID,
+		},
+	})
+
 	return tx, nil
 }
 
@@ -278,7 +520,18 @@ func (s *contractService) ExecuteExitPath(
 type ContractRepository interface {
 	Create(ctx context.Context, contract *Contract) error
 	FindByID(ctx context.Context, id string) (*Contract, error)
-	FindByUser(ctx context.Context, userID string, status []ContractStatus) ([]*Contract, error)
+	FindByUser(ctx context.Context, userID string, status []ContractStatus, includeArchived bool, page PageRequest) ([]*Contract, string, error)
+	FindActiveByUser(ctx context.Context, userID string) ([]*Contract, error)
+	CountActiveContracts(ctx context.Context) (int, error)
+	CountByStatus(ctx context.Context) (map[ContractStatus]int, error)
+	FindActiveContracts(ctx context.Context) ([]*Contract, error)
+	FindByIdempotencyKey(ctx context.Context, idempotencyKey string) (*Contract, error)
+	FindByStatus(ctx context.Context, status ContractStatus) ([]*Contract, error)
+	FindByExpiryRange(ctx context.Context, fromHeight, toHeight uint64) ([]*Contract, error)
+	Search(ctx context.Context, filter ContractFilter, page PageRequest) ([]*Contract, string, error)
+	SumActiveByType(ctx context.Context) (map[ContractType]ContractTotals, error)
+	SummarizeOpenInterest(ctx context.Context, contractType ContractType) ([]*OpenInterestBucket, error)
+	SummarizeSettlementsByExpiry(ctx context.Context, contractType ContractType, fromHeight, toHeight uint64) ([]*SettlementHistoryPoint, error)
 	Update(ctx context.Context, contract *Contract) error
 	Delete(ctx context.Context, id string) error
 }
@@ -288,7 +541,8 @@ type VTXORepository interface {
 	Create(ctx context.Context, vtxo *VTXO) error
 	FindByID(ctx context.Context, id string) (*VTXO, error)
 	FindByContract(ctx context.Context, contractID string) ([]*VTXO, error)
-	FindByUser(ctx context.Context, userID string, onlyActive bool) ([]*VTXO, error)
+	FindActiveByOwnerAndContract(ctx context.Context, contractID string, ownerID string) (*VTXO, error)
+	FindByUser(ctx context.Context, userID string, onlyActive bool, includeArchived bool, page PageRequest) ([]*VTXO, string, error)
 	Update(ctx context.Context, vtxo *VTXO) error
 	Delete(ctx context.Context, id string) error
 }
@@ -297,14 +551,24 @@ type VTXORepository interface {
 type TransactionRepository interface {
 	Create(ctx context.Context, tx *Transaction) error
 	FindByID(ctx context.Context, id string) (*Transaction, error)
-	FindByUser(ctx context.Context, userID string, types []TransactionType) ([]*Transaction, error)
-	FindByContract(ctx context.Context, contractID string) ([]*Transaction, error)
+	FindByTxHash(ctx context.Context, txHash string) (*Transaction, error)
+	FindByUser(ctx context.Context, userID string, types []TransactionType, page PageRequest) ([]*Transaction, string, error)
+	FindByContract(ctx context.Context, contractID string, types []TransactionType, order SortOrder) ([]*Transaction, error)
+	FindByType(ctx context.Context, transactionType TransactionType) ([]*Transaction, error)
+	FindByTimeRange(ctx context.Context, startTime, endTime time.Time) ([]*Transaction, error)
+	FindByBlockRange(ctx context.Context, fromHeight, toHeight uint64) ([]*Transaction, error)
+	SumAmountSince(ctx context.Context, transactionType TransactionType, since time.Time) (int, float64, error)
+	FindAwaitingConfirmation(ctx context.Context) ([]*Transaction, error)
+	FindByTypeAndStatus(ctx context.Context, transactionType TransactionType, status string) ([]*Transaction, error)
 }
 
 // BitcoinClient defines the interface for interacting with the Bitcoin network
 type BitcoinClient interface {
 	GetCurrentBlockHeight(ctx context.Context) (uint64, error)
-	GetBlockHashRate(ctx context.Context, blockHeight uint64) (float64, error)
+	GetBlockByHeight(ctx context.Context, height uint64) (map[string]interface{}, error)
+	GetBlockHashRate(ctx context.Context, blockHeight uint64, minConfirmations uint64) (float64, error)
+	GetNetworkHashRate(ctx context.Context, windowBlocks uint64, endBlockHeight uint64, minConfirmations uint64) (float64, error)
+	EnsureBlockConfirmed(ctx context.Context, blockHeight uint64, minConfirmations uint64) (string, error)
 	BroadcastTransaction(ctx context.Context, txHex string) (string, error)
 	ValidateSignature(ctx context.Context, message []byte, signature []byte, pubKey []byte) (bool, error)
 }
@@ -325,7 +589,96 @@ func NewContractService(
 		scriptGen:       scriptGen,
 		btcClient:       btcClient,
 		swapManager:     swapManager,
+		priceProvider:   NewNoopPriceProvider(),
+		eventBus:        NewContractEventBus(),
+		eventPublisher:  NewNoopEventPublisher(),
+		settlementConfirmations: defaultSettlementConfirmations,
+		defaultExitFeeRate:      defaultExitFeeRate,
+		metrics:                 NewNoopMetricsRecorder(),
+		expiryNoticeBlocks:      defaultExpiryNoticeBlocks,
+		durations:               DefaultDurationConfig(),
+		exitTimeouts:            DefaultExitTimeoutConfig(),
+		payoutPolicy:            DefaultPayoutPolicy(),
+		blockTimes:              newBlockIntervalEstimator(btcClient),
+		ids:                     NewUUIDGenerator(),
+		clock:                   NewSystemClock(),
+	}
+}
+
+// SetDefaultExitFeeRate configures the exit fee rate given to contracts created without one
+func (s *contractService) SetDefaultExitFeeRate(rate float64) {
+	s.defaultExitFeeRate = rate
+}
+
+// SetSettlementConfirmations configures how many confirmations the expiry block needs
+// before its hash rate is used to settle a contract
+func (s *contractService) SetSettlementConfirmations(confirmations uint64) {
+	s.settlementConfirmations = confirmations
+}
+
+// SetSettlementDisputeWindow configures how many blocks a proposed settlement stays open to
+// dispute before it can be finalized. A window of 0 settles immediately.
+func (s *contractService) SetSettlementDisputeWindow(blocks uint64) {
+	s.settlementDisputeWindow = blocks
+}
+
+// SetDurationConfig configures the bounds on contract duration. Order placement must use the
+// same config.
+func (s *contractService) SetDurationConfig(durations DurationConfig) {
+	s.durations = durations
+}
+
+// SetExitTimeoutConfig configures the exit windows given to new contracts
+func (s *contractService) SetExitTimeoutConfig(exitTimeouts ExitTimeoutConfig) {
+	s.exitTimeouts = exitTimeouts
+}
+
+// SetAllowSelfContracts configures whether a user may take both sides of a contract. Self
+// contracts are wash positions and are rejected by default.
+func (s *contractService) SetAllowSelfContracts(allow bool) {
+	s.allowSelfContracts = allow
+}
+
+// SetEventPublisher configures where lifecycle events are published
+func (s *contractService) SetEventPublisher(publisher EventPublisher) {
+	s.eventPublisher = publisher
+}
+
+// SetMetricsRecorder configures where operational metrics are recorded
+func (s *contractService) SetMetricsRecorder(recorder MetricsRecorder) {
+	s.metrics = recorder
+}
+
+// SubscribeToContract implements ContractManager.SubscribeToContract
+func (s *contractService) SubscribeToContract(contractID string) (<-chan ContractStatusEvent, func()) {
+	return s.eventBus.Subscribe(contractID)
+}
+
+// updateContract persists a contract and publishes a status event if its status changed
+func (s *contractService) updateContract(ctx context.Context, contract *Contract, previousStatus ContractStatus) error {
+	if err := s.contractRepo.Update(ctx, contract); err != nil {
+		return err
+	}
+
+	if contract.Status != previousStatus {
+		snapshot := *contract
+		s.eventBus.Publish(ContractStatusEvent{
+			ContractID:     contract.ID,
+			PreviousStatus: previousStatus,
+			Status:         contract.Status,
+			Contract:       &snapshot,
+			Timestamp:      s.clock.Now().UTC(),
+		})
 	}
+
+	return nil
+}
+
+// SetPriceProvider configures the provider and currency used to record fiat-equivalent
+// payouts at settlement. Deployments that never call this remain BTC-only.
+func (s *contractService) SetPriceProvider(priceProvider PriceProvider, fiatCurrency string) {
+	s.priceProvider = priceProvider
+	s.fiatCurrency = fiatCurrency
 }
 
 // Helper function to generate a unique ID
@@ -333,24 +686,170 @@ func generateUniqueID() string {
 	return uuid.New().String()
 }
 
-// Helper function to calculate expiry date based on block height
-func calculateExpiryDate(expiryBlockHeight, currentBlockHeight uint64) time.Time {
-	// Assuming average 10 minutes per block
+// calculateExpiryDate projects the time expiryBlockHeight will be mined, assuming blocks keep
+// arriving at blockInterval from now
+func calculateExpiryDate(now time.Time, expiryBlockHeight, currentBlockHeight uint64, blockInterval time.Duration) time.Time {
+	now = now.UTC()
+	if expiryBlockHeight <= currentBlockHeight {
+		return now
+	}
 	blockDifference := expiryBlockHeight - currentBlockHeight
-	minutesUntilExpiry := blockDifference * 10
-	return time.Now().UTC().Add(time.Duration(minutesUntilExpiry) * time.Minute)
+	return now.Add(time.Duration(blockDifference) * blockInterval)
+}
+
+// Block subsidy schedule
+const (
+	blocksPerDay           = 144     // 6 blocks per hour * 24 hours
+	halvingInterval        = 210000  // Blocks between subsidy halvings
+	initialSubsidySatoshis = 50 * 1e8 // Subsidy of the first halving epoch
+	satoshisPerBTC         = 1e8
+)
+
+// blockSubsidy returns the block subsidy in BTC for a block height, halving every
+// halvingInterval blocks until it reaches zero
+func blockSubsidy(blockHeight uint64) float64 {
+	halvings := blockHeight / halvingInterval
+	if halvings >= 64 {
+		return 0
+	}
+	subsidy := int64(initialSubsidySatoshis) >> halvings
+	return float64(subsidy) / satoshisPerBTC
+}
+
+// calculateBTCPerPHPerDay calculates the BTC earned per day by one PH/s of hash power at a
+// block height, given the network hash rate in PH/s derived from that block's difficulty.
+// Transaction fees are not included.
+func calculateBTCPerPHPerDay(networkHashRate float64, blockHeight uint64) float64 {
+	if networkHashRate <= 0 {
+		return 0
+	}
+	return blocksPerDay * blockSubsidy(blockHeight) / networkHashRate
+}
+
+// validateLeverage checks leverage bounds and that the resulting collateral can meet
+// the maintenance margin
+func validateLeverage(size, leverage float64) error {
+	if leverage < minLeverage || leverage > maxLeverage {
+		return fmt.Errorf("%w: leverage must be between %.0fx and %.0fx, got %v",
+			ErrInvalidLeverage, minLeverage, maxLeverage, leverage)
+	}
+
+	collateral := SatsToBTC(collateralSats(BTCToSats(size), leverage))
+	if collateral < minCollateral {
+		return fmt.Errorf("%w: collateral of %.8f BTC per side is below the minimum of %.8f BTC",
+			ErrInvalidLeverage, collateral, minCollateral)
+	}
+
+	maintenanceMargin := size * maintenanceMarginRatio
+	if collateral < maintenanceMargin {
+		return fmt.Errorf("%w: collateral of %.8f BTC per side cannot meet maintenance margin of %.8f BTC",
+			ErrInvalidLeverage, collateral, maintenanceMargin)
+	}
+
+	return nil
+}
+
+// contractLeverage returns the leverage of a contract, treating unset leverage as fully collateralized
+func contractLeverage(contract *Contract) float64 {
+	if contract.Leverage <= 0 {
+		return defaultLeverage
+	}
+	return contract.Leverage
+}
+
+// validateSettlementMethod checks that a settlement method is supported
+func validateSettlementMethod(method SettlementMethod) error {
+	if method != SETTLEMENT_SPOT && method != SETTLEMENT_TWAP {
+		return fmt.Errorf("%w: settlement method must be %s or %s, got %q",
+			ErrInvalidParameters, SETTLEMENT_SPOT, SETTLEMENT_TWAP, method)
+	}
+	return nil
+}
+
+// contractSettlementMethod returns the settlement method of a contract, treating an unset method as SPOT
+func contractSettlementMethod(contract *Contract) SettlementMethod {
+	if contract.SettlementMethod == "" {
+		return SETTLEMENT_SPOT
+	}
+	return contract.SettlementMethod
+}
+
+// sideCollateral returns the collateral one side of a contract posts, in whole satoshis.
+// The two sides differ by a satoshi when the collateral is odd, see splitCollateralSats.
+func sideCollateral(contract *Contract, isBuyer bool) float64 {
+	buyerSats, sellerSats := splitCollateralSats(BTCToSats(contract.Size), contractLeverage(contract))
+	if isBuyer {
+		return SatsToBTC(buyerSats)
+	}
+	return SatsToBTC(sellerSats)
+}
+
+// atTheMoney reports whether a BTC/PH/day rate equals a contract's strike exactly.
+// Neither side wins at the money: both parties get their collateral back with no PnL.
+func atTheMoney(contract *Contract, btcPerPHPerDay float64) bool {
+	return btcPerPHPerDay == contract.StrikeRate
+}
+
+// positionPnL returns the uncapped profit (positive) or loss (negative) in BTC for
+// one side of a contract at the given BTC/PH/day rate
+func positionPnL(contract *Contract, isBuyer bool, btcPerPHPerDay float64) float64 {
+	if contract.StrikeRate <= 0 || atTheMoney(contract, btcPerPHPerDay) {
+		return 0
+	}
+
+	move := (btcPerPHPerDay - contract.StrikeRate) / contract.StrikeRate * contract.Size
+	if contract.ContractType == PUT {
+		move = -move
+	}
+
+	if isBuyer {
+		return move
+	}
+	return -move
+}
+
+// belowMaintenanceMargin reports whether one side of a contract, valued at the given
+// BTC/PH/day rate, holds less than its maintenance margin and can be liquidated
+func belowMaintenanceMargin(contract *Contract, isBuyer bool, btcPerPHPerDay float64) bool {
+	maintenanceMargin := contract.Size * maintenanceMarginRatio
+	return sideCollateral(contract, isBuyer)+positionPnL(contract, isBuyer, btcPerPHPerDay) < maintenanceMargin
 }
 
-// calculateBTCPerPHPerDay calculates BTC per PetaHash per day based on hash rate
-func calculateBTCPerPHPerDay(hashRate float64) float64 {
-	// Simplified calculation - in a real implementation this would be more complex
-	// and consider difficulty, block rewards, etc.
-	const blocksPerDay = 144 // 6 blocks per hour * 24 hours
-	const blockRewardBTC = 6.25 // Current block reward as of 2022-2023
-	const networkHashRatePH = 400000 // Example network hash rate in PH/s
+// liquidationPrice returns the BTC/PH/day rate at which one side of a contract falls
+// to its maintenance margin
+func liquidationPrice(contract *Contract, isBuyer bool) float64 {
+	maintenanceMargin := contract.Size * maintenanceMarginRatio
+	buffer := sideCollateral(contract, isBuyer) - maintenanceMargin
+
+	// Buyers of CALLs and sellers of PUTs lose as the rate falls
+	direction := 1.0
+	if contract.ContractType == PUT {
+		direction = -direction
+	}
+	if !isBuyer {
+		direction = -direction
+	}
+
+	price := contract.StrikeRate - direction*buffer*contract.StrikeRate/contract.Size
+	if price < 0 {
+		return 0
+	}
+	return price
+}
 
-	// Calculate BTC per PH per day
-	return (blocksPerDay * blockRewardBTC) / networkHashRatePH
+// capPayout limits a position's payout to the collateral available on both sides. Profits
+// are capped at what the counterparty posted, and a loss exceeding the position's own
+// collateral leaves nothing and marks the position as liquidated. The payout is computed in
+// satoshis so the payouts of opposite positions sum to both collaterals exactly.
+func capPayout(collateral, counterpartyCollateral, pnl float64) (payout float64, liquidated bool) {
+	collateralSats, counterpartySats, pnlSats := BTCToSats(collateral), BTCToSats(counterpartyCollateral), BTCToSats(pnl)
+	if pnlSats > counterpartySats {
+		pnlSats = counterpartySats
+	}
+	if -pnlSats >= collateralSats {
+		return 0, true
+	}
+	return SatsToBTC(collateralSats + pnlSats), false
 }
 
 // Helper method to validate contract parameters
@@ -360,6 +859,7 @@ func (s *contractService) validateContractParameters(
 	strikeRate float64,
 	expiryBlockHeight uint64,
 	size float64,
+	exitFeeRate float64,
 ) error {
 	// Validate contract type
 	if contractType != CALL && contractType != PUT {
@@ -376,24 +876,19 @@ func (s *contractService) validateContractParameters(
 		return fmt.Errorf("%w: size must be positive", ErrInvalidParameters)
 	}
 
-	// Validate expiry block height
-	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get current block height: %w", err)
-	}
-	s.blockHeight = currentBlockHeight // Update cached block height
-
-	if expiryBlockHeight <= currentBlockHeight {
-		return fmt.Errorf("%w: expiry block height must be in the future", ErrInvalidBlockHeight)
+	// Validate exit fee rate
+	if exitFeeRate < 0 || exitFeeRate > maxExitFeeRate {
+		return fmt.Errorf("%w: exit fee rate must be between 0 and %.2f, got %v",
+			ErrInvalidParameters, maxExitFeeRate, exitFeeRate)
 	}
 
-	// Validate minimum contract duration (e.g., at least 100 blocks ~16 hours)
-	const minBlockDuration = 100
-	if expiryBlockHeight < currentBlockHeight+minBlockDuration {
-		return fmt.Errorf("%w: contract duration too short, minimum %d blocks", ErrInvalidParameters, minBlockDuration)
+	// Validate expiry block height
+	currentBlockHeight, err := cachedBlockHeight(ctx, s.btcClient)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return s.durations.validateExpiry(expiryBlockHeight, currentBlockHeight)
 }
 
 // Helper method to create a VTXO for a contract
@@ -406,12 +901,12 @@ func (s *contractService) createContractVTXO(
 	signatureData []byte,
 ) (*VTXO, error) {
 	vtxo := &VTXO{
-		ID:                generateUniqueID(),
+		ID:                s.ids.NewID(),
 		ContractID:        contractID,
 		OwnerID:           ownerID,
 		Amount:            amount,
 		ScriptPath:        scriptPath,
-		CreationTimestamp: time.Now().UTC(),
+		CreationTimestamp: s.clock.Now().UTC(),
 		SignatureData:     signatureData,
 		IsActive:          true,
 	}
@@ -431,14 +926,14 @@ func (s *contractService) recordContractCreationTransaction(
 	sellerVTXOID string,
 ) (*Transaction, error) {
 	tx := &Transaction{
-		ID:              generateUniqueID(),
+		ID:              s.ids.NewID(),
 		Type:            CONTRACT_CREATION,
-		Timestamp:       time.Now().UTC(),
+		Timestamp:       s.clock.Now().UTC(),
 		ContractID:      contract.ID,
 		UserIDs:         []string{contract.BuyerID, contract.SellerID},
 		Amount:          contract.Size,
 		BTCPerPHPerDay:  contract.StrikeRate, // Using strike rate at creation time
-		BlockHeight:     s.blockHeight,
+		BlockHeight:     contract.OriginBlockHeight,
 		RelatedEntities: map[string]string{
 			"buyer_vtxo":  buyerVTXOID,
 			"seller_vtxo": sellerVTXOID,
@@ -460,17 +955,66 @@ func (s *contractService) CreateContract(
 	strikeRate float64,
 	expiryBlockHeight uint64,
 	size float64,
-) (*Contract, error) {
+	leverage float64,
+	settlementMethod SettlementMethod,
+	exitFeeRate *float64,
+	idempotencyKey string,
+) (_ *Contract, err error) {
+	defer func() { s.metrics.ContractCreated(contractType, MetricOutcome(err)) }()
+
 	// 1. Validate parameters
-	if err := s.validateContractParameters(ctx, contractType, strikeRate, expiryBlockHeight, size); err != nil {
+	if leverage == 0 {
+		leverage = defaultLeverage
+	}
+	if settlementMethod == "" {
+		settlementMethod = SETTLEMENT_SPOT
+	}
+	feeRate := s.defaultExitFeeRate
+	if exitFeeRate != nil {
+		feeRate = *exitFeeRate
+	}
+
+	// 1a. Return the existing contract if this idempotency key was already used
+	if idempotencyKey != "" {
+		existing, err := s.contractRepo.FindByIdempotencyKey(ctx, idempotencyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up idempotency key: %w", err)
+		}
+		if existing != nil {
+			return resolveDuplicateContract(existing, buyerID, sellerID, contractType, strikeRate, expiryBlockHeight, size, leverage, settlementMethod, feeRate)
+		}
+	}
+
+	if buyerID == sellerID && !s.allowSelfContracts {
+		return nil, fmt.Errorf("%w: user %s cannot take both sides of a contract", ErrSelfContract, buyerID)
+	}
+
+	if err := validateSettlementMethod(settlementMethod); err != nil {
+		return nil, err
+	}
+
+	if err := s.exitTimeouts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateContractParameters(ctx, contractType, strikeRate, expiryBlockHeight, size, feeRate); err != nil {
+		return nil, err
+	}
+
+	if err := validateLeverage(size, leverage); err != nil {
 		return nil, err
 	}
 
 	// 2. Generate a unique contract ID
-	contractID := generateUniqueID()
+	contractID := s.ids.NewID()
 
-	// 3. Calculate human-readable expiry date based on block height and average block time
-	expiryDate := calculateExpiryDate(expiryBlockHeight, s.blockHeight)
+	// 3. Calculate human-readable expiry date based on block height and average block time.
+	// The current height is also the origin of the contract's rollover chain.
+	currentBlockHeight, err := cachedBlockHeight(ctx, s.btcClient)
+	if err != nil {
+		return nil, err
+	}
+	expiryDate := s.blockTimes.expiryDate(ctx, expiryBlockHeight, currentBlockHeight)
 
 	// 4. Create the contract
 	contract := &Contract{
@@ -479,59 +1023,134 @@ func (s *contractService) CreateContract(
 		StrikeRate:       strikeRate,
 		ExpiryBlockHeight: expiryBlockHeight,
 		ExpiryDate:       expiryDate,
-		CreationTime:     time.Now().UTC(),
+		CreationTime:     s.clock.Now().UTC(),
 		Status:           PENDING,
 		BuyerID:          buyerID,
 		SellerID:         sellerID,
 		Size:             size,
+		Leverage:         leverage,
+		OriginBlockHeight: currentBlockHeight,
+		SettlementMethod: settlementMethod,
+		ExitFeeRate:      feeRate,
+		TimeoutExitBlocks: s.exitTimeouts.TimeoutExitBlocks,
+		SweepWindowBlocks: s.exitTimeouts.SweepWindowBlocks,
+		IdempotencyKey:   idempotencyKey,
 		// VTXO IDs will be set later
 	}
 
 	// 5. Save the contract to the repository
 	if err := s.contractRepo.Create(ctx, contract); err != nil {
+		// A concurrent retry may have won the unique idempotency key constraint
+		if idempotencyKey != "" {
+			existing, findErr := s.contractRepo.FindByIdempotencyKey(ctx, idempotencyKey)
+			if findErr == nil && existing != nil {
+				return resolveDuplicateContract(existing, buyerID, sellerID, contractType, strikeRate, expiryBlockHeight, size, leverage, settlementMethod, feeRate)
+			}
+		}
 		return nil, fmt.Errorf("failed to create contract: %w", err)
 	}
 
 	// 6. Generate scripts for the contract
 	scripts, err := s.scriptGen.GenerateContractScripts(ctx, contract)
 	if err != nil {
-		// If script generation fails, delete the contract
+		// If script generation fails, archive the contract
 		_ = s.contractRepo.Delete(ctx, contractID)
 		return nil, fmt.Errorf("failed to generate contract scripts: %w", err)
 	}
 
-	// 7. Create VTXOs for buyer and seller
-	buyerVTXO, err := s.createContractVTXO(ctx, contractID, buyerID, size/2, scripts["buyerScriptPath"], nil)
+	// 7. Lock the required collateral from each party's balance, the seller absorbs the odd
+	// satoshi so both VTXOs together hold the full collateral
+	buyerCollateral := sideCollateral(contract, true)
+	sellerCollateral := sideCollateral(contract, false)
+
+	if err := lockCollateral(ctx, s.userRepo, buyerID, buyerCollateral); err != nil {
+		_ = s.contractRepo.Delete(ctx, contractID)
+		return nil, err
+	}
+
+	if err := lockCollateral(ctx, s.userRepo, sellerID, sellerCollateral); err != nil {
+		_ = s.contractRepo.Delete(ctx, contractID)
+		_ = releaseCollateral(ctx, s.userRepo, buyerID, buyerCollateral)
+		return nil, err
+	}
+
+	// 8. Create VTXOs for buyer and seller, each holding the locked collateral
+	buyerVTXO, err := s.createContractVTXO(ctx, contractID, buyerID, buyerCollateral, scripts["buyerScriptPath"], nil)
 	if err != nil {
 		_ = s.contractRepo.Delete(ctx, contractID)
+		_ = releaseCollateral(ctx, s.userRepo, buyerID, buyerCollateral)
+		_ = releaseCollateral(ctx, s.userRepo, sellerID, sellerCollateral)
 		return nil, fmt.Errorf("failed to create buyer VTXO: %w", err)
 	}
 
-	sellerVTXO, err := s.createContractVTXO(ctx, contractID, sellerID, size/2, scripts["sellerScriptPath"], nil)
+	sellerVTXO, err := s.createContractVTXO(ctx, contractID, sellerID, sellerCollateral, scripts["sellerScriptPath"], nil)
 	if err != nil {
 		_ = s.contractRepo.Delete(ctx, contractID)
 		_ = s.vtxoRepo.Delete(ctx, buyerVTXO.ID)
+		_ = releaseCollateral(ctx, s.userRepo, buyerID, buyerCollateral)
+		_ = releaseCollateral(ctx, s.userRepo, sellerID, sellerCollateral)
 		return nil, fmt.Errorf("failed to create seller VTXO: %w", err)
 	}
 
-	// 8. Update contract with VTXO IDs
+	// 9. Update contract with VTXO IDs, it stays PENDING until FundContract broadcasts the setup
 	contract.BuyerVTXO = buyerVTXO.ID
 	contract.SellerVTXO = sellerVTXO.ID
-	contract.Status = ACTIVE
 
-	if err := s.contractRepo.Update(ctx, contract); err != nil {
+	if err := s.updateContract(ctx, contract, PENDING); err != nil {
 		return nil, fmt.Errorf("failed to update contract with VTXOs: %w", err)
 	}
+	s.storeContractScripts(ctx, contract, scripts)
 
-	// 9. Record transaction
+	// 10. Record transaction
 	_, err = s.recordContractCreationTransaction(ctx, contract, buyerVTXO.ID, sellerVTXO.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to record contract creation transaction: %w", err)
 	}
 
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:       EVENT_CONTRACT_CREATED,
+		ContractID: contract.ID,
+		UserIDs:    []string{buyerID, sellerID},
+		Attributes: map[string]string{
+			"contract_type":       string(contractType),
+			"strike_rate":         fmt.Sprintf("%.8f", strikeRate),
+			"expiry_block_height": fmt.Sprintf("%d", expiryBlockHeight),
+			"size":                fmt.Sprintf("%.8f", size),
+			"settlement_method":   string(settlementMethod),
+		},
+	})
+
 	return contract, nil
 }
 
+// resolveDuplicateContract returns a contract previously created with the same idempotency key
+// if its parameters match the retried request, and ErrIdempotencyKeyConflict otherwise
+func resolveDuplicateContract(
+	existing *Contract,
+	buyerID, sellerID string,
+	contractType ContractType,
+	strikeRate float64,
+	expiryBlockHeight uint64,
+	size float64,
+	leverage float64,
+	settlementMethod SettlementMethod,
+	exitFeeRate float64,
+) (*Contract, error) {
+	if existing.BuyerID != buyerID ||
+		existing.SellerID != sellerID ||
+		existing.ContractType != contractType ||
+		existing.StrikeRate != strikeRate ||
+		existing.ExpiryBlockHeight != expiryBlockHeight ||
+		existing.Size != size ||
+		existing.Leverage != leverage ||
+		contractSettlementMethod(existing) != settlementMethod ||
+		existing.ExitFeeRate != exitFeeRate {
+		return nil, fmt.Errorf("%w: %s", ErrIdempotencyKeyConflict, existing.IdempotencyKey)
+	}
+
+	return existing, nil
+}
+
 // GetContract implements ContractManager.GetContract
 func (s *contractService) GetContract(ctx context.Context, contractID string) (*Contract, error) {
 	contract, err := s.contractRepo.FindByID(ctx, contractID)
@@ -545,16 +1164,84 @@ func (s *contractService) GetContract(ctx context.Context, contractID string) (*
 }
 
 // GetContractsByUser implements ContractManager.GetContractsByUser
-func (s *contractService) GetContractsByUser(ctx context.Context, userID string, status []ContractStatus) ([]*Contract, error) {
-	contracts, err := s.contractRepo.FindByUser(ctx, userID, status)
+func (s *contractService) GetContractsByUser(
+	ctx context.Context,
+	userID string,
+	status []ContractStatus,
+	includeArchived bool,
+	page PageRequest,
+) ([]*Contract, string, error) {
+	contracts, nextCursor, err := s.contractRepo.FindByUser(ctx, userID, status, includeArchived, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get contracts by user: %w", err)
+	}
+	return contracts, nextCursor, nil
+}
+
+// GetContractsByExpiryRange implements ContractManager.GetContractsByExpiryRange
+func (s *contractService) GetContractsByExpiryRange(ctx context.Context, fromHeight, toHeight uint64) ([]*Contract, error) {
+	if toHeight < fromHeight {
+		return nil, fmt.Errorf("%w: range end %d is before range start %d", ErrInvalidBlockHeight, toHeight, fromHeight)
+	}
+	if toHeight-fromHeight > maxExpiryRangeSpan {
+		return nil, fmt.Errorf("%w: range may span at most %d blocks", ErrInvalidBlockHeight, maxExpiryRangeSpan)
+	}
+
+	contracts, err := s.contractRepo.FindByExpiryRange(ctx, fromHeight, toHeight)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get contracts by user: %w", err)
+		return nil, fmt.Errorf("failed to get contracts by expiry range: %w", err)
 	}
 	return contracts, nil
 }
 
+// GetSettlementHistory implements ContractManager.GetSettlementHistory
+func (s *contractService) GetSettlementHistory(
+	ctx context.Context,
+	contractType ContractType,
+	fromHeight, toHeight uint64,
+) ([]*SettlementHistoryPoint, error) {
+	if contractType != "" && contractType != CALL && contractType != PUT {
+		return nil, fmt.Errorf("%w: invalid contract type %q", ErrInvalidParameters, contractType)
+	}
+	if toHeight < fromHeight {
+		return nil, fmt.Errorf("%w: range end %d is before range start %d", ErrInvalidBlockHeight, toHeight, fromHeight)
+	}
+	if toHeight-fromHeight > maxSettlementHistorySpan {
+		return nil, fmt.Errorf("%w: range may span at most %d blocks", ErrInvalidBlockHeight, maxSettlementHistorySpan)
+	}
+
+	history, err := s.contractRepo.SummarizeSettlementsByExpiry(ctx, contractType, fromHeight, toHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settlement history: %w", err)
+	}
+	return history, nil
+}
+
+// GetOpenInterest implements ContractManager.GetOpenInterest
+func (s *contractService) GetOpenInterest(ctx context.Context, contractType ContractType) ([]*OpenInterestBucket, error) {
+	if contractType != "" && contractType != CALL && contractType != PUT {
+		return nil, fmt.Errorf("%w: invalid contract type %q", ErrInvalidParameters, contractType)
+	}
+
+	buckets, err := s.contractRepo.SummarizeOpenInterest(ctx, contractType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open interest: %w", err)
+	}
+	return buckets, nil
+}
+
 // SettleContract implements ContractManager.SettleContract
-func (s *contractService) SettleContract(ctx context.Context, contractID string) (*Transaction, error) {
+// With a dispute window configured, settling an ACTIVE contract only proposes the outcome;
+// calling it again once the window has elapsed finalizes the proposed settlement.
+func (s *contractService) SettleContract(ctx context.Context, contractID string, feePriority FeePriority) (tx *Transaction, err error) {
+	var contractType ContractType
+	defer func() { s.metrics.ContractSettlement(contractType, settlementOutcomeLabel(tx, err)) }()
+
+	feePriority, err = resolveFeePriority(feePriority)
+	if err != nil {
+		return nil, err
+	}
+
 	// 1. Get the contract
 	contract, err := s.contractRepo.FindByID(ctx, contractID)
 	if err != nil {
@@ -563,9 +1250,10 @@ func (s *contractService) SettleContract(ctx context.Context, contractID string)
 	if contract == nil {
 		return nil, ErrContractNotFound
 	}
+	contractType = contract.ContractType
 
 	// 2. Validate contract status
-	if contract.Status != ACTIVE {
+	if !isLive(contract.Status) && contract.Status != SETTLEMENT_PENDING {
 		return nil, ErrInvalidContractStatus
 	}
 
@@ -574,37 +1262,383 @@ func (s *contractService) SettleContract(ctx context.Context, contractID string)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current block height: %w", err)
 	}
-	s.blockHeight = currentBlockHeight // Update cached block height
+
+	// 3a. A pending settlement is finalized once the dispute deadline set when it was proposed
+	// has passed, so reconfiguring the window does not move deadlines already announced
+	if contract.Status == SETTLEMENT_PENDING {
+		if currentBlockHeight < contract.SettlementDisputeDeadline {
+			return nil, fmt.Errorf("%w: finalizable at block %d", ErrDisputeWindowOpen,
+				contract.SettlementDisputeDeadline)
+		}
+		btcPerPHPerDay, err := s.readSettlementRate(ctx, contract)
+		if err != nil {
+			return nil, err
+		}
+		return s.finalizeSettlement(ctx, contract, btcPerPHPerDay, feePriority)
+	}
 
 	if currentBlockHeight < contract.ExpiryBlockHeight {
 		return nil, fmt.Errorf("contract has not expired yet, current height %d < expiry height %d", 
 		    currentBlockHeight, contract.ExpiryBlockHeight)
 	}
 
-	// 4. Get the hash rate at expiry block
-	hashRate, err := s.btcClient.GetBlockHashRate(ctx, contract.ExpiryBlockHeight)
+	// 4. Read the BTC per PH per day rate at the expiry block once it is final
+	btcPerPHPerDay, err := s.readSettlementRate(ctx, contract)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get hash rate at expiry block: %w", err)
+		return nil, err
 	}
 
-	// 5. Calculate BTC per PH per day rate at settlement
-	btcPerPHPerDay := calculateBTCPerPHPerDay(hashRate)
-
-	// 6. Determine winner (buyer or seller)
-	var winnerID, loserID string
-	if (contract.ContractType == CALL && btcPerPHPerDay > contract.StrikeRate) ||
-		(contract.ContractType == PUT && btcPerPHPerDay < contract.StrikeRate) {
-		winnerID = contract.BuyerID
-		loserID = contract.SellerID
-	} else {
-		winnerID = contract.SellerID
-		loserID = contract.BuyerID
+	// 5. Propose the outcome if a dispute window applies, otherwise settle immediately
+	if s.settlementDisputeWindow > 0 {
+		return s.proposeSettlement(ctx, contract, btcPerPHPerDay, currentBlockHeight)
 	}
 
-	// 7. Generate settlement transaction
-	buyerVTXO, err := s.vtxoRepo.FindByID(ctx, contract.BuyerVTXO)
+	return s.finalizeSettlement(ctx, contract, btcPerPHPerDay, feePriority)
+}
+
+// readSettlementRate reads the BTC per PH per day rate at a contract's expiry block, requiring
+// the block to be buried under the configured number of confirmations. If the block at the
+// expiry height differs from the one previously observed, the rate is re-read and the
+// discrepancy logged. The observed block hash is recorded on the contract.
+func (s *contractService) readSettlementRate(ctx context.Context, contract *Contract) (float64, error) {
+	// 1. Make sure the expiry block is final
+	blockHash, err := s.btcClient.EnsureBlockConfirmed(ctx, contract.ExpiryBlockHeight, s.settlementConfirmations)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get buyer VTXO: %w", err)
+		if errors.Is(err, ErrBlockNotFinal) {
+			return 0, fmt.Errorf("contract %s cannot settle until expiry block %d has %d confirmations: %w",
+				contract.ID, contract.ExpiryBlockHeight, s.settlementConfirmations, err)
+		}
+		return 0, fmt.Errorf("failed to check expiry block confirmations: %w", err)
+	}
+
+	if contract.SettlementBlockHash != "" && contract.SettlementBlockHash != blockHash {
+		s.reportExpiryBlockChange(ctx, contract, contract.SettlementBlockHash, blockHash)
+	}
+
+	// 2. Read the hash rate at the expiry block
+	hashRate, err := s.settlementHashRate(ctx, contract)
+	if err != nil {
+		return 0, err
+	}
+
+	// 3. Make sure the block did not change underneath the read
+	confirmedHash, err := s.btcClient.EnsureBlockConfirmed(ctx, contract.ExpiryBlockHeight, s.settlementConfirmations)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recheck expiry block: %w", err)
+	}
+	if confirmedHash != blockHash {
+		s.reportExpiryBlockChange(ctx, contract, blockHash, confirmedHash)
+
+		hashRate, err = s.settlementHashRate(ctx, contract)
+		if err != nil {
+			return 0, err
+		}
+		blockHash = confirmedHash
+	}
+
+	// 4. Calculate the settlement rate
+	btcPerPHPerDay := calculateBTCPerPHPerDay(hashRate, contract.ExpiryBlockHeight)
+	if contract.SettlementRate != 0 && contract.SettlementRate != btcPerPHPerDay {
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: contract.ID,
+			UserIDs:    contractUserIDs(contract),
+			Operation:  "read_settlement_rate",
+			Error: fmt.Sprintf("settlement rate changed from %.8f to %.8f after re-reading expiry block %d",
+				contract.SettlementRate, btcPerPHPerDay, contract.ExpiryBlockHeight),
+		})
+	}
+
+	contract.SettlementBlockHash = blockHash
+	return btcPerPHPerDay, nil
+}
+
+// reportExpiryBlockChange publishes that the block at a contract's expiry height changed
+// between two reads, so the settlement hash rate is re-read
+func (s *contractService) reportExpiryBlockChange(ctx context.Context, contract *Contract, previousHash, currentHash string) {
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:       EVENT_OPERATION_FAILED,
+		ContractID: contract.ID,
+		UserIDs:    contractUserIDs(contract),
+		Operation:  "confirm_expiry_block",
+		Error:      fmt.Sprintf("expiry block %d changed, re-reading settlement hash rate", contract.ExpiryBlockHeight),
+		Attributes: map[string]string{
+			"previous_block_hash": previousHash,
+			"block_hash":          currentHash,
+		},
+	})
+}
+
+// settlementHashRate reads the hash rate a contract settles on according to its settlement method
+func (s *contractService) settlementHashRate(ctx context.Context, contract *Contract) (float64, error) {
+	switch contractSettlementMethod(contract) {
+	case SETTLEMENT_TWAP:
+		hashRate, err := averageBlockHashRate(ctx, s.btcClient, contract.ExpiryBlockHeight, twapSettlementWindow, s.settlementConfirmations)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get average hash rate before expiry block: %w", err)
+		}
+		return hashRate, nil
+	default:
+		hashRate, err := s.btcClient.GetBlockHashRate(ctx, contract.ExpiryBlockHeight, s.settlementConfirmations)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get hash rate at expiry block: %w", err)
+		}
+		return hashRate, nil
+	}
+}
+
+// resolveFeePriority validates a fee priority, defaulting an empty one to normal
+func resolveFeePriority(priority FeePriority) (FeePriority, error) {
+	if priority == "" {
+		return FEE_PRIORITY_NORMAL, nil
+	}
+	if _, ok := feePriorityTargetBlocks[priority]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidFeePriority, priority)
+	}
+	return priority, nil
+}
+
+// estimateFeeRate estimates the fee rate in satoshis per byte for a transaction to confirm
+// within the target of a fee priority
+func (s *contractService) estimateFeeRate(ctx context.Context, priority FeePriority) (uint64, error) {
+	feeRate, err := s.btcClient.GetNetworkFeeEstimate(ctx, feePriorityTargetBlocks[priority])
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate %s priority fee: %w", priority, err)
+	}
+	return feeRate, nil
+}
+
+// recordFee adds the fee priority and rate a transaction was built with to its related entities
+func recordFee(relatedEntities map[string]string, priority FeePriority, feeRate uint64) {
+	relatedEntities["fee_priority"] = string(priority)
+	relatedEntities["fee_rate_sat_per_byte"] = fmt.Sprintf("%d", feeRate)
+}
+
+// settlementWinner determines the winning and losing parties at a settlement rate.
+// A contract that settles exactly at the money has no winner and both IDs are empty.
+func settlementWinner(contract *Contract, btcPerPHPerDay float64) (winnerID, loserID string) {
+	if atTheMoney(contract, btcPerPHPerDay) {
+		return "", ""
+	}
+	if (contract.ContractType == CALL && btcPerPHPerDay > contract.StrikeRate) ||
+		(contract.ContractType == PUT && btcPerPHPerDay < contract.StrikeRate) {
+		return contract.BuyerID, contract.SellerID
+	}
+	return contract.SellerID, contract.BuyerID
+}
+
+// settlementOutcome is the winner and payouts of a contract at a settlement rate
+type settlementOutcome struct {
+	winnerID         string
+	loserID          string
+	buyerPayout      float64
+	sellerPayout     float64
+	buyerLiquidated  bool
+	sellerLiquidated bool
+	settlementFee    float64            // Charged on the winning side's profit
+	payouts          map[string]float64 // Per user, set when either side is pooled
+}
+
+// computeSettlementOutcome determines the winner (buyer or seller, or neither at the money)
+// and each side's payout, capped at the collateral each side posted, less the settlement fee
+// on the winning side's profit and rounded by the payout policy. Settlement and its preview
+// both use it so they cannot disagree.
+func computeSettlementOutcome(contract *Contract, btcPerPHPerDay float64, policy PayoutPolicy, settlementFeeRate float64) settlementOutcome {
+	var outcome settlementOutcome
+	outcome.winnerID, outcome.loserID = settlementWinner(contract, btcPerPHPerDay)
+
+	buyerCollateral, sellerCollateral := sideCollateral(contract, true), sideCollateral(contract, false)
+	outcome.buyerPayout, outcome.buyerLiquidated = capPayout(buyerCollateral, sellerCollateral, positionPnL(contract, true, btcPerPHPerDay))
+	outcome.sellerPayout, outcome.sellerLiquidated = capPayout(sellerCollateral, buyerCollateral, positionPnL(contract, false, btcPerPHPerDay))
+
+	buyerFeeSats := settlementFeeSats(BTCToSats(outcome.buyerPayout), BTCToSats(buyerCollateral), settlementFeeRate)
+	sellerFeeSats := settlementFeeSats(BTCToSats(outcome.sellerPayout), BTCToSats(sellerCollateral), settlementFeeRate)
+	outcome.settlementFee = SatsToBTC(buyerFeeSats + sellerFeeSats)
+	outcome.buyerPayout = SatsToBTC(BTCToSats(outcome.buyerPayout) - buyerFeeSats)
+	outcome.sellerPayout = SatsToBTC(BTCToSats(outcome.sellerPayout) - sellerFeeSats)
+
+	outcome.buyerPayout, outcome.sellerPayout = policy.apply(outcome.buyerPayout, outcome.sellerPayout)
+
+	// Pooled sides split their payout pro-rata across members
+	if len(contract.BuyerPool) > 0 || len(contract.SellerPool) > 0 {
+		outcome.payouts = poolPayouts(contract, outcome.buyerPayout, outcome.sellerPayout)
+	}
+
+	return outcome
+}
+
+// proposeSettlement moves a contract to SETTLEMENT_PENDING with its proposed outcome,
+// opening the dispute window
+func (s *contractService) proposeSettlement(
+	ctx context.Context,
+	contract *Contract,
+	btcPerPHPerDay float64,
+	currentBlockHeight uint64,
+) (*Transaction, error) {
+	// 1. Record the proposed outcome on the contract
+	winnerID, loserID := settlementWinner(contract, btcPerPHPerDay)
+
+	previousStatus := contract.Status
+	contract.Status = SETTLEMENT_PENDING
+	contract.SettlementRate = btcPerPHPerDay
+	contract.ProposedWinnerID = winnerID
+	contract.SettlementProposedHeight = currentBlockHeight
+	contract.SettlementDisputeDeadline = currentBlockHeight + s.settlementDisputeWindow
+
+	if err := s.updateContract(ctx, contract, previousStatus); err != nil {
+		return nil, fmt.Errorf("failed to update contract status: %w", err)
+	}
+
+	// 2. Record the proposal
+	tx := &Transaction{
+		ID:              s.ids.NewID(),
+		Type:            SETTLEMENT_PROPOSAL,
+		Timestamp:       s.clock.Now().UTC(),
+		ContractID:      contract.ID,
+		UserIDs:         contractUserIDs(contract),
+		Amount:          contract.Size,
+		BTCPerPHPerDay:  btcPerPHPerDay,
+		BlockHeight:     contract.ExpiryBlockHeight,
+		Status:          "PENDING",
+		RelatedEntities: map[string]string{
+			"proposed_winner_id": winnerID,
+			"proposed_loser_id":  loserID,
+			"proposed_height":    fmt.Sprintf("%d", currentBlockHeight),
+			"dispute_deadline":   fmt.Sprintf("%d", contract.SettlementDisputeDeadline),
+			"expiry_block_hash":  contract.SettlementBlockHash,
+		},
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to record settlement proposal: %w", err)
+	}
+
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:          EVENT_SETTLEMENT_PROPOSED,
+		ContractID:    contract.ID,
+		UserIDs:       tx.UserIDs,
+		TransactionID: tx.ID,
+		Attributes: map[string]string{
+			"proposed_winner_id": winnerID,
+			"settlement_rate":    fmt.Sprintf("%.8f", btcPerPHPerDay),
+			"dispute_deadline":   tx.RelatedEntities["dispute_deadline"],
+		},
+	})
+
+	return tx, nil
+}
+
+// ConfirmSettlement implements ContractManager.ConfirmSettlement
+func (s *contractService) ConfirmSettlement(ctx context.Context, contractID string, userID string) (*Transaction, error) {
+	// 1. Get the contract
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, ErrContractNotFound
+	}
+
+	// 2. Validate contract status
+	if contract.Status != SETTLEMENT_PENDING {
+		return nil, ErrInvalidContractStatus
+	}
+
+	// 3. Only the proposed loser can waive the window, the winner has nothing to dispute.
+	// With no proposed winner (at the money) either party can confirm.
+	if contract.BuyerID != userID && contract.SellerID != userID {
+		return nil, ErrUserNotInContract
+	}
+	if userID == contract.ProposedWinnerID {
+		return nil, fmt.Errorf("%w: settlement must be confirmed by the counterparty", ErrInvalidParameters)
+	}
+
+	// 4. Finalize with the rate at the expiry block, re-read in case it was reorged
+	btcPerPHPerDay, err := s.readSettlementRate(ctx, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.finalizeSettlement(ctx, contract, btcPerPHPerDay, FEE_PRIORITY_NORMAL)
+}
+
+// DisputeSettlement implements ContractManager.DisputeSettlement
+func (s *contractService) DisputeSettlement(
+	ctx context.Context,
+	contractID string,
+	userID string,
+	evidence string,
+) (*Transaction, error) {
+	// 1. Get the contract
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, ErrContractNotFound
+	}
+
+	// 2. Validate contract status and that the window is still open
+	if contract.Status != SETTLEMENT_PENDING {
+		return nil, ErrInvalidContractStatus
+	}
+
+	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	if currentBlockHeight >= contract.SettlementDisputeDeadline {
+		return nil, ErrDisputeWindowClosed
+	}
+
+	// 2a. Only the proposed loser can dispute, the winner has nothing to dispute.
+	// With no proposed winner (at the money) either party can dispute.
+	if contract.BuyerID != userID && contract.SellerID != userID {
+		return nil, ErrUserNotInContract
+	}
+	if userID == contract.ProposedWinnerID {
+		return nil, fmt.Errorf("%w: settlement can only be disputed by the counterparty", ErrInvalidParameters)
+	}
+
+	// 3. Route to the dispute resolution exit path
+	tx, err := s.ExecuteExitPath(ctx, contractID, userID, "dispute_resolution", FEE_PRIORITY_NORMAL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute dispute resolution: %w", err)
+	}
+
+	// 4. Attach the dispute details to the exit transaction
+	tx.RelatedEntities["dispute_evidence"] = evidence
+	tx.RelatedEntities["proposed_winner_id"] = contract.ProposedWinnerID
+	tx.RelatedEntities["proposed_settlement_rate"] = fmt.Sprintf("%.8f", contract.SettlementRate)
+
+	if err := s.transactionRepo.Update(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to record dispute details: %w", err)
+	}
+
+	return tx, nil
+}
+
+// finalizeSettlement broadcasts the settlement of a contract at the given rate and marks it SETTLED.
+// The broadcast transactions reserve a fee estimated for the fee priority.
+func (s *contractService) finalizeSettlement(
+	ctx context.Context,
+	contract *Contract,
+	btcPerPHPerDay float64,
+	feePriority FeePriority,
+) (*Transaction, error) {
+	// 1. Determine winner (buyer or seller, or neither at the money)
+	outcome := computeSettlementOutcome(contract, btcPerPHPerDay, s.payoutPolicy, s.treasury.settlementFeeRate())
+	winnerID, loserID := outcome.winnerID, outcome.loserID
+
+	// 2. Calculate payouts, capped at the collateral each side posted and rounded
+	buyerPayout, buyerLiquidated := outcome.buyerPayout, outcome.buyerLiquidated
+	sellerPayout, sellerLiquidated := outcome.sellerPayout, outcome.sellerLiquidated
+
+	// 3. Generate settlement transaction
+	buyerVTXO, err := s.vtxoRepo.FindByID(ctx, contract.BuyerVTXO)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get buyer VTXO: %w", err)
 	}
 
 	sellerVTXO, err := s.vtxoRepo.FindByID(ctx, contract.SellerVTXO)
@@ -612,18 +1646,27 @@ func (s *contractService) SettleContract(ctx context.Context, contractID string)
 		return nil, fmt.Errorf("failed to get seller VTXO: %w", err)
 	}
 
-	// 8. Generate and broadcast settlement transaction
-	setupTx, err := s.scriptGen.GenerateSetupTransaction(ctx, contract, buyerVTXO, sellerVTXO)
+	// 4. Generate and broadcast settlement transaction
+	feeRate, err := s.estimateFeeRate(ctx, feePriority)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate setup transaction: %w", err)
+		return nil, err
 	}
 
-	setupTxID, err := s.btcClient.BroadcastTransaction(ctx, setupTx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to broadcast setup transaction: %w", err)
+	// Funded contracts already broadcast their setup transaction
+	setupTxID := contract.SetupTxID
+	if setupTxID == "" {
+		setupTx, err := s.scriptGen.GenerateSetupTransaction(ctx, contract, buyerVTXO, sellerVTXO, feeRate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate setup transaction: %w", err)
+		}
+
+		setupTxID, err = s.btcClient.BroadcastTransaction(ctx, setupTx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to broadcast setup transaction: %w", err)
+		}
 	}
 
-	finalTx, err := s.scriptGen.GenerateFinalTransaction(ctx, contract, setupTxID)
+	finalTx, err := s.scriptGen.GenerateFinalTransaction(ctx, contract, setupTxID, feeRate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate final transaction: %w", err)
 	}
@@ -633,7 +1676,7 @@ func (s *contractService) SettleContract(ctx context.Context, contractID string)
 		return nil, fmt.Errorf("failed to broadcast final transaction: %w", err)
 	}
 
-	settlementTx, err := s.scriptGen.GenerateSettlementTransaction(ctx, contract, finalTxID, winnerID)
+	settlementTx, err := s.scriptGen.GenerateSettlementTransaction(ctx, contract, finalTxID, winnerID, feeRate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate settlement transaction: %w", err)
 	}
@@ -643,16 +1686,19 @@ func (s *contractService) SettleContract(ctx context.Context, contractID string)
 		return nil, fmt.Errorf("failed to broadcast settlement transaction: %w", err)
 	}
 
-	// 9. Update contract status
+	// 5. Update contract status
+	previousStatus := contract.Status
 	contract.Status = SETTLED
 	contract.SettlementTx = settlementTxID
 	contract.SettlementRate = btcPerPHPerDay
+	contract.BuyerLiquidated = buyerLiquidated
+	contract.SellerLiquidated = sellerLiquidated
 
-	if err := s.contractRepo.Update(ctx, contract); err != nil {
+	if err := s.updateContract(ctx, contract, previousStatus); err != nil {
 		return nil, fmt.Errorf("failed to update contract status: %w", err)
 	}
 
-	// 10. Mark VTXOs as inactive
+	// 6. Mark VTXOs as inactive
 	buyerVTXO.IsActive = false
 	sellerVTXO.IsActive = false
 
@@ -664,13 +1710,27 @@ func (s *contractService) SettleContract(ctx context.Context, contractID string)
 		return nil, fmt.Errorf("failed to update seller VTXO: %w", err)
 	}
 
-	// 11. Record settlement transaction
+	for _, member := range append(append([]PoolMember{}, contract.BuyerPool...), contract.SellerPool...) {
+		memberVTXO, err := s.vtxoRepo.FindByID(ctx, member.VTXOID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pool member VTXO %s: %w", member.VTXOID, err)
+		}
+		if memberVTXO == nil {
+			return nil, fmt.Errorf("%w: pool member VTXO %s", ErrVTXONotFound, member.VTXOID)
+		}
+		memberVTXO.IsActive = false
+		if err := s.vtxoRepo.Update(ctx, memberVTXO); err != nil {
+			return nil, fmt.Errorf("failed to update pool member VTXO: %w", err)
+		}
+	}
+
+	// 7. Record settlement transaction
 	tx := &Transaction{
-		ID:              generateUniqueID(),
+		ID:              s.ids.NewID(),
 		Type:            CONTRACT_SETTLEMENT,
-		Timestamp:       time.Now().UTC(),
-		ContractID:      contractID,
-		UserIDs:         []string{contract.BuyerID, contract.SellerID},
+		Timestamp:       s.clock.Now().UTC(),
+		ContractID:      contract.ID,
+		UserIDs:         contractUserIDs(contract),
 		TxHash:          settlementTxID,
 		Amount:          contract.Size,
 		BTCPerPHPerDay:  btcPerPHPerDay,
@@ -680,16 +1740,191 @@ func (s *contractService) SettleContract(ctx context.Context, contractID string)
 			"seller_vtxo": contract.SellerVTXO,
 			"winner_id":   winnerID,
 			"loser_id":    loserID,
+			"leverage":          fmt.Sprintf("%.2f", contractLeverage(contract)),
+			"buyer_payout":      fmt.Sprintf("%.8f", buyerPayout),
+			"seller_payout":     fmt.Sprintf("%.8f", sellerPayout),
+			"buyer_liquidated":  fmt.Sprintf("%t", buyerLiquidated),
+			"seller_liquidated": fmt.Sprintf("%t", sellerLiquidated),
 		},
 	}
+	recordFee(tx.RelatedEntities, feePriority, feeRate)
+	recordPayoutPolicy(tx.RelatedEntities, s.payoutPolicy)
+	if outcome.settlementFee > 0 {
+		tx.RelatedEntities["settlement_fee"] = fmt.Sprintf("%.8f", outcome.settlementFee)
+	}
+	for userID, payout := range outcome.payouts {
+		tx.RelatedEntities["payout_"+userID] = fmt.Sprintf("%.8f", payout)
+	}
+
+	// 7a. Capture the fiat reference price, if configured
+	if s.fiatCurrency != "" {
+		btcPrice, err := s.priceProvider.GetBTCPrice(ctx, s.fiatCurrency)
+		if err != nil {
+			// Non-critical error, the settlement stands without a fiat reference
+			publishEvent(ctx, s.eventPublisher, Event{
+				Type:       EVENT_OPERATION_FAILED,
+				ContractID: contract.ID,
+				Operation:  "get_btc_" + s.fiatCurrency + "_price",
+				Error:      err.Error(),
+			})
+		} else if btcPrice > 0 {
+			recordFiatReference(tx.RelatedEntities, s.fiatCurrency, btcPrice, buyerPayout, sellerPayout)
+		}
+	}
 
 	if err := s.transactionRepo.Create(ctx, tx); err != nil {
 		return nil, fmt.Errorf("failed to record settlement transaction: %w", err)
 	}
 
+	// 8. Credit the payouts back to each party's balance
+	releasePayouts(ctx, s.userRepo, s.eventPublisher, contract.ID, "credit_settlement_payout",
+		sidePayouts(contract, buyerPayout, sellerPayout))
+	s.collectFee(ctx, contract, feeTypeSettlement, BTCToSats(outcome.settlementFee), tx.ID)
+
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:          EVENT_CONTRACT_SETTLED,
+		ContractID:    contract.ID,
+		UserIDs:       tx.UserIDs,
+		TransactionID: tx.ID,
+		Attributes: map[string]string{
+			"winner_id":       winnerID,
+			"settlement_rate": fmt.Sprintf("%.8f", btcPerPHPerDay),
+			"tx_hash":         settlementTxID,
+		},
+	})
+
 	return tx, nil
 }
 
+// GetSettlementResult implements ContractManager.GetSettlementResult
+func (s *contractService) GetSettlementResult(ctx context.Context, contractID string) (*SettlementResult, error) {
+	// 1. Get the contract
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, ErrContractNotFound
+	}
+
+	if contract.Status != SETTLED {
+		return nil, ErrInvalidContractStatus
+	}
+
+	// 2. Find the settlement transaction
+	txs, err := s.transactionRepo.FindByContract(ctx, contractID, []TransactionType{CONTRACT_SETTLEMENT}, SORT_ASCENDING)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract transactions: %w", err)
+	}
+
+	var settlementTx *Transaction
+	for _, tx := range txs {
+		if tx.Type == CONTRACT_SETTLEMENT {
+			settlementTx = tx
+			break
+		}
+	}
+	if settlementTx == nil {
+		return nil, ErrSettlementNotFound
+	}
+
+	// 3. Build the result from the recorded figures
+	return SettlementResultFromTransaction(settlementTx), nil
+}
+
+// recordFiatReference records the BTC price in a fiat currency and the payouts converted at
+// that price on a settlement transaction's related entities
+func recordFiatReference(entities map[string]string, currency string, btcPrice, buyerPayout, sellerPayout float64) {
+	entities["fiat_currency"] = currency
+	entities["btc_fiat_price"] = fmt.Sprintf("%.2f", btcPrice)
+	entities["buyer_payout_fiat"] = fmt.Sprintf("%.2f", buyerPayout*btcPrice)
+	entities["seller_payout_fiat"] = fmt.Sprintf("%.2f", sellerPayout*btcPrice)
+}
+
+// SettlementResultFromTransaction builds a settlement result from the figures recorded on a
+// CONTRACT_SETTLEMENT transaction
+func SettlementResultFromTransaction(settlementTx *Transaction) *SettlementResult {
+	entities := settlementTx.RelatedEntities
+	return &SettlementResult{
+		ContractID:       settlementTx.ContractID,
+		TransactionID:    settlementTx.ID,
+		TxHash:           settlementTx.TxHash,
+		SettlementRate:   settlementTx.BTCPerPHPerDay,
+		BlockHeight:      settlementTx.BlockHeight,
+		Timestamp:        settlementTx.Timestamp,
+		WinnerID:         entities["winner_id"],
+		BuyerPayout:      parseEntityFloat(entities, "buyer_payout"),
+		SellerPayout:     parseEntityFloat(entities, "seller_payout"),
+		FiatCurrency:     entities["fiat_currency"],
+		BTCFiatPrice:     parseEntityFloat(entities, "btc_fiat_price"),
+		BuyerPayoutFiat:  parseEntityFloat(entities, "buyer_payout_fiat"),
+		SellerPayoutFiat: parseEntityFloat(entities, "seller_payout_fiat"),
+	}
+}
+
+// PreviewSettlement implements ContractManager.PreviewSettlement
+func (s *contractService) PreviewSettlement(ctx context.Context, contractID string) (*SettlementPreview, error) {
+	// 1. Get the contract
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, ErrContractNotFound
+	}
+
+	// 2. Validate contract status
+	if !isLive(contract.Status) && contract.Status != SETTLEMENT_PENDING {
+		return nil, ErrInvalidContractStatus
+	}
+
+	// 3. Check if the contract has expired
+	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	if currentBlockHeight < contract.ExpiryBlockHeight {
+		return nil, fmt.Errorf("contract has not expired yet, current height %d < expiry height %d",
+			currentBlockHeight, contract.ExpiryBlockHeight)
+	}
+
+	// 4. Read the settlement rate exactly as settlement would
+	btcPerPHPerDay, err := s.readSettlementRate(ctx, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Compute the outcome without persisting or broadcasting anything
+	outcome := computeSettlementOutcome(contract, btcPerPHPerDay, s.payoutPolicy, s.treasury.settlementFeeRate())
+
+	return &SettlementPreview{
+		ContractID:       contract.ID,
+		SettlementRate:   btcPerPHPerDay,
+		BlockHeight:      contract.ExpiryBlockHeight,
+		WinnerID:         outcome.winnerID,
+		BuyerPayout:      outcome.buyerPayout,
+		SellerPayout:     outcome.sellerPayout,
+		BuyerLiquidated:  outcome.buyerLiquidated,
+		SellerLiquidated: outcome.sellerLiquidated,
+		SettlementFee:    outcome.settlementFee,
+		Payouts:          outcome.payouts,
+	}, nil
+}
+
+// parseEntityFloat reads a numeric related-entity value, returning 0 if it is missing or malformed
+func parseEntityFloat(entities map[string]string, key string) float64 {
+	value, ok := entities[key]
+	if !ok {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
 // ExitContract implements ContractManager.ExitContract
 func (s *contractService) ExitContract(
 	ctx context.Context,
@@ -711,7 +1946,7 @@ func (s *contractService) ExitContract(
 	}
 
 	// 3. Validate contract status
-	if contract.Status != ACTIVE {
+	if !isLive(contract.Status) {
 		return nil, ErrInvalidContractStatus
 	}
 
@@ -720,13 +1955,12 @@ func (s *contractService) ExitContract(
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current block height: %w", err)
 	}
-	s.blockHeight = currentBlockHeight // Update cached block height
 
-	hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight)
+	hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight, 0)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current hash rate: %w", err)
 	}
-	currentBTCPerPHPerDay := calculateBTCPerPHPerDay(hashRate)
+	currentBTCPerPHPerDay := calculateBTCPerPHPerDay(hashRate, currentBlockHeight)
 
 	// 5. Determine counterparty
 	var counterpartyID string
@@ -737,44 +1971,39 @@ func (s *contractService) ExitContract(
 	}
 
 	// 6. Calculate exit fee and settlement amount
-	// For early exit, apply a penalty to the exiting party
-	exitFee := contract.Size * 0.05 // 5% exit fee as an example
-	
-	// Calculate settlement based on current market conditions
-	// This is a simplified approach - in a real system, this would involve more complex pricing
-	var settlementAmount float64
-	if userID == contract.BuyerID {
-		if contract.ContractType == CALL && currentBTCPerPHPerDay > contract.StrikeRate {
-			// Buyer is in profit on a CALL
-			profit := (currentBTCPerPHPerDay - contract.StrikeRate) / contract.StrikeRate * contract.Size
-			settlementAmount = (contract.Size / 2) + profit - exitFee
-		} else if contract.ContractType == PUT && currentBTCPerPHPerDay < contract.StrikeRate {
-			// Buyer is in profit on a PUT
-			profit := (contract.StrikeRate - currentBTCPerPHPerDay) / contract.StrikeRate * contract.Size
-			settlementAmount = (contract.Size / 2) + profit - exitFee
-		} else {
-			// Buyer is not in profit
-			settlementAmount = (contract.Size / 2) - exitFee
-		}
+	// Calculate settlement based on current market conditions, capped at the
+	// collateral available on each side of the contract
+	isBuyer := userID == contract.BuyerID
+	pnl := positionPnL(contract, isBuyer, currentBTCPerPHPerDay)
+	ownCollateral, counterpartyCollateral := sideCollateral(contract, isBuyer), sideCollateral(contract, !isBuyer)
+	positionValue, liquidated := capPayout(ownCollateral, counterpartyCollateral, pnl)
+
+	// For early exit, charge the exiting party a fee on the collateral they posted, so that
+	// leverage never lets the fee take their whole position
+	exitFee := ownCollateral * contract.ExitFeeRate
+
+	settlementSats := BTCToSats(positionValue) - BTCToSats(exitFee)
+	if settlementSats < 0 {
+		settlementSats = 0
+	}
+
+	// The counterparty receives the rest of the locked collateral, including the exit fee
+	// unless a treasury account collects it
+	var collectedFeeSats int64
+	if s.treasury.UserID != "" {
+		collectedFeeSats = BTCToSats(positionValue) - settlementSats
+	}
+	counterpartySats := BTCToSats(ownCollateral) + BTCToSats(counterpartyCollateral) - settlementSats - collectedFeeSats
+	settlementAmount, counterpartyAmount := SatsToBTC(settlementSats), SatsToBTC(counterpartySats)
+	if isBuyer {
+		settlementAmount, counterpartyAmount = s.payoutPolicy.apply(settlementAmount, counterpartyAmount)
 	} else {
-		// Seller exit logic - inverse of buyer logic
-		if contract.ContractType == CALL && currentBTCPerPHPerDay < contract.StrikeRate {
-			// Seller is in profit on a CALL
-			profit := (contract.StrikeRate - currentBTCPerPHPerDay) / contract.StrikeRate * contract.Size
-			settlementAmount = (contract.Size / 2) + profit - exitFee
-		} else if contract.ContractType == PUT && currentBTCPerPHPerDay > contract.StrikeRate {
-			// Seller is in profit on a PUT
-			profit := (currentBTCPerPHPerDay - contract.StrikeRate) / contract.StrikeRate * contract.Size
-			settlementAmount = (contract.Size / 2) + profit - exitFee
-		} else {
-			// Seller is not in profit
-			settlementAmount = (contract.Size / 2) - exitFee
-		}
+		counterpartyAmount, settlementAmount = s.payoutPolicy.apply(counterpartyAmount, settlementAmount)
 	}
 
 	// 7. Generate early exit transaction using a mutual agreement exit path
 	exitPathType := "early_exit"
-	tx, err := s.ExecuteExitPath(ctx, contractID, userID, exitPathType)
+	tx, err := s.ExecuteExitPath(ctx, contractID, userID, exitPathType, FEE_PRIORITY_NORMAL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute exit path: %w", err)
 	}
@@ -784,12 +2013,207 @@ func (s *contractService) ExitContract(
 	tx.RelatedEntities["settlement_amount"] = fmt.Sprintf("%.8f", settlementAmount)
 	tx.RelatedEntities["exit_initiator"] = userID
 	tx.RelatedEntities["current_btc_ph_day"] = fmt.Sprintf("%.8f", currentBTCPerPHPerDay)
+	tx.RelatedEntities["leverage"] = fmt.Sprintf("%.2f", contractLeverage(contract))
+	tx.RelatedEntities["liquidated"] = fmt.Sprintf("%t", liquidated)
+	tx.RelatedEntities["at_the_money"] = fmt.Sprintf("%t", atTheMoney(contract, currentBTCPerPHPerDay))
+	recordPayoutPolicy(tx.RelatedEntities, s.payoutPolicy)
 
 	// 9. Update the transaction in the repository
 	if err := s.transactionRepo.Update(ctx, tx); err != nil {
 		return nil, fmt.Errorf("failed to update exit transaction: %w", err)
 	}
 
+	// 10. Mark the exiting position as liquidated if its losses exceeded its collateral
+	if liquidated {
+		exited, err := s.contractRepo.FindByID(ctx, contractID)
+		if err != nil || exited == nil {
+			return nil, fmt.Errorf("failed to reload contract after exit: %w", err)
+		}
+		if isBuyer {
+			exited.BuyerLiquidated = true
+		} else {
+			exited.SellerLiquidated = true
+		}
+		if err := s.contractRepo.Update(ctx, exited); err != nil {
+			return nil, fmt.Errorf("failed to mark position as liquidated: %w", err)
+		}
+	}
+
+	// 11. Credit the exiting party its settlement amount, the counterparty the rest of the
+	// locked collateral and the treasury the exit fee, if it collects it
+	buyerAmount, sellerAmount := settlementAmount, counterpartyAmount
+	if !isBuyer {
+		buyerAmount, sellerAmount = counterpartyAmount, settlementAmount
+	}
+	releasePayouts(ctx, s.userRepo, s.eventPublisher, contractID, "credit_exit_payout",
+		sidePayouts(contract, buyerAmount, sellerAmount))
+	s.collectFee(ctx, contract, feeTypeExit, collectedFeeSats, tx.ID)
+
+	return tx, nil
+}
+
+// SettleExpiredContracts implements ContractManager.SettleExpiredContracts
+// Contracts whose expiry block is not yet final or whose dispute window is still open
+// are skipped and picked up by a later run.
+func (s *contractService) SettleExpiredContracts(ctx context.Context) ([]*Transaction, error) {
+	// 1. Find expired active contracts and pending settlements
+	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	expired, err := s.contractRepo.FindByExpiryRange(ctx, 0, currentBlockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired contracts: %w", err)
+	}
+
+	pending, err := s.contractRepo.FindByStatus(ctx, SETTLEMENT_PENDING)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending settlements: %w", err)
+	}
+
+	// 2. Settle each one independently so a failure does not block the rest
+	var settlements []*Transaction
+	for _, contract := range append(expired, pending...) {
+		tx, err := s.SettleContract(ctx, contract.ID, FEE_PRIORITY_NORMAL)
+		if err != nil {
+			if !errors.Is(err, ErrBlockNotFinal) && !errors.Is(err, ErrDisputeWindowOpen) {
+				publishEvent(ctx, s.eventPublisher, Event{
+					Type:       EVENT_OPERATION_FAILED,
+					ContractID: contract.ID,
+					Operation:  "settle_expired_contract",
+					Error:      err.Error(),
+				})
+			}
+			continue
+		}
+		settlements = append(settlements, tx)
+	}
+
+	return settlements, nil
+}
+
+// CheckLiquidations implements ContractManager.CheckLiquidations
+func (s *contractService) CheckLiquidations(ctx context.Context) ([]*Transaction, error) {
+	// 1. Get all active contracts
+	contracts, err := s.contractRepo.FindActiveContracts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active contracts: %w", err)
+	}
+
+	if len(contracts) == 0 {
+		return []*Transaction{}, nil
+	}
+
+	// 2. Get the current rate once for the whole scan
+	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current hash rate: %w", err)
+	}
+	currentBTCPerPHPerDay := calculateBTCPerPHPerDay(hashRate, currentBlockHeight)
+
+	// 3. Liquidate any position whose equity has fallen below the maintenance margin
+	var liquidations []*Transaction
+	for _, contract := range contracts {
+		var liquidatedUserID string
+		var isBuyer bool
+		switch {
+		case belowMaintenanceMargin(contract, true, currentBTCPerPHPerDay):
+			liquidatedUserID = contract.BuyerID
+			isBuyer = true
+		case belowMaintenanceMargin(contract, false, currentBTCPerPHPerDay):
+			liquidatedUserID = contract.SellerID
+		default:
+			continue
+		}
+
+		tx, err := s.liquidatePosition(ctx, contract, liquidatedUserID, isBuyer, currentBTCPerPHPerDay)
+		if err != nil {
+			// Keep scanning, the next run will retry this contract
+			publishEvent(ctx, s.eventPublisher, Event{
+				Type:       EVENT_OPERATION_FAILED,
+				ContractID: contract.ID,
+				Operation:  "liquidate_contract",
+				Error:      err.Error(),
+			})
+			continue
+		}
+
+		liquidations = append(liquidations, tx)
+	}
+
+	return liquidations, nil
+}
+
+// liquidatePosition force-settles a contract through the liquidation exit path and
+// records the result as a LIQUIDATION transaction
+func (s *contractService) liquidatePosition(
+	ctx context.Context,
+	contract *Contract,
+	liquidatedUserID string,
+	isBuyer bool,
+	currentBTCPerPHPerDay float64,
+) (*Transaction, error) {
+	// 1. Force settlement via the exit path machinery
+	tx, err := s.executeExitPath(ctx, contract.ID, liquidatedUserID, liquidationExitPath, FEE_PRIORITY_FAST)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute liquidation exit path: %w", err)
+	}
+
+	// 2. Calculate the payouts for both sides
+	ownCollateral, counterpartyCollateral := sideCollateral(contract, isBuyer), sideCollateral(contract, !isBuyer)
+	pnl := positionPnL(contract, isBuyer, currentBTCPerPHPerDay)
+	liquidatedPayout, _ := capPayout(ownCollateral, counterpartyCollateral, pnl)
+	counterpartyPayout, _ := capPayout(counterpartyCollateral, ownCollateral, -pnl)
+	if isBuyer {
+		liquidatedPayout, counterpartyPayout = s.payoutPolicy.apply(liquidatedPayout, counterpartyPayout)
+	} else {
+		counterpartyPayout, liquidatedPayout = s.payoutPolicy.apply(counterpartyPayout, liquidatedPayout)
+	}
+
+	// 3. Re-type the transaction as a liquidation and add liquidation details
+	tx.Type = LIQUIDATION
+	tx.RelatedEntities["liquidation_price"] = fmt.Sprintf("%.8f", liquidationPrice(contract, isBuyer))
+	tx.RelatedEntities["current_btc_ph_day"] = fmt.Sprintf("%.8f", currentBTCPerPHPerDay)
+	tx.RelatedEntities["maintenance_margin"] = fmt.Sprintf("%.8f", contract.Size*maintenanceMarginRatio)
+	tx.RelatedEntities["leverage"] = fmt.Sprintf("%.2f", contractLeverage(contract))
+	tx.RelatedEntities["liquidated_payout"] = fmt.Sprintf("%.8f", liquidatedPayout)
+	tx.RelatedEntities["counterparty_payout"] = fmt.Sprintf("%.8f", counterpartyPayout)
+	recordPayoutPolicy(tx.RelatedEntities, s.payoutPolicy)
+
+	if err := s.transactionRepo.Update(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to update liquidation transaction: %w", err)
+	}
+
+	// 4. Mark the liquidated side on the contract
+	liquidated, err := s.contractRepo.FindByID(ctx, contract.ID)
+	if err != nil || liquidated == nil {
+		return nil, fmt.Errorf("failed to reload contract after liquidation: %w", err)
+	}
+
+	if isBuyer {
+		liquidated.BuyerLiquidated = true
+	} else {
+		liquidated.SellerLiquidated = true
+	}
+
+	if err := s.contractRepo.Update(ctx, liquidated); err != nil {
+		return nil, fmt.Errorf("failed to mark position as liquidated: %w", err)
+	}
+
+	// 5. Credit both sides their payouts
+	buyerPayout, sellerPayout := liquidatedPayout, counterpartyPayout
+	if !isBuyer {
+		buyerPayout, sellerPayout = counterpartyPayout, liquidatedPayout
+	}
+	releasePayouts(ctx, s.userRepo, s.eventPublisher, contract.ID, "credit_liquidation_payout",
+		sidePayouts(contract, buyerPayout, sellerPayout))
+
 	return tx, nil
 }
 
@@ -809,7 +2233,7 @@ func (s *contractService) RolloverContract(
 	}
 
 	// 2. Validate contract status
-	if contract.Status != ACTIVE {
+	if !isLive(contract.Status) {
 		return nil, nil, ErrInvalidContractStatus
 	}
 
@@ -818,7 +2242,6 @@ func (s *contractService) RolloverContract(
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get current block height: %w", err)
 	}
-	s.blockHeight = currentBlockHeight // Update cached block height
 
 	if newExpiryBlockHeight <= currentBlockHeight {
 		return nil, nil, fmt.Errorf("%w: new expiry must be in the future", ErrInvalidBlockHeight)
@@ -828,25 +2251,56 @@ func (s *contractService) RolloverContract(
 		return nil, nil, fmt.Errorf("%w: new expiry must be later than current expiry", ErrInvalidBlockHeight)
 	}
 
+	// The duration limit applies relative to the current height, not the old expiry,
+	// so a rollover can never extend a position further than a fresh contract could
+	if newExpiryBlockHeight > currentBlockHeight+s.durations.MaxContractBlocks {
+		return nil, nil, fmt.Errorf("%w: new expiry exceeds maximum duration of %d blocks from current height %d",
+			ErrInvalidBlockHeight, s.durations.MaxContractBlocks, currentBlockHeight)
+	}
+
+	// 3a. Enforce the rollover chain policy
+	if contract.RolloverCount >= maxRolloverChainLength {
+		return nil, nil, fmt.Errorf("%w: contract has already been rolled over %d times (maximum %d)",
+			ErrRolloverLimitExceeded, contract.RolloverCount, maxRolloverChainLength)
+	}
+
+	originBlockHeight := contract.OriginBlockHeight
+	if originBlockHeight == 0 {
+		// Contracts created before chain tracking start their chain here
+		originBlockHeight = currentBlockHeight
+	}
+
+	if newExpiryBlockHeight > originBlockHeight+maxRolloverTotalDuration {
+		return nil, nil, fmt.Errorf("%w: rollover chain would span %d blocks from origin height %d (maximum %d)",
+			ErrRolloverLimitExceeded, newExpiryBlockHeight-originBlockHeight, originBlockHeight, maxRolloverTotalDuration)
+	}
+
 	// 4. Get current hash rate for pricing
-	hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight)
+	hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight, 0)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get current hash rate: %w", err)
 	}
-	currentBTCPerPHPerDay := calculateBTCPerPHPerDay(hashRate)
+	currentBTCPerPHPerDay := calculateBTCPerPHPerDay(hashRate, currentBlockHeight)
 
 	// 5. Create a new contract with the same parameters but new expiry
 	newContract := &Contract{
-		ID:                generateUniqueID(),
+		ID:                s.ids.NewID(),
 		ContractType:      contract.ContractType,
 		StrikeRate:        contract.StrikeRate, // Could adjust based on market conditions
 		ExpiryBlockHeight: newExpiryBlockHeight,
-		ExpiryDate:        calculateExpiryDate(newExpiryBlockHeight, currentBlockHeight),
-		CreationTime:      time.Now().UTC(),
+		ExpiryDate:        s.blockTimes.expiryDate(ctx, newExpiryBlockHeight, currentBlockHeight),
+		CreationTime:      s.clock.Now().UTC(),
 		Status:            PENDING,
 		BuyerID:           contract.BuyerID,
 		SellerID:          contract.SellerID,
 		Size:              contract.Size,
+		Leverage:          contractLeverage(contract),
+		RolloverCount:     contract.RolloverCount + 1,
+		OriginBlockHeight: originBlockHeight,
+		SettlementMethod:  contractSettlementMethod(contract),
+		ExitFeeRate:       contract.ExitFeeRate,
+		TimeoutExitBlocks: contract.TimeoutExitBlocks,
+		SweepWindowBlocks: contract.SweepWindowBlocks,
 	}
 
 	// 6. Save the new contract
@@ -880,7 +2334,7 @@ func (s *contractService) RolloverContract(
 		ctx, 
 		newContract.ID, 
 		newContract.BuyerID, 
-		newContract.Size/2, 
+		sideCollateral(newContract, true), 
 		scripts["buyerScriptPath"], 
 		nil,
 	)
@@ -893,7 +2347,7 @@ func (s *contractService) RolloverContract(
 		ctx, 
 		newContract.ID, 
 		newContract.SellerID, 
-		newContract.Size/2, 
+		sideCollateral(newContract, false), 
 		scripts["sellerScriptPath"], 
 		nil,
 	)
@@ -903,20 +2357,22 @@ func (s *contractService) RolloverContract(
 		return nil, nil, fmt.Errorf("failed to create new seller VTXO: %w", err)
 	}
 
-	// 10. Update new contract with VTXO IDs and set to ACTIVE
+	// 10. Update new contract with VTXO IDs, it stays PENDING until both parties sign its
+	// setup transaction through FundContract
 	newContract.BuyerVTXO = newBuyerVTXO.ID
 	newContract.SellerVTXO = newSellerVTXO.ID
-	newContract.Status = ACTIVE
 
-	if err := s.contractRepo.Update(ctx, newContract); err != nil {
+	if err := s.updateContract(ctx, newContract, PENDING); err != nil {
 		return nil, nil, fmt.Errorf("failed to update new contract: %w", err)
 	}
+	s.storeContractScripts(ctx, newContract, scripts)
 
 	// 11. Mark original contract as ROLLED_OVER and reference new contract
+	previousStatus := contract.Status
 	contract.Status = ROLLED_OVER
 	contract.RolledOverToID = newContract.ID
 
-	if err := s.contractRepo.Update(ctx, contract); err != nil {
+	if err := s.updateContract(ctx, contract, previousStatus); err != nil {
 		return nil, nil, fmt.Errorf("failed to update original contract: %w", err)
 	}
 
@@ -934,9 +2390,9 @@ func (s *contractService) RolloverContract(
 
 	// 13. Record rollover transaction
 	tx := &Transaction{
-		ID:              generateUniqueID(),
+		ID:              s.ids.NewID(),
 		Type:            CONTRACT_ROLLOVER,
-		Timestamp:       time.Now().UTC(),
+		Timestamp:       s.clock.Now().UTC(),
 		ContractID:      contractID,
 		UserIDs:         []string{contract.BuyerID, contract.SellerID},
 		Amount:          contract.Size,
@@ -951,6 +2407,8 @@ func (s *contractService) RolloverContract(
 			"original_seller_vtxo":  contract.SellerVTXO,
 			"new_buyer_vtxo":        newContract.BuyerVTXO,
 			"new_seller_vtxo":       newContract.SellerVTXO,
+			"rollover_count":        fmt.Sprintf("%d", newContract.RolloverCount),
+			"origin_block_height":   fmt.Sprintf("%d", originBlockHeight),
 		},
 	}
 
@@ -1002,27 +2460,10 @@ func (s *contractService) validateContractParameters(
 	}
 
 	// 4. Validate expiry block height
-	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	currentBlockHeight, err := cachedBlockHeight(ctx, s.btcClient)
 	if err != nil {
-		return fmt.Errorf("failed to get current block height: %w", err)
-	}
-	s.blockHeight = currentBlockHeight // Update cached block height
-
-	if expiryBlockHeight <= currentBlockHeight {
-		return fmt.Errorf("%w: expiry block height must be in the future", ErrInvalidBlockHeight)
+		return err
 	}
 
-	// Validate minimum contract duration (e.g., at least 100 blocks ~16 hours)
-	const minBlockDuration = 100
-	if expiryBlockHeight < currentBlockHeight+minBlockDuration {
-		return fmt.Errorf("%w: contract duration too short, minimum %d blocks", ErrInvalidParameters, minBlockDuration)
-	}
-	
-	// Validate maximum contract duration (e.g., at most 52560 blocks ~1 year)
-	const maxBlockDuration = 52560 // ~1 year at 10 min per block
-	if expiryBlockHeight > currentBlockHeight+maxBlockDuration {
-		return fmt.Errorf("%w: contract duration too long, maximum %d blocks (~1 year)", ErrInvalidParameters, maxBlockDuration)
-	}
-
-	return nil
+	return s.durations.validateExpiry(expiryBlockHeight, currentBlockHeight)
 }
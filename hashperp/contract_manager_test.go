@@ -0,0 +1,143 @@
+package hashperp
+
+import "testing"
+
+func TestBlockSubsidy(t *testing.T) {
+	tests := []struct {
+		name        string
+		blockHeight uint64
+		want        float64
+	}{
+		{"genesis", 0, 50},
+		{"last block of the first epoch", halvingInterval - 1, 50},
+		{"first halving", halvingInterval, 25},
+		{"last block before the second halving", 2*halvingInterval - 1, 25},
+		{"second halving", 2 * halvingInterval, 12.5},
+		{"third halving", 630000, 6.25},
+		{"last block before the fourth halving", 839999, 6.25},
+		{"fourth halving", 840000, 3.125},
+		{"last satoshi subsidy", 32 * halvingInterval, 0.00000001},
+		{"subsidy rounds down to zero", 33 * halvingInterval, 0},
+		{"shift past the subsidy width", 64 * halvingInterval, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := blockSubsidy(tt.blockHeight); got != tt.want {
+				t.Errorf("blockSubsidy(%d) = %v, want %v", tt.blockHeight, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateBTCPerPHPerDay(t *testing.T) {
+	const networkHashRate = 600000 // PH/s
+
+	tests := []struct {
+		name            string
+		networkHashRate float64
+		blockHeight     uint64
+		want            float64
+	}{
+		{"before the fourth halving", networkHashRate, 839999, blocksPerDay * 6.25 / networkHashRate},
+		{"at the fourth halving", networkHashRate, 840000, blocksPerDay * 3.125 / networkHashRate},
+		{"after the fourth halving", networkHashRate, 840001, blocksPerDay * 3.125 / networkHashRate},
+		{"double the hash rate halves the rate", 2 * networkHashRate, 839999, blocksPerDay * 6.25 / (2 * networkHashRate)},
+		{"no subsidy left", networkHashRate, 64 * halvingInterval, 0},
+		{"zero hash rate", 0, 840000, 0},
+		{"negative hash rate", -networkHashRate, 840000, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateBTCPerPHPerDay(tt.networkHashRate, tt.blockHeight); got != tt.want {
+				t.Errorf("calculateBTCPerPHPerDay(%v, %d) = %v, want %v",
+					tt.networkHashRate, tt.blockHeight, got, tt.want)
+			}
+		})
+	}
+
+	// A halving halves the rate at the same hash rate
+	before := calculateBTCPerPHPerDay(networkHashRate, 839999)
+	after := calculateBTCPerPHPerDay(networkHashRate, 840000)
+	if after != before/2 {
+		t.Errorf("rate after the halving = %v, want half of %v", after, before)
+	}
+}
+
+func TestComputeSettlementOutcome(t *testing.T) {
+	const strike = 0.001
+	policy := PayoutPolicy{RoundingSats: 1}
+
+	tests := []struct {
+		name         string
+		contractType ContractType
+		rate         float64
+		wantWinner   string
+		wantLoser    string
+		wantBuyer    float64
+		wantSeller   float64
+	}{
+		{"CALL below the strike", CALL, 0.0009, "seller", "buyer", 0.4, 0.6},
+		{"CALL just below the strike", CALL, strike - 1e-12, "seller", "buyer", 0.5, 0.5},
+		{"CALL at the strike", CALL, strike, "", "", 0.5, 0.5},
+		{"CALL just above the strike", CALL, strike + 1e-12, "buyer", "seller", 0.5, 0.5},
+		{"CALL above the strike", CALL, 0.0011, "buyer", "seller", 0.6, 0.4},
+		{"PUT below the strike", PUT, 0.0009, "buyer", "seller", 0.6, 0.4},
+		{"PUT just below the strike", PUT, strike - 1e-12, "buyer", "seller", 0.5, 0.5},
+		{"PUT at the strike", PUT, strike, "", "", 0.5, 0.5},
+		{"PUT just above the strike", PUT, strike + 1e-12, "seller", "buyer", 0.5, 0.5},
+		{"PUT above the strike", PUT, 0.0011, "seller", "buyer", 0.4, 0.6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contract := &Contract{
+				ContractType: tt.contractType,
+				StrikeRate:   strike,
+				Size:         1,
+				Leverage:     1,
+				BuyerID:      "buyer",
+				SellerID:     "seller",
+			}
+
+			outcome := computeSettlementOutcome(contract, tt.rate, policy, 0)
+			if outcome.winnerID != tt.wantWinner || outcome.loserID != tt.wantLoser {
+				t.Errorf("winner, loser = %q, %q, want %q, %q",
+					outcome.winnerID, outcome.loserID, tt.wantWinner, tt.wantLoser)
+			}
+			if outcome.buyerPayout != tt.wantBuyer || outcome.sellerPayout != tt.wantSeller {
+				t.Errorf("payouts = %v, %v, want %v, %v",
+					outcome.buyerPayout, outcome.sellerPayout, tt.wantBuyer, tt.wantSeller)
+			}
+			if outcome.buyerLiquidated || outcome.sellerLiquidated {
+				t.Errorf("liquidated = %t, %t, want neither", outcome.buyerLiquidated, outcome.sellerLiquidated)
+			}
+		})
+	}
+}
+
+func TestSettlementFiatReference(t *testing.T) {
+	settlementTx := &Transaction{
+		ID:              "tx",
+		Type:            CONTRACT_SETTLEMENT,
+		ContractID:      "contract",
+		RelatedEntities: map[string]string{"buyer_payout": "0.60000000", "seller_payout": "0.40000000"},
+	}
+	recordFiatReference(settlementTx.RelatedEntities, "USD", 65000, 0.6, 0.4)
+
+	result := SettlementResultFromTransaction(settlementTx)
+	if result.FiatCurrency != "USD" || result.BTCFiatPrice != 65000 {
+		t.Errorf("fiat reference = %s %v, want USD 65000", result.FiatCurrency, result.BTCFiatPrice)
+	}
+	if result.BuyerPayoutFiat != 39000 || result.SellerPayoutFiat != 26000 {
+		t.Errorf("fiat payouts = %v, %v, want 39000, 26000", result.BuyerPayoutFiat, result.SellerPayoutFiat)
+	}
+
+	// A settlement recorded without a price reports no fiat figures
+	result = SettlementResultFromTransaction(&Transaction{RelatedEntities: map[string]string{"buyer_payout": "1"}})
+	if result.FiatCurrency != "" || result.BTCFiatPrice != 0 || result.BuyerPayoutFiat != 0 {
+		t.Errorf("fiat reference without a price = %s %v %v, want none",
+			result.FiatCurrency, result.BTCFiatPrice, result.BuyerPayoutFiat)
+	}
+}
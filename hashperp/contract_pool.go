@@ -0,0 +1,210 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// minPoolShare is the smallest share of a contract side, in BTC, a pool member can hold
+const minPoolShare = 0.0001
+
+// poolSide returns the pool, founding party and founding VTXO of one side of a contract
+func poolSide(contract *Contract, side PositionSide) (pool []PoolMember, founderID string, founderVTXO string) {
+	if side == SIDE_BUYER {
+		return contract.BuyerPool, contract.BuyerID, contract.BuyerVTXO
+	}
+	return contract.SellerPool, contract.SellerID, contract.SellerVTXO
+}
+
+// founderShare returns the share of a side's size still held by its founding party
+func founderShare(contract *Contract, side PositionSide) float64 {
+	pool, _, _ := poolSide(contract, side)
+	share := contract.Size
+	for _, member := range pool {
+		share -= member.Size
+	}
+	return share
+}
+
+// holdsPosition reports whether a user is a founding party or pool member on either side
+func holdsPosition(contract *Contract, userID string) bool {
+	if contract.BuyerID == userID || contract.SellerID == userID {
+		return true
+	}
+	for _, member := range append(append([]PoolMember{}, contract.BuyerPool...), contract.SellerPool...) {
+		if member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// contractUserIDs returns the founding parties of a contract followed by its pool members
+func contractUserIDs(contract *Contract) []string {
+	userIDs := []string{contract.BuyerID, contract.SellerID}
+	for _, member := range append(append([]PoolMember{}, contract.BuyerPool...), contract.SellerPool...) {
+		userIDs = append(userIDs, member.UserID)
+	}
+	return userIDs
+}
+
+// poolPayouts splits each side's payout pro-rata across the side's founding party and pool
+// members by the share of the side's size each holds, keyed by user ID
+func poolPayouts(contract *Contract, buyerPayout, sellerPayout float64) map[string]float64 {
+	payouts := make(map[string]float64)
+	if contract.Size <= 0 {
+		return payouts
+	}
+
+	// Split in satoshis with the rounding remainder going to the founder, so the shares sum
+	// to the side's payout exactly
+	for _, side := range []PositionSide{SIDE_BUYER, SIDE_SELLER} {
+		sidePayout := buyerPayout
+		if side == SIDE_SELLER {
+			sidePayout = sellerPayout
+		}
+
+		pool, founderID, _ := poolSide(contract, side)
+		userIDs := []string{founderID}
+		weights := []int64{BTCToSats(founderShare(contract, side))}
+		for _, member := range pool {
+			userIDs = append(userIDs, member.UserID)
+			weights = append(weights, BTCToSats(member.Size))
+		}
+
+		for i, share := range splitSatsProRata(BTCToSats(sidePayout), weights) {
+			payouts[userIDs[i]] += SatsToBTC(share)
+		}
+	}
+
+	return payouts
+}
+
+// SetUserRepository configures the repository of the public keys that join signatures are
+// verified against
+func (s *contractService) SetUserRepository(userRepo UserRepository) {
+	s.userRepo = userRepo
+}
+
+// verifyUserSignature checks a user's signature over a message against their registered public key
+func (s *contractService) verifyUserSignature(ctx context.Context, userID string, message []byte, signature []byte) error {
+	return verifySignedMessage(ctx, s.btcClient, s.userRepo, userID, message, signature)
+}
+
+// JoinContractSide implements ContractManager.JoinContractSide
+func (s *contractService) JoinContractSide(
+	ctx context.Context,
+	contractID string,
+	userID string,
+	side PositionSide,
+	amount float64,
+	signature []byte,
+) (*Contract, *Transaction, error) {
+	// 1. Validate parameters
+	if side != SIDE_BUYER && side != SIDE_SELLER {
+		return nil, nil, fmt.Errorf("%w: invalid side %q", ErrInvalidParameters, side)
+	}
+	if amount < minPoolShare {
+		return nil, nil, fmt.Errorf("%w: pool share must be at least %.4f BTC", ErrInvalidParameters, minPoolShare)
+	}
+
+	// 2. Get the contract and validate its status
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, nil, ErrContractNotFound
+	}
+	if contract.Status != ACTIVE {
+		return nil, nil, ErrInvalidContractStatus
+	}
+
+	// 3. A user holds at most one position in a contract
+	if holdsPosition(contract, userID) {
+		return nil, nil, fmt.Errorf("%w: user %s already holds a position in contract %s",
+			ErrInvalidParameters, userID, contractID)
+	}
+
+	// 4. The founding party must keep a share of the side
+	remaining := founderShare(contract, side)
+	if amount > remaining-minPoolShare {
+		return nil, nil, fmt.Errorf("%w: at most %.8f BTC of the %s side is available",
+			ErrInvalidParameters, remaining-minPoolShare, side)
+	}
+
+	// 5. Verify the joiner's signature
+	message := buildJoinMessage(contractID, userID, side, amount)
+	if err := s.verifyUserSignature(ctx, userID, message, signature); err != nil {
+		return nil, nil, err
+	}
+
+	// 6. Move the collateral backing the share from the founding party's VTXO to the joiner's
+	_, founderID, founderVTXOID := poolSide(contract, side)
+	founderVTXO, err := s.vtxoRepo.FindByID(ctx, founderVTXOID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get %s VTXO: %w", side, err)
+	}
+	if founderVTXO == nil {
+		return nil, nil, ErrVTXONotFound
+	}
+
+	collateral := SatsToBTC(collateralSats(BTCToSats(amount), contractLeverage(contract)))
+	if err := lockCollateral(ctx, s.userRepo, userID, collateral); err != nil {
+		return nil, nil, err
+	}
+
+	memberVTXO, err := s.createContractVTXO(ctx, contract.ID, userID, collateral, "pool_member", signature)
+	if err != nil {
+		_ = releaseCollateral(ctx, s.userRepo, userID, collateral)
+		return nil, nil, err
+	}
+
+	founderVTXO.Amount -= collateral
+	if err := s.vtxoRepo.Update(ctx, founderVTXO); err != nil {
+		return nil, nil, fmt.Errorf("failed to update %s VTXO: %w", side, err)
+	}
+	if err := releaseCollateral(ctx, s.userRepo, founderID, collateral); err != nil {
+		return nil, nil, err
+	}
+
+	// 7. Add the joiner to the side's pool
+	member := PoolMember{
+		UserID:   userID,
+		VTXOID:   memberVTXO.ID,
+		Size:     amount,
+		JoinedAt: s.clock.Now().UTC(),
+	}
+	if side == SIDE_BUYER {
+		contract.BuyerPool = append(contract.BuyerPool, member)
+	} else {
+		contract.SellerPool = append(contract.SellerPool, member)
+	}
+
+	if err := s.updateContract(ctx, contract, contract.Status); err != nil {
+		return nil, nil, fmt.Errorf("failed to update contract pool: %w", err)
+	}
+
+	// 8. Record the join
+	tx := &Transaction{
+		ID:          s.ids.NewID(),
+		Type:        POOL_JOIN,
+		Timestamp:   s.clock.Now().UTC(),
+		ContractID:  contract.ID,
+		UserIDs:     []string{userID, founderID},
+		Amount:      amount,
+		BlockHeight: recordedBlockHeight(ctx, s.btcClient, s.clock),
+		RelatedEntities: map[string]string{
+			"side":         string(side),
+			"member_vtxo":  memberVTXO.ID,
+			"founder_vtxo": founderVTXO.ID,
+			"collateral":   fmt.Sprintf("%.8f", collateral),
+		},
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		return nil, nil, fmt.Errorf("failed to record pool join transaction: %w", err)
+	}
+
+	return contract, tx, nil
+}
@@ -0,0 +1,94 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Keys of the parties' script paths in the scripts GenerateContractScripts returns
+const (
+	buyerScriptPathKey  = "buyerScriptPath"
+	sellerScriptPathKey = "sellerScriptPath"
+)
+
+// SetContractScriptRepository configures the repository the scripts generated for contracts
+// are stored in
+func (s *contractService) SetContractScriptRepository(scriptRepo ContractScriptRepository) {
+	s.scriptRepo = scriptRepo
+}
+
+// newContractScripts splits the scripts generated for a contract into the parties' script
+// paths and the exit scripts
+func newContractScripts(contractID string, scripts map[string]string) *ContractScripts {
+	contractScripts := &ContractScripts{
+		ContractID:       contractID,
+		BuyerScriptPath:  scripts[buyerScriptPathKey],
+		SellerScriptPath: scripts[sellerScriptPathKey],
+		ExitScripts:      make(map[string]string),
+	}
+	for name, script := range scripts {
+		if name != buyerScriptPathKey && name != sellerScriptPathKey {
+			contractScripts.ExitScripts[name] = script
+		}
+	}
+	return contractScripts
+}
+
+// storeContractScripts stores the scripts just generated for a contract. A failure is
+// published rather than returned, GetContractScripts regenerates scripts that were not stored.
+func (s *contractService) storeContractScripts(ctx context.Context, contract *Contract, scripts map[string]string) {
+	if s.scriptRepo == nil {
+		return
+	}
+
+	contractScripts := newContractScripts(contract.ID, scripts)
+	contractScripts.CreatedAt = s.clock.Now().UTC()
+	contractScripts.UpdatedAt = contractScripts.CreatedAt
+
+	if err := s.scriptRepo.Save(ctx, contractScripts); err != nil {
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: contract.ID,
+			Operation:  "store_contract_scripts",
+			Error:      err.Error(),
+		})
+	}
+}
+
+// GetContractScripts implements ContractManager.GetContractScripts
+func (s *contractService) GetContractScripts(ctx context.Context, contractID string) (*ContractScripts, error) {
+	if s.scriptRepo == nil {
+		return nil, errors.New("no contract script repository configured")
+	}
+
+	// 1. Return the stored scripts
+	contractScripts, err := s.scriptRepo.FindByContract(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract scripts: %w", err)
+	}
+	if contractScripts != nil {
+		return contractScripts, nil
+	}
+
+	// 2. Scripts of contracts created before they were stored, or whose storing failed, are
+	// regenerated from the contract's current parties
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, ErrContractNotFound
+	}
+
+	scripts, err := s.scriptGen.GenerateContractScripts(ctx, contract)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate contract scripts: %w", err)
+	}
+	s.storeContractScripts(ctx, contract, scripts)
+
+	contractScripts = newContractScripts(contract.ID, scripts)
+	contractScripts.CreatedAt = s.clock.Now().UTC()
+	contractScripts.UpdatedAt = contractScripts.CreatedAt
+	return contractScripts, nil
+}
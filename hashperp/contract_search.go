@@ -0,0 +1,60 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// validateContractFilter rejects filters the contract repository cannot serve from an index.
+// Every search must be anchored on a party or a bounded expiry range, the remaining criteria
+// only narrow the rows those select.
+func validateContractFilter(filter ContractFilter) error {
+	if filter.ContractType != "" && filter.ContractType != CALL && filter.ContractType != PUT {
+		return fmt.Errorf("%w: invalid contract type %q", ErrInvalidParameters, filter.ContractType)
+	}
+	if filter.MaxStrikeRate > 0 && filter.MaxStrikeRate < filter.MinStrikeRate {
+		return fmt.Errorf("%w: max strike rate is below min strike rate", ErrInvalidParameters)
+	}
+	if filter.MaxSize > 0 && filter.MaxSize < filter.MinSize {
+		return fmt.Errorf("%w: max size is below min size", ErrInvalidParameters)
+	}
+	if filter.MinStrikeRate < 0 || filter.MinSize < 0 {
+		return fmt.Errorf("%w: range bounds must not be negative", ErrInvalidParameters)
+	}
+
+	byParty := filter.UserID != "" || filter.CounterpartyID != ""
+	byExpiry := filter.ToExpiryHeight > 0
+	if byExpiry {
+		if filter.ToExpiryHeight < filter.FromExpiryHeight {
+			return fmt.Errorf("%w: range end %d is before range start %d",
+				ErrInvalidBlockHeight, filter.ToExpiryHeight, filter.FromExpiryHeight)
+		}
+		if filter.ToExpiryHeight-filter.FromExpiryHeight > maxSettlementHistorySpan {
+			return fmt.Errorf("%w: expiry range may span at most %d blocks",
+				ErrInvalidBlockHeight, maxSettlementHistorySpan)
+		}
+	}
+	if !byParty && !byExpiry {
+		return fmt.Errorf("%w: search must filter by user, counterparty or a bounded expiry range",
+			ErrInvalidParameters)
+	}
+
+	return nil
+}
+
+// SearchContracts implements ContractManager.SearchContracts
+func (s *contractService) SearchContracts(
+	ctx context.Context,
+	filter ContractFilter,
+	page PageRequest,
+) ([]*Contract, string, error) {
+	if err := validateContractFilter(filter); err != nil {
+		return nil, "", err
+	}
+
+	contracts, nextCursor, err := s.contractRepo.Search(ctx, filter, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to search contracts: %w", err)
+	}
+	return contracts, nextCursor, nil
+}
@@ -0,0 +1,151 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SetContractTemplateRepository configures the repository contract templates are published to
+func (s *contractService) SetContractTemplateRepository(templateRepo ContractTemplateRepository) {
+	s.templateRepo = templateRepo
+}
+
+// validateContractTemplate checks a template's bounds are consistent and fills its defaults
+func validateContractTemplate(template *ContractTemplate, durations DurationConfig) error {
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" {
+		return fmt.Errorf("%w: template name is required", ErrInvalidParameters)
+	}
+
+	if err := ValidateContractType(template.ContractType); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParameters, err)
+	}
+
+	if template.MinStrikeRate <= 0 || template.MaxStrikeRate < template.MinStrikeRate {
+		return fmt.Errorf("%w: strike range [%v, %v] is invalid",
+			ErrInvalidParameters, template.MinStrikeRate, template.MaxStrikeRate)
+	}
+
+	if len(template.ExpiryOffsets) == 0 {
+		return fmt.Errorf("%w: at least one expiry offset is required", ErrInvalidParameters)
+	}
+	for _, offset := range template.ExpiryOffsets {
+		if offset < durations.MinContractBlocks || offset > durations.MaxContractBlocks {
+			return fmt.Errorf("%w: expiry offset %d must be between %d and %d blocks",
+				ErrInvalidParameters, offset, durations.MinContractBlocks, durations.MaxContractBlocks)
+		}
+	}
+
+	if template.MinSize <= 0 || template.MaxSize < template.MinSize {
+		return fmt.Errorf("%w: size range [%v, %v] is invalid",
+			ErrInvalidParameters, template.MinSize, template.MaxSize)
+	}
+	if template.DefaultSize == 0 {
+		template.DefaultSize = template.MinSize
+	}
+	if template.DefaultSize < template.MinSize || template.DefaultSize > template.MaxSize {
+		return fmt.Errorf("%w: default size %v is outside the size range",
+			ErrInvalidParameters, template.DefaultSize)
+	}
+
+	if template.Leverage == 0 {
+		template.Leverage = defaultLeverage
+	}
+	// The smallest size posts the least collateral, so it bounds the leverage
+	if err := validateLeverage(template.MinSize, template.Leverage); err != nil {
+		return err
+	}
+
+	if template.SettlementMethod == "" {
+		template.SettlementMethod = SETTLEMENT_SPOT
+	}
+	return validateSettlementMethod(template.SettlementMethod)
+}
+
+// CreateContractTemplate implements ContractManager.CreateContractTemplate
+func (s *contractService) CreateContractTemplate(ctx context.Context, template *ContractTemplate) (*ContractTemplate, error) {
+	if s.templateRepo == nil {
+		return nil, fmt.Errorf("no contract template repository configured")
+	}
+
+	// 1. Validate the template and fill its defaults
+	if err := validateContractTemplate(template, s.durations); err != nil {
+		return nil, err
+	}
+
+	// 2. Save the template
+	template.ID = s.ids.NewID()
+	template.CreatedAt = s.clock.Now().UTC()
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to create contract template: %w", err)
+	}
+
+	return template, nil
+}
+
+// GetContractTemplates implements ContractManager.GetContractTemplates
+func (s *contractService) GetContractTemplates(ctx context.Context) ([]*ContractTemplate, error) {
+	if s.templateRepo == nil {
+		return []*ContractTemplate{}, nil
+	}
+
+	templates, err := s.templateRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// CreateContractFromTemplate implements ContractManager.CreateContractFromTemplate
+func (s *contractService) CreateContractFromTemplate(
+	ctx context.Context,
+	templateID string,
+	buyerID string,
+	sellerID string,
+	strikeRate float64,
+) (*Contract, error) {
+	if s.templateRepo == nil {
+		return nil, ErrTemplateNotFound
+	}
+
+	// 1. Get the template
+	template, err := s.templateRepo.FindByID(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract template: %w", err)
+	}
+	if template == nil {
+		return nil, ErrTemplateNotFound
+	}
+
+	// 2. Validate the strike against the template's range
+	if strikeRate < template.MinStrikeRate || strikeRate > template.MaxStrikeRate {
+		return nil, fmt.Errorf("%w: strike rate %v is outside [%v, %v] for template %s",
+			ErrTemplateViolation, strikeRate, template.MinStrikeRate, template.MaxStrikeRate, template.Name)
+	}
+
+	// 3. Expire at the template's default offset from the current block
+	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	expiryBlockHeight := currentBlockHeight + template.ExpiryOffsets[0]
+
+	// 4. Create the contract with the template's defaults
+	return s.CreateContract(
+		ctx,
+		buyerID,
+		sellerID,
+		template.ContractType,
+		strikeRate,
+		expiryBlockHeight,
+		template.DefaultSize,
+		template.Leverage,
+		template.SettlementMethod,
+		nil,
+		"",
+	)
+}
@@ -0,0 +1,148 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// RequestDynamicJoin implements ContractManager.RequestDynamicJoin
+func (s *contractService) RequestDynamicJoin(
+	ctx context.Context,
+	contractID string,
+	userID string,
+	side PositionSide,
+	signature []byte,
+) (*Contract, *Transaction, error) {
+	// 1. Validate parameters
+	if side != SIDE_BUYER && side != SIDE_SELLER {
+		return nil, nil, fmt.Errorf("%w: invalid side %q", ErrInvalidParameters, side)
+	}
+	if userID == "" {
+		return nil, nil, fmt.Errorf("%w: user ID is required", ErrInvalidParameters)
+	}
+
+	// 2. Get the contract and validate its status
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, nil, ErrContractNotFound
+	}
+	if contract.Status != ACTIVE {
+		return nil, nil, ErrInvalidContractStatus
+	}
+
+	// 3. The requested side must be open and the joiner must not already hold a position
+	_, partyID, _ := poolSide(contract, side)
+	if partyID != "" {
+		return nil, nil, fmt.Errorf("%w: %s side of contract %s is already filled",
+			ErrDynamicJoinRejected, side, contractID)
+	}
+	if holdsPosition(contract, userID) {
+		return nil, nil, fmt.Errorf("%w: user %s already holds a position in contract %s",
+			ErrDynamicJoinRejected, userID, contractID)
+	}
+
+	// 4. The opposite side must still post the collateral the joiner is trading against
+	oppositeSide := SIDE_SELLER
+	if side == SIDE_SELLER {
+		oppositeSide = SIDE_BUYER
+	}
+	_, counterpartyID, counterpartyVTXOID := poolSide(contract, oppositeSide)
+	if counterpartyID == "" || counterpartyVTXOID == "" {
+		return nil, nil, fmt.Errorf("%w: contract %s has no %s to trade against",
+			ErrDynamicJoinRejected, contractID, oppositeSide)
+	}
+
+	counterpartyVTXO, err := s.vtxoRepo.FindByID(ctx, counterpartyVTXOID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get %s VTXO: %w", oppositeSide, err)
+	}
+	counterpartyCollateral := sideCollateral(contract, oppositeSide == SIDE_BUYER)
+	if counterpartyVTXO == nil || !counterpartyVTXO.IsActive || counterpartyVTXO.Amount < counterpartyCollateral {
+		return nil, nil, fmt.Errorf("%w: insufficient %s collateral, %.8f BTC required",
+			ErrDynamicJoinRejected, oppositeSide, counterpartyCollateral)
+	}
+
+	// 5. The contract must run long enough after the join
+	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	if contract.ExpiryBlockHeight < currentBlockHeight+s.durations.MinContractBlocks {
+		return nil, nil, fmt.Errorf("%w: contract expires in less than %d blocks",
+			ErrDynamicJoinRejected, s.durations.MinContractBlocks)
+	}
+
+	// 6. Verify the joiner's signature
+	message := buildDynamicJoinMessage(contractID, userID, side)
+	if err := s.verifyUserSignature(ctx, userID, message, signature); err != nil {
+		return nil, nil, err
+	}
+
+	// 7. Generate the joiner's script path and create their VTXO
+	if side == SIDE_BUYER {
+		contract.BuyerID = userID
+	} else {
+		contract.SellerID = userID
+	}
+
+	scripts, err := s.scriptGen.GenerateContractScripts(ctx, contract)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate contract scripts: %w", err)
+	}
+
+	scriptPath := scripts["sellerScriptPath"]
+	if side == SIDE_BUYER {
+		scriptPath = scripts["buyerScriptPath"]
+	}
+
+	collateral := sideCollateral(contract, side == SIDE_BUYER)
+	if err := lockCollateral(ctx, s.userRepo, userID, collateral); err != nil {
+		return nil, nil, err
+	}
+
+	joinerVTXO, err := s.createContractVTXO(ctx, contract.ID, userID, collateral, scriptPath, signature)
+	if err != nil {
+		_ = releaseCollateral(ctx, s.userRepo, userID, collateral)
+		return nil, nil, err
+	}
+
+	// 8. Fill the side
+	if side == SIDE_BUYER {
+		contract.BuyerVTXO = joinerVTXO.ID
+	} else {
+		contract.SellerVTXO = joinerVTXO.ID
+	}
+
+	if err := s.updateContract(ctx, contract, contract.Status); err != nil {
+		_ = s.vtxoRepo.Delete(ctx, joinerVTXO.ID)
+		_ = releaseCollateral(ctx, s.userRepo, userID, collateral)
+		return nil, nil, fmt.Errorf("failed to update contract: %w", err)
+	}
+	s.storeContractScripts(ctx, contract, scripts)
+
+	// 9. Record the join
+	tx := &Transaction{
+		ID:          s.ids.NewID(),
+		Type:        DYNAMIC_JOIN,
+		Timestamp:   s.clock.Now().UTC(),
+		ContractID:  contract.ID,
+		UserIDs:     []string{userID, counterpartyID},
+		Amount:      contract.Size,
+		BlockHeight: currentBlockHeight,
+		RelatedEntities: map[string]string{
+			"side":        string(side),
+			"joiner_vtxo": joinerVTXO.ID,
+			"collateral":  fmt.Sprintf("%.8f", collateral),
+		},
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		return nil, nil, fmt.Errorf("failed to record dynamic join transaction: %w", err)
+	}
+
+	return contract, tx, nil
+}
@@ -0,0 +1,76 @@
+package hashperp
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// EventType identifies a lifecycle event
+type EventType string
+
+const (
+	EVENT_CONTRACT_CREATED         EventType = "CONTRACT_CREATED"
+	EVENT_CONTRACT_FUNDED          EventType = "CONTRACT_FUNDED"   // Carries the setup transaction, the contract is now ACTIVE
+	EVENT_CONTRACT_CANCELED        EventType = "CONTRACT_CANCELED" // Carries the party that canceled the unfunded contract
+	EVENT_SETTLEMENT_PROPOSED      EventType = "SETTLEMENT_PROPOSED"
+	EVENT_CONTRACT_SETTLED         EventType = "CONTRACT_SETTLED"
+	EVENT_VTXO_SWAPPED             EventType = "VTXO_SWAPPED"
+	EVENT_VTXO_SPLIT               EventType = "VTXO_SPLIT" // Carries the child VTXOs the split created
+	EVENT_EXIT_EXECUTED            EventType = "EXIT_EXECUTED"
+	EVENT_CONTRACT_CLOSE_TO_EXPIRY EventType = "CLOSE_TO_EXPIRY"          // Carries the estimated expiry time and each side's current PnL
+	EVENT_OPERATION_FAILED         EventType = "OPERATION_FAILED"         // A follow-up step failed after the main operation took effect
+	EVENT_RECONCILIATION_CORRECTED EventType = "RECONCILIATION_CORRECTED" // A record was corrected to match the chain
+	EVENT_SELF_TRADE_PREVENTED     EventType = "SELF_TRADE_PREVENTED"     // Carries the policy applied and the orders involved
+)
+
+// Event is a structured lifecycle event emitted by the managers
+type Event struct {
+	Type          EventType         `json:"type"`
+	Timestamp     time.Time         `json:"timestamp"`
+	ContractID    string            `json:"contract_id,omitempty"`
+	VTXOID        string            `json:"vtxo_id,omitempty"`
+	UserIDs       []string          `json:"user_ids,omitempty"`
+	TransactionID string            `json:"transaction_id,omitempty"`
+	Operation     string            `json:"operation,omitempty"` // Failed step, for OPERATION_FAILED events
+	Error         string            `json:"error,omitempty"`
+	Attributes    map[string]string `json:"attributes,omitempty"`
+}
+
+// publishEvent stamps an event with the current time and hands it to a publisher
+func publishEvent(ctx context.Context, publisher EventPublisher, event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	publisher.Publish(ctx, event)
+}
+
+// noopEventPublisher is the default EventPublisher. It discards every event.
+type noopEventPublisher struct{}
+
+// NewNoopEventPublisher creates an EventPublisher that discards events
+func NewNoopEventPublisher() EventPublisher {
+	return noopEventPublisher{}
+}
+
+// Publish implements EventPublisher.Publish
+func (noopEventPublisher) Publish(ctx context.Context, event Event) {}
+
+// loggingEventPublisher writes each event to the standard logger as JSON
+type loggingEventPublisher struct{}
+
+// NewLoggingEventPublisher creates an EventPublisher that logs events as JSON
+func NewLoggingEventPublisher() EventPublisher {
+	return loggingEventPublisher{}
+}
+
+// Publish implements EventPublisher.Publish
+func (loggingEventPublisher) Publish(ctx context.Context, event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("event %s: %+v", event.Type, event)
+		return
+	}
+	log.Printf("event %s", payload)
+}
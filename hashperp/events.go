@@ -0,0 +1,88 @@
+package hashperp
+
+import (
+	"sync"
+	"time"
+)
+
+// contractEventBufferSize is the number of undelivered events held per subscriber
+// before further events for that subscriber are dropped
+const contractEventBufferSize = 16
+
+// ContractStatusEvent describes a contract moving from one status to another
+type ContractStatusEvent struct {
+	ContractID     string         `json:"contract_id"`
+	PreviousStatus ContractStatus `json:"previous_status"`
+	Status         ContractStatus `json:"status"`
+	Contract       *Contract      `json:"contract"`
+	Timestamp      time.Time      `json:"timestamp"`
+}
+
+// ContractEventBus fans contract status events out to per-contract subscribers
+type ContractEventBus interface {
+	// Publish delivers an event to every subscriber of the event's contract without blocking
+	Publish(event ContractStatusEvent)
+
+	// Subscribe registers for events on a contract. The returned function cancels the
+	// subscription and closes the channel; it is safe to call more than once.
+	Subscribe(contractID string) (<-chan ContractStatusEvent, func())
+}
+
+// contractEventBus implements the ContractEventBus interface
+type contractEventBus struct {
+	mu          sync.RWMutex
+	nextID      uint64
+	subscribers map[string]map[uint64]chan ContractStatusEvent
+}
+
+// NewContractEventBus creates a new in-process contract event bus
+func NewContractEventBus() ContractEventBus {
+	return &contractEventBus{
+		subscribers: make(map[string]map[uint64]chan ContractStatusEvent),
+	}
+}
+
+// Publish implements ContractEventBus.Publish
+func (b *contractEventBus) Publish(event ContractStatusEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subscribers[event.ContractID] {
+		select {
+		case ch <- event:
+		default:
+			// Slow subscriber, drop the event rather than stall the publisher
+		}
+	}
+}
+
+// Subscribe implements ContractEventBus.Subscribe
+func (b *contractEventBus) Subscribe(contractID string) (<-chan ContractStatusEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	ch := make(chan ContractStatusEvent, contractEventBufferSize)
+	if b.subscribers[contractID] == nil {
+		b.subscribers[contractID] = make(map[uint64]chan ContractStatusEvent)
+	}
+	b.subscribers[contractID][id] = ch
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers[contractID], id)
+			if len(b.subscribers[contractID]) == 0 {
+				delete(b.subscribers, contractID)
+			}
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
@@ -0,0 +1,152 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultExpiryNoticeBlocks is how close to expiry a contract is flagged CLOSE_TO_EXPIRY
+const defaultExpiryNoticeBlocks = blocksPerDay
+
+// SetExpiryNoticeBlocks configures how many blocks before expiry a contract is flagged
+// CLOSE_TO_EXPIRY. A window of 0 disables expiry notices.
+func (s *contractService) SetExpiryNoticeBlocks(blocks uint64) {
+	s.expiryNoticeBlocks = blocks
+}
+
+// GetContractsNearingExpiry implements ContractManager.GetContractsNearingExpiry
+func (s *contractService) GetContractsNearingExpiry(ctx context.Context, withinBlocks uint64) ([]*Contract, error) {
+	if withinBlocks == 0 || withinBlocks > maxExpiryRangeSpan {
+		return nil, fmt.Errorf("%w: window must be between 1 and %d blocks", ErrInvalidBlockHeight, maxExpiryRangeSpan)
+	}
+
+	// A pure read, served at the last known height while the node is unreachable
+	reading, err := readBlockHeight(ctx, s.btcClient, s.clock)
+	if err != nil {
+		return nil, err
+	}
+	currentBlockHeight := reading.BlockHeight
+
+	// Contracts at or past their expiry block are left to settlement
+	return s.GetContractsByExpiryRange(ctx, currentBlockHeight+1, currentBlockHeight+withinBlocks)
+}
+
+// NotifyContractsNearingExpiry implements ContractManager.NotifyContractsNearingExpiry
+func (s *contractService) NotifyContractsNearingExpiry(ctx context.Context) ([]*Contract, error) {
+	if s.expiryNoticeBlocks == 0 {
+		return []*Contract{}, nil
+	}
+
+	// 1. Find contracts within the notice window
+	contracts, err := s.GetContractsNearingExpiry(ctx, s.expiryNoticeBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*Contract
+	for _, contract := range contracts {
+		if contract.Status == ACTIVE {
+			pending = append(pending, contract)
+		}
+	}
+	if len(pending) == 0 {
+		return []*Contract{}, nil
+	}
+
+	// 2. Get the current rate once to value every position
+	currentBlockHeight, err := cachedBlockHeight(ctx, s.btcClient)
+	if err != nil {
+		return nil, err
+	}
+	hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current hash rate: %w", err)
+	}
+	currentBTCPerPHPerDay := calculateBTCPerPHPerDay(hashRate, currentBlockHeight)
+
+	// 3. Flag and notify each contract independently so a failure does not block the rest
+	var notified []*Contract
+	for _, contract := range pending {
+		if err := s.notifyContractNearingExpiry(ctx, contract, currentBlockHeight, currentBTCPerPHPerDay); err != nil {
+			publishEvent(ctx, s.eventPublisher, Event{
+				Type:       EVENT_OPERATION_FAILED,
+				ContractID: contract.ID,
+				Operation:  "notify_contract_nearing_expiry",
+				Error:      err.Error(),
+			})
+			continue
+		}
+		notified = append(notified, contract)
+	}
+
+	return notified, nil
+}
+
+// notifyContractNearingExpiry moves a contract to CLOSE_TO_EXPIRY and tells its parties when it
+// is expected to settle and what each position is worth at the current rate
+func (s *contractService) notifyContractNearingExpiry(
+	ctx context.Context,
+	contract *Contract,
+	currentBlockHeight uint64,
+	currentBTCPerPHPerDay float64,
+) error {
+	// 1. Flag the contract
+	previousStatus := contract.Status
+	contract.Status = CLOSE_TO_EXPIRY
+	if err := s.updateContract(ctx, contract, previousStatus); err != nil {
+		return fmt.Errorf("failed to update contract status: %w", err)
+	}
+
+	// 2. Value each position as if it settled now
+	buyerCollateral, sellerCollateral := sideCollateral(contract, true), sideCollateral(contract, false)
+	buyerPayout, _ := capPayout(buyerCollateral, sellerCollateral, positionPnL(contract, true, currentBTCPerPHPerDay))
+	sellerPayout, _ := capPayout(sellerCollateral, buyerCollateral, positionPnL(contract, false, currentBTCPerPHPerDay))
+
+	attributes := map[string]string{
+		"expiry_block_height":   fmt.Sprintf("%d", contract.ExpiryBlockHeight),
+		"blocks_remaining":      fmt.Sprintf("%d", contract.ExpiryBlockHeight-currentBlockHeight),
+		"estimated_expiry_time": s.blockTimes.expiryDate(ctx, contract.ExpiryBlockHeight, currentBlockHeight).Format(time.RFC3339),
+		"btc_ph_day":            fmt.Sprintf("%.8f", currentBTCPerPHPerDay),
+		"buyer_pnl":             fmt.Sprintf("%.8f", buyerPayout-buyerCollateral),
+		"seller_pnl":            fmt.Sprintf("%.8f", sellerPayout-sellerCollateral),
+	}
+	userIDs := contractUserIDs(contract)
+
+	// 3. Record the notice, which queues webhook deliveries to the parties
+	tx := &Transaction{
+		ID:              s.ids.NewID(),
+		Type:            EXPIRY_NOTICE,
+		Timestamp:       s.clock.Now().UTC(),
+		ContractID:      contract.ID,
+		UserIDs:         userIDs,
+		BTCPerPHPerDay:  currentBTCPerPHPerDay,
+		BlockHeight:     currentBlockHeight,
+		RelatedEntities: attributes,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: contract.ID,
+			UserIDs:    userIDs,
+			Operation:  "record_expiry_notice",
+			Error:      err.Error(),
+		})
+		tx = nil
+	}
+
+	// 4. Publish the lifecycle event
+	event := Event{
+		Type:       EVENT_CONTRACT_CLOSE_TO_EXPIRY,
+		ContractID: contract.ID,
+		UserIDs:    userIDs,
+		Attributes: attributes,
+	}
+	if tx != nil {
+		event.TransactionID = tx.ID
+	}
+	publishEvent(ctx, s.eventPublisher, event)
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package hashperp
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// ErrSigningKeyUnavailable is returned when a key store has no usable key for a key ID
+var ErrSigningKeyUnavailable = errors.New("signing key is not available")
+
+// SWAP_SIGNING_KEY_ID identifies the key that signs position swap authorizations
+const SWAP_SIGNING_KEY_ID = "swap"
+
+// KeyStore holds the service's signing keys. Implementations backed by an HSM or KMS can
+// sign without ever exposing the key, in which case GetSigningKey returns
+// ErrSigningKeyUnavailable and only Sign is usable.
+type KeyStore interface {
+	// GetSigningKey retrieves the private key with the given ID
+	GetSigningKey(ctx context.Context, keyID string) (*btcec.PrivateKey, error)
+
+	// Sign signs a 32-byte hash with the key with the given ID and returns the 65-byte
+	// compact signature [RecoveryID+27+4 || R || S]
+	Sign(ctx context.Context, keyID string, hash []byte) ([]byte, error)
+}
+
+// envKeyStore implements the KeyStore interface with hex-encoded keys read from environment
+// variables. It is meant for development, production deployments should use an HSM or KMS.
+type envKeyStore struct{}
+
+// NewEnvKeyStore creates a key store that reads each key from HASHPERP_SIGNING_KEY_<KEY ID>,
+// falling back to HASHPERP_SIGNING_KEY for the swap key
+func NewEnvKeyStore() KeyStore {
+	return envKeyStore{}
+}
+
+// GetSigningKey implements KeyStore.GetSigningKey
+func (envKeyStore) GetSigningKey(ctx context.Context, keyID string) (*btcec.PrivateKey, error) {
+	envVar := "HASHPERP_SIGNING_KEY_" + strings.ToUpper(keyID)
+	privKeyHex := os.Getenv(envVar)
+	if privKeyHex == "" && keyID == SWAP_SIGNING_KEY_ID {
+		envVar = "HASHPERP_SIGNING_KEY"
+		privKeyHex = os.Getenv(envVar)
+	}
+	if privKeyHex == "" {
+		return nil, fmt.Errorf("%w: %s is not set", ErrSigningKeyUnavailable, envVar)
+	}
+
+	privKeyBytes, err := hex.DecodeString(privKeyHex)
+	if err != nil || len(privKeyBytes) != btcec.PrivKeyBytesLen {
+		return nil, fmt.Errorf("%w: %s is not a hex-encoded 32-byte key", ErrSigningKeyUnavailable, envVar)
+	}
+
+	privateKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), privKeyBytes)
+	return privateKey, nil
+}
+
+// Sign implements KeyStore.Sign
+func (k envKeyStore) Sign(ctx context.Context, keyID string, hash []byte) ([]byte, error) {
+	privateKey, err := k.GetSigningKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := btcec.SignCompact(btcec.S256(), privateKey, hash, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign with key %s: %w", keyID, err)
+	}
+	return signature, nil
+}
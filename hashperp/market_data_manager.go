@@ -0,0 +1,214 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// hashRateBufferSize is the number of undelivered updates held per subscriber
+// before further updates for that subscriber are dropped
+const hashRateBufferSize = 4
+
+// marketDataService implements the MarketDataManager interface
+type marketDataService struct {
+	hashRateRepo HashRateRepository
+	btcClient    BitcoinClient
+	clock        Clock // Source of timestamps
+
+	eventPublisher EventPublisher
+
+	mu          sync.Mutex
+	nextID      uint64
+	subscribers map[uint64]chan *HashRateData
+}
+
+// NewMarketDataManager creates a new market data service. Subscribers receive hash rate data
+// for each new block passed to HandleNewBlock, which the block height poller calls.
+func NewMarketDataManager(hashRateRepo HashRateRepository, btcClient BitcoinClient) MarketDataManager {
+	return &marketDataService{
+		hashRateRepo: hashRateRepo,
+		btcClient:    btcClient,
+		subscribers:  make(map[uint64]chan *HashRateData),
+		clock:        NewSystemClock(),
+
+		eventPublisher: NewNoopEventPublisher(),
+	}
+}
+
+// SetEventPublisher sets the publisher that receives failures to store or poll hash rate data
+func (s *marketDataService) SetEventPublisher(publisher EventPublisher) {
+	s.eventPublisher = publisher
+}
+
+// GetCurrentHashRate implements MarketDataManager.GetCurrentHashRate
+func (s *marketDataService) GetCurrentHashRate(ctx context.Context) (*HashRateData, error) {
+	blockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	return s.GetHashRateAtBlockHeight(ctx, blockHeight)
+}
+
+// GetHistoricalHashRate implements MarketDataManager.GetHistoricalHashRate
+func (s *marketDataService) GetHistoricalHashRate(ctx context.Context, startTime, endTime time.Time) ([]*HashRateData, error) {
+	data, err := s.hashRateRepo.FindByTimeRange(ctx, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get historical hash rate: %w", err)
+	}
+	return data, nil
+}
+
+// GetHashRateAtBlockHeight implements MarketDataManager.GetHashRateAtBlockHeight
+func (s *marketDataService) GetHashRateAtBlockHeight(ctx context.Context, blockHeight uint64) (*HashRateData, error) {
+	// 1. Use the stored observation if we have one
+	data, err := s.hashRateRepo.FindByBlockHeight(ctx, blockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hash rate data: %w", err)
+	}
+	if data != nil {
+		return data, nil
+	}
+
+	// 2. Otherwise compute it from the node
+	hashRate, err := s.btcClient.GetBlockHashRate(ctx, blockHeight, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hash rate: %w", err)
+	}
+
+	btcPerPHPerDay, err := s.CalculateBTCPerPHPerDay(ctx, hashRate, blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	data = &HashRateData{
+		Timestamp:      s.clock.Now().UTC(),
+		BlockHeight:    blockHeight,
+		HashRate:       hashRate,
+		BTCPerPHPerDay: btcPerPHPerDay,
+	}
+
+	// 3. Store it for later lookups
+	if err := s.hashRateRepo.Create(ctx, data); err != nil {
+		// Non-critical error, the computed value is still valid
+		s.reportHashRateFailure(ctx, "store_hash_rate", blockHeight, err)
+	}
+
+	return data, nil
+}
+
+// CalculateBTCPerPHPerDay implements MarketDataManager.CalculateBTCPerPHPerDay
+// A non-positive hash rate is derived from the difficulty at the given block height.
+func (s *marketDataService) CalculateBTCPerPHPerDay(ctx context.Context, hashRate float64, blockHeight uint64) (float64, error) {
+	if hashRate <= 0 {
+		networkHashRate, err := s.btcClient.GetBlockHashRate(ctx, blockHeight, 0)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get network hash rate: %w", err)
+		}
+		if networkHashRate <= 0 {
+			return 0, fmt.Errorf("%w: network hash rate must be positive", ErrInvalidRate)
+		}
+		hashRate = networkHashRate
+	}
+	return calculateBTCPerPHPerDay(hashRate, blockHeight), nil
+}
+
+// GetAverageHashRate implements MarketDataManager.GetAverageHashRate
+func (s *marketDataService) GetAverageHashRate(ctx context.Context, endBlockHeight uint64, windowBlocks uint64) (float64, error) {
+	return averageBlockHashRate(ctx, s.btcClient, endBlockHeight, windowBlocks, 0)
+}
+
+// averageBlockHashRate averages the network hash rate over the windowBlocks blocks ending at
+// endBlockHeight, requiring minConfirmations on the end block. The node computes the average
+// from the window's work and timestamps in a single call.
+func averageBlockHashRate(
+	ctx context.Context,
+	btcClient BitcoinClient,
+	endBlockHeight uint64,
+	windowBlocks uint64,
+	minConfirmations uint64,
+) (float64, error) {
+	if windowBlocks == 0 || windowBlocks > endBlockHeight+1 {
+		return 0, fmt.Errorf("%w: window of %d blocks ending at block %d", ErrInvalidBlockHeight, windowBlocks, endBlockHeight)
+	}
+
+	hashRate, err := btcClient.GetNetworkHashRate(ctx, windowBlocks, endBlockHeight, minConfirmations)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get average hash rate over %d blocks ending at block %d: %w",
+			windowBlocks, endBlockHeight, err)
+	}
+
+	return hashRate, nil
+}
+
+// SubscribeToHashRate implements MarketDataManager.SubscribeToHashRate
+func (s *marketDataService) SubscribeToHashRate() (<-chan *HashRateData, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+
+	ch := make(chan *HashRateData, hashRateBufferSize)
+	s.subscribers[id] = ch
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			delete(s.subscribers, id)
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// HandleNewBlock broadcasts the hash rate at a new block to the subscribers. It is called by
+// the block height poller, so no subscriber triggers its own reads of the block height.
+func (s *marketDataService) HandleNewBlock(ctx context.Context, blockHeight uint64) {
+	s.mu.Lock()
+	subscribed := len(s.subscribers) > 0
+	s.mu.Unlock()
+	if !subscribed {
+		return
+	}
+
+	data, err := s.GetHashRateAtBlockHeight(ctx, blockHeight)
+	if err != nil {
+		s.reportHashRateFailure(ctx, "poll_hash_rate", blockHeight, err)
+		return
+	}
+	s.broadcastHashRate(data)
+}
+
+// reportHashRateFailure publishes a failure to read or store the hash rate at a block height
+func (s *marketDataService) reportHashRateFailure(ctx context.Context, operation string, blockHeight uint64, err error) {
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:      EVENT_OPERATION_FAILED,
+		Operation: operation,
+		Error:     err.Error(),
+		Attributes: map[string]string{
+			"block_height": strconv.FormatUint(blockHeight, 10),
+		},
+	})
+}
+
+// broadcastHashRate delivers hash rate data to every subscriber without blocking
+func (s *marketDataService) broadcastHashRate(data *HashRateData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ch := range s.subscribers {
+		select {
+		case ch <- data:
+		default:
+			// Slow subscriber, drop the update rather than stall the poller
+		}
+	}
+}
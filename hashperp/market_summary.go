@@ -0,0 +1,55 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// settledVolumeWindow is how far back GetMarketSummary sums settled volume
+const settledVolumeWindow = 24 * time.Hour
+
+// GetMarketSummary implements HashPerpService.GetMarketSummary
+func (s *hashPerpService) GetMarketSummary(ctx context.Context) (*MarketSummary, error) {
+	if s.contractRepo == nil || s.transactionRepo == nil {
+		return nil, errors.New("no repositories configured for market summaries")
+	}
+
+	// 1. Aggregate active contracts in the database rather than loading them
+	totals, err := s.contractRepo.SumActiveByType(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum active contracts: %w", err)
+	}
+
+	// 2. Sum the settlements of the last 24 hours
+	now := s.clock.Now().UTC()
+	settlements, settledVolume, err := s.transactionRepo.SumAmountSince(ctx, CONTRACT_SETTLEMENT, now.Add(-settledVolumeWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum settled volume: %w", err)
+	}
+
+	// 3. Get the current rates
+	hashRate, err := s.marketDataManager.GetCurrentHashRate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current hash rate: %w", err)
+	}
+
+	summary := &MarketSummary{
+		Timestamp:             now,
+		BlockHeight:           hashRate.BlockHeight,
+		ActiveContractsByType: make(map[ContractType]int, len(totals)),
+		SettledVolume24h:      settledVolume,
+		Settlements24h:        settlements,
+		HashRate:              hashRate.HashRate,
+		BTCPerPHPerDay:        hashRate.BTCPerPHPerDay,
+	}
+
+	for contractType, total := range totals {
+		summary.ActiveContractsByType[contractType] = total.Count
+		summary.ActiveContracts += total.Count
+		summary.OpenInterest += total.Size
+	}
+
+	return summary, nil
+}
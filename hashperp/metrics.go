@@ -0,0 +1,107 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Metric outcome labels
+const (
+	OUTCOME_SUCCESS  = "success"
+	OUTCOME_FAILURE  = "failure"
+	OUTCOME_PROPOSED = "proposed" // Settlement proposed, waiting out its dispute window
+	OUTCOME_DEFERRED = "deferred" // Settlement not yet possible, retried by a later run
+)
+
+// MetricOutcome returns the outcome label of an operation that returned err
+func MetricOutcome(err error) string {
+	if err != nil {
+		return OUTCOME_FAILURE
+	}
+	return OUTCOME_SUCCESS
+}
+
+// settlementOutcomeLabel returns the outcome label of a SettleContract call
+func settlementOutcomeLabel(tx *Transaction, err error) string {
+	switch {
+	case errors.Is(err, ErrBlockNotFinal), errors.Is(err, ErrDisputeWindowOpen):
+		return OUTCOME_DEFERRED
+	case err != nil:
+		return OUTCOME_FAILURE
+	case tx != nil && tx.Type == SETTLEMENT_PROPOSAL:
+		return OUTCOME_PROPOSED
+	default:
+		return OUTCOME_SUCCESS
+	}
+}
+
+// noopMetricsRecorder is the default MetricsRecorder. It discards every measurement.
+type noopMetricsRecorder struct{}
+
+// NewNoopMetricsRecorder creates a MetricsRecorder that discards measurements
+func NewNoopMetricsRecorder() MetricsRecorder {
+	return noopMetricsRecorder{}
+}
+
+func (noopMetricsRecorder) ContractCreated(contractType ContractType, outcome string)     {}
+func (noopMetricsRecorder) ContractSettlement(contractType ContractType, outcome string)  {}
+func (noopMetricsRecorder) OrdersMatched(outcome string, contracts int)                   {}
+func (noopMetricsRecorder) VTXOSwapped(outcome string)                                    {}
+func (noopMetricsRecorder) RPCCall(method string, outcome string, duration time.Duration) {}
+func (noopMetricsRecorder) SetContractsByStatus(counts map[ContractStatus]int)            {}
+func (noopMetricsRecorder) SetOrdersByStatus(counts map[OrderStatus]int)                  {}
+func (noopMetricsRecorder) SetSwapOffersByStatus(counts map[SwapOfferStatus]int)          {}
+
+// StatusMetricsCollector refreshes the gauges of records by status
+type StatusMetricsCollector interface {
+	// Collect counts contracts, orders and swap offers by status and records the counts
+	Collect(ctx context.Context) error
+}
+
+// statusMetricsCollector implements the StatusMetricsCollector interface
+type statusMetricsCollector struct {
+	contractRepo  ContractRepository
+	orderRepo     OrderRepository
+	swapOfferRepo SwapOfferRepository
+	recorder      MetricsRecorder
+}
+
+// NewStatusMetricsCollector creates a collector that records status counts from the repositories
+func NewStatusMetricsCollector(
+	contractRepo ContractRepository,
+	orderRepo OrderRepository,
+	swapOfferRepo SwapOfferRepository,
+	recorder MetricsRecorder,
+) StatusMetricsCollector {
+	return &statusMetricsCollector{
+		contractRepo:  contractRepo,
+		orderRepo:     orderRepo,
+		swapOfferRepo: swapOfferRepo,
+		recorder:      recorder,
+	}
+}
+
+// Collect implements StatusMetricsCollector.Collect
+func (c *statusMetricsCollector) Collect(ctx context.Context) error {
+	contractCounts, err := c.contractRepo.CountByStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count contracts by status: %w", err)
+	}
+	c.recorder.SetContractsByStatus(contractCounts)
+
+	orderCounts, err := c.orderRepo.CountByStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count orders by status: %w", err)
+	}
+	c.recorder.SetOrdersByStatus(orderCounts)
+
+	offerCounts, err := c.swapOfferRepo.CountByStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count swap offers by status: %w", err)
+	}
+	c.recorder.SetSwapOffersByStatus(offerCounts)
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package hashperp
+
+import (
+	"fmt"
+)
+
+// Network is the Bitcoin network the service runs against. Address encodings differ between
+// networks, so the node, the client and the script generator must all agree on it.
+type Network string
+
+const (
+	NETWORK_MAINNET Network = "mainnet"
+	NETWORK_TESTNET Network = "testnet"
+	NETWORK_REGTEST Network = "regtest"
+	NETWORK_SIGNET  Network = "signet"
+)
+
+// ParseNetwork parses a network name, rejecting unknown networks
+func ParseNetwork(name string) (Network, error) {
+	network := Network(name)
+	if network.ChainName() == "" {
+		return "", fmt.Errorf("%w: network must be %q, %q, %q or %q", ErrInvalidParameters,
+			NETWORK_MAINNET, NETWORK_TESTNET, NETWORK_REGTEST, NETWORK_SIGNET)
+	}
+	return network, nil
+}
+
+// ChainName returns the chain name a node on this network reports in getblockchaininfo,
+// or "" for an unknown network
+func (n Network) ChainName() string {
+	switch n {
+	case NETWORK_MAINNET:
+		return "main"
+	case NETWORK_TESTNET:
+		return "test"
+	case NETWORK_REGTEST:
+		return "regtest"
+	case NETWORK_SIGNET:
+		return "signet"
+	}
+	return ""
+}
+
+// Bech32HRP returns the human-readable part of the network's segwit and taproot addresses
+func (n Network) Bech32HRP() string {
+	switch n {
+	case NETWORK_MAINNET:
+		return "bc"
+	case NETWORK_REGTEST:
+		return "bcrt"
+	case NETWORK_TESTNET, NETWORK_SIGNET:
+		return "tb"
+	}
+	return ""
+}
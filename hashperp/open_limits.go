@@ -0,0 +1,60 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// Default caps on what a single user may have open at once, keeping one user from flooding
+// the order book or the swap market. A cap of 0 disables it.
+const (
+	defaultMaxOpenOrdersPerUser     = 100
+	defaultMaxOpenSwapOffersPerUser = 50
+)
+
+// SetMaxOpenOrdersPerUser configures how many OPEN orders a user may have, 0 disables the cap
+func (s *orderBookService) SetMaxOpenOrdersPerUser(max int) {
+	s.maxOpenOrders = max
+}
+
+// checkOpenOrderLimit rejects a new order from a user who already has the maximum number of
+// OPEN orders. Concurrent placements can each pass the check, so the cap may be exceeded
+// by the number of orders placed at the same time.
+func (s *orderBookService) checkOpenOrderLimit(ctx context.Context, userID string) error {
+	if s.maxOpenOrders <= 0 {
+		return nil
+	}
+
+	open, err := s.orderRepo.CountOpenByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to count open orders: %w", err)
+	}
+	if open >= s.maxOpenOrders {
+		return fmt.Errorf("%w: %d open orders, cancel one before placing another",
+			ErrOpenOrderLimit, s.maxOpenOrders)
+	}
+	return nil
+}
+
+// SetMaxOpenSwapOffersPerUser configures how many OPEN swap offers a user may have, 0 disables the cap
+func (s *swapOfferService) SetMaxOpenSwapOffersPerUser(max int) {
+	s.maxOpenOffers = max
+}
+
+// checkOpenOfferLimit rejects a new swap offer from a user who already has the maximum number
+// of OPEN offers. replacing is the number of the user's open offers the new offer cancels.
+func (s *swapOfferService) checkOpenOfferLimit(ctx context.Context, offerorID string, replacing int) error {
+	if s.maxOpenOffers <= 0 {
+		return nil
+	}
+
+	open, err := s.swapOfferRepo.CountOpenByOfferor(ctx, offerorID)
+	if err != nil {
+		return fmt.Errorf("failed to count open swap offers: %w", err)
+	}
+	if open-replacing >= s.maxOpenOffers {
+		return fmt.Errorf("%w: %d open swap offers, cancel one before creating another",
+			ErrOpenSwapOfferLimit, s.maxOpenOffers)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// OrderBookAction identifies the change an order book update carries
+type OrderBookAction string
+
+const (
+	ORDER_BOOK_INSERT OrderBookAction = "INSERT" // A new order rests in the book
+	ORDER_BOOK_UPDATE OrderBookAction = "UPDATE" // An open order was reduced or modified
+	ORDER_BOOK_CANCEL OrderBookAction = "CANCEL" // An order left the book by cancellation
+	ORDER_BOOK_MATCH  OrderBookAction = "MATCH"  // An order left the book by matching
+	ORDER_BOOK_EXPIRE OrderBookAction = "EXPIRE" // An order left the book by reaching its expiry block
+)
+
+// OrderBookSnapshot is the open orders of a market together with the sequence number of
+// the last change they reflect
+type OrderBookSnapshot struct {
+	ContractType      ContractType `json:"contract_type"`
+	ExpiryBlockHeight uint64       `json:"expiry_block_height"`
+	Sequence          uint64       `json:"sequence"`
+	Orders            []*Order     `json:"orders"`
+}
+
+// OrderBookUpdate is a single change to a market's order book. Sequence numbers increase by
+// one per change within a market, so a client that sees a gap must take a new snapshot.
+type OrderBookUpdate struct {
+	ContractType      ContractType    `json:"contract_type"`
+	ExpiryBlockHeight uint64          `json:"expiry_block_height"`
+	Sequence          uint64          `json:"sequence"`
+	Action            OrderBookAction `json:"action"`
+	Order             *Order          `json:"order"` // State of the order after the change
+	Timestamp         time.Time       `json:"timestamp"`
+}
+
+// orderBookUpdateBufferSize is the number of undelivered updates held per subscriber before
+// further updates for that subscriber are dropped
+const orderBookUpdateBufferSize = 64
+
+// orderBookKey identifies a market's order book
+func orderBookKey(contractType ContractType, expiryBlockHeight uint64) string {
+	return fmt.Sprintf("%s-%d", contractType, expiryBlockHeight)
+}
+
+// orderBookUpdateBus fans order book updates out to per-market subscribers
+type orderBookUpdateBus struct {
+	mu          sync.RWMutex
+	nextID      uint64
+	subscribers map[string]map[uint64]chan OrderBookUpdate
+}
+
+// newOrderBookUpdateBus creates an empty order book update bus
+func newOrderBookUpdateBus() *orderBookUpdateBus {
+	return &orderBookUpdateBus{
+		subscribers: make(map[string]map[uint64]chan OrderBookUpdate),
+	}
+}
+
+// publish delivers an update to every subscriber of its market without blocking
+func (b *orderBookUpdateBus) publish(update OrderBookUpdate) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subscribers[orderBookKey(update.ContractType, update.ExpiryBlockHeight)] {
+		select {
+		case ch <- update:
+		default:
+			// Slow subscriber, it detects the gap in sequence numbers and re-snapshots
+		}
+	}
+}
+
+// subscribe registers for updates on a market. The returned function cancels the
+// subscription and closes the channel; it is safe to call more than once.
+func (b *orderBookUpdateBus) subscribe(contractType ContractType, expiryBlockHeight uint64) (<-chan OrderBookUpdate, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := orderBookKey(contractType, expiryBlockHeight)
+	id := b.nextID
+	b.nextID++
+
+	ch := make(chan OrderBookUpdate, orderBookUpdateBufferSize)
+	if b.subscribers[key] == nil {
+		b.subscribers[key] = make(map[uint64]chan OrderBookUpdate)
+	}
+	b.subscribers[key][id] = ch
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers[key], id)
+			if len(b.subscribers[key]) == 0 {
+				delete(b.subscribers, key)
+			}
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// SubscribeToOrderBook implements OrderBookManager.SubscribeToOrderBook
+func (s *orderBookService) SubscribeToOrderBook(contractType ContractType, expiryBlockHeight uint64) (<-chan OrderBookUpdate, func()) {
+	return s.bookUpdates.subscribe(contractType, expiryBlockHeight)
+}
+
+// recordOrderBookChange advances the sequence of an order's market after the order was
+// written and streams the change. The sequence is advanced after the write, so a snapshot
+// taken at sequence N reflects every change up to N.
+func (s *orderBookService) recordOrderBookChange(ctx context.Context, order *Order, action OrderBookAction) {
+	sequence, err := s.orderRepo.NextBookSequence(ctx, order.ContractType, order.ExpiryBlockHeight)
+	if err != nil {
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:      EVENT_OPERATION_FAILED,
+			UserIDs:   []string{order.UserID},
+			Operation: "advance_order_book_sequence",
+			Error:     err.Error(),
+			Attributes: map[string]string{
+				"order_id": order.ID,
+			},
+		})
+		return
+	}
+
+	snapshot := *order
+	s.bookUpdates.publish(OrderBookUpdate{
+		ContractType:      order.ContractType,
+		ExpiryBlockHeight: order.ExpiryBlockHeight,
+		Sequence:          sequence,
+		Action:            action,
+		Order:             &snapshot,
+		Timestamp:         s.clock.Now().UTC(),
+	})
+}
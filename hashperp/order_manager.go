@@ -5,7 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"sort"
-	"time"
+)
+
+// Order errors
+var (
+	ErrOrderNotFound         = errors.New("order not found")
+	ErrClientOrderIDConflict = errors.New("client order ID already used with different parameters")
 )
 
 // OrderRepository defines the data access interface for orders
@@ -13,8 +18,16 @@ type OrderRepository interface {
 	Create(ctx context.Context, order *Order) error
 	FindByID(ctx context.Context, id string) (*Order, error)
 	FindByUser(ctx context.Context, userID string, status []OrderStatus) ([]*Order, error)
+	FindByClientOrderID(ctx context.Context, userID string, clientOrderID string) (*Order, error)
+	CountOpenByUser(ctx context.Context, userID string) (int, error)
+	NextBookSequence(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) (uint64, error)
+	GetBookSequence(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) (uint64, error)
 	FindByContractType(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) ([]*Order, error)
+	FindBestLevel(ctx context.Context, contractType ContractType, expiryBlockHeight uint64, orderType OrderType) (*OrderBookLevel, error)
 	FindOpenOrders(ctx context.Context) ([]*Order, error)
+	FindExpiredOpenOrders(ctx context.Context, currentBlockHeight uint64) ([]*Order, error)
+	CountOpenOrders(ctx context.Context) (int, error)
+	CountByStatus(ctx context.Context) (map[OrderStatus]int, error)
 	Update(ctx context.Context, order *Order) error
 	Delete(ctx context.Context, id string) error
 }
@@ -26,7 +39,16 @@ type orderBookService struct {
 	contractMgr    ContractManager
 	transactionRepo TransactionRepository
 	btcClient      BitcoinClient
-	blockHeight    uint64 // Current block height, regularly updated
+	snapshotRepo   MarketSnapshotRepository
+	metrics        MetricsRecorder // Operational metrics
+	blockTimes     *blockIntervalEstimator // Projects expiry blocks to dates
+	eventPublisher EventPublisher // Lifecycle events for downstream integrations
+	selfTradePolicy SelfTradePolicy // Default handling of a user's orders matching each other
+	maxOpenOrders  int // OPEN orders allowed per user, 0 disables the cap
+	priceBand      PriceBandConfig // Allowed deviation of order strikes from the market
+	priceBandExempt map[string]bool // Market makers the price band does not apply to
+	bookUpdates    *orderBookUpdateBus // Sequenced order book changes for streaming clients
+	clock          Clock // Source of timestamps
 }
 
 // NewOrderBookService creates a new order book service
@@ -36,6 +58,7 @@ func NewOrderBookService(
 	contractMgr ContractManager,
 	transactionRepo TransactionRepository,
 	btcClient BitcoinClient,
+	snapshotRepo MarketSnapshotRepository,
 ) OrderBookManager {
 	return &orderBookService{
 		orderRepo:      orderRepo,
@@ -43,9 +66,28 @@ func NewOrderBookService(
 		contractMgr:    contractMgr,
 		transactionRepo: transactionRepo,
 		btcClient:      btcClient,
+		snapshotRepo:   snapshotRepo,
+		metrics:        NewNoopMetricsRecorder(),
+		blockTimes:     newBlockIntervalEstimator(btcClient),
+		eventPublisher: NewNoopEventPublisher(),
+		selfTradePolicy: SELF_TRADE_SKIP,
+		maxOpenOrders:  defaultMaxOpenOrdersPerUser,
+		priceBand:      DefaultPriceBandConfig(),
+		bookUpdates:    newOrderBookUpdateBus(),
+		clock:          NewSystemClock(),
 	}
 }
 
+// SetMetricsRecorder configures where operational metrics are recorded
+func (s *orderBookService) SetMetricsRecorder(recorder MetricsRecorder) {
+	s.metrics = recorder
+}
+
+// SetEventPublisher configures where lifecycle events are published
+func (s *orderBookService) SetEventPublisher(publisher EventPublisher) {
+	s.eventPublisher = publisher
+}
+
 // PlaceOrder implements OrderBookManager.PlaceOrder
 func (s *orderBookService) PlaceOrder(
 	ctx context.Context,
@@ -55,6 +97,9 @@ func (s *orderBookService) PlaceOrder(
 	strikeRate float64,
 	expiryBlockHeight uint64,
 	size float64,
+	clientOrderID string,
+	selfTradePolicy SelfTradePolicy,
+	timeInForce TimeInForce,
 ) (*Order, error) {
 	// 1. Validate inputs
 	if orderType != BUY && orderType != SELL {
@@ -73,19 +118,45 @@ func (s *orderBookService) PlaceOrder(
 		return nil, errors.New("size must be positive")
 	}
 
+	if err := ValidateSelfTradePolicy(selfTradePolicy); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateTimeInForce(timeInForce); err != nil {
+		return nil, err
+	}
+	if timeInForce == "" {
+		timeInForce = TIF_GTC
+	}
+
+	// 1a. Return the existing order if this client order ID was already submitted
+	if clientOrderID != "" {
+		existing, err := s.orderRepo.FindByClientOrderID(ctx, userID, clientOrderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up client order ID: %w", err)
+		}
+		if existing != nil {
+			return resolveDuplicateOrder(existing, orderType, contractType, strikeRate, expiryBlockHeight, size)
+		}
+	}
+
+	// 1b. Cap the user's open orders, a retried order above is not a new one
+	if err := s.checkOpenOrderLimit(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// 2. Validate expiry block height
-	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	currentBlockHeight, err := cachedBlockHeight(ctx, s.btcClient)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current block height: %w", err)
+		return nil, err
 	}
-	s.blockHeight = currentBlockHeight // Update cached block height
 
 	if expiryBlockHeight <= currentBlockHeight {
 		return nil, ErrInvalidBlockHeight
 	}
 
 	// 3. Calculate human-readable expiry date
-	expiryDate := calculateExpiryDate(expiryBlockHeight, currentBlockHeight)
+	expiryDate := s.blockTimes.expiryDate(ctx, expiryBlockHeight, currentBlockHeight)
 
 	// 4. Create the order
 	order := &Order{
@@ -98,22 +169,51 @@ func (s *orderBookService) PlaceOrder(
 		ExpiryDate:        expiryDate,
 		Size:              size,
 		Status:            OPEN,
-		CreationTime:      time.Now().UTC(),
+		CreationTime:      s.clock.Now().UTC(),
+		ClientOrderID:     clientOrderID,
+		SelfTradePolicy:   selfTradePolicy,
+		TimeInForce:       timeInForce,
+	}
+
+	// 4a. Reject a strike too far from the market, it is most likely mis-keyed
+	if err := s.checkPriceBand(ctx, order, currentBlockHeight); err != nil {
+		return nil, err
 	}
 
 	// 5. Save the order
 	if err := s.orderRepo.Create(ctx, order); err != nil {
+		// A concurrent retry may have won the unique (user_id, client_order_id) constraint
+		if clientOrderID != "" {
+			existing, findErr := s.orderRepo.FindByClientOrderID(ctx, userID, clientOrderID)
+			if findErr == nil && existing != nil {
+				return resolveDuplicateOrder(existing, orderType, contractType, strikeRate, expiryBlockHeight, size)
+			}
+		}
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
+	s.recordOrderBookChange(ctx, order, ORDER_BOOK_INSERT)
 
-	// 6. Try to match the order immediately
+	// 6. Try to match the order immediately. A FOK order only matches an order it fills in full.
 	matched, err := s.tryMatchOrder(ctx, order)
 	if err != nil {
-		// If matching fails, we still keep the order but log the error
-		fmt.Printf("failed to match order: %v\n", err)
+		// If matching fails, we still keep the order but report the error
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:      EVENT_OPERATION_FAILED,
+			UserIDs:   []string{order.UserID},
+			Operation: "match_order",
+			Error:     err.Error(),
+			Attributes: map[string]string{
+				"order_id": order.ID,
+			},
+		})
+	}
+
+	// 7. IOC and FOK orders never rest in the book, cancel whatever did not match
+	if !restsInBook(order) {
+		return s.cancelUnfilledOrder(ctx, order)
 	}
 
-	// 7. If the order was matched, update it
+	// 8. If the order was matched, update it
 	if matched {
 		order, err = s.orderRepo.FindByID(ctx, order.ID)
 		if err != nil {
@@ -124,6 +224,27 @@ func (s *orderBookService) PlaceOrder(
 	return order, nil
 }
 
+// resolveDuplicateOrder returns an order previously placed with the same client order ID
+// if its parameters match the resubmission, and ErrClientOrderIDConflict otherwise
+func resolveDuplicateOrder(
+	existing *Order,
+	orderType OrderType,
+	contractType ContractType,
+	strikeRate float64,
+	expiryBlockHeight uint64,
+	size float64,
+) (*Order, error) {
+	if existing.OrderType != orderType ||
+		existing.ContractType != contractType ||
+		existing.StrikeRate != strikeRate ||
+		existing.ExpiryBlockHeight != expiryBlockHeight ||
+		existing.Size != size {
+		return nil, fmt.Errorf("%w: %s", ErrClientOrderIDConflict, existing.ClientOrderID)
+	}
+
+	return existing, nil
+}
+
 // CancelOrder implements OrderBookManager.CancelOrder
 func (s *orderBookService) CancelOrder(
 	ctx context.Context,
@@ -136,7 +257,7 @@ func (s *orderBookService) CancelOrder(
 		return fmt.Errorf("failed to get order: %w", err)
 	}
 	if order == nil {
-		return errors.New("order not found")
+		return ErrOrderNotFound
 	}
 
 	// 2. Validate the user is the owner of this order
@@ -154,10 +275,142 @@ func (s *orderBookService) CancelOrder(
 	if err := s.orderRepo.Update(ctx, order); err != nil {
 		return fmt.Errorf("failed to update order status: %w", err)
 	}
+	s.recordOrderBookChange(ctx, order, ORDER_BOOK_CANCEL)
 
 	return nil
 }
 
+// ReduceOrder implements OrderBookManager.ReduceOrder
+func (s *orderBookService) ReduceOrder(
+	ctx context.Context,
+	orderID string,
+	userID string,
+	newSize float64,
+) (*Order, error) {
+	// 1. Get the order
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	// 2. Validate the user is the owner of this order
+	if order.UserID != userID {
+		return nil, errors.New("user is not the owner of this order")
+	}
+
+	// 3. Validate order status
+	if order.Status != OPEN {
+		return nil, errors.New("order is not open for reduction")
+	}
+
+	// 4. The new size must be a strict reduction, cancelling is how an order goes to zero
+	if newSize <= 0 {
+		return nil, errors.New("size must be positive")
+	}
+	if newSize >= order.Size {
+		return nil, fmt.Errorf("new size %.8f must be smaller than the current size %.8f", newSize, order.Size)
+	}
+
+	// 5. Update the order, which keeps its place in the book
+	order.Size = newSize
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to update order size: %w", err)
+	}
+	s.recordOrderBookChange(ctx, order, ORDER_BOOK_UPDATE)
+
+	return order, nil
+}
+
+// ModifyOrder implements OrderBookManager.ModifyOrder
+func (s *orderBookService) ModifyOrder(
+	ctx context.Context,
+	orderID string,
+	userID string,
+	newStrikeRate float64,
+	newSize float64,
+) (*Order, error) {
+	// 1. Validate inputs
+	if newStrikeRate <= 0 {
+		return nil, errors.New("strike rate must be positive")
+	}
+
+	if newSize <= 0 {
+		return nil, errors.New("size must be positive")
+	}
+
+	// 2. Get the order
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	// 3. Validate the user is the owner of this order
+	if order.UserID != userID {
+		return nil, errors.New("user is not the owner of this order")
+	}
+
+	// 4. Validate order status
+	if order.Status != OPEN {
+		return nil, errors.New("order is not open for modification")
+	}
+
+	// 5. The order must still be able to match, refreshing the block height tryMatchOrder uses
+	currentBlockHeight, err := cachedBlockHeight(ctx, s.btcClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.ExpiryBlockHeight <= currentBlockHeight {
+		return nil, ErrInvalidBlockHeight
+	}
+
+	// 6. Update the order in place. A new price is a new price level and a larger size is new
+	// liquidity, either moves the order to the back of the queue. A smaller size keeps its place.
+	previous := *order
+	order.StrikeRate = newStrikeRate
+	order.Size = newSize
+	if newStrikeRate != previous.StrikeRate || newSize > previous.Size {
+		order.CreationTime = s.clock.Now().UTC()
+	}
+	if newStrikeRate != previous.StrikeRate {
+		if err := s.checkPriceBand(ctx, order, currentBlockHeight); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+	s.recordOrderBookChange(ctx, order, ORDER_BOOK_UPDATE)
+
+	// 7. Match the order if the new price crosses the book. If matching fails the old terms
+	// are restored, a crossing order must not rest in the book.
+	matched, err := s.tryMatchOrder(ctx, order)
+	if err != nil {
+		if restoreErr := s.orderRepo.Update(ctx, &previous); restoreErr != nil {
+			return nil, fmt.Errorf("failed to match modified order: %v, and failed to restore it: %w", err, restoreErr)
+		}
+		s.recordOrderBookChange(ctx, &previous, ORDER_BOOK_UPDATE)
+		return nil, fmt.Errorf("failed to match modified order: %w", err)
+	}
+
+	// 8. If the order was matched, return its updated state
+	if matched {
+		order, err = s.orderRepo.FindByID(ctx, order.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get updated order: %w", err)
+		}
+	}
+
+	return order, nil
+}
+
 // GetOrder implements OrderBookManager.GetOrder
 func (s *orderBookService) GetOrder(
 	ctx context.Context,
@@ -168,7 +421,7 @@ func (s *orderBookService) GetOrder(
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
 	if order == nil {
-		return nil, errors.New("order not found")
+		return nil, ErrOrderNotFound
 	}
 	return order, nil
 }
@@ -191,36 +444,239 @@ func (s *orderBookService) GetOrderBook(
 	ctx context.Context,
 	contractType ContractType,
 	expiryBlockHeight uint64,
-) ([]*Order, error) {
-	// Get orders for the specified contract type and expiry
+) (*OrderBookSnapshot, error) {
+	// 1. Read the sequence before the orders. A change is sequenced after it is written, so
+	// the orders reflect at least every change up to the sequence.
+	sequence, err := s.orderRepo.GetBookSequence(ctx, contractType, expiryBlockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book sequence: %w", err)
+	}
+
+	// 2. Get open orders for the specified contract type and expiry, already in price-time priority
+	orders, err := s.orderRepo.FindByContractType(ctx, contractType, expiryBlockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders for contract type: %w", err)
+	}
+
+	return &OrderBookSnapshot{
+		ContractType:      contractType,
+		ExpiryBlockHeight: expiryBlockHeight,
+		Sequence:          sequence,
+		Orders:            orders,
+	}, nil
+}
+
+// GetMarketView implements OrderBookManager.GetMarketView
+func (s *orderBookService) GetMarketView(
+	ctx context.Context,
+	contractType ContractType,
+	expiryBlockHeight uint64,
+) (*MarketView, error) {
+	// 1. Read the block height once so every figure in the view refers to the same tip. The
+	// view is a pure read and is served at the last known height while the node is unreachable.
+	reading, err := readBlockHeight(ctx, s.btcClient, s.clock)
+	if err != nil {
+		return nil, err
+	}
+	currentBlockHeight := reading.BlockHeight
+
+	// 2. Read orders and contracts within a single database transaction
+	orders, contracts, err := s.snapshotRepo.FindMarketSnapshot(ctx, contractType, expiryBlockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get market snapshot: %w", err)
+	}
+
+	view := &MarketView{
+		ContractType:      contractType,
+		ExpiryBlockHeight: expiryBlockHeight,
+		BlockHeight:       currentBlockHeight,
+		BlockHeightStale:  reading.Stale,
+		Timestamp:         s.clock.Now().UTC(),
+		Bids:              []*Order{},
+		Asks:              []*Order{},
+	}
+
+	// 3. Get the index rate at the captured block height, left at zero on a stale view the
+	// node cannot price
+	hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight, 0)
+	if err != nil && !reading.Stale {
+		return nil, fmt.Errorf("failed to get hash rate: %w", err)
+	}
+	if err == nil {
+		view.IndexRate = calculateBTCPerPHPerDay(hashRate, currentBlockHeight)
+	}
+
+	// 4. Split the order book into bids and asks, best price first
+	for _, order := range orders {
+		if order.OrderType == BUY {
+			view.Bids = append(view.Bids, order)
+		} else {
+			view.Asks = append(view.Asks, order)
+		}
+	}
+
+	sort.Slice(view.Bids, func(i, j int) bool {
+		return view.Bids[i].StrikeRate > view.Bids[j].StrikeRate
+	})
+
+	sort.Slice(view.Asks, func(i, j int) bool {
+		return view.Asks[i].StrikeRate < view.Asks[j].StrikeRate
+	})
+
+	// 5. Calculate open interest from active contracts
+	for _, contract := range contracts {
+		view.OpenInterest += contract.Size
+	}
+	view.ActiveContracts = len(contracts)
+
+	// 6. Derive the implied rate from the top of the book
+	switch {
+	case len(view.Bids) > 0 && len(view.Asks) > 0:
+		view.ImpliedRate = (view.Bids[0].StrikeRate + view.Asks[0].StrikeRate) / 2
+	case len(view.Bids) > 0:
+		view.ImpliedRate = view.Bids[0].StrikeRate
+	case len(view.Asks) > 0:
+		view.ImpliedRate = view.Asks[0].StrikeRate
+	}
+
+	return view, nil
+}
+
+// GetOrderBookDepth implements OrderBookManager.GetOrderBookDepth
+func (s *orderBookService) GetOrderBookDepth(
+	ctx context.Context,
+	contractType ContractType,
+	expiryBlockHeight uint64,
+) (*OrderBookDepth, error) {
+	// 1. Get open orders for the market
 	orders, err := s.orderRepo.FindByContractType(ctx, contractType, expiryBlockHeight)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get orders for contract type: %w", err)
 	}
 
-	// Filter for only open orders
-	var openOrders []*Order
+	// 2. Split them into bids and asks
+	var bids, asks []*Order
 	for _, order := range orders {
-		if order.Status == OPEN {
-			openOrders = append(openOrders, order)
+		if order.OrderType == BUY {
+			bids = append(bids, order)
+		} else {
+			asks = append(asks, order)
 		}
 	}
 
-	return openOrders, nil
+	depth := &OrderBookDepth{
+		ContractType:      contractType,
+		ExpiryBlockHeight: expiryBlockHeight,
+		Bids:              aggregateOrderBookLevels(bids, true),
+		Asks:              aggregateOrderBookLevels(asks, false),
+	}
+
+	// 3. Derive the top of the book
+	if len(depth.Bids) > 0 {
+		depth.BestBid = depth.Bids[0].StrikeRate
+	}
+	if len(depth.Asks) > 0 {
+		depth.BestAsk = depth.Asks[0].StrikeRate
+	}
+	if len(depth.Bids) > 0 && len(depth.Asks) > 0 {
+		depth.Spread = depth.BestAsk - depth.BestBid
+	}
+
+	return depth, nil
+}
+
+// aggregateOrderBookLevels buckets orders by strike rate, best rate first, with running cumulative size.
+// Bids are best at the highest rate, asks at the lowest.
+func aggregateOrderBookLevels(orders []*Order, highestFirst bool) []*OrderBookLevel {
+	levelsByRate := make(map[float64]*OrderBookLevel)
+	levels := []*OrderBookLevel{}
+	for _, order := range orders {
+		level, ok := levelsByRate[order.StrikeRate]
+		if !ok {
+			level = &OrderBookLevel{StrikeRate: order.StrikeRate}
+			levelsByRate[order.StrikeRate] = level
+			levels = append(levels, level)
+		}
+		level.Size += order.Size
+		level.OrderCount++
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		if highestFirst {
+			return levels[i].StrikeRate > levels[j].StrikeRate
+		}
+		return levels[i].StrikeRate < levels[j].StrikeRate
+	})
+
+	cumulativeSize := 0.0
+	for _, level := range levels {
+		cumulativeSize += level.Size
+		level.CumulativeSize = cumulativeSize
+	}
+
+	return levels
+}
+
+// ExpireStaleOrders implements OrderBookManager.ExpireStaleOrders
+func (s *orderBookService) ExpireStaleOrders(ctx context.Context) (int, error) {
+	// 1. Get the current block height
+	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	// 2. Find open orders whose expiry block has been reached
+	expiredOrders, err := s.orderRepo.FindExpiredOpenOrders(ctx, currentBlockHeight)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get expired open orders: %w", err)
+	}
+
+	// 3. Expire them
+	expiredCount := 0
+	for _, order := range expiredOrders {
+		order.Status = EXPIRED
+		if err := s.orderRepo.Update(ctx, order); err != nil {
+			publishEvent(ctx, s.eventPublisher, Event{
+				Type:      EVENT_OPERATION_FAILED,
+				UserIDs:   []string{order.UserID},
+				Operation: "expire_order",
+				Error:     err.Error(),
+				Attributes: map[string]string{
+					"order_id": order.ID,
+				},
+			})
+			continue
+		}
+		s.recordOrderBookChange(ctx, order, ORDER_BOOK_EXPIRE)
+		expiredCount++
+	}
+
+	return expiredCount, nil
 }
 
 // MatchOrders implements OrderBookManager.MatchOrders
 // This is the core function that attempts to match open buy and sell orders
-func (s *orderBookService) MatchOrders(ctx context.Context) ([]*Contract, error) {
+func (s *orderBookService) MatchOrders(ctx context.Context) (matched []*Contract, err error) {
+	defer func() { s.metrics.OrdersMatched(MetricOutcome(err), len(matched)) }()
+
 	// 1. Get all open orders
 	allOrders, err := s.orderRepo.FindOpenOrders(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get open orders: %w", err)
 	}
 
-	// 2. Group orders by contract type and expiry
+	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	// 2. Group orders by contract type and expiry, skipping orders that have expired
+	// but not yet been swept by ExpireStaleOrders
 	orderGroups := make(map[string][]*Order)
 	for _, order := range allOrders {
+		if order.ExpiryBlockHeight <= currentBlockHeight {
+			continue
+		}
 		key := fmt.Sprintf("%s-%d", order.ContractType, order.ExpiryBlockHeight)
 		orderGroups[key] = append(orderGroups[key], order)
 	}
@@ -264,10 +720,44 @@ func (s *orderBookService) MatchOrders(ctx context.Context) ([]*Contract, error)
 
 				// Check if the buy price is >= sell price
 				if buyOrder.StrikeRate >= sellOrder.StrikeRate {
+					// Self-trade prevention, the newer order's policy decides
+					if sellOrder.UserID == buyOrder.UserID {
+						resting, incoming := restingAndIncoming(buyOrder, sellOrder)
+						allowed, err := s.preventSelfTrade(ctx, resting, incoming)
+						if err != nil {
+							publishEvent(ctx, s.eventPublisher, Event{
+								Type:      EVENT_OPERATION_FAILED,
+								UserIDs:   []string{buyOrder.UserID},
+								Operation: "prevent_self_trade",
+								Error:     err.Error(),
+								Attributes: map[string]string{
+									"buy_order_id":  buyOrder.ID,
+									"sell_order_id": sellOrder.ID,
+								},
+							})
+							continue
+						}
+						if !allowed {
+							if buyOrder.Status != OPEN {
+								break // The buy order was canceled
+							}
+							continue
+						}
+					}
+
 					// Match the orders
 					contract, err := s.createContractFromOrders(ctx, buyOrder, sellOrder)
 					if err != nil {
-						fmt.Printf("failed to create contract from orders: %v\n", err)
+						publishEvent(ctx, s.eventPublisher, Event{
+							Type:      EVENT_OPERATION_FAILED,
+							UserIDs:   []string{buyOrder.UserID, sellOrder.UserID},
+							Operation: "create_contract_from_orders",
+							Error:     err.Error(),
+							Attributes: map[string]string{
+								"buy_order_id":  buyOrder.ID,
+								"sell_order_id": sellOrder.ID,
+							},
+						})
 						continue
 					}
 
@@ -283,11 +773,33 @@ func (s *orderBookService) MatchOrders(ctx context.Context) ([]*Contract, error)
 					sellOrder.ResultingContractID = contract.ID
 
 					if err := s.orderRepo.Update(ctx, buyOrder); err != nil {
-						fmt.Printf("failed to update buy order: %v\n", err)
+						publishEvent(ctx, s.eventPublisher, Event{
+							Type:       EVENT_OPERATION_FAILED,
+							ContractID: contract.ID,
+							UserIDs:    []string{buyOrder.UserID},
+							Operation:  "update_matched_order",
+							Error:      err.Error(),
+							Attributes: map[string]string{
+								"order_id": buyOrder.ID,
+							},
+						})
+					} else {
+						s.recordOrderBookChange(ctx, buyOrder, ORDER_BOOK_MATCH)
 					}
 
 					if err := s.orderRepo.Update(ctx, sellOrder); err != nil {
-						fmt.Printf("failed to update sell order: %v\n", err)
+						publishEvent(ctx, s.eventPublisher, Event{
+							Type:       EVENT_OPERATION_FAILED,
+							ContractID: contract.ID,
+							UserIDs:    []string{sellOrder.UserID},
+							Operation:  "update_matched_order",
+							Error:      err.Error(),
+							Attributes: map[string]string{
+								"order_id": sellOrder.ID,
+							},
+						})
+					} else {
+						s.recordOrderBookChange(ctx, sellOrder, ORDER_BOOK_MATCH)
 					}
 
 					break // Move to the next buy order
@@ -316,12 +828,21 @@ func (s *orderBookService) tryMatchOrder(
 		return false, fmt.Errorf("failed to get open orders: %w", err)
 	}
 
-	// 3. Filter for compatible orders
+	// 3. Filter for compatible orders that have not expired
+	currentBlockHeight, err := cachedBlockHeight(ctx, s.btcClient)
+	if err != nil {
+		return false, err
+	}
+	if order.ExpiryBlockHeight <= currentBlockHeight {
+		return false, nil
+	}
+
 	var compatibleOrders []*Order
 	for _, o := range allOrders {
 		if o.OrderType == oppositeType &&
 			o.ContractType == order.ContractType &&
 			o.ExpiryBlockHeight == order.ExpiryBlockHeight &&
+			o.ExpiryBlockHeight > currentBlockHeight &&
 			o.Status == OPEN {
 			compatibleOrders = append(compatibleOrders, o)
 		}
@@ -346,6 +867,25 @@ func (s *orderBookService) tryMatchOrder(
 		if (order.OrderType == BUY && order.StrikeRate >= compatibleOrder.StrikeRate) ||
 			(order.OrderType == SELL && order.StrikeRate <= compatibleOrder.StrikeRate) {
 			
+			// A fill-or-kill order must be filled by this match alone
+			if !fillsCompletely(order, compatibleOrder) {
+				continue
+			}
+
+			// Self-trade prevention against the user's own resting order
+			if compatibleOrder.UserID == order.UserID {
+				allowed, err := s.preventSelfTrade(ctx, compatibleOrder, order)
+				if err != nil {
+					return false, err
+				}
+				if !allowed {
+					if order.Status != OPEN {
+						return false, nil // The incoming order was canceled
+					}
+					continue
+				}
+			}
+
 			// Determine buyer and seller
 			var buyOrder, sellOrder *Order
 			if order.OrderType == BUY {
@@ -374,10 +914,12 @@ func (s *orderBookService) tryMatchOrder(
 			if err := s.orderRepo.Update(ctx, buyOrder); err != nil {
 				return false, fmt.Errorf("failed to update buy order: %w", err)
 			}
+			s.recordOrderBookChange(ctx, buyOrder, ORDER_BOOK_MATCH)
 
 			if err := s.orderRepo.Update(ctx, sellOrder); err != nil {
 				return false, fmt.Errorf("failed to update sell order: %w", err)
 			}
+			s.recordOrderBookChange(ctx, sellOrder, ORDER_BOOK_MATCH)
 
 			return true, nil
 		}
@@ -416,6 +958,10 @@ func (s *orderBookService) createContractFromOrders(
 		buyOrder.StrikeRate, // Use the buyer's strike rate (ensures the buyer got a price they're ok with)
 		buyOrder.ExpiryBlockHeight,
 		size,
+		defaultLeverage,
+		SETTLEMENT_SPOT,
+		nil, // Default exit fee rate
+		"",
 	)
 
 	if err != nil {
@@ -0,0 +1,100 @@
+package hashperp
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Page limits of list queries
+const (
+	DefaultPageLimit = 100  // Page size of a caller's request that sets no limit
+	MaxPageLimit     = 1000 // Largest page a list query may request
+)
+
+// Pagination errors
+var (
+	ErrInvalidCursor    = errors.New("invalid pagination cursor")
+	ErrInvalidPageLimit = errors.New("page limit must be between 0 and the maximum page limit")
+)
+
+// PageRequest bounds a list query. Results are ordered by creation time then ID,
+// and Cursor is the NextCursor returned by the previous page. A zero Limit returns every
+// remaining result to internal reads, the service pages caller requests with
+// NormalizePageRequest so no caller can ask for an unbounded list.
+type PageRequest struct {
+	Limit  int    `json:"limit,omitempty"`
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// SortOrder is the direction a time-ordered list is returned in
+type SortOrder string
+
+const (
+	SORT_ASCENDING  SortOrder = "asc"  // Oldest first, the default
+	SORT_DESCENDING SortOrder = "desc" // Newest first
+)
+
+// ValidateSortOrder accepts the sort orders and the empty default
+func ValidateSortOrder(order SortOrder) error {
+	switch order {
+	case "", SORT_ASCENDING, SORT_DESCENDING:
+		return nil
+	}
+	return fmt.Errorf("%w: sort order must be %q or %q", ErrInvalidParameters, SORT_ASCENDING, SORT_DESCENDING)
+}
+
+// ValidatePageRequest validates a page request's limit and cursor
+func ValidatePageRequest(page PageRequest) error {
+	if page.Limit < 0 || page.Limit > MaxPageLimit {
+		return fmt.Errorf("%w: %d", ErrInvalidPageLimit, page.Limit)
+	}
+
+	if page.Cursor != "" {
+		if _, _, err := DecodePageCursor(page.Cursor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NormalizePageRequest validates a caller's page request and applies DefaultPageLimit when it
+// sets no limit. Every paginated getter of the service passes its page request through here.
+func NormalizePageRequest(page PageRequest) (PageRequest, error) {
+	if err := ValidatePageRequest(page); err != nil {
+		return PageRequest{}, err
+	}
+	if page.Limit == 0 {
+		page.Limit = DefaultPageLimit
+	}
+	return page, nil
+}
+
+// EncodePageCursor builds an opaque cursor pointing just past the given row
+func EncodePageCursor(creationTime time.Time, id string) string {
+	raw := creationTime.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodePageCursor extracts the creation time and ID encoded in a cursor
+func DecodePageCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	creationTime, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	return creationTime, parts[1], nil
+}
@@ -0,0 +1,83 @@
+package hashperp
+
+import (
+	"fmt"
+)
+
+// Payout rounding policy, in satoshis. The values are defaults, see PayoutPolicy.
+const (
+	defaultPayoutRoundingSats = 1     // Round to the satoshi
+	defaultMinPayoutSats      = 546   // Standard dust limit of a P2PKH output
+	maxPayoutRoundingSats     = 10000 // 0.0001 BTC
+)
+
+// PayoutPolicy sets how the payouts of a settlement, early exit or liquidation are rounded
+// before they are paid out, so no payout creates a dust output on-chain
+type PayoutPolicy struct {
+	RoundingSats  int64 // Granularity payouts are rounded to, 1 rounds to the satoshi
+	MinPayoutSats int64 // Payouts below this are rolled to the counterparty, 0 disables
+}
+
+// DefaultPayoutPolicy returns the payout policy used when none is configured
+func DefaultPayoutPolicy() PayoutPolicy {
+	return PayoutPolicy{
+		RoundingSats:  defaultPayoutRoundingSats,
+		MinPayoutSats: defaultMinPayoutSats,
+	}
+}
+
+// Validate checks that the rounding granularity is set and both amounts are bounded. The
+// minimum payout cannot exceed the minimum collateral, or every payout of the smallest
+// contracts would be rolled to the counterparty.
+func (p PayoutPolicy) Validate() error {
+	if p.RoundingSats < 1 || p.RoundingSats > maxPayoutRoundingSats {
+		return fmt.Errorf("%w: payout rounding must be between 1 and %d sats",
+			ErrInvalidParameters, maxPayoutRoundingSats)
+	}
+	if p.MinPayoutSats < 0 || p.MinPayoutSats > BTCToSats(minCollateral) {
+		return fmt.Errorf("%w: minimum payout must be between 0 and %d sats",
+			ErrInvalidParameters, BTCToSats(minCollateral))
+	}
+	return nil
+}
+
+// apply rounds the buyer and seller payouts out of a shared pot. The smaller payout is
+// rounded down to a multiple of RoundingSats and the larger takes the rest of the pot, the
+// buyer's counting as the smaller on a tie. A smaller payout then below MinPayoutSats is
+// rolled to the larger. The pair always sums to the pot, and since every step is integer
+// satoshi math both parties reach the same payouts from the same inputs.
+func (p PayoutPolicy) apply(buyerPayout, sellerPayout float64) (float64, float64) {
+	buyerSats, sellerSats := BTCToSats(buyerPayout), BTCToSats(sellerPayout)
+	potSats := buyerSats + sellerSats
+
+	smallerSats := buyerSats
+	if sellerSats < buyerSats {
+		smallerSats = sellerSats
+	}
+
+	rounding := p.RoundingSats
+	if rounding < 1 {
+		rounding = 1
+	}
+	smallerSats -= smallerSats % rounding
+	if smallerSats < p.MinPayoutSats {
+		smallerSats = 0
+	}
+
+	if sellerSats < buyerSats {
+		return SatsToBTC(potSats - smallerSats), SatsToBTC(smallerSats)
+	}
+	return SatsToBTC(smallerSats), SatsToBTC(potSats - smallerSats)
+}
+
+// recordPayoutPolicy adds the payout policy a transaction's payouts were rounded with to its
+// related entities, so the payouts can be verified independently
+func recordPayoutPolicy(relatedEntities map[string]string, policy PayoutPolicy) {
+	relatedEntities["payout_rounding_sats"] = fmt.Sprintf("%d", policy.RoundingSats)
+	relatedEntities["min_payout_sats"] = fmt.Sprintf("%d", policy.MinPayoutSats)
+}
+
+// SetPayoutPolicy configures how settlement, exit and liquidation payouts are rounded
+func (s *contractService) SetPayoutPolicy(policy PayoutPolicy) {
+	s.payoutPolicy = policy
+}
@@ -0,0 +1,37 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// GetPlatformStats implements HashPerpService.GetPlatformStats
+func (s *hashPerpService) GetPlatformStats(ctx context.Context) (*PlatformStats, error) {
+	if s.contractRepo == nil || s.orderRepo == nil || s.swapOfferRepo == nil {
+		return nil, errors.New("no repositories configured for platform stats")
+	}
+
+	// Count in the database rather than loading the rows
+	activeContracts, err := s.contractRepo.CountActiveContracts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active contracts: %w", err)
+	}
+
+	openOrders, err := s.orderRepo.CountOpenOrders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open orders: %w", err)
+	}
+
+	openOffers, err := s.swapOfferRepo.CountOffersByStatus(ctx, OFFER_OPEN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open swap offers: %w", err)
+	}
+
+	return &PlatformStats{
+		Timestamp:       s.clock.Now().UTC(),
+		ActiveContracts: activeContracts,
+		OpenOrders:      openOrders,
+		OpenSwapOffers:  openOffers,
+	}, nil
+}
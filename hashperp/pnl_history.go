@@ -0,0 +1,96 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// SetHashRateRepository configures the recorded hash rates contract PnL history is computed from
+func (s *contractService) SetHashRateRepository(hashRateRepo HashRateRepository) {
+	s.hashRateRepo = hashRateRepo
+}
+
+// GetContractPnLHistory implements ContractManager.GetContractPnLHistory
+// Only recorded hash rates are used, an interval without a recorded block has no point.
+func (s *contractService) GetContractPnLHistory(ctx context.Context, contractID string, intervalBlocks uint64) ([]*PnLPoint, error) {
+	// 1. Validate the request
+	if intervalBlocks == 0 {
+		return nil, fmt.Errorf("%w: interval must be at least one block", ErrInvalidParameters)
+	}
+	if s.hashRateRepo == nil {
+		return nil, errors.New("no hash rate repository configured for PnL history")
+	}
+
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, ErrContractNotFound
+	}
+
+	// 2. The history runs from the creation block until now or the expiry block
+	startHeight, err := s.contractCreationBlockHeight(ctx, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	// The history is read from recorded rates, so the last known height is enough while the
+	// node is unreachable
+	reading, err := readBlockHeight(ctx, s.btcClient, s.clock)
+	if err != nil {
+		return nil, err
+	}
+	endHeight := reading.BlockHeight
+	if endHeight > contract.ExpiryBlockHeight {
+		endHeight = contract.ExpiryBlockHeight
+	}
+
+	history := []*PnLPoint{}
+	if startHeight > endHeight {
+		return history, nil
+	}
+
+	rates, err := s.hashRateRepo.FindByBlockRange(ctx, startHeight, endHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recorded hash rates: %w", err)
+	}
+
+	// 3. Value both sides at the first recorded rate of each interval
+	buyerCollateral, sellerCollateral := sideCollateral(contract, true), sideCollateral(contract, false)
+	nextHeight := startHeight
+	for _, rate := range rates {
+		if rate.BlockHeight < nextHeight {
+			continue
+		}
+
+		buyerPayout, _ := capPayout(buyerCollateral, sellerCollateral, positionPnL(contract, true, rate.BTCPerPHPerDay))
+		sellerPayout, _ := capPayout(sellerCollateral, buyerCollateral, positionPnL(contract, false, rate.BTCPerPHPerDay))
+		history = append(history, &PnLPoint{
+			BlockHeight:    rate.BlockHeight,
+			Timestamp:      rate.Timestamp,
+			BTCPerPHPerDay: rate.BTCPerPHPerDay,
+			BuyerPnL:       buyerPayout - buyerCollateral,
+			SellerPnL:      sellerPayout - sellerCollateral,
+		})
+
+		// Skip ahead to the interval after this block
+		nextHeight = rate.BlockHeight - (rate.BlockHeight-startHeight)%intervalBlocks + intervalBlocks
+	}
+
+	return history, nil
+}
+
+// contractCreationBlockHeight returns the block height a contract was created at, read from its
+// creation transaction. Contracts without one fall back to the start of their rollover chain.
+func (s *contractService) contractCreationBlockHeight(ctx context.Context, contract *Contract) (uint64, error) {
+	txs, err := s.transactionRepo.FindByContract(ctx, contract.ID, []TransactionType{CONTRACT_CREATION}, SORT_ASCENDING)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get contract creation transaction: %w", err)
+	}
+	if len(txs) > 0 && txs[0].BlockHeight > 0 {
+		return txs[0].BlockHeight, nil
+	}
+	return contract.OriginBlockHeight, nil
+}
@@ -0,0 +1,115 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetUserPositionSummary implements ContractManager.GetUserPositionSummary
+// The user's contracts are loaded in a single query and valued at one rate, so every position
+// in the summary is consistent with the others.
+func (s *contractService) GetUserPositionSummary(ctx context.Context, userID string) (*UserPositionSummary, error) {
+	// 1. Load every active contract the user holds a position in
+	contracts, err := s.contractRepo.FindActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active contracts: %w", err)
+	}
+
+	// 2. Get the current rate
+	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current hash rate: %w", err)
+	}
+	currentBTCPerPHPerDay := calculateBTCPerPHPerDay(hashRate, currentBlockHeight)
+
+	summary := &UserPositionSummary{
+		UserID:         userID,
+		Timestamp:      s.clock.Now().UTC(),
+		BlockHeight:    currentBlockHeight,
+		BTCPerPHPerDay: currentBTCPerPHPerDay,
+		Positions:      []*PositionSummary{},
+		ExposureByType: make(map[ContractType]*TypeExposure),
+	}
+
+	// 3. Value the user's share of each side and aggregate the exposure
+	for _, contract := range contracts {
+		for _, position := range userPositions(contract, userID, currentBTCPerPHPerDay) {
+			summary.Positions = append(summary.Positions, position)
+			summary.TotalCollateral += position.Collateral
+			summary.TotalPnL += position.PnL
+
+			exposure, ok := summary.ExposureByType[contract.ContractType]
+			if !ok {
+				exposure = &TypeExposure{}
+				summary.ExposureByType[contract.ContractType] = exposure
+			}
+			if position.Side == SIDE_BUYER {
+				exposure.Long += position.Size
+			} else {
+				exposure.Short += position.Size
+			}
+
+			// CALL buyers and PUT sellers gain as the rate rises
+			if (contract.ContractType == CALL) == (position.Side == SIDE_BUYER) {
+				summary.NetExposure += position.Size
+			} else {
+				summary.NetExposure -= position.Size
+			}
+		}
+	}
+
+	return summary, nil
+}
+
+// userPositions values a user's share of each side of a contract they hold, as the founding
+// party or a pool member. A side's collateral and capped PnL are split pro-rata by size.
+func userPositions(contract *Contract, userID string, btcPerPHPerDay float64) []*PositionSummary {
+	positions := []*PositionSummary{}
+	if contract.Size <= 0 {
+		return positions
+	}
+
+	for _, side := range []PositionSide{SIDE_BUYER, SIDE_SELLER} {
+		isBuyer := side == SIDE_BUYER
+		if (isBuyer && (contract.BuyerExited || contract.BuyerLiquidated)) ||
+			(!isBuyer && (contract.SellerExited || contract.SellerLiquidated)) {
+			continue
+		}
+
+		pool, founderID, _ := poolSide(contract, side)
+		var size float64
+		if founderID == userID {
+			size += founderShare(contract, side)
+		}
+		for _, member := range pool {
+			if member.UserID == userID {
+				size += member.Size
+			}
+		}
+		if size <= 0 {
+			continue
+		}
+
+		collateral, counterpartyCollateral := sideCollateral(contract, isBuyer), sideCollateral(contract, !isBuyer)
+		payout, _ := capPayout(collateral, counterpartyCollateral, positionPnL(contract, isBuyer, btcPerPHPerDay))
+		share := size / contract.Size
+
+		positions = append(positions, &PositionSummary{
+			ContractID:        contract.ID,
+			ContractType:      contract.ContractType,
+			Side:              side,
+			ExpiryBlockHeight: contract.ExpiryBlockHeight,
+			StrikeRate:        contract.StrikeRate,
+			Size:              size,
+			Collateral:        collateral * share,
+			PnL:               (payout - collateral) * share,
+		})
+	}
+
+	return positions
+}
@@ -1,9 +1,8 @@
-// Create hashperp/presigned_exit.go
-
 package hashperp
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -30,6 +29,115 @@ type PreSignedExitRepository interface {
 	// FindByVTXO retrieves pre-signed exits for a VTXO
 	FindByVTXO(ctx context.Context, vtxoID string) ([]*PreSignedExit, error)
 	
+	// FindUnused retrieves the pre-signed exits for a VTXO that have not been broadcast
+	FindUnused(ctx context.Context, vtxoID string) ([]*PreSignedExit, error)
+	
+	// Update updates a pre-signed exit
+	Update(ctx context.Context, preSignedExit *PreSignedExit) error
+	
 	// MarkAsUsed marks a pre-signed exit as used
 	MarkAsUsed(ctx context.Context, id string) error
+	
+	// Delete deletes a pre-signed exit
+	Delete(ctx context.Context, id string) error
+}
+
+// GetPreSignedExit implements VTXOManager.GetPreSignedExit
+func (s *vtxoService) GetPreSignedExit(ctx context.Context, preSignedExitID string) (*PreSignedExit, error) {
+	preSignedExit, err := s.preSignedExitRepo.FindByID(ctx, preSignedExitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pre-signed exit: %w", err)
+	}
+	if preSignedExit == nil {
+		return nil, ErrPreSignedExitNotFound
+	}
+	return preSignedExit, nil
+}
+
+// GetPreSignedExits implements VTXOManager.GetPreSignedExits
+func (s *vtxoService) GetPreSignedExits(ctx context.Context, vtxoID string) ([]*PreSignedExit, error) {
+	preSignedExits, err := s.preSignedExitRepo.FindByVTXO(ctx, vtxoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pre-signed exits: %w", err)
+	}
+	return preSignedExits, nil
+}
+
+// BroadcastPreSignedExit implements VTXOManager.BroadcastPreSignedExit
+// The exit is only marked used once the node accepts it, so a failed broadcast can be retried.
+func (s *vtxoService) BroadcastPreSignedExit(ctx context.Context, preSignedExitID string) (*Transaction, error) {
+	// 1. Get the pre-signed exit
+	preSignedExit, err := s.GetPreSignedExit(ctx, preSignedExitID)
+	if err != nil {
+		return nil, err
+	}
+	if preSignedExit.IsUsed {
+		return nil, ErrPreSignedExitUsed
+	}
+
+	// 2. The VTXO must not have been exited, swept or swapped since the exit was signed
+	vtxo, err := s.vtxoRepo.FindByID(ctx, preSignedExit.VTXOID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VTXO: %w", err)
+	}
+	if vtxo == nil {
+		return nil, ErrVTXONotFound
+	}
+	if !vtxo.IsActive || vtxo.OwnerID != preSignedExit.UserID {
+		return nil, ErrVTXONotActive
+	}
+
+	// 3. Broadcast the exit
+	txHash, err := s.btcClient.BroadcastTransaction(ctx, preSignedExit.ExitTxHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to broadcast pre-signed exit: %w", err)
+	}
+
+	// 4. Mark the exit used
+	if err := s.preSignedExitRepo.MarkAsUsed(ctx, preSignedExit.ID); err != nil {
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: preSignedExit.ContractID,
+			VTXOID:     vtxo.ID,
+			Operation:  "mark_pre_signed_exit_used",
+			Error:      err.Error(),
+		})
+	}
+
+	// 5. Record the exit transaction
+	tx := &Transaction{
+		ID:          s.ids.NewID(),
+		Type:        EXIT_PATH_EXECUTION,
+		Timestamp:   s.clock.Now().UTC(),
+		ContractID:  preSignedExit.ContractID,
+		UserIDs:     []string{preSignedExit.UserID},
+		TxHash:      txHash,
+		Amount:      vtxo.Amount,
+		Status:      TX_STATUS_PENDING,
+		RelatedEntities: map[string]string{
+			"vtxo_id":            vtxo.ID,
+			"exit_type":          "pre_signed",
+			"owner_id":           preSignedExit.UserID,
+			"pre_signed_exit_id": preSignedExit.ID,
+			"btc_tx_hash":        txHash,
+		},
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		return nil, fmt.Errorf("pre-signed exit broadcast as %s but failed to record its transaction: %w", txHash, err)
+	}
+
+	// 6. Apply the exit to the VTXO and contract
+	if err := s.finalizeExit(ctx, tx); err != nil {
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:          EVENT_OPERATION_FAILED,
+			ContractID:    preSignedExit.ContractID,
+			VTXOID:        vtxo.ID,
+			TransactionID: tx.ID,
+			Operation:     "finalize_pre_signed_exit",
+			Error:         err.Error(),
+		})
+	}
+
+	return tx, nil
 }
@@ -0,0 +1,140 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// defaultMaxPriceDeviation is how far an order's strike may be from the reference rate, 0.5
+// accepts strikes between half and one and a half times the reference
+const defaultMaxPriceDeviation = 0.5
+
+// PriceBandConfig guards the order book against mis-keyed strikes. An order whose strike
+// deviates from the market's reference rate by more than MaxDeviation is rejected, unless
+// it comes from a designated market maker. The deviation widens for expiries more than a
+// difficulty period out, see priceBandDeviation.
+type PriceBandConfig struct {
+	MaxDeviation float64  // Allowed deviation from the reference rate as a fraction, 0 disables the band
+	MarketMakers []string // User IDs exempt from the band
+}
+
+// DefaultPriceBandConfig returns the price band used when none is configured
+func DefaultPriceBandConfig() PriceBandConfig {
+	return PriceBandConfig{MaxDeviation: defaultMaxPriceDeviation}
+}
+
+// Validate checks that the deviation is not negative
+func (c PriceBandConfig) Validate() error {
+	if c.MaxDeviation < 0 || math.IsNaN(c.MaxDeviation) || math.IsInf(c.MaxDeviation, 0) {
+		return fmt.Errorf("%w: price band deviation must be a non-negative fraction", ErrInvalidParameters)
+	}
+	return nil
+}
+
+// SetPriceBandConfig configures how far order strikes may deviate from the market
+func (s *orderBookService) SetPriceBandConfig(band PriceBandConfig) {
+	s.priceBand = band
+	s.priceBandExempt = make(map[string]bool, len(band.MarketMakers))
+	for _, userID := range band.MarketMakers {
+		s.priceBandExempt[userID] = true
+	}
+}
+
+// difficultyPeriodBlocks is the length of a difficulty period. The index rate can only move
+// by a difficulty adjustment, so the band widens with each period an expiry lies ahead.
+const difficultyPeriodBlocks = 2016
+
+// checkPriceBand rejects an order whose strike deviates too far from the reference rate of
+// its market. The band is anchored to the index rate at the current block, or the last
+// settlement rate of the contract type while the hash rate cannot be read. The mid of the
+// best bid and ask, or the one side that is quoted, not counting the order itself, becomes
+// the reference only while it sits inside the band around that anchor, so a run of
+// mis-keyed quotes cannot drag the band away from the market.
+func (s *orderBookService) checkPriceBand(ctx context.Context, order *Order, currentBlockHeight uint64) error {
+	if s.priceBand.MaxDeviation <= 0 || s.priceBandExempt[order.UserID] {
+		return nil
+	}
+
+	// 1. Anchor the band to the index rate, or the last settlement rate without one
+	anchor, err := s.priceBandAnchor(ctx, order.ContractType, currentBlockHeight)
+	if err != nil {
+		return err
+	}
+	if anchor <= 0 {
+		return nil
+	}
+	deviation := priceBandDeviation(s.priceBand.MaxDeviation, order.ExpiryBlockHeight, currentBlockHeight)
+
+	// 2. Derive the book's reference rate from the top of the book
+	orders, err := s.orderRepo.FindByContractType(ctx, order.ContractType, order.ExpiryBlockHeight)
+	if err != nil {
+		return fmt.Errorf("failed to get orders for price band: %w", err)
+	}
+
+	var bestBid, bestAsk float64
+	for _, o := range orders {
+		if o.ID == order.ID || o.Status != OPEN {
+			continue
+		}
+		if o.OrderType == BUY && o.StrikeRate > bestBid {
+			bestBid = o.StrikeRate
+		}
+		if o.OrderType == SELL && (bestAsk == 0 || o.StrikeRate < bestAsk) {
+			bestAsk = o.StrikeRate
+		}
+	}
+
+	reference := bestBid
+	switch {
+	case bestBid > 0 && bestAsk > 0:
+		reference = (bestBid + bestAsk) / 2
+	case bestAsk > 0:
+		reference = bestAsk
+	}
+
+	// 3. Only trust the book while it agrees with the anchor
+	if reference <= 0 || math.Abs(reference-anchor)/anchor > deviation {
+		reference = anchor
+	}
+
+	// 4. Compare the strike against the band around the reference
+	if math.Abs(order.StrikeRate-reference)/reference > deviation {
+		return fmt.Errorf("%w: strike rate %.8f is more than %.0f%% from the reference rate %.8f",
+			ErrPriceBandExceeded, order.StrikeRate, deviation*100, reference)
+	}
+	return nil
+}
+
+// priceBandAnchor returns the index rate at the current block. When the node cannot report
+// the hash rate it falls back to the size-weighted rate of the latest expiry settled in the
+// last difficulty period, and to 0, leaving the band unchecked, when there is none.
+func (s *orderBookService) priceBandAnchor(ctx context.Context, contractType ContractType, currentBlockHeight uint64) (float64, error) {
+	hashRate, err := s.btcClient.GetBlockHashRate(ctx, currentBlockHeight, 0)
+	if err == nil {
+		return calculateBTCPerPHPerDay(hashRate, currentBlockHeight), nil
+	}
+
+	var fromHeight uint64
+	if currentBlockHeight > difficultyPeriodBlocks {
+		fromHeight = currentBlockHeight - difficultyPeriodBlocks
+	}
+	history, historyErr := s.contractRepo.SummarizeSettlementsByExpiry(ctx, contractType, fromHeight, currentBlockHeight)
+	if historyErr != nil || len(history) == 0 {
+		return 0, fmt.Errorf("failed to get hash rate for price band: %w", err)
+	}
+	return history[len(history)-1].SettlementRate, nil
+}
+
+// priceBandDeviation widens the configured deviation for expiries further out. The anchor is
+// read at the current block, while a contract settles at the index rate of its expiry block,
+// which drifts with every difficulty adjustment in between. The band grows with the square
+// root of the difficulty periods to expiry, like the spread of a random walk, so a contract
+// expiring a year out, about 26 periods, accepts strikes about 5.2 times as far away.
+func priceBandDeviation(maxDeviation float64, expiryBlockHeight, currentBlockHeight uint64) float64 {
+	if expiryBlockHeight <= currentBlockHeight {
+		return maxDeviation
+	}
+	periods := float64(expiryBlockHeight-currentBlockHeight) / difficultyPeriodBlocks
+	return maxDeviation * math.Sqrt(1+periods)
+}
@@ -0,0 +1,19 @@
+package hashperp
+
+import (
+	"context"
+)
+
+// noopPriceProvider is the default PriceProvider for BTC-only deployments.
+// It never reports a price, so no fiat figures are recorded.
+type noopPriceProvider struct{}
+
+// NewNoopPriceProvider creates a PriceProvider that never returns a price
+func NewNoopPriceProvider() PriceProvider {
+	return noopPriceProvider{}
+}
+
+// GetBTCPrice implements PriceProvider.GetBTCPrice
+func (noopPriceProvider) GetBTCPrice(ctx context.Context, currency string) (float64, error) {
+	return 0, nil
+}
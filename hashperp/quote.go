@@ -0,0 +1,44 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetQuote implements OrderBookManager.GetQuote
+func (s *orderBookService) GetQuote(
+	ctx context.Context,
+	contractType ContractType,
+	expiryBlockHeight uint64,
+) (*Quote, error) {
+	// 1. Get the best level of each side of the book
+	bid, err := s.orderRepo.FindBestLevel(ctx, contractType, expiryBlockHeight, BUY)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best bid: %w", err)
+	}
+	ask, err := s.orderRepo.FindBestLevel(ctx, contractType, expiryBlockHeight, SELL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best ask: %w", err)
+	}
+
+	quote := &Quote{
+		ContractType:      contractType,
+		ExpiryBlockHeight: expiryBlockHeight,
+		Timestamp:         s.clock.Now().UTC(),
+	}
+	if bid != nil {
+		quote.BestBid = bid.StrikeRate
+		quote.BestBidSize = bid.Size
+	}
+	if ask != nil {
+		quote.BestAsk = ask.StrikeRate
+		quote.BestAskSize = ask.Size
+	}
+
+	// 2. The mid price is only meaningful with both sides quoted
+	if bid != nil && ask != nil {
+		quote.MidPrice = (quote.BestBid + quote.BestAsk) / 2
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,297 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DiscrepancyKind classifies a mismatch between the database and the chain
+type DiscrepancyKind string
+
+const (
+	DISCREPANCY_TX_NOT_FOUND        DiscrepancyKind = "TX_NOT_FOUND"        // A recorded transaction hash is unknown to the node
+	DISCREPANCY_TX_CONFLICTED       DiscrepancyKind = "TX_CONFLICTED"       // A recorded transaction lost to a conflicting one but is not marked FAILED
+	DISCREPANCY_TX_FAILED_ON_RECORD DiscrepancyKind = "TX_FAILED_ON_RECORD" // A transaction marked FAILED is known to the node
+	DISCREPANCY_SETTLEMENT_MISSING  DiscrepancyKind = "SETTLEMENT_MISSING"  // A settled contract has no settlement transaction on chain
+	DISCREPANCY_EXIT_MISSING        DiscrepancyKind = "EXIT_MISSING"        // An exit is recorded but its transaction is not on chain
+	DISCREPANCY_VTXO_STILL_ACTIVE   DiscrepancyKind = "VTXO_STILL_ACTIVE"   // A VTXO is active although a transaction spending it is on chain
+)
+
+// Discrepancy is a single mismatch found by ReconcileContract
+type Discrepancy struct {
+	Kind          DiscrepancyKind `json:"kind"`
+	TxHash        string          `json:"tx_hash,omitempty"`
+	TransactionID string          `json:"transaction_id,omitempty"`
+	VTXOID        string          `json:"vtxo_id,omitempty"`
+	Description   string          `json:"description"`
+	Corrected     bool            `json:"corrected"` // Whether the database was updated to match the chain
+}
+
+// ReconciliationReport lists how a contract's database records differ from the chain
+type ReconciliationReport struct {
+	ContractID      string         `json:"contract_id"`
+	ContractStatus  ContractStatus `json:"contract_status"`
+	Timestamp       time.Time      `json:"timestamp"`
+	CheckedTxHashes int            `json:"checked_tx_hashes"`
+	Discrepancies   []Discrepancy  `json:"discrepancies"`
+}
+
+// chainTxState is what the node reports about a transaction hash
+type chainTxState struct {
+	known         bool
+	conflicted    bool
+	confirmations uint64
+}
+
+// onChain reports whether the transaction is in the mempool or the chain
+func (c chainTxState) onChain() bool {
+	return c.known && !c.conflicted
+}
+
+// chainLookup queries the node once per transaction hash
+type chainLookup struct {
+	btcClient BitcoinClient
+	states    map[string]chainTxState
+}
+
+// state returns what the node knows about a transaction hash. Node errors other than an
+// unknown or conflicted transaction abort the reconciliation, a report built on them would
+// flag every transaction.
+func (l *chainLookup) state(ctx context.Context, txHash string) (chainTxState, error) {
+	if state, ok := l.states[txHash]; ok {
+		return state, nil
+	}
+
+	var state chainTxState
+	confirmations, err := l.btcClient.GetTransactionConfirmations(ctx, txHash)
+	switch {
+	case err == nil:
+		state = chainTxState{known: true, confirmations: confirmations}
+	case errors.Is(err, ErrTransactionConflicted):
+		state = chainTxState{known: true, conflicted: true}
+	case errors.Is(err, ErrTransactionUnknown):
+		state = chainTxState{}
+	default:
+		return chainTxState{}, fmt.Errorf("failed to get confirmations of %s: %w", txHash, err)
+	}
+
+	l.states[txHash] = state
+	return state, nil
+}
+
+// ReconcileContract implements ContractManager.ReconcileContract
+func (s *contractService) ReconcileContract(
+	ctx context.Context,
+	contractID string,
+	autoCorrect bool,
+) (*ReconciliationReport, error) {
+	// 1. Load the contract with its transactions and VTXOs
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, ErrContractNotFound
+	}
+
+	transactions, err := s.transactionRepo.FindByContract(ctx, contractID, nil, SORT_ASCENDING)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract transactions: %w", err)
+	}
+
+	vtxos, err := s.vtxoRepo.FindByContract(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract VTXOs: %w", err)
+	}
+
+	report := &ReconciliationReport{
+		ContractID:     contract.ID,
+		ContractStatus: contract.Status,
+		Timestamp:      s.clock.Now().UTC(),
+		Discrepancies:  []Discrepancy{},
+	}
+	lookup := &chainLookup{btcClient: s.btcClient, states: make(map[string]chainTxState)}
+
+	// 2. Every recorded transaction hash must be on chain unless it is marked FAILED
+	for _, tx := range transactions {
+		if tx.TxHash == "" {
+			continue
+		}
+		state, err := lookup.state(ctx, tx.TxHash)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case tx.Status == TX_STATUS_FAILED:
+			if state.onChain() {
+				report.Discrepancies = append(report.Discrepancies, Discrepancy{
+					Kind:          DISCREPANCY_TX_FAILED_ON_RECORD,
+					TxHash:        tx.TxHash,
+					TransactionID: tx.ID,
+					Description: fmt.Sprintf("%s transaction is marked FAILED but has %d confirmations",
+						tx.Type, state.confirmations),
+				})
+			}
+		case state.conflicted:
+			discrepancy := Discrepancy{
+				Kind:          DISCREPANCY_TX_CONFLICTED,
+				TxHash:        tx.TxHash,
+				TransactionID: tx.ID,
+				Description:   fmt.Sprintf("%s transaction conflicts with the chain", tx.Type),
+			}
+			// A conflicted transaction can never confirm, failing it is always safe
+			if autoCorrect {
+				tx.Status = TX_STATUS_FAILED
+				if err := s.transactionRepo.Update(ctx, tx); err != nil {
+					return nil, fmt.Errorf("failed to fail conflicted transaction %s: %w", tx.ID, err)
+				}
+				discrepancy.Corrected = true
+			}
+			report.Discrepancies = append(report.Discrepancies, discrepancy)
+		case !state.known:
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{
+				Kind:          DISCREPANCY_TX_NOT_FOUND,
+				TxHash:        tx.TxHash,
+				TransactionID: tx.ID,
+				Description:   fmt.Sprintf("%s transaction is not known to the node", tx.Type),
+			})
+		}
+	}
+
+	// 3. A settled contract's settlement transaction must be on chain, and it spends both VTXOs
+	settlementOnChain := false
+	if contract.Status == SETTLED {
+		if contract.SettlementTx == "" {
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{
+				Kind:        DISCREPANCY_SETTLEMENT_MISSING,
+				Description: "contract is SETTLED but has no settlement transaction",
+			})
+		} else {
+			state, err := lookup.state(ctx, contract.SettlementTx)
+			if err != nil {
+				return nil, err
+			}
+			settlementOnChain = state.onChain()
+			if !settlementOnChain {
+				report.Discrepancies = append(report.Discrepancies, Discrepancy{
+					Kind:        DISCREPANCY_SETTLEMENT_MISSING,
+					TxHash:      contract.SettlementTx,
+					Description: "contract is SETTLED but its settlement transaction is not on chain",
+				})
+			}
+		}
+	}
+
+	// 4. Recorded exits must be on chain
+	for _, exit := range []struct {
+		side   PositionSide
+		exited bool
+		txHash string
+	}{
+		{SIDE_BUYER, contract.BuyerExited, contract.BuyerExitTxHash},
+		{SIDE_SELLER, contract.SellerExited, contract.SellerExitTxHash},
+	} {
+		if !exit.exited {
+			continue
+		}
+		if exit.txHash == "" {
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{
+				Kind:        DISCREPANCY_EXIT_MISSING,
+				Description: fmt.Sprintf("%s is marked exited but has no exit transaction", exit.side),
+			})
+			continue
+		}
+		state, err := lookup.state(ctx, exit.txHash)
+		if err != nil {
+			return nil, err
+		}
+		if !state.onChain() {
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{
+				Kind:        DISCREPANCY_EXIT_MISSING,
+				TxHash:      exit.txHash,
+				Description: fmt.Sprintf("%s is marked exited but the exit transaction is not on chain", exit.side),
+			})
+		}
+	}
+
+	// 5. VTXOs must be inactive exactly when a transaction spending them is on chain
+	for _, vtxo := range vtxos {
+		if vtxo.ExitTxHash == "" {
+			if vtxo.IsActive && settlementOnChain &&
+				(vtxo.ID == contract.BuyerVTXO || vtxo.ID == contract.SellerVTXO) {
+				discrepancy := Discrepancy{
+					Kind:        DISCREPANCY_VTXO_STILL_ACTIVE,
+					TxHash:      contract.SettlementTx,
+					VTXOID:      vtxo.ID,
+					Description: "VTXO is still active although the settlement transaction spending it is on chain",
+				}
+				if autoCorrect {
+					vtxo.IsActive = false
+					if err := s.vtxoRepo.Update(ctx, vtxo); err != nil {
+						return nil, fmt.Errorf("failed to deactivate settled VTXO %s: %w", vtxo.ID, err)
+					}
+					discrepancy.Corrected = true
+				}
+				report.Discrepancies = append(report.Discrepancies, discrepancy)
+			}
+			continue
+		}
+
+		state, err := lookup.state(ctx, vtxo.ExitTxHash)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case !vtxo.IsActive && !state.onChain():
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{
+				Kind:        DISCREPANCY_EXIT_MISSING,
+				TxHash:      vtxo.ExitTxHash,
+				VTXOID:      vtxo.ID,
+				Description: "VTXO is inactive but its exit transaction is not on chain",
+			})
+		case vtxo.IsActive && state.onChain():
+			discrepancy := Discrepancy{
+				Kind:        DISCREPANCY_VTXO_STILL_ACTIVE,
+				TxHash:      vtxo.ExitTxHash,
+				VTXOID:      vtxo.ID,
+				Description: "VTXO is still active although its exit transaction is on chain",
+			}
+			if autoCorrect {
+				vtxo.IsActive = false
+				if vtxo.ExitTimestamp.IsZero() {
+					vtxo.ExitTimestamp = s.clock.Now().UTC()
+				}
+				if err := s.vtxoRepo.Update(ctx, vtxo); err != nil {
+					return nil, fmt.Errorf("failed to deactivate exited VTXO %s: %w", vtxo.ID, err)
+				}
+				discrepancy.Corrected = true
+			}
+			report.Discrepancies = append(report.Discrepancies, discrepancy)
+		}
+	}
+
+	report.CheckedTxHashes = len(lookup.states)
+
+	// 6. Surface corrections, which change records outside of their normal flows
+	for _, discrepancy := range report.Discrepancies {
+		if !discrepancy.Corrected {
+			continue
+		}
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:          EVENT_RECONCILIATION_CORRECTED,
+			ContractID:    contract.ID,
+			VTXOID:        discrepancy.VTXOID,
+			TransactionID: discrepancy.TransactionID,
+			Attributes: map[string]string{
+				"kind":    string(discrepancy.Kind),
+				"tx_hash": discrepancy.TxHash,
+			},
+		})
+	}
+
+	return report, nil
+}
@@ -0,0 +1,202 @@
+package hashperp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SchedulerConfig holds the interval of each maintenance job. A zero interval disables the job.
+type SchedulerConfig struct {
+	OfferCleanupInterval   time.Duration // How often expired swap offers are cleaned up
+	OrderExpiryInterval    time.Duration // How often orders past their expiry block are expired
+	SettlementInterval     time.Duration // How often contracts past their expiry block are settled
+	ExpiryNoticeInterval   time.Duration // How often contracts nearing expiry are flagged and notified
+	WebhookInterval        time.Duration // How often due webhook deliveries are attempted
+	MetricsInterval        time.Duration // How often the counts of records by status are refreshed
+	ConfirmationInterval   time.Duration // How often broadcast transactions are checked for confirmation
+	SweepReconcileInterval time.Duration // How often sweeps left pending are reconciled
+}
+
+// Scheduler runs periodic maintenance jobs in the background
+type Scheduler interface {
+	// Start launches the maintenance jobs
+	Start()
+
+	// Stop cancels the maintenance jobs and waits for any run in progress to finish
+	Stop()
+}
+
+// maintenanceScheduler implements the Scheduler interface
+type maintenanceScheduler struct {
+	contractMgr  ContractManager
+	vtxoMgr      VTXOManager
+	orderBookMgr OrderBookManager
+	swapOfferMgr SwapOfferManager
+	webhookMgr   WebhookManager
+	metrics      StatusMetricsCollector
+	confirmer    TransactionConfirmer
+	config       SchedulerConfig
+
+	eventPublisher EventPublisher
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // Non-nil while the jobs are running
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a new maintenance scheduler
+func NewScheduler(
+	contractMgr ContractManager,
+	vtxoMgr VTXOManager,
+	orderBookMgr OrderBookManager,
+	swapOfferMgr SwapOfferManager,
+	webhookMgr WebhookManager,
+	metrics StatusMetricsCollector,
+	confirmer TransactionConfirmer,
+	config SchedulerConfig,
+) Scheduler {
+	return &maintenanceScheduler{
+		contractMgr:  contractMgr,
+		vtxoMgr:      vtxoMgr,
+		orderBookMgr: orderBookMgr,
+		swapOfferMgr: swapOfferMgr,
+		webhookMgr:   webhookMgr,
+		metrics:      metrics,
+		confirmer:    confirmer,
+		config:       config,
+
+		eventPublisher: NewNoopEventPublisher(),
+	}
+}
+
+// SetEventPublisher sets the publisher that receives the failures of maintenance jobs
+func (s *maintenanceScheduler) SetEventPublisher(publisher EventPublisher) {
+	s.eventPublisher = publisher
+}
+
+// Start implements Scheduler.Start
+func (s *maintenanceScheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.runEvery(ctx, s.config.OfferCleanupInterval, s.cleanupExpiredOffers)
+	s.runEvery(ctx, s.config.OrderExpiryInterval, s.expireStaleOrders)
+	s.runEvery(ctx, s.config.SettlementInterval, s.settleExpiredContracts)
+	s.runEvery(ctx, s.config.ExpiryNoticeInterval, s.notifyContractsNearingExpiry)
+	s.runEvery(ctx, s.config.WebhookInterval, s.deliverPendingWebhooks)
+	s.runEvery(ctx, s.config.MetricsInterval, s.collectStatusMetrics)
+	s.runEvery(ctx, s.config.ConfirmationInterval, s.confirmPendingTransactions)
+	s.runEvery(ctx, s.config.SweepReconcileInterval, s.reconcileSweeps)
+}
+
+// Stop implements Scheduler.Stop
+func (s *maintenanceScheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	s.wg.Wait()
+}
+
+// runEvery runs a job on its own ticker until the context is canceled
+func (s *maintenanceScheduler) runEvery(ctx context.Context, interval time.Duration, job func(context.Context)) {
+	if interval <= 0 {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				job(ctx)
+			}
+		}
+	}()
+}
+
+// cleanupExpiredOffers marks swap offers past their expiry time as expired
+func (s *maintenanceScheduler) cleanupExpiredOffers(ctx context.Context) {
+	if _, err := s.swapOfferMgr.CleanupExpiredOffers(ctx); err != nil {
+		s.reportJobFailure(ctx, "cleanup_expired_offers", err)
+	}
+}
+
+// expireStaleOrders marks open orders past their expiry block as expired
+func (s *maintenanceScheduler) expireStaleOrders(ctx context.Context) {
+	if _, err := s.orderBookMgr.ExpireStaleOrders(ctx); err != nil {
+		s.reportJobFailure(ctx, "expire_stale_orders", err)
+	}
+}
+
+// settleExpiredContracts settles contracts whose expiry block has been reached
+func (s *maintenanceScheduler) settleExpiredContracts(ctx context.Context) {
+	if _, err := s.contractMgr.SettleExpiredContracts(ctx); err != nil {
+		s.reportJobFailure(ctx, "settle_expired_contracts", err)
+	}
+}
+
+// notifyContractsNearingExpiry flags contracts that came within the expiry notice window
+func (s *maintenanceScheduler) notifyContractsNearingExpiry(ctx context.Context) {
+	if _, err := s.contractMgr.NotifyContractsNearingExpiry(ctx); err != nil {
+		s.reportJobFailure(ctx, "notify_contracts_nearing_expiry", err)
+	}
+}
+
+// deliverPendingWebhooks attempts webhook deliveries that are due
+func (s *maintenanceScheduler) deliverPendingWebhooks(ctx context.Context) {
+	if _, err := s.webhookMgr.DeliverPendingWebhooks(ctx); err != nil {
+		s.reportJobFailure(ctx, "deliver_webhooks", err)
+	}
+}
+
+// collectStatusMetrics refreshes the counts of contracts, orders and swap offers by status
+func (s *maintenanceScheduler) collectStatusMetrics(ctx context.Context) {
+	if err := s.metrics.Collect(ctx); err != nil {
+		s.reportJobFailure(ctx, "collect_status_metrics", err)
+	}
+}
+
+// confirmPendingTransactions updates the status of broadcast transactions that confirmed or failed
+func (s *maintenanceScheduler) confirmPendingTransactions(ctx context.Context) {
+	if _, err := s.confirmer.ConfirmPendingTransactions(ctx); err != nil {
+		s.reportJobFailure(ctx, "confirm_pending_transactions", err)
+	}
+}
+
+// reconcileSweeps finishes VTXO sweeps that were recorded but never finalized
+func (s *maintenanceScheduler) reconcileSweeps(ctx context.Context) {
+	if _, err := s.vtxoMgr.ReconcileSweeps(ctx); err != nil {
+		s.reportJobFailure(ctx, "reconcile_sweeps", err)
+	}
+}
+
+// reportJobFailure publishes the failure of a maintenance job, the job runs again on its next tick
+func (s *maintenanceScheduler) reportJobFailure(ctx context.Context, operation string, err error) {
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:      EVENT_OPERATION_FAILED,
+		Operation: operation,
+		Error:     err.Error(),
+	})
+}
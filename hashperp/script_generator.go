@@ -14,6 +14,22 @@ import (
 	"time"
 )
 
+// scriptGeneratorService implements the ScriptGenerator interface
+type scriptGeneratorService struct {
+	btcClient     BitcoinClient
+	timeoutBlocks uint64  // Blocks after expiry before the timeout exit path opens
+	network       Network // Selects the address encoding of generated outputs
+}
+
+// NewScriptGeneratorService creates a new script generator for the given network
+func NewScriptGeneratorService(btcClient BitcoinClient, timeoutBlocks uint64, network Network) ScriptGenerator {
+	return &scriptGeneratorService{
+		btcClient:     btcClient,
+		timeoutBlocks: timeoutBlocks,
+		network:       network,
+	}
+}
+
 // GenerateExitScript (continued)
 func (s *scriptGeneratorService) GenerateExitScript(
 	ctx context.Context,
@@ -0,0 +1,101 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// SelfTradePolicy decides what happens when a user's buy and sell orders would match each other
+type SelfTradePolicy string
+
+const (
+	SELF_TRADE_SKIP          SelfTradePolicy = "skip"          // Leave both orders open and match past them
+	SELF_TRADE_CANCEL_OLDEST SelfTradePolicy = "cancel_oldest" // Cancel the resting order
+	SELF_TRADE_CANCEL_NEWEST SelfTradePolicy = "cancel_newest" // Cancel the incoming order
+	SELF_TRADE_ALLOW         SelfTradePolicy = "allow"         // Match them into a self contract, operator default only
+)
+
+// ValidateSelfTradePolicy validates the self-trade policy of an order, empty means the
+// operator's default. Only the operator may allow self trades.
+func ValidateSelfTradePolicy(policy SelfTradePolicy) error {
+	switch policy {
+	case "", SELF_TRADE_SKIP, SELF_TRADE_CANCEL_OLDEST, SELF_TRADE_CANCEL_NEWEST:
+		return nil
+	}
+	return fmt.Errorf("%w: self-trade policy must be %q, %q or %q", ErrInvalidParameters,
+		SELF_TRADE_SKIP, SELF_TRADE_CANCEL_OLDEST, SELF_TRADE_CANCEL_NEWEST)
+}
+
+// ParseSelfTradePolicy parses the operator's default self-trade policy
+func ParseSelfTradePolicy(name string) (SelfTradePolicy, error) {
+	policy := SelfTradePolicy(name)
+	if policy == SELF_TRADE_ALLOW {
+		return policy, nil
+	}
+	if policy == "" {
+		return "", fmt.Errorf("%w: self-trade policy is required", ErrInvalidParameters)
+	}
+	if err := ValidateSelfTradePolicy(policy); err != nil {
+		return "", err
+	}
+	return policy, nil
+}
+
+// SetSelfTradePolicy configures how orders without a policy of their own handle matching
+// another order of the same user. Contract creation must allow self contracts for
+// SELF_TRADE_ALLOW to produce any.
+func (s *orderBookService) SetSelfTradePolicy(policy SelfTradePolicy) {
+	s.selfTradePolicy = policy
+}
+
+// restingAndIncoming orders two matching orders by creation time
+func restingAndIncoming(a, b *Order) (resting, incoming *Order) {
+	if b.CreationTime.Before(a.CreationTime) {
+		return b, a
+	}
+	return a, b
+}
+
+// preventSelfTrade applies the self-trade policy to a match between two orders of the same
+// user and reports whether they may still match. The incoming order's own policy wins over
+// the default. Cancel policies cancel one order, the caller must check which is still open.
+func (s *orderBookService) preventSelfTrade(ctx context.Context, resting, incoming *Order) (bool, error) {
+	policy := incoming.SelfTradePolicy
+	if policy == "" {
+		policy = s.selfTradePolicy
+	}
+	if policy == SELF_TRADE_ALLOW {
+		return true, nil
+	}
+
+	var canceled *Order
+	switch policy {
+	case SELF_TRADE_CANCEL_OLDEST:
+		canceled = resting
+	case SELF_TRADE_CANCEL_NEWEST:
+		canceled = incoming
+	}
+	if canceled != nil {
+		canceled.Status = CANCELED
+		if err := s.orderRepo.Update(ctx, canceled); err != nil {
+			return false, fmt.Errorf("failed to cancel self-trading order %s: %w", canceled.ID, err)
+		}
+		s.recordOrderBookChange(ctx, canceled, ORDER_BOOK_CANCEL)
+	}
+
+	attributes := map[string]string{
+		"policy":            string(policy),
+		"resting_order_id":  resting.ID,
+		"incoming_order_id": incoming.ID,
+	}
+	if canceled != nil {
+		attributes["canceled_order_id"] = canceled.ID
+	}
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:       EVENT_SELF_TRADE_PREVENTED,
+		UserIDs:    []string{incoming.UserID},
+		Attributes: attributes,
+	})
+
+	return false, nil
+}
@@ -15,8 +15,20 @@ type hashPerpService struct {
 	swapOfferManager  SwapOfferManager
 	marketDataManager MarketDataManager
 	transactionManager TransactionManager
+	webhookManager     WebhookManager
 	scriptGenerator    ScriptGenerator
 	btcClient         BitcoinClient
+	dbPinger          DatabasePinger // Checked by Healthcheck when set
+	userRepo          UserRepository // Registered public keys, set to enable key registration
+	contractRepo      ContractRepository    // Aggregated by GetMarketSummary
+	transactionRepo   TransactionRepository // Aggregated by GetMarketSummary
+	orderRepo         OrderRepository       // Counted by GetPlatformStats
+	swapOfferRepo     SwapOfferRepository   // Counted by GetPlatformStats
+	statusChangeRepo  StatusChangeRepository // Read by GetAuditTrail
+	operators         map[string]bool        // Users allowed to run settlement and maintenance methods
+	transactionConfirmations uint64 // Confirmations a broadcast transaction needs to be reported confirmed
+	durations                DurationConfig // Bounds on order expiry, shared with contract creation
+	clock                    Clock          // Source of timestamps
 }
 
 // NewHashPerpService creates a new HashPerp service that implements the HashPerpService interface
@@ -27,6 +39,7 @@ func NewHashPerpService(
 	swapOfferManager SwapOfferManager,
 	marketDataManager MarketDataManager,
 	transactionManager TransactionManager,
+	webhookManager WebhookManager,
 	scriptGenerator ScriptGenerator,
 	btcClient BitcoinClient,
 ) HashPerpService {
@@ -37,8 +50,63 @@ func NewHashPerpService(
 		swapOfferManager:  swapOfferManager,
 		marketDataManager: marketDataManager,
 		transactionManager: transactionManager,
+		webhookManager:     webhookManager,
 		scriptGenerator:    scriptGenerator,
 		btcClient:         btcClient,
+		transactionConfirmations: defaultTransactionConfirmations,
+		durations:                DefaultDurationConfig(),
+		clock:                    NewSystemClock(),
+	}
+}
+
+// SetTransactionConfirmations configures how many confirmations a broadcast transaction
+// needs before GetTransactionStatus reports it confirmed
+func (s *hashPerpService) SetTransactionConfirmations(confirmations uint64) {
+	s.transactionConfirmations = confirmations
+}
+
+// SetDurationConfig configures the bounds on order expiry. Contract creation must use the
+// same config.
+func (s *hashPerpService) SetDurationConfig(durations DurationConfig) {
+	s.durations = durations
+}
+
+// SetUserRepository configures the repository user public keys are registered in
+func (s *hashPerpService) SetUserRepository(userRepo UserRepository) {
+	s.userRepo = userRepo
+}
+
+// SetContractRepository configures the repository market summaries aggregate contracts from
+func (s *hashPerpService) SetContractRepository(contractRepo ContractRepository) {
+	s.contractRepo = contractRepo
+}
+
+// SetTransactionRepository configures the repository market summaries aggregate settlements from
+func (s *hashPerpService) SetTransactionRepository(transactionRepo TransactionRepository) {
+	s.transactionRepo = transactionRepo
+}
+
+// SetOrderRepository configures the repository platform stats count open orders from
+func (s *hashPerpService) SetOrderRepository(orderRepo OrderRepository) {
+	s.orderRepo = orderRepo
+}
+
+// SetSwapOfferRepository configures the repository platform stats count open swap offers from
+func (s *hashPerpService) SetSwapOfferRepository(swapOfferRepo SwapOfferRepository) {
+	s.swapOfferRepo = swapOfferRepo
+}
+
+// SetStatusChangeRepository configures the repository audit trails are read from
+func (s *hashPerpService) SetStatusChangeRepository(statusChangeRepo StatusChangeRepository) {
+	s.statusChangeRepo = statusChangeRepo
+}
+
+// SetOperators configures the users allowed to run settlement and maintenance methods. Without
+// operators only internal callers may run them.
+func (s *hashPerpService) SetOperators(userIDs []string) {
+	s.operators = make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		s.operators[userID] = true
 	}
 }
 
@@ -54,32 +122,239 @@ func (s *hashPerpService) CreateContract(
 	strikeRate float64,
 	expiryBlockHeight uint64,
 	size float64,
+	leverage float64,
+	settlementMethod SettlementMethod,
+	exitFeeRate *float64,
+	idempotencyKey string,
 ) (*Contract, error) {
-	return s.contractManager.CreateContract(ctx, buyerID, sellerID, contractType, strikeRate, expiryBlockHeight, size)
+	if err := ValidateIdempotencyKey(idempotencyKey); err != nil {
+		return nil, err
+	}
+	// Creation locks both parties' collateral, which one caller cannot consent to for the
+	// other. Users create contracts by placing orders, each side authorizing its own.
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return nil, err
+	}
+	return s.contractManager.CreateContract(ctx, buyerID, sellerID, contractType, strikeRate, expiryBlockHeight, size, leverage, settlementMethod, exitFeeRate, idempotencyKey)
 }
 
 func (s *hashPerpService) GetContract(ctx context.Context, contractID string) (*Contract, error) {
 	return s.contractManager.GetContract(ctx, contractID)
 }
 
-func (s *hashPerpService) GetContractsByUser(ctx context.Context, userID string, status []ContractStatus) ([]*Contract, error) {
-	return s.contractManager.GetContractsByUser(ctx, userID, status)
+func (s *hashPerpService) GetContractsByUser(ctx context.Context, userID string, status []ContractStatus, includeArchived bool, page PageRequest) ([]*Contract, string, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, "", err
+	}
+	page, err := NormalizePageRequest(page)
+	if err != nil {
+		return nil, "", err
+	}
+	return s.contractManager.GetContractsByUser(ctx, userID, status, includeArchived, page)
+}
+
+func (s *hashPerpService) SearchContracts(ctx context.Context, filter ContractFilter, page PageRequest) ([]*Contract, string, error) {
+	// Users may only search their own contracts, internal callers may search any
+	if err := authorizeUser(ctx, filter.UserID); err != nil {
+		return nil, "", err
+	}
+	page, err := NormalizePageRequest(page)
+	if err != nil {
+		return nil, "", err
+	}
+	return s.contractManager.SearchContracts(ctx, filter, page)
+}
+
+func (s *hashPerpService) GetContractsByExpiryRange(ctx context.Context, fromHeight, toHeight uint64) ([]*Contract, error) {
+	contracts, err := s.contractManager.GetContractsByExpiryRange(ctx, fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+	return callerContracts(ctx, contracts), nil
+}
+
+func (s *hashPerpService) GetContractsNearingExpiry(ctx context.Context, withinBlocks uint64) ([]*Contract, error) {
+	contracts, err := s.contractManager.GetContractsNearingExpiry(ctx, withinBlocks)
+	if err != nil {
+		return nil, err
+	}
+	return callerContracts(ctx, contracts), nil
+}
+
+func (s *hashPerpService) GetSettlementHistory(ctx context.Context, contractType ContractType, fromHeight, toHeight uint64) ([]*SettlementHistoryPoint, error) {
+	return s.contractManager.GetSettlementHistory(ctx, contractType, fromHeight, toHeight)
 }
 
-func (s *hashPerpService) SettleContract(ctx context.Context, contractID string) (*Transaction, error) {
-	return s.contractManager.SettleContract(ctx, contractID)
+func (s *hashPerpService) GetOpenInterest(ctx context.Context, contractType ContractType) ([]*OpenInterestBucket, error) {
+	return s.contractManager.GetOpenInterest(ctx, contractType)
+}
+
+func (s *hashPerpService) SettleContract(ctx context.Context, contractID string, feePriority FeePriority) (*Transaction, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		if err := s.authorizeContractParty(ctx, contractID); err != nil {
+			return nil, err
+		}
+	}
+	return s.contractManager.SettleContract(ctx, contractID, feePriority)
 }
 
 func (s *hashPerpService) ExitContract(ctx context.Context, contractID string, userID string) (*Transaction, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
 	return s.contractManager.ExitContract(ctx, contractID, userID)
 }
 
 func (s *hashPerpService) RolloverContract(ctx context.Context, contractID string, newExpiryBlockHeight uint64) (*Contract, *Transaction, error) {
+	if err := s.authorizeContractParty(ctx, contractID); err != nil {
+		return nil, nil, err
+	}
 	return s.contractManager.RolloverContract(ctx, contractID, newExpiryBlockHeight)
 }
 
-func (s *hashPerpService) ExecuteExitPath(ctx context.Context, contractID string, userID string, exitPathType string) (*Transaction, error) {
-	return s.contractManager.ExecuteExitPath(ctx, contractID, userID, exitPathType)
+func (s *hashPerpService) JoinContractSide(ctx context.Context, contractID string, userID string, side PositionSide, amount float64, signature []byte) (*Contract, *Transaction, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+	return s.contractManager.JoinContractSide(ctx, contractID, userID, side, amount, signature)
+}
+
+func (s *hashPerpService) RequestDynamicJoin(ctx context.Context, contractID string, userID string, side PositionSide, signature []byte) (*Contract, *Transaction, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+	return s.contractManager.RequestDynamicJoin(ctx, contractID, userID, side, signature)
+}
+
+func (s *hashPerpService) GetContractScripts(ctx context.Context, contractID string) (*ContractScripts, error) {
+	if err := s.authorizeContractParty(ctx, contractID); err != nil {
+		return nil, err
+	}
+	return s.contractManager.GetContractScripts(ctx, contractID)
+}
+
+func (s *hashPerpService) CreateContractTemplate(ctx context.Context, template *ContractTemplate) (*ContractTemplate, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return nil, err
+	}
+	return s.contractManager.CreateContractTemplate(ctx, template)
+}
+
+func (s *hashPerpService) GetContractTemplates(ctx context.Context) ([]*ContractTemplate, error) {
+	return s.contractManager.GetContractTemplates(ctx)
+}
+
+func (s *hashPerpService) CreateContractFromTemplate(ctx context.Context, templateID string, buyerID, sellerID string, strikeRate float64) (*Contract, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return nil, err
+	}
+	return s.contractManager.CreateContractFromTemplate(ctx, templateID, buyerID, sellerID, strikeRate)
+}
+
+func (s *hashPerpService) ExecuteExitPath(ctx context.Context, contractID string, userID string, exitPathType string, feePriority FeePriority) (*Transaction, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.contractManager.ExecuteExitPath(ctx, contractID, userID, exitPathType, feePriority)
+}
+
+func (s *hashPerpService) CheckLiquidations(ctx context.Context) ([]*Transaction, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return nil, err
+	}
+	return s.contractManager.CheckLiquidations(ctx)
+}
+
+func (s *hashPerpService) SettleExpiredContracts(ctx context.Context) ([]*Transaction, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return nil, err
+	}
+	return s.contractManager.SettleExpiredContracts(ctx)
+}
+
+func (s *hashPerpService) GetContractPnLHistory(ctx context.Context, contractID string, intervalBlocks uint64) ([]*PnLPoint, error) {
+	if err := s.authorizeContractMember(ctx, contractID); err != nil {
+		return nil, err
+	}
+	return s.contractManager.GetContractPnLHistory(ctx, contractID, intervalBlocks)
+}
+
+func (s *hashPerpService) GetUserPositionSummary(ctx context.Context, userID string) (*UserPositionSummary, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.contractManager.GetUserPositionSummary(ctx, userID)
+}
+
+func (s *hashPerpService) FundContract(ctx context.Context, contractID string, buyerSignature, sellerSignature []byte) (*Transaction, error) {
+	return s.contractManager.FundContract(ctx, contractID, buyerSignature, sellerSignature)
+}
+
+func (s *hashPerpService) CancelContract(ctx context.Context, contractID string, userID string) (*Transaction, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.contractManager.CancelContract(ctx, contractID, userID)
+}
+
+func (s *hashPerpService) GetContractsRequiringSettlement(ctx context.Context) ([]*Contract, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return nil, err
+	}
+	return s.contractManager.GetContractsRequiringSettlement(ctx)
+}
+
+func (s *hashPerpService) AutoSettleExpired(ctx context.Context) (*AutoSettleSummary, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return nil, err
+	}
+	return s.contractManager.AutoSettleExpired(ctx)
+}
+
+func (s *hashPerpService) NotifyContractsNearingExpiry(ctx context.Context) ([]*Contract, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return nil, err
+	}
+	return s.contractManager.NotifyContractsNearingExpiry(ctx)
+}
+
+func (s *hashPerpService) ReconcileContract(ctx context.Context, contractID string, autoCorrect bool) (*ReconciliationReport, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return nil, err
+	}
+	return s.contractManager.ReconcileContract(ctx, contractID, autoCorrect)
+}
+
+func (s *hashPerpService) GetSettlementResult(ctx context.Context, contractID string) (*SettlementResult, error) {
+	if err := s.authorizeContractMember(ctx, contractID); err != nil {
+		return nil, err
+	}
+	return s.contractManager.GetSettlementResult(ctx, contractID)
+}
+
+func (s *hashPerpService) PreviewSettlement(ctx context.Context, contractID string) (*SettlementPreview, error) {
+	if err := s.authorizeContractMember(ctx, contractID); err != nil {
+		return nil, err
+	}
+	return s.contractManager.PreviewSettlement(ctx, contractID)
+}
+
+func (s *hashPerpService) ConfirmSettlement(ctx context.Context, contractID string, userID string) (*Transaction, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.contractManager.ConfirmSettlement(ctx, contractID, userID)
+}
+
+func (s *hashPerpService) DisputeSettlement(ctx context.Context, contractID string, userID string, evidence string) (*Transaction, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.contractManager.DisputeSettlement(ctx, contractID, userID, evidence)
+}
+
+func (s *hashPerpService) SubscribeToContract(contractID string) (<-chan ContractStatusEvent, func()) {
+	return s.contractManager.SubscribeToContract(contractID)
 }
 
 // ===========================
@@ -94,189 +369,377 @@ func (s *hashPerpService) CreateVTXO(
 	scriptPath string,
 	signatureData []byte,
 ) (*VTXO, error) {
+	if err := authorizeUser(ctx, ownerID); err != nil {
+		return nil, err
+	}
 	return s.vtxoManager.CreateVTXO(ctx, contractID, ownerID, amount, scriptPath, signatureData)
 }
 
 func (s *hashPerpService) GetVTXO(ctx context.Context, vtxoID string) (*VTXO, error) {
-	return s.vtxoManager.GetVTXO(ctx, vtxoID)
+	vtxo, err := s.vtxoManager.GetVTXO(ctx, vtxoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, vtxo.OwnerID); err != nil {
+		return nil, err
+	}
+	return vtxo, nil
 }
 
 func (s *hashPerpService) GetVTXOsByContract(ctx context.Context, contractID string) ([]*VTXO, error) {
+	if err := s.authorizeContractMember(ctx, contractID); err != nil {
+		return nil, err
+	}
 	return s.vtxoManager.GetVTXOsByContract(ctx, contractID)
 }
 
-func (s *hashPerpService) GetVTXOsByUser(ctx context.Context, userID string, onlyActive bool) ([]*VTXO, error) {
-	return s.vtxoManager.GetVTXOsByUser(ctx, userID, onlyActive)
+func (s *hashPerpService) GetUserVTXOInContract(ctx context.Context, contractID string, userID string) (*VTXO, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.vtxoManager.GetUserVTXOInContract(ctx, contractID, userID)
+}
+
+func (s *hashPerpService) GetVTXOsByUser(ctx context.Context, userID string, onlyActive bool, includeArchived bool, page PageRequest) ([]*VTXO, string, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, "", err
+	}
+	page, err := NormalizePageRequest(page)
+	if err != nil {
+		return nil, "", err
+	}
+	return s.vtxoManager.GetVTXOsByUser(ctx, userID, onlyActive, includeArchived, page)
 }
 
 func (s *hashPerpService) SwapVTXO(ctx context.Context, vtxoID string, newOwnerID string, newSignatureData []byte) (*VTXO, *Transaction, error) {
+	if err := s.authorizeVTXOOwner(ctx, vtxoID); err != nil {
+		return nil, nil, err
+	}
 	return s.vtxoManager.SwapVTXO(ctx, vtxoID, newOwnerID, newSignatureData)
 }
 
+func (s *hashPerpService) SplitVTXO(ctx context.Context, vtxoID string, amounts []float64, owners []string, signatures [][]byte) ([]*VTXO, *Transaction, error) {
+	if err := s.authorizeVTXOOwner(ctx, vtxoID); err != nil {
+		return nil, nil, err
+	}
+	return s.vtxoManager.SplitVTXO(ctx, vtxoID, amounts, owners, signatures)
+}
+
 func (s *hashPerpService) CreatePresignedExitTransaction(ctx context.Context, vtxoID string, signatureData []byte) (string, error) {
+	if err := s.authorizeVTXOOwner(ctx, vtxoID); err != nil {
+		return "", err
+	}
 	return s.vtxoManager.CreatePresignedExitTransaction(ctx, vtxoID, signatureData)
 }
 
-func (s *hashPerpService) ExecuteVTXOSweep(ctx context.Context, vtxoID string) (*Transaction, error) {
-	return s.vtxoManager.ExecuteVTXOSweep(ctx, vtxoID)
+func (s *hashPerpService) GetPreSignedExit(ctx context.Context, preSignedExitID string) (*PreSignedExit, error) {
+	preSignedExit, err := s.vtxoManager.GetPreSignedExit(ctx, preSignedExitID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, preSignedExit.UserID); err != nil {
+		return nil, err
+	}
+	return preSignedExit, nil
 }
 
-// ===========================
-// OrderBookManager delegation
-// ===========================
+func (s *hashPerpService) GetPreSignedExits(ctx context.Context, vtxoID string) ([]*PreSignedExit, error) {
+	if err := s.authorizeVTXOOwner(ctx, vtxoID); err != nil {
+		return nil, err
+	}
+	return s.vtxoManager.GetPreSignedExits(ctx, vtxoID)
+}
 
-func (s *hashPerpService) PlaceOrder(
-	ctx context.Context,
-	userID string,
-	orderType OrderType,
-	contractType ContractType,
-	strikeRate float64,
-	expiryBlockHeight uint64,
-	size float64,
-) (*Order, error) {
-	return s.orderBookManager.PlaceOrder(ctx, userID, orderType, contractType, strikeRate, expiryBlockHeight, size)
+func (s *hashPerpService) BroadcastPreSignedExit(ctx context.Context, preSignedExitID string) (*Transaction, error) {
+	if _, err := s.GetPreSignedExit(ctx, preSignedExitID); err != nil {
+		return nil, err
+	}
+	return s.vtxoManager.BroadcastPreSignedExit(ctx, preSignedExitID)
 }
 
-func (s *hashPerpService) CancelOrder(ctx context.Context, orderID string, userID string) error {
-	return s.orderBookManager.CancelOrder(ctx, orderID, userID)
+func (s *hashPerpService) SweepAllVTXOs(ctx context.Context, userID string) (*SweepSummary, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.vtxoManager.SweepAllVTXOs(ctx, userID)
 }
 
-func (s *hashPerpService) GetOrder(ctx context.Context, orderID string) (*Order, error) {
-	return s.orderBookManager.GetOrder(ctx, orderID)
+func (s *hashPerpService) ReconcileSweeps(ctx context.Context) (int, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return 0, err
+	}
+	return s.vtxoManager.ReconcileSweeps(ctx)
 }
 
-func (s *hashPerpService) GetOrdersByUser(ctx context.Context, userID string, status []OrderStatus) ([]*Order, error) {
-	return s.orderBookManager.GetOrdersByUser(ctx, userID, status)
+// ===========================
+// OrderBookManager delegation
+// ===========================
+
+func (s *hashPerpService) ReduceOrder(ctx context.Context, orderID string, userID string, newSize float64) (*Order, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.orderBookManager.ReduceOrder(ctx, orderID, userID, newSize)
 }
 
-func (s *hashPerpService) GetOrderBook(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) ([]*Order, error) {
-	return s.orderBookManager.GetOrderBook(ctx, contractType, expiryBlockHeight)
+func (s *hashPerpService) ModifyOrder(ctx context.Context, orderID string, userID string, newStrikeRate float64, newSize float64) (*Order, error) {
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	// A modified order is held to the same limits as a new one
+	if err := ValidateRate(newStrikeRate, 0.0001, 1000); err != nil {
+		return nil, fmt.Errorf("invalid strike rate: %w", err)
+	}
+	if err := ValidateAmount(newSize, 0.001, 100); err != nil {
+		return nil, fmt.Errorf("invalid order size: %w", err)
+	}
+	return s.orderBookManager.ModifyOrder(ctx, orderID, userID, newStrikeRate, newSize)
 }
 
-func (s *hashPerpService) MatchOrders(ctx context.Context) ([]*Contract, error) {
-	return s.orderBookManager.MatchOrders(ctx)
+func (s *hashPerpService) SubscribeToOrderBook(contractType ContractType, expiryBlockHeight uint64) (<-chan OrderBookUpdate, func()) {
+	return s.orderBookManager.SubscribeToOrderBook(contractType, expiryBlockHeight)
+}
+
+func (s *hashPerpService) ExpireStaleOrders(ctx context.Context) (int, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return 0, err
+	}
+	return s.orderBookManager.ExpireStaleOrders(ctx)
 }
 
 // ===========================
 // SwapOfferManager delegation
 // ===========================
 
-func (s *hashPerpService) CreateSwapOffer(
-	ctx context.Context,
-	offerorID string,
-	vtxoID string,
-	offeredRate float64,
-	expiryTime time.Time,
-) (*SwapOffer, error) {
-	return s.swapOfferManager.CreateSwapOffer(ctx, offerorID, vtxoID, offeredRate, expiryTime)
+func (s *hashPerpService) GetBestSwapOffer(ctx context.Context, contractID string, side PositionSide) (*SwapOffer, error) {
+	return s.swapOfferManager.GetBestSwapOffer(ctx, contractID, side)
 }
 
-func (s *hashPerpService) AcceptSwapOffer(ctx context.Context, offerID string, acceptorID string) (*Transaction, error) {
-	return s.swapOfferManager.AcceptSwapOffer(ctx, offerID, acceptorID)
+func (s *hashPerpService) AutoAcceptBestOffer(ctx context.Context, vtxoID string, acceptorID string, maxRate float64, signatureData []byte) (*SwapOffer, *Transaction, error) {
+	if err := authorizeUser(ctx, acceptorID); err != nil {
+		return nil, nil, err
+	}
+	return s.swapOfferManager.AutoAcceptBestOffer(ctx, vtxoID, acceptorID, maxRate, signatureData)
 }
 
-func (s *hashPerpService) CancelSwapOffer(ctx context.Context, offerID string, offerorID string) error {
-	return s.swapOfferManager.CancelSwapOffer(ctx, offerID, offerorID)
+func (s *hashPerpService) InvalidateOffersForVTXO(ctx context.Context, vtxoID string) (int, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return 0, err
+	}
+	return s.swapOfferManager.InvalidateOffersForVTXO(ctx, vtxoID)
 }
 
-func (s *hashPerpService) GetSwapOffer(ctx context.Context, offerID string) (*SwapOffer, error) {
-	return s.swapOfferManager.GetSwapOffer(ctx, offerID)
-}
+// ===========================
+// MarketDataManager delegation
+// ===========================
 
-func (s *hashPerpService) GetSwapOffersByUser(ctx context.Context, userID string, isOfferor bool) ([]*SwapOffer, error) {
-	return s.swapOfferManager.GetSwapOffersByUser(ctx, userID, isOfferor)
+func (s *hashPerpService) GetAverageHashRate(ctx context.Context, endBlockHeight uint64, windowBlocks uint64) (float64, error) {
+	return s.marketDataManager.GetAverageHashRate(ctx, endBlockHeight, windowBlocks)
 }
 
-func (s *hashPerpService) GetSwapOffersByContract(ctx context.Context, contractID string) ([]*SwapOffer, error) {
-	return s.swapOfferManager.GetSwapOffersByContract(ctx, contractID)
+func (s *hashPerpService) SubscribeToHashRate() (<-chan *HashRateData, func()) {
+	return s.marketDataManager.SubscribeToHashRate()
 }
 
 // ===========================
-// MarketDataManager delegation
+// TransactionManager delegation
 // ===========================
 
-func (s *hashPerpService) GetCurrentHashRate(ctx context.Context) (*HashRateData, error) {
-	return s.marketDataManager.GetCurrentHashRate(ctx)
-}
+// GetTransactionStatus implements HashPerpService.GetTransactionStatus
+func (s *hashPerpService) GetTransactionStatus(ctx context.Context, transactionID string) (*TransactionStatus, error) {
+	if err := ValidateUUID(transactionID); err != nil {
+		return nil, fmt.Errorf("invalid transaction ID: %w", err)
+	}
 
-func (s *hashPerpService) GetHistoricalHashRate(ctx context.Context, startTime, endTime time.Time) ([]*HashRateData, error) {
-	return s.marketDataManager.GetHistoricalHashRate(ctx, startTime, endTime)
-}
+	tx, err := s.transactionManager.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx == nil {
+		return nil, ErrTransactionNotFound
+	}
+	if err := authorizeUser(ctx, tx.UserIDs...); err != nil {
+		return nil, err
+	}
 
-func (s *hashPerpService) GetHashRateAtBlockHeight(ctx context.Context, blockHeight uint64) (*HashRateData, error) {
-	return s.marketDataManager.GetHashRateAtBlockHeight(ctx, blockHeight)
+	return checkTransactionStatus(ctx, s.btcClient, tx, s.transactionConfirmations)
 }
 
-func (s *hashPerpService) CalculateBTCPerPHPerDay(ctx context.Context, hashRate float64, blockHeight uint64) (float64, error) {
-	return s.marketDataManager.CalculateBTCPerPHPerDay(ctx, hashRate, blockHeight)
-}
+// RegisterUserKey implements HashPerpService.RegisterUserKey
+func (s *hashPerpService) RegisterUserKey(ctx context.Context, userID string, pubKey, signature, currentKeySignature []byte) error {
+	if err := ValidateUserID(userID); err != nil {
+		return err
+	}
+	if err := ValidatePublicKey(pubKey); err != nil {
+		return err
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return err
+	}
+	if s.userRepo == nil {
+		return errors.New("no user repository configured")
+	}
 
-// ===========================
-// TransactionManager delegation
-// ===========================
+	// 1. The new key must sign the registration, proving the user holds it
+	message := buildRegisterKeyMessage(userID, pubKey)
+	if err := verifySignatureWithKey(ctx, s.btcClient, pubKey, message, signature); err != nil {
+		return fmt.Errorf("new key: %w", err)
+	}
 
-func (s *hashPerpService) RecordTransaction(
-	ctx context.Context,
-	transactionType TransactionType,
-	contractID string,
-	userIDs []string,
-	txHash string,
-	amount float64,
-	btcPerPHPerDay float64,
-	blockHeight uint64,
-	relatedEntities map[string]string,
-) (*Transaction, error) {
-	return s.transactionManager.RecordTransaction(ctx, transactionType, contractID, userIDs, txHash, amount, btcPerPHPerDay, blockHeight, relatedEntities)
-}
+	// 2. Rotating also needs the current key, so a stolen session cannot replace it
+	currentKey, err := s.userRepo.GetPublicKey(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user key: %w", err)
+	}
+	if len(currentKey) > 0 {
+		if err := verifySignatureWithKey(ctx, s.btcClient, currentKey, message, currentKeySignature); err != nil {
+			return fmt.Errorf("current key: %w", err)
+		}
+	}
 
-func (s *hashPerpService) GetTransaction(ctx context.Context, transactionID string) (*Transaction, error) {
-	return s.transactionManager.GetTransaction(ctx, transactionID)
+	if err := s.userRepo.RegisterUserKey(ctx, userID, pubKey); err != nil {
+		return fmt.Errorf("failed to register user key: %w", err)
+	}
+	return nil
 }
 
-func (s *hashPerpService) GetTransactionsByUser(ctx context.Context, userID string, transactionTypes []TransactionType) ([]*Transaction, error) {
-	return s.transactionManager.GetTransactionsByUser(ctx, userID, transactionTypes)
-}
+// GetUserKey implements HashPerpService.GetUserKey
+func (s *hashPerpService) GetUserKey(ctx context.Context, userID string) ([]byte, error) {
+	if err := ValidateUserID(userID); err != nil {
+		return nil, err
+	}
+	if s.userRepo == nil {
+		return nil, ErrUserKeyNotFound
+	}
 
-func (s *hashPerpService) GetTransactionsByContract(ctx context.Context, contractID string) ([]*Transaction, error) {
-	return s.transactionManager.GetTransactionsByContract(ctx, contractID)
+	pubKey, err := s.userRepo.GetPublicKey(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user key: %w", err)
+	}
+	if len(pubKey) == 0 {
+		return nil, ErrUserKeyNotFound
+	}
+	return pubKey, nil
 }
 
 // ===========================
-// ScriptGenerator delegation
+// WebhookManager delegation
 // ===========================
 
-func (s *hashPerpService) GenerateContractScripts(ctx context.Context, contract *Contract) (map[string]string, error) {
-	return s.scriptGenerator.GenerateContractScripts(ctx, contract)
+func (s *hashPerpService) RegisterWebhook(ctx context.Context, userID string, url string, eventTypes []TransactionType) (*WebhookSubscription, error) {
+	if err := ValidateUserID(userID); err != nil {
+		return nil, err
+	}
+	for _, eventType := range eventTypes {
+		if err := ValidateTransactionType(eventType); err != nil {
+			return nil, err
+		}
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.webhookManager.RegisterWebhook(ctx, userID, url, eventTypes)
 }
 
-func (s *hashPerpService) GenerateSetupTransaction(ctx context.Context, contract *Contract, buyerVTXO, sellerVTXO *VTXO) (string, error) {
-	return s.scriptGenerator.GenerateSetupTransaction(ctx, contract, buyerVTXO, sellerVTXO)
+func (s *hashPerpService) GetWebhooksByUser(ctx context.Context, userID string) ([]*WebhookSubscription, error) {
+	if err := ValidateUserID(userID); err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.webhookManager.GetWebhooksByUser(ctx, userID)
 }
 
-func (s *hashPerpService) GenerateFinalTransaction(ctx context.Context, contract *Contract, setupTxID string) (string, error) {
-	return s.scriptGenerator.GenerateFinalTransaction(ctx, contract, setupTxID)
+func (s *hashPerpService) DeleteWebhook(ctx context.Context, webhookID string, userID string) error {
+	if err := ValidateUUID(webhookID); err != nil {
+		return fmt.Errorf("invalid webhook ID: %w", err)
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return err
+	}
+	return s.webhookManager.DeleteWebhook(ctx, webhookID, userID)
 }
 
-func (s *hashPerpService) GenerateSettlementTransaction(ctx context.Context, contract *Contract, finalTxID string, winnerID string) (string, error) {
-	return s.scriptGenerator.GenerateSettlementTransaction(ctx, contract, finalTxID, winnerID)
+func (s *hashPerpService) NotifyTransaction(ctx context.Context, tx *Transaction) {
+	s.webhookManager.NotifyTransaction(ctx, tx)
 }
 
-func (s *hashPerpService) GenerateExitPathScripts(ctx context.Context, contract *Contract) (map[string]string, error) {
-	return s.scriptGenerator.GenerateExitPathScripts(ctx, contract)
+func (s *hashPerpService) DeliverPendingWebhooks(ctx context.Context) (int, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return 0, err
+	}
+	return s.webhookManager.DeliverPendingWebhooks(ctx)
 }
 
 // ===========================
 // Additional service methods
 // ===========================
 
+// SetDatabasePinger configures the database connection checked by Healthcheck
+func (s *hashPerpService) SetDatabasePinger(pinger DatabasePinger) {
+	s.dbPinger = pinger
+}
+
 // Healthcheck implements HashPerpService.Healthcheck
-func (s *hashPerpService) Healthcheck(ctx context.Context) error {
-	// Basic healthcheck: check if we can connect to Bitcoin node
-	_, err := s.btcClient.GetCurrentBlockHeight(ctx)
+func (s *hashPerpService) Healthcheck(ctx context.Context) *HealthReport {
+	return s.checkHealth(ctx)
+}
+
+// checkHealth checks the database and the Bitcoin node, timing each check
+func (s *hashPerpService) checkHealth(ctx context.Context) *HealthReport {
+	report := &HealthReport{
+		Status:    HEALTH_HEALTHY,
+		CheckedAt: s.clock.Now().UTC(),
+	}
+
+	// 1. Check the database connection
+	if s.dbPinger != nil {
+		report.addDependency("postgres", func() error {
+			return s.dbPinger.Ping(ctx)
+		})
+	}
+
+	// 2. Check the Bitcoin node is reachable and has finished its initial block download
+	report.addDependency("bitcoin_node", func() error {
+		blockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get current block height: %w", err)
+		}
+		report.BlockHeight = blockHeight
+
+		ibd, err := s.btcClient.IsInitialBlockDownload(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get initial block download state: %w", err)
+		}
+		report.InitialBlockDownload = ibd
+		if ibd {
+			return errors.New("node is still in initial block download")
+		}
+		return nil
+	})
+
+	return report
+}
+
+// addDependency runs a dependency check and records its outcome and latency.
+// Any failing dependency marks the whole report unhealthy.
+func (r *HealthReport) addDependency(name string, check func() error) {
+	start := time.Now()
+	err := check()
+
+	dependency := DependencyHealth{
+		Name:      name,
+		Status:    HEALTH_HEALTHY,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
 	if err != nil {
-		return fmt.Errorf("failed to connect to Bitcoin node: %w", err)
+		dependency.Status = HEALTH_UNHEALTHY
+		dependency.Error = err.Error()
+		r.Status = HEALTH_UNHEALTHY
 	}
-	return nil
+
+	r.Dependencies = append(r.Dependencies, dependency)
 }
 
 // GetCurrentBlockHeight implements HashPerpService.GetCurrentBlockHeight
@@ -284,6 +747,49 @@ func (s *hashPerpService) GetCurrentBlockHeight(ctx context.Context) (uint64, er
 	return s.btcClient.GetCurrentBlockHeight(ctx)
 }
 
+// authorizeContractParty checks that the authenticated caller is the buyer or seller of a contract
+func (s *hashPerpService) authorizeContractParty(ctx context.Context, contractID string) error {
+	if isInternalCaller(ctx) {
+		return nil
+	}
+
+	contract, err := s.contractManager.GetContract(ctx, contractID)
+	if err != nil {
+		return err
+	}
+
+	return authorizeUser(ctx, contract.BuyerID, contract.SellerID)
+}
+
+// authorizeContractMember checks that the authenticated caller holds a position in a contract,
+// as its buyer, its seller or a member of either side's pool
+func (s *hashPerpService) authorizeContractMember(ctx context.Context, contractID string) error {
+	if isInternalCaller(ctx) {
+		return nil
+	}
+
+	contract, err := s.contractManager.GetContract(ctx, contractID)
+	if err != nil {
+		return err
+	}
+
+	return authorizeUser(ctx, contractUserIDs(contract)...)
+}
+
+// authorizeVTXOOwner checks that the authenticated caller owns a VTXO
+func (s *hashPerpService) authorizeVTXOOwner(ctx context.Context, vtxoID string) error {
+	if isInternalCaller(ctx) {
+		return nil
+	}
+
+	vtxo, err := s.vtxoManager.GetVTXO(ctx, vtxoID)
+	if err != nil {
+		return err
+	}
+
+	return authorizeUser(ctx, vtxo.OwnerID)
+}
+
 // ValidateContractParameters implements HashPerpService.ValidateContractParameters
 func (s *hashPerpService) ValidateContractParameters(
 	ctx context.Context,
@@ -303,18 +809,13 @@ func (s *hashPerpService) ValidateContractParameters(
 	}
 
 	// 3. Validate expiry block height
-	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	currentBlockHeight, err := cachedBlockHeight(ctx, s.btcClient)
 	if err != nil {
-		return fmt.Errorf("failed to get current block height: %w", err)
-	}
-
-	if expiryBlockHeight <= currentBlockHeight {
-		return errors.New("expiry block height must be in the future")
+		return err
 	}
 
-	// Require at least 100 blocks (approx. 1 day) into the future
-	if expiryBlockHeight < currentBlockHeight+100 {
-		return errors.New("expiry block height must be at least 100 blocks in the future")
+	if err := s.durations.validateExpiry(expiryBlockHeight, currentBlockHeight); err != nil {
+		return err
 	}
 
 	// 4. Validate contract size
@@ -330,7 +831,7 @@ func (s *hashPerpService) ValidateContractParameters(
 	return nil
 }
 
-// ExecuteVTXOSweep adds input validation (continued)
+// ExecuteVTXOSweep adds input validation. Operators sweep any VTXO, users their own.
 func (s *hashPerpService) ExecuteVTXOSweep(
 	ctx context.Context, 
 	vtxoID string,
@@ -339,6 +840,12 @@ func (s *hashPerpService) ExecuteVTXOSweep(
 		return nil, fmt.Errorf("invalid VTXO ID: %w", err)
 	}
 	
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		if err := s.authorizeVTXOOwner(ctx, vtxoID); err != nil {
+			return nil, err
+		}
+	}
+	
 	return s.vtxoManager.ExecuteVTXOSweep(ctx, vtxoID)
 }
 
@@ -351,11 +858,22 @@ func (s *hashPerpService) PlaceOrder(
 	strikeRate float64,
 	expiryBlockHeight uint64,
 	size float64,
+	clientOrderID string,
+	selfTradePolicy SelfTradePolicy,
+	timeInForce TimeInForce,
 ) (*Order, error) {
 	if err := ValidateUserID(userID); err != nil {
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 	
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	
+	if err := ValidateIdempotencyKey(clientOrderID); err != nil {
+		return nil, fmt.Errorf("invalid client order ID: %w", err)
+	}
+	
 	if err := ValidateOrderType(orderType); err != nil {
 		return nil, err
 	}
@@ -372,30 +890,27 @@ func (s *hashPerpService) PlaceOrder(
 		return nil, fmt.Errorf("invalid order size: %w", err)
 	}
 	
-	// Validate expiry block height
-	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	if err := ValidateSelfTradePolicy(selfTradePolicy); err != nil {
+		return nil, err
 	}
 	
-	if expiryBlockHeight <= currentBlockHeight {
-		return nil, errors.New("expiry block height must be in the future")
+	if err := ValidateTimeInForce(timeInForce); err != nil {
+		return nil, err
 	}
 	
-	// Minimum and maximum durations
-	minBlocks := uint64(144)   // ~1 day
-	maxBlocks := uint64(52560) // ~1 year
-	
-	if expiryBlockHeight < currentBlockHeight+minBlocks {
-		return nil, fmt.Errorf("expiry must be at least %d blocks in the future", minBlocks)
+	// Validate expiry block height
+	currentBlockHeight, err := cachedBlockHeight(ctx, s.btcClient)
+	if err != nil {
+		return nil, err
 	}
 	
-	if expiryBlockHeight > currentBlockHeight+maxBlocks {
-		return nil, fmt.Errorf("expiry must not exceed %d blocks in the future", maxBlocks)
+	// Orders must be able to become contracts, so they share the contract duration bounds
+	if err := s.durations.validateExpiry(expiryBlockHeight, currentBlockHeight); err != nil {
+		return nil, err
 	}
 	
 	return s.orderBookManager.PlaceOrder(
-		ctx, userID, orderType, contractType, strikeRate, expiryBlockHeight, size)
+		ctx, userID, orderType, contractType, strikeRate, expiryBlockHeight, size, clientOrderID, selfTradePolicy, timeInForce)
 }
 
 // CancelOrder adds input validation
@@ -412,6 +927,10 @@ func (s *hashPerpService) CancelOrder(
 		return fmt.Errorf("invalid user ID: %w", err)
 	}
 	
+	if err := authorizeUser(ctx, userID); err != nil {
+		return err
+	}
+	
 	return s.orderBookManager.CancelOrder(ctx, orderID, userID)
 }
 
@@ -424,7 +943,14 @@ func (s *hashPerpService) GetOrder(
 		return nil, fmt.Errorf("invalid order ID: %w", err)
 	}
 	
-	return s.orderBookManager.GetOrder(ctx, orderID)
+	order, err := s.orderBookManager.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, order.UserID); err != nil {
+		return nil, err
+	}
+	return order, nil
 }
 
 // GetOrdersByUser adds input validation
@@ -437,6 +963,10 @@ func (s *hashPerpService) GetOrdersByUser(
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 	
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	
 	// Validate each order status if provided
 	if len(status) > 0 {
 		for _, s := range status {
@@ -454,18 +984,18 @@ func (s *hashPerpService) GetOrderBook(
 	ctx context.Context,
 	contractType ContractType,
 	expiryBlockHeight uint64,
-) ([]*Order, error) {
+) (*OrderBookSnapshot, error) {
 	if err := ValidateContractType(contractType); err != nil {
 		return nil, err
 	}
 	
-	// Validate expiry block height
-	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	// Validate expiry block height, against the last known height while the node is unreachable
+	reading, err := readBlockHeight(ctx, s.btcClient, s.clock)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current block height: %w", err)
+		return nil, err
 	}
 	
-	if expiryBlockHeight <= currentBlockHeight {
+	if expiryBlockHeight <= reading.BlockHeight {
 		return nil, errors.New("expiry block height must be in the future")
 	}
 	
@@ -475,9 +1005,63 @@ func (s *hashPerpService) GetOrderBook(
 // MatchOrders adds input validation
 func (s *hashPerpService) MatchOrders(ctx context.Context) ([]*Contract, error) {
 	// No inputs to validate for this method
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return nil, err
+	}
 	return s.orderBookManager.MatchOrders(ctx)
 }
 
+// GetMarketView adds input validation
+func (s *hashPerpService) GetMarketView(
+	ctx context.Context,
+	contractType ContractType,
+	expiryBlockHeight uint64,
+) (*MarketView, error) {
+	if err := ValidateContractType(contractType); err != nil {
+		return nil, err
+	}
+	
+	if expiryBlockHeight == 0 {
+		return nil, fmt.Errorf("%w: expiry block height is required", ErrInvalidParameters)
+	}
+	
+	return s.orderBookManager.GetMarketView(ctx, contractType, expiryBlockHeight)
+}
+
+// GetOrderBookDepth adds input validation
+func (s *hashPerpService) GetOrderBookDepth(
+	ctx context.Context,
+	contractType ContractType,
+	expiryBlockHeight uint64,
+) (*OrderBookDepth, error) {
+	if err := ValidateContractType(contractType); err != nil {
+		return nil, err
+	}
+	
+	if expiryBlockHeight == 0 {
+		return nil, fmt.Errorf("%w: expiry block height is required", ErrInvalidParameters)
+	}
+	
+	return s.orderBookManager.GetOrderBookDepth(ctx, contractType, expiryBlockHeight)
+}
+
+// GetQuote adds input validation
+func (s *hashPerpService) GetQuote(
+	ctx context.Context,
+	contractType ContractType,
+	expiryBlockHeight uint64,
+) (*Quote, error) {
+	if err := ValidateContractType(contractType); err != nil {
+		return nil, err
+	}
+	
+	if expiryBlockHeight == 0 {
+		return nil, fmt.Errorf("%w: expiry block height is required", ErrInvalidParameters)
+	}
+	
+	return s.orderBookManager.GetQuote(ctx, contractType, expiryBlockHeight)
+}
+
 // CreateSwapOffer adds input validation
 func (s *hashPerpService) CreateSwapOffer(
 	ctx context.Context,
@@ -490,6 +1074,10 @@ func (s *hashPerpService) CreateSwapOffer(
 		return nil, fmt.Errorf("invalid offeror ID: %w", err)
 	}
 	
+	if err := authorizeUser(ctx, offerorID); err != nil {
+		return nil, err
+	}
+	
 	if err := ValidateUUID(vtxoID); err != nil {
 		return nil, fmt.Errorf("invalid VTXO ID: %w", err)
 	}
@@ -499,7 +1087,7 @@ func (s *hashPerpService) CreateSwapOffer(
 	}
 	
 	// Validate expiry time
-	now := time.Now().UTC()
+	now := s.clock.Now().UTC()
 	minExpiry := now.Add(1 * time.Hour)    // Minimum 1 hour in the future
 	maxExpiry := now.Add(30 * 24 * time.Hour) // Maximum 30 days in the future
 	
@@ -519,6 +1107,7 @@ func (s *hashPerpService) AcceptSwapOffer(
 	ctx context.Context,
 	offerID string,
 	acceptorID string,
+	signatureData []byte,
 ) (*Transaction, error) {
 	if err := ValidateUUID(offerID); err != nil {
 		return nil, fmt.Errorf("invalid offer ID: %w", err)
@@ -528,7 +1117,15 @@ func (s *hashPerpService) AcceptSwapOffer(
 		return nil, fmt.Errorf("invalid acceptor ID: %w", err)
 	}
 	
-	return s.swapOfferManager.AcceptSwapOffer(ctx, offerID, acceptorID)
+	if err := authorizeUser(ctx, acceptorID); err != nil {
+		return nil, err
+	}
+	
+	if err := ValidateSignatureData(signatureData, 64); err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	
+	return s.swapOfferManager.AcceptSwapOffer(ctx, offerID, acceptorID, signatureData)
 }
 
 // CancelSwapOffer adds input validation
@@ -545,6 +1142,10 @@ func (s *hashPerpService) CancelSwapOffer(
 		return fmt.Errorf("invalid offeror ID: %w", err)
 	}
 	
+	if err := authorizeUser(ctx, offerorID); err != nil {
+		return err
+	}
+	
 	return s.swapOfferManager.CancelSwapOffer(ctx, offerID, offerorID)
 }
 
@@ -565,24 +1166,50 @@ func (s *hashPerpService) GetSwapOffersByUser(
 	ctx context.Context,
 	userID string,
 	isOfferor bool,
+	status []SwapOfferStatus,
 ) ([]*SwapOffer, error) {
 	if err := ValidateUserID(userID); err != nil {
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 	
-	return s.swapOfferManager.GetSwapOffersByUser(ctx, userID, isOfferor)
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	
+	// Validate each swap offer status if provided
+	for _, s := range status {
+		if err := ValidateSwapOfferStatus(s); err != nil {
+			return nil, err
+		}
+	}
+	
+	return s.swapOfferManager.GetSwapOffersByUser(ctx, userID, isOfferor, status)
 }
 
 // GetSwapOffersByContract adds input validation
 func (s *hashPerpService) GetSwapOffersByContract(
 	ctx context.Context,
 	contractID string,
-) ([]*SwapOffer, error) {
+	status []SwapOfferStatus,
+	page PageRequest,
+) ([]*SwapOffer, string, error) {
 	if err := ValidateUUID(contractID); err != nil {
-		return nil, fmt.Errorf("invalid contract ID: %w", err)
+		return nil, "", fmt.Errorf("invalid contract ID: %w", err)
+	}
+	
+	// Validate each swap offer status if provided
+	for _, s := range status {
+		if err := ValidateSwapOfferStatus(s); err != nil {
+			return nil, "", err
+		}
+	}
+	
+	page, err := NormalizePageRequest(page)
+	if err != nil {
+		return nil, "", err
 	}
 	
-	return s.swapOfferManager.GetSwapOffersByContract(ctx, contractID)
+	return s.swapOfferManager.GetSwapOffersByContract(ctx, contractID, status, page)
 }
 
 // GetCurrentHashRate adds input validation
@@ -639,9 +1266,9 @@ func (s *hashPerpService) CalculateBTCPerPHPerDay(
 	hashRate float64,
 	blockHeight uint64,
 ) (float64, error) {
-	// Validate hash rate
-	if hashRate <= 0 {
-		return 0, errors.New("hash rate must be positive")
+	// Validate hash rate, zero means derive it from the block's difficulty
+	if hashRate < 0 {
+		return 0, errors.New("hash rate cannot be negative")
 	}
 	
 	// Validate hash rate is within reasonable bounds
@@ -679,6 +1306,9 @@ func (s *hashPerpService) RecordTransaction(
 	blockHeight uint64,
 	relatedEntities map[string]string,
 ) (*Transaction, error) {
+	if err := authorizeOperator(ctx, s.operators); err != nil {
+		return nil, err
+	}
 	if err := ValidateTransactionType(transactionType); err != nil {
 		return nil, err
 	}
@@ -728,7 +1358,8 @@ func (s *hashPerpService) RecordTransaction(
 		ctx, transactionType, contractID, userIDs, txHash, amount, btcPerPHPerDay, blockHeight, relatedEntities)
 }
 
-// GetTransaction adds input validation
+// GetTransaction adds input validation and limits an authenticated caller to their own
+// transactions
 func (s *hashPerpService) GetTransaction(
 	ctx context.Context,
 	transactionID string,
@@ -737,7 +1368,14 @@ func (s *hashPerpService) GetTransaction(
 		return nil, fmt.Errorf("invalid transaction ID: %w", err)
 	}
 	
-	return s.transactionManager.GetTransaction(ctx, transactionID)
+	tx, err := s.transactionManager.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeUser(ctx, tx.UserIDs...); err != nil {
+		return nil, err
+	}
+	return tx, nil
 }
 
 // GetTransactionsByUser adds input validation
@@ -745,33 +1383,157 @@ func (s *hashPerpService) GetTransactionsByUser(
 	ctx context.Context,
 	userID string,
 	transactionTypes []TransactionType,
-) ([]*Transaction, error) {
+	page PageRequest,
+) ([]*Transaction, string, error) {
 	if err := ValidateUserID(userID); err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
+		return nil, "", fmt.Errorf("invalid user ID: %w", err)
+	}
+	
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, "", err
 	}
 	
 	// Validate transaction types if provided
 	if len(transactionTypes) > 0 {
 		for _, txType := range transactionTypes {
 			if err := ValidateTransactionType(txType); err != nil {
-				return nil, err
+				return nil, "", err
 			}
 		}
 	}
 	
-	return s.transactionManager.GetTransactionsByUser(ctx, userID, transactionTypes)
+	page, err := NormalizePageRequest(page)
+	if err != nil {
+		return nil, "", err
+	}
+	
+	return s.transactionManager.GetTransactionsByUser(ctx, userID, transactionTypes, page)
 }
 
-// GetTransactionsByContract adds input validation
+// GetTransactionsByContract adds input validation and limits an authenticated caller to the
+// contracts they hold a position in
 func (s *hashPerpService) GetTransactionsByContract(
 	ctx context.Context,
 	contractID string,
+	transactionTypes []TransactionType,
+	order SortOrder,
 ) ([]*Transaction, error) {
 	if err := ValidateUUID(contractID); err != nil {
 		return nil, fmt.Errorf("invalid contract ID: %w", err)
 	}
 	
-	return s.transactionManager.GetTransactionsByContract(ctx, contractID)
+	if err := s.authorizeContractMember(ctx, contractID); err != nil {
+		return nil, err
+	}
+	
+	for _, txType := range transactionTypes {
+		if err := ValidateTransactionType(txType); err != nil {
+			return nil, err
+		}
+	}
+	
+	if err := ValidateSortOrder(order); err != nil {
+		return nil, err
+	}
+	
+	return s.transactionManager.GetTransactionsByContract(ctx, contractID, transactionTypes, order)
+}
+
+// GetTransactionsByType adds input validation and limits an authenticated caller to their
+// own transactions
+func (s *hashPerpService) GetTransactionsByType(
+	ctx context.Context,
+	transactionType TransactionType,
+) ([]*Transaction, error) {
+	if err := ValidateTransactionType(transactionType); err != nil {
+		return nil, err
+	}
+	
+	txs, err := s.transactionManager.GetTransactionsByType(ctx, transactionType)
+	if err != nil {
+		return nil, err
+	}
+	return callerTransactions(ctx, txs), nil
+}
+
+// GetTransactionsByTimeRange adds input validation and limits an authenticated caller to
+// their own transactions
+func (s *hashPerpService) GetTransactionsByTimeRange(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]*Transaction, error) {
+	if err := ValidateTimeRange(startTime, endTime); err != nil {
+		return nil, err
+	}
+	
+	txs, err := s.transactionManager.GetTransactionsByTimeRange(ctx, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	return callerTransactions(ctx, txs), nil
+}
+
+// GetTransactionsByBlockRange adds input validation and limits an authenticated caller to
+// their own transactions
+func (s *hashPerpService) GetTransactionsByBlockRange(
+	ctx context.Context,
+	fromHeight, toHeight uint64,
+) ([]*Transaction, error) {
+	if err := ValidateBlockRange(fromHeight, toHeight); err != nil {
+		return nil, err
+	}
+	
+	txs, err := s.transactionManager.GetTransactionsByBlockRange(ctx, fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+	return callerTransactions(ctx, txs), nil
+}
+
+// callerTransactions keeps the transactions involving the authenticated caller. Internal
+// callers see every transaction, contexts without any caller see none.
+func callerTransactions(ctx context.Context, txs []*Transaction) []*Transaction {
+	if isInternalCaller(ctx) {
+		return txs
+	}
+	callerID, ok := AuthenticatedUserID(ctx)
+	if !ok {
+		return []*Transaction{}
+	}
+	
+	filtered := make([]*Transaction, 0, len(txs))
+	for _, tx := range txs {
+		for _, userID := range tx.UserIDs {
+			if userID == callerID {
+				filtered = append(filtered, tx)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// callerContracts keeps the contracts the authenticated caller holds a position in. Internal
+// callers see every contract, contexts without any caller see none.
+func callerContracts(ctx context.Context, contracts []*Contract) []*Contract {
+	if isInternalCaller(ctx) {
+		return contracts
+	}
+	callerID, ok := AuthenticatedUserID(ctx)
+	if !ok {
+		return []*Contract{}
+	}
+	
+	filtered := make([]*Contract, 0, len(contracts))
+	for _, contract := range contracts {
+		for _, userID := range contractUserIDs(contract) {
+			if userID == callerID {
+				filtered = append(filtered, contract)
+				break
+			}
+		}
+	}
+	return filtered
 }
 
 // GenerateContractScripts adds input validation
@@ -807,6 +1569,7 @@ func (s *hashPerpService) GenerateSetupTransaction(
 	ctx context.Context,
 	contract *Contract,
 	buyerVTXO, sellerVTXO *VTXO,
+	feeRate uint64,
 ) (string, error) {
 	if contract == nil {
 		return "", errors.New("contract cannot be nil")
@@ -832,7 +1595,7 @@ func (s *hashPerpService) GenerateSetupTransaction(
 		return "", fmt.Errorf("invalid seller VTXO ID: %w", err)
 	}
 	
-	return s.scriptGenerator.GenerateSetupTransaction(ctx, contract, buyerVTXO, sellerVTXO)
+	return s.scriptGenerator.GenerateSetupTransaction(ctx, contract, buyerVTXO, sellerVTXO, feeRate)
 }
 
 // GenerateFinalTransaction adds input validation
@@ -840,6 +1603,7 @@ func (s *hashPerpService) GenerateFinalTransaction(
 	ctx context.Context,
 	contract *Contract,
 	setupTxID string,
+	feeRate uint64,
 ) (string, error) {
 	if contract == nil {
 		return "", errors.New("contract cannot be nil")
@@ -853,7 +1617,7 @@ func (s *hashPerpService) GenerateFinalTransaction(
 		return "", errors.New("setup transaction ID cannot be empty")
 	}
 	
-	return s.scriptGenerator.GenerateFinalTransaction(ctx, contract, setupTxID)
+	return s.scriptGenerator.GenerateFinalTransaction(ctx, contract, setupTxID, feeRate)
 }
 
 // GenerateSettlementTransaction adds input validation
@@ -862,6 +1626,7 @@ func (s *hashPerpService) GenerateSettlementTransaction(
 	contract *Contract,
 	finalTxID string,
 	winnerID string,
+	feeRate uint64,
 ) (string, error) {
 	if contract == nil {
 		return "", errors.New("contract cannot be nil")
@@ -875,6 +1640,11 @@ func (s *hashPerpService) GenerateSettlementTransaction(
 		return "", errors.New("final transaction ID cannot be empty")
 	}
 	
+	// An empty winner ID settles at the money, refunding both parties
+	if winnerID == "" {
+		return s.scriptGenerator.GenerateSettlementTransaction(ctx, contract, finalTxID, winnerID, feeRate)
+	}
+	
 	if err := ValidateUserID(winnerID); err != nil {
 		return "", fmt.Errorf("invalid winner ID: %w", err)
 	}
@@ -884,13 +1654,14 @@ func (s *hashPerpService) GenerateSettlementTransaction(
 		return "", errors.New("winner ID must be either the buyer or seller of the contract")
 	}
 	
-	return s.scriptGenerator.GenerateSettlementTransaction(ctx, contract, finalTxID, winnerID)
+	return s.scriptGenerator.GenerateSettlementTransaction(ctx, contract, finalTxID, winnerID, feeRate)
 }
 
 // GenerateExitPathScripts adds input validation
 func (s *hashPerpService) GenerateExitPathScripts(
 	ctx context.Context,
 	contract *Contract,
+	feeRate uint64,
 ) (map[string]string, error) {
 	if contract == nil {
 		return nil, errors.New("contract cannot be nil")
@@ -900,17 +1671,6 @@ func (s *hashPerpService) GenerateExitPathScripts(
 		return nil, fmt.Errorf("invalid contract ID: %w", err)
 	}
 	
-	return s.scriptGenerator.GenerateExitPathScripts(ctx, contract)
+	return s.scriptGenerator.GenerateExitPathScripts(ctx, contract, feeRate)
 }
 
-// Healthcheck adds input validation
-func (s *hashPerpService) Healthcheck(ctx context.Context) error {
-	// No inputs to validate for this method
-	return nil
-}
-
-// GetCurrentBlockHeight adds input validation
-func (s *hashPerpService) GetCurrentBlockHeight(ctx context.Context) (uint64, error) {
-	// No inputs to validate for this method
-	return s.btcClient.GetCurrentBlockHeight(ctx)
-}
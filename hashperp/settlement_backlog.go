@@ -0,0 +1,92 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SettlementOutcome is the result of settling a single contract in an automatic settlement run
+type SettlementOutcome struct {
+	ContractID  string       `json:"contract_id"`
+	Transaction *Transaction `json:"transaction,omitempty"` // The settlement transaction, if settled
+	Reason      string       `json:"reason,omitempty"`      // Why the contract was deferred or failed
+}
+
+// AutoSettleSummary reports which overdue contracts an automatic settlement run settled,
+// deferred to a later run and failed to settle
+type AutoSettleSummary struct {
+	BlockHeight uint64              `json:"block_height"`
+	Timestamp   time.Time           `json:"timestamp"`
+	Settled     []SettlementOutcome `json:"settled"`
+	Deferred    []SettlementOutcome `json:"deferred"` // Expiry block not final or dispute window open
+	Failed      []SettlementOutcome `json:"failed"`
+}
+
+// GetContractsRequiringSettlement implements ContractManager.GetContractsRequiringSettlement
+func (s *contractService) GetContractsRequiringSettlement(ctx context.Context) ([]*Contract, error) {
+	_, contracts, err := s.findContractsRequiringSettlement(ctx)
+	return contracts, err
+}
+
+// findContractsRequiringSettlement returns the current block height and the active contracts
+// whose expiry block is at or below it
+func (s *contractService) findContractsRequiringSettlement(ctx context.Context) (uint64, []*Contract, error) {
+	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	contracts, err := s.contractRepo.FindByExpiryRange(ctx, 0, currentBlockHeight)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get contracts requiring settlement: %w", err)
+	}
+	return currentBlockHeight, contracts, nil
+}
+
+// AutoSettleExpired implements ContractManager.AutoSettleExpired
+// Each contract is settled independently, a failure is recorded in the summary and the
+// remaining contracts are still settled. Only failing to find the contracts aborts the run.
+func (s *contractService) AutoSettleExpired(ctx context.Context) (*AutoSettleSummary, error) {
+	// 1. Find the overdue contracts
+	currentBlockHeight, contracts, err := s.findContractsRequiringSettlement(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &AutoSettleSummary{
+		BlockHeight: currentBlockHeight,
+		Timestamp:   s.clock.Now().UTC(),
+		Settled:     []SettlementOutcome{},
+		Deferred:    []SettlementOutcome{},
+		Failed:      []SettlementOutcome{},
+	}
+
+	// 2. Settle each one, continuing past failures
+	for _, contract := range contracts {
+		outcome := SettlementOutcome{ContractID: contract.ID}
+
+		tx, err := s.SettleContract(ctx, contract.ID, FEE_PRIORITY_NORMAL)
+		if err != nil {
+			outcome.Reason = err.Error()
+			// Picked up again by a later run once the block is final or the window closes
+			if errors.Is(err, ErrBlockNotFinal) || errors.Is(err, ErrDisputeWindowOpen) {
+				summary.Deferred = append(summary.Deferred, outcome)
+				continue
+			}
+			summary.Failed = append(summary.Failed, outcome)
+			publishEvent(ctx, s.eventPublisher, Event{
+				Type:       EVENT_OPERATION_FAILED,
+				ContractID: contract.ID,
+				Operation:  "auto_settle_expired",
+				Error:      err.Error(),
+			})
+			continue
+		}
+		outcome.Transaction = tx
+		summary.Settled = append(summary.Settled, outcome)
+	}
+
+	return summary, nil
+}
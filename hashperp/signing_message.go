@@ -0,0 +1,146 @@
+package hashperp
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Signing message layout. Every message a user or the operator signs is built here, so the
+// signer and the verifier always serialize the same terms the same way:
+//
+//	[0]     version, SIGNING_MESSAGE_VERSION
+//	[1:]    the message domain ("swap", "exit", ...) followed by its fields in order, each
+//	        as a 4-byte big-endian length and the field's bytes
+//
+// Length prefixes keep a field containing a separator from shifting into the next one, and
+// the domain keeps a signature over one kind of message from being accepted as another.
+// Numbers are written as decimal strings and amounts in satoshis.
+const SIGNING_MESSAGE_VERSION = 0x01
+
+// Signing message domains
+const (
+	signingDomainSwap        = "swap"
+	signingDomainExit        = "exit"
+	signingDomainSplit       = "split"
+	signingDomainJoin        = "join"
+	signingDomainDynamicJoin = "dynamic_join"
+	signingDomainFund        = "fund"
+	signingDomainRegisterKey = "register_key"
+)
+
+// encodeSigningMessage serializes a message domain and its fields in the canonical encoding
+func encodeSigningMessage(domain string, fields ...string) []byte {
+	size := 1 + 4 + len(domain)
+	for _, field := range fields {
+		size += 4 + len(field)
+	}
+
+	message := make([]byte, size)
+	message[0] = SIGNING_MESSAGE_VERSION
+	offset := 1
+	for _, field := range append([]string{domain}, fields...) {
+		binary.BigEndian.PutUint32(message[offset:], uint32(len(field)))
+		offset += 4
+		offset += copy(message[offset:], field)
+	}
+	return message
+}
+
+// buildSwapMessage is the message a new owner signs to take over a VTXO
+func buildSwapMessage(vtxoID, newOwnerID, contractID string) []byte {
+	return encodeSigningMessage(signingDomainSwap, vtxoID, newOwnerID, contractID)
+}
+
+// buildSignedSwapMessage is the message the operator's swap key signs to authorize a swap at
+// the given time, the swap message with the signing time appended
+func buildSignedSwapMessage(vtxoID, newOwnerID, contractID string, signedAt time.Time) []byte {
+	return encodeSigningMessage(signingDomainSwap, vtxoID, newOwnerID, contractID,
+		strconv.FormatInt(signedAt.Unix(), 10))
+}
+
+// buildExitMessage is the message a VTXO owner signs to pre-sign the exit of their VTXO
+func buildExitMessage(vtxoID, ownerID string, expiryBlockHeight uint64) []byte {
+	return encodeSigningMessage(signingDomainExit, vtxoID, ownerID,
+		strconv.FormatUint(expiryBlockHeight, 10))
+}
+
+// buildSplitMessage is the message the owner of a child VTXO signs to accept their part of a split
+func buildSplitMessage(vtxoID, ownerID string, amount float64) []byte {
+	return encodeSigningMessage(signingDomainSplit, vtxoID, ownerID,
+		strconv.FormatInt(BTCToSats(amount), 10))
+}
+
+// buildJoinMessage is the message a user signs to join a side of a contract
+func buildJoinMessage(contractID, userID string, side PositionSide, amount float64) []byte {
+	return encodeSigningMessage(signingDomainJoin, contractID, userID, string(side),
+		strconv.FormatInt(BTCToSats(amount), 10))
+}
+
+// buildDynamicJoinMessage is the message a user signs to take the open side of a contract
+func buildDynamicJoinMessage(contractID, userID string, side PositionSide) []byte {
+	return encodeSigningMessage(signingDomainDynamicJoin, contractID, userID, string(side))
+}
+
+// buildFundMessage is the message a party signs to commit their VTXO to a contract's setup
+// transaction
+func buildFundMessage(contractID, userID, vtxoID string) []byte {
+	return encodeSigningMessage(signingDomainFund, contractID, userID, vtxoID)
+}
+
+// buildRegisterKeyMessage is the message a user signs with a new public key, and with their
+// current key when rotating, to prove they hold the keys
+func buildRegisterKeyMessage(userID string, pubKey []byte) []byte {
+	return encodeSigningMessage(signingDomainRegisterKey, userID, hex.EncodeToString(pubKey))
+}
+
+// verifySignedMessage checks a user's signature over a message built above against the public
+// key registered for them
+func verifySignedMessage(
+	ctx context.Context,
+	btcClient BitcoinClient,
+	userRepo UserRepository,
+	userID string,
+	message []byte,
+	signature []byte,
+) error {
+	// 1. Validate signature data is present
+	if len(signature) == 0 {
+		return ErrInvalidSignature
+	}
+	if userRepo == nil {
+		return fmt.Errorf("%w: no user repository configured to verify signatures", ErrInvalidSignature)
+	}
+
+	// 2. Get the user's public key from repository
+	pubKey, err := userRepo.GetPublicKey(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user public key: %w", err)
+	}
+	if len(pubKey) == 0 {
+		return fmt.Errorf("%w: no public key registered for user %s", ErrInvalidSignature, userID)
+	}
+
+	// 3. Verify the signature
+	return verifySignatureWithKey(ctx, btcClient, pubKey, message, signature)
+}
+
+// verifySignatureWithKey checks a signature over a signing message against the given public key
+func verifySignatureWithKey(ctx context.Context, btcClient BitcoinClient, pubKey []byte, message []byte, signature []byte) error {
+	if len(signature) == 0 {
+		return ErrInvalidSignature
+	}
+
+	isValid, err := btcClient.ValidateSignature(ctx, message, signature, pubKey)
+	if err != nil {
+		return fmt.Errorf("signature validation error: %w", err)
+	}
+	if !isValid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// contractTransitions lists the statuses each contract status may move to.
+// ROLLED_OVER, COMPLETED and CANCELED are terminal.
+var contractTransitions = map[ContractStatus][]ContractStatus{
+	PENDING:                {FUNDING, CONTRACT_CANCELED},
+	FUNDING:                {ACTIVE},
+	ACTIVE:                 {CLOSE_TO_EXPIRY, SETTLEMENT_PENDING, SETTLEMENT_IN_PROGRESS, EXITED, ROLLED_OVER, COMPLETED},
+	CLOSE_TO_EXPIRY:        {SETTLEMENT_PENDING, SETTLEMENT_IN_PROGRESS, EXITED, ROLLED_OVER, COMPLETED},
+	SETTLEMENT_PENDING:     {SETTLEMENT_IN_PROGRESS, SETTLED, EXITED, COMPLETED},
+	SETTLEMENT_IN_PROGRESS: {SETTLEMENT_PENDING, SETTLED, COMPLETED},
+	SETTLED:                {COMPLETED},
+	EXITED:                 {COMPLETED},
+}
+
+// orderTransitions lists the statuses each order status may move to.
+// Every status other than OPEN is terminal.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OPEN: {MATCHED, CANCELED, EXPIRED},
+}
+
+// swapOfferTransitions lists the statuses each swap offer status may move to.
+// Every status other than OPEN is terminal.
+var swapOfferTransitions = map[SwapOfferStatus][]SwapOfferStatus{
+	OFFER_OPEN: {OFFER_ACCEPTED, OFFER_EXPIRED, OFFER_CANCELED, OFFER_REJECTED},
+}
+
+// isLive reports whether a contract's positions are still open. A contract flagged
+// CLOSE_TO_EXPIRY can still be traded, exited, rolled over and settled, only new
+// positions are limited to ACTIVE contracts.
+func isLive(status ContractStatus) bool {
+	return status == ACTIVE || status == CLOSE_TO_EXPIRY
+}
+
+// CanTransition reports whether a contract may move from one status to another.
+// Keeping the same status is always allowed.
+func CanTransition(from, to ContractStatus) bool {
+	if from == to {
+		return true
+	}
+	for _, allowed := range contractTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionOrder reports whether an order may move from one status to another.
+// Keeping the same status is always allowed.
+func CanTransitionOrder(from, to OrderStatus) bool {
+	if from == to {
+		return true
+	}
+	for _, allowed := range orderTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionSwapOffer reports whether a swap offer may move from one status to another.
+// Keeping the same status is always allowed.
+func CanTransitionSwapOffer(from, to SwapOfferStatus) bool {
+	if from == to {
+		return true
+	}
+	for _, allowed := range swapOfferTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionCheckedContractRepository rejects contract updates that make an illegal status change
+type transitionCheckedContractRepository struct {
+	ContractRepository
+}
+
+// NewTransitionCheckedContractRepository wraps a contract repository so that Update
+// fails with ErrInvalidContractStatus instead of persisting an illegal status change
+func NewTransitionCheckedContractRepository(repo ContractRepository) ContractRepository {
+	return &transitionCheckedContractRepository{
+		ContractRepository: repo,
+	}
+}
+
+// Update implements ContractRepository.Update
+func (r *transitionCheckedContractRepository) Update(ctx context.Context, contract *Contract) error {
+	current, err := r.ContractRepository.FindByID(ctx, contract.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get contract: %w", err)
+	}
+	if current != nil && !CanTransition(current.Status, contract.Status) {
+		return fmt.Errorf("%w: cannot move contract %s from %s to %s",
+			ErrInvalidContractStatus, contract.ID, current.Status, contract.Status)
+	}
+
+	return r.ContractRepository.Update(ctx, contract)
+}
+
+// transitionCheckedOrderRepository rejects order updates that make an illegal status change
+type transitionCheckedOrderRepository struct {
+	OrderRepository
+}
+
+// NewTransitionCheckedOrderRepository wraps an order repository so that Update
+// fails with ErrInvalidOrderStatus instead of persisting an illegal status change
+func NewTransitionCheckedOrderRepository(repo OrderRepository) OrderRepository {
+	return &transitionCheckedOrderRepository{
+		OrderRepository: repo,
+	}
+}
+
+// Update implements OrderRepository.Update
+func (r *transitionCheckedOrderRepository) Update(ctx context.Context, order *Order) error {
+	current, err := r.OrderRepository.FindByID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+	if current != nil && !CanTransitionOrder(current.Status, order.Status) {
+		return fmt.Errorf("%w: cannot move order %s from %s to %s",
+			ErrInvalidOrderStatus, order.ID, current.Status, order.Status)
+	}
+
+	return r.OrderRepository.Update(ctx, order)
+}
+
+// transitionCheckedSwapOfferRepository rejects swap offer updates that make an illegal status change
+type transitionCheckedSwapOfferRepository struct {
+	SwapOfferRepository
+}
+
+// NewTransitionCheckedSwapOfferRepository wraps a swap offer repository so that Update
+// fails with ErrInvalidSwapOfferStatus instead of persisting an illegal status change
+func NewTransitionCheckedSwapOfferRepository(repo SwapOfferRepository) SwapOfferRepository {
+	return &transitionCheckedSwapOfferRepository{
+		SwapOfferRepository: repo,
+	}
+}
+
+// Update implements SwapOfferRepository.Update
+func (r *transitionCheckedSwapOfferRepository) Update(ctx context.Context, offer *SwapOffer) error {
+	current, err := r.SwapOfferRepository.FindByID(ctx, offer.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get swap offer: %w", err)
+	}
+	if current != nil && !CanTransitionSwapOffer(SwapOfferStatus(current.Status), SwapOfferStatus(offer.Status)) {
+		return fmt.Errorf("%w: cannot move swap offer %s from %s to %s",
+			ErrInvalidSwapOfferStatus, offer.ID, current.Status, offer.Status)
+	}
+
+	return r.SwapOfferRepository.Update(ctx, offer)
+}
@@ -0,0 +1,65 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// InvalidateOffersForVTXO implements SwapOfferManager.InvalidateOffersForVTXO
+// Offers on a VTXO that is still active are left open.
+func (s *swapOfferService) InvalidateOffersForVTXO(ctx context.Context, vtxoID string) (int, error) {
+	// 1. Only a VTXO that was swapped, swept or rolled over invalidates its offers
+	vtxo, err := s.vtxoRepo.FindByID(ctx, vtxoID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get VTXO: %w", err)
+	}
+	if vtxo == nil {
+		return 0, ErrVTXONotFound
+	}
+	if vtxo.IsActive {
+		return 0, nil
+	}
+
+	// 2. Cancel every offer still open on it
+	offers, err := s.swapOfferRepo.FindOpenOffersByVTXO(ctx, vtxoID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get open swap offers: %w", err)
+	}
+
+	canceled := 0
+	for _, offer := range offers {
+		if offer.Status != string(OFFER_OPEN) {
+			continue
+		}
+		offer.Status = string(OFFER_CANCELED)
+		if err := s.swapOfferRepo.Update(ctx, offer); err != nil {
+			return canceled, fmt.Errorf("failed to cancel swap offer %s: %w", offer.ID, err)
+		}
+		canceled++
+	}
+
+	return canceled, nil
+}
+
+// SetSwapOfferManager configures the swap offer manager whose offers on a VTXO are canceled
+// once the VTXO is swapped, swept or rolled over
+func (s *vtxoService) SetSwapOfferManager(swapOfferManager SwapOfferManager) {
+	s.swapOfferManager = swapOfferManager
+}
+
+// invalidateSwapOffers cancels the open offers on a VTXO that is no longer active. Failing to
+// cancel them is non-critical, accepting a stale offer still fails on the inactive VTXO.
+func (s *vtxoService) invalidateSwapOffers(ctx context.Context, contractID string, vtxoID string) {
+	if s.swapOfferManager == nil {
+		return
+	}
+	if _, err := s.swapOfferManager.InvalidateOffersForVTXO(ctx, vtxoID); err != nil {
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: contractID,
+			VTXOID:     vtxoID,
+			Operation:  "invalidate_swap_offers",
+			Error:      err.Error(),
+		})
+	}
+}
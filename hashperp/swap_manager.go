@@ -6,12 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"time"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/binary"
-	"log"
-	"os"
-	"github.com/btcsuite/btcd/btcec"
 )
 
 // SwapOfferStatus represents the current status of a swap offer
@@ -29,9 +23,13 @@ const (
 type SwapOfferRepository interface {
 	Create(ctx context.Context, offer *SwapOffer) error
 	FindByID(ctx context.Context, id string) (*SwapOffer, error)
-	FindByUser(ctx context.Context, userID string, isOfferor bool) ([]*SwapOffer, error)
-	FindByContract(ctx context.Context, contractID string) ([]*SwapOffer, error)
+	FindByUser(ctx context.Context, userID string, isOfferor bool, status []SwapOfferStatus) ([]*SwapOffer, error)
+	FindByContract(ctx context.Context, contractID string, status []SwapOfferStatus, page PageRequest) ([]*SwapOffer, string, error)
 	FindOpenOffersByVTXO(ctx context.Context, vtxoID string) ([]*SwapOffer, error)
+	CountOpenByOfferor(ctx context.Context, offerorID string) (int, error)
+	CountOffersByStatus(ctx context.Context, status SwapOfferStatus) (int, error)
+	CountByStatus(ctx context.Context) (map[SwapOfferStatus]int, error)
+	ExpireOffers(ctx context.Context, asOf time.Time) (int, error)
 	Update(ctx context.Context, offer *SwapOffer) error
 	Delete(ctx context.Context, id string) error
 }
@@ -43,6 +41,11 @@ type swapOfferService struct {
 	contractRepo    ContractRepository
 	transactionRepo TransactionRepository
 	vtxoManager     VTXOManager
+	keyStore        KeyStore       // Signs position swap authorizations
+	eventPublisher  EventPublisher // Lifecycle events for downstream integrations
+	maxOpenOffers   int            // OPEN offers allowed per user, 0 disables the cap
+	ids             IDGenerator    // IDs of new swap offers and transactions
+	clock           Clock          // Source of timestamps and offer expiry checks
 }
 
 // NewSwapOfferService creates a new swap offer service
@@ -52,6 +55,7 @@ func NewSwapOfferService(
 	contractRepo ContractRepository,
 	transactionRepo TransactionRepository,
 	vtxoManager VTXOManager,
+	keyStore KeyStore,
 ) SwapOfferManager {
 	return &swapOfferService{
 		swapOfferRepo:   swapOfferRepo,
@@ -59,9 +63,19 @@ func NewSwapOfferService(
 		contractRepo:    contractRepo,
 		transactionRepo: transactionRepo,
 		vtxoManager:     vtxoManager,
+		keyStore:        keyStore,
+		eventPublisher:  NewNoopEventPublisher(),
+		maxOpenOffers:   defaultMaxOpenSwapOffersPerUser,
+		ids:             NewUUIDGenerator(),
+		clock:           NewSystemClock(),
 	}
 }
 
+// SetEventPublisher configures where lifecycle events are published
+func (s *swapOfferService) SetEventPublisher(publisher EventPublisher) {
+	s.eventPublisher = publisher
+}
+
 // CreateSwapOffer implements SwapOfferManager.CreateSwapOffer
 func (s *swapOfferService) CreateSwapOffer(
 	ctx context.Context,
@@ -99,7 +113,7 @@ func (s *swapOfferService) CreateSwapOffer(
 	}
 
 	// 5. Validate contract status
-	if contract.Status != ACTIVE {
+	if !isLive(contract.Status) {
 		return nil, ErrInvalidContractStatus
 	}
 
@@ -109,7 +123,7 @@ func (s *swapOfferService) CreateSwapOffer(
 	}
 
 	// 7. Validate expiry time is in the future
-	if expiryTime.Before(time.Now()) {
+	if expiryTime.Before(s.clock.Now()) {
 		return nil, errors.New("expiry time must be in the future")
 	}
 
@@ -119,6 +133,17 @@ func (s *swapOfferService) CreateSwapOffer(
 		return nil, fmt.Errorf("failed to check existing offers: %w", err)
 	}
 
+	// 8a. Cap the offeror's open offers, not counting the ones this offer replaces
+	replacing := 0
+	for _, existingOffer := range existingOffers {
+		if existingOffer.OfferorID == offerorID {
+			replacing++
+		}
+	}
+	if err := s.checkOpenOfferLimit(ctx, offerorID, replacing); err != nil {
+		return nil, err
+	}
+
 	// 9. Cancel any existing offers for this VTXO
 	for _, existingOffer := range existingOffers {
 		existingOffer.Status = string(OFFER_CANCELED)
@@ -129,12 +154,12 @@ func (s *swapOfferService) CreateSwapOffer(
 
 	// 10. Create the new swap offer
 	offer := &SwapOffer{
-		ID:           generateUniqueID(),
+		ID:           s.ids.NewID(),
 		OfferorID:    offerorID,
 		VTXOID:       vtxoID,
 		ContractID:   vtxo.ContractID,
 		OfferedRate:  offeredRate,
-		CreationTime: time.Now().UTC(),
+		CreationTime: s.clock.Now().UTC(),
 		ExpiryTime:   expiryTime,
 		Status:       string(OFFER_OPEN),
 	}
@@ -152,6 +177,7 @@ func (s *swapOfferService) AcceptSwapOffer(
 	ctx context.Context,
 	offerID string,
 	acceptorID string,
+	signatureData []byte,
 ) (*Transaction, error) {
 	// 1. Get the offer
 	offer, err := s.swapOfferRepo.FindByID(ctx, offerID)
@@ -164,14 +190,14 @@ func (s *swapOfferService) AcceptSwapOffer(
 
 	// 2. Validate offer status
 	if offer.Status != string(OFFER_OPEN) {
-		return nil, errors.New("swap offer is not open for acceptance")
+		return nil, fmt.Errorf("%w: swap offer is not open for acceptance", ErrSwapNotAvailable)
 	}
 
 	// 3. Validate offer hasn't expired
-	if offer.ExpiryTime.Before(time.Now()) {
+	if offer.ExpiryTime.Before(s.clock.Now()) {
 		offer.Status = string(OFFER_EXPIRED)
 		_ = s.swapOfferRepo.Update(ctx, offer)
-		return nil, errors.New("swap offer has expired")
+		return nil, fmt.Errorf("%w: swap offer has expired", ErrSwapNotAvailable)
 	}
 
 	// 4. Get the VTXO
@@ -200,7 +226,7 @@ func (s *swapOfferService) AcceptSwapOffer(
 	}
 
 	// 7. Validate contract status
-	if contract.Status != ACTIVE {
+	if !isLive(contract.Status) {
 		offer.Status = string(OFFER_CANCELED)
 		_ = s.swapOfferRepo.Update(ctx, offer)
 		return nil, ErrInvalidContractStatus
@@ -217,31 +243,32 @@ func (s *swapOfferService) AcceptSwapOffer(
 		return nil, errors.New("VTXO is not associated with this contract's buyer or seller")
 	}
 
-	// 9. Generate dummy signature data for the new VTXO
-	// In a real implementation, the acceptor would provide their signature
-	dummySignatureData := []byte("dummy_signature_data")
-
-	// 10. Execute the VTXO swap
-	newVTXO, tx, err := s.vtxoManager.SwapVTXO(ctx, vtxo.ID, acceptorID, dummySignatureData)
+	// 9. Execute the VTXO swap, which verifies the acceptor's signature over the swap
+	newVTXO, tx, err := s.vtxoManager.SwapVTXO(ctx, vtxo.ID, acceptorID, signatureData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute VTXO swap: %w", err)
 	}
 
-	// 11. Update the offer status to ACCEPTED
+	// 10. Update the offer status to ACCEPTED
 	offer.Status = string(OFFER_ACCEPTED)
 	offer.AcceptorID = acceptorID
 	if err := s.swapOfferRepo.Update(ctx, offer); err != nil {
 		return nil, fmt.Errorf("failed to update swap offer status: %w", err)
 	}
 
-	// 12. Add offer details to the transaction's related entities
+	// 11. Add offer details to the transaction's related entities
 	tx.RelatedEntities["swap_offer_id"] = offer.ID
 	tx.RelatedEntities["offered_rate"] = fmt.Sprintf("%f", offer.OfferedRate)
 
 	if err := s.transactionRepo.Update(ctx, tx); err != nil {
 		// If updating the transaction fails, we'll still proceed with the swap
-		// but log the error
-		fmt.Printf("failed to update transaction with offer details: %v\n", err)
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:          EVENT_OPERATION_FAILED,
+			ContractID:    offer.ContractID,
+			TransactionID: tx.ID,
+			Operation:     "update_swap_transaction",
+			Error:         err.Error(),
+		})
 	}
 
 	return tx, nil
@@ -301,8 +328,9 @@ func (s *swapOfferService) GetSwapOffersByUser(
 	ctx context.Context,
 	userID string,
 	isOfferor bool,
+	status []SwapOfferStatus,
 ) ([]*SwapOffer, error) {
-	offers, err := s.swapOfferRepo.FindByUser(ctx, userID, isOfferor)
+	offers, err := s.swapOfferRepo.FindByUser(ctx, userID, isOfferor, status)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get swap offers by user: %w", err)
 	}
@@ -313,23 +341,25 @@ func (s *swapOfferService) GetSwapOffersByUser(
 func (s *swapOfferService) GetSwapOffersByContract(
 	ctx context.Context,
 	contractID string,
-) ([]*SwapOffer, error) {
+	status []SwapOfferStatus,
+	page PageRequest,
+) ([]*SwapOffer, string, error) {
 	// 1. Validate contract exists
 	contract, err := s.contractRepo.FindByID(ctx, contractID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get contract: %w", err)
+		return nil, "", fmt.Errorf("failed to get contract: %w", err)
 	}
 	if contract == nil {
-		return nil, ErrContractNotFound
+		return nil, "", ErrContractNotFound
 	}
 
-	// 2. Get offers for the contract
-	offers, err := s.swapOfferRepo.FindByContract(ctx, contractID)
+	// 2. Get a page of offers for the contract
+	offers, nextCursor, err := s.swapOfferRepo.FindByContract(ctx, contractID, status, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get swap offers by contract: %w", err)
+		return nil, "", fmt.Errorf("failed to get swap offers by contract: %w", err)
 	}
 
-	return offers, nil
+	return offers, nextCursor, nil
 }
 
 // RejectSwapOffer implements SwapOfferManager.RejectSwapOffer
@@ -354,7 +384,7 @@ func (s *swapOfferService) RejectSwapOffer(
 	}
 
 	// 3. Validate offer hasn't expired
-	if offer.ExpiryTime.Before(time.Now()) {
+	if offer.ExpiryTime.Before(s.clock.Now()) {
 		offer.Status = string(OFFER_EXPIRED)
 		_ = s.swapOfferRepo.Update(ctx, offer)
 		return errors.New("swap offer has already expired")
@@ -373,60 +403,11 @@ func (s *swapOfferService) RejectSwapOffer(
 // CleanupExpiredOffers implements SwapOfferManager.CleanupExpiredOffers
 // This is typically run as a scheduled job to update the status of expired offers
 func (s *swapOfferService) CleanupExpiredOffers(ctx context.Context) (int, error) {
-	// In a real implementation, this would use a database query to efficiently
-	// update all expired offers in a single operation. For this example,
-	// we'll simulate fetching and updating individual offers.
-	
-	// This is a simplified approach - in production we'd want to:
-	// 1. Use a database transaction
-	// 2. Batch updates
-	// 3. Handle pagination for large datasets
-	// 4. Use database-specific features like UPDATE WHERE for efficiency
-	
-	// 1. Find all open offers
-	// This is a simplified implementation - in reality, we'd want to directly
-	// query for open offers that have expired
-	allContracts, err := s.contractRepo.FindAll(ctx)
+	expiredCount, err := s.swapOfferRepo.ExpireOffers(ctx, s.clock.Now().UTC())
 	if err != nil {
-		return 0, fmt.Errorf("failed to get contracts: %w", err)
+		return 0, fmt.Errorf("failed to expire swap offers: %w", err)
 	}
-	
-	expiredCount := 0
-	now := time.Now().UTC()
-	
-	// 2. Process each contract
-	for _, contract := range allContracts {
-		// Only process active contracts
-		if contract.Status != ACTIVE {
-			continue
-		}
-		
-		// Get all open offers for this contract
-		offers, err := s.swapOfferRepo.FindByContract(ctx, contract.ID)
-		if err != nil {
-			fmt.Printf("failed to get offers for contract %s: %v\n", contract.ID, err)
-			continue
-		}
-		
-		// Check each offer
-		for _, offer := range offers {
-			// Skip non-open offers
-			if offer.Status != string(OFFER_OPEN) {
-				continue
-			}
-			
-			// Check if expired
-			if offer.ExpiryTime.Before(now) {
-				offer.Status = string(OFFER_EXPIRED)
-				if err := s.swapOfferRepo.Update(ctx, offer); err != nil {
-					fmt.Printf("failed to update expired offer %s: %v\n", offer.ID, err)
-					continue
-				}
-				expiredCount++
-			}
-		}
-	}
-	
+
 	return expiredCount, nil
 }
 
@@ -444,8 +425,8 @@ func (s *swapOfferService) GetOpenOffersCount(
 		return 0, ErrContractNotFound
 	}
 	
-	// 2. Get all offers for this contract
-	offers, err := s.swapOfferRepo.FindByContract(ctx, contractID)
+	// 2. Get the open offers for this contract
+	offers, _, err := s.swapOfferRepo.FindByContract(ctx, contractID, []SwapOfferStatus{OFFER_OPEN}, PageRequest{})
 	if err != nil {
 		return 0, fmt.Errorf("failed to get swap offers by contract: %w", err)
 	}
@@ -500,7 +481,7 @@ func (s *swapOfferService) CreateDirectSwapOffer(
 	}
 	
 	// 5. Validate contract status
-	if contract.Status != ACTIVE {
+	if !isLive(contract.Status) {
 		return nil, ErrInvalidContractStatus
 	}
 	
@@ -510,7 +491,7 @@ func (s *swapOfferService) CreateDirectSwapOffer(
 	}
 	
 	// 7. Validate expiry time is in the future
-	if expiryTime.Before(time.Now()) {
+	if expiryTime.Before(s.clock.Now()) {
 		return nil, errors.New("expiry time must be in the future")
 	}
 	
@@ -519,15 +500,20 @@ func (s *swapOfferService) CreateDirectSwapOffer(
 		return nil, errors.New("cannot create direct swap offer to yourself")
 	}
 	
+	// 8a. Cap the offeror's open offers
+	if err := s.checkOpenOfferLimit(ctx, offerorID, 0); err != nil {
+		return nil, err
+	}
+	
 	// 9. Create the new direct swap offer
 	offer := &SwapOffer{
-		ID:           generateUniqueID(),
+		ID:           s.ids.NewID(),
 		OfferorID:    offerorID,
 		VTXOID:       vtxoID,
 		ContractID:   vtxo.ContractID,
 		TargetUserID: targetUserID, // This is what makes it a direct offer
 		OfferedRate:  offeredRate,
-		CreationTime: time.Now().UTC(),
+		CreationTime: s.clock.Now().UTC(),
 		ExpiryTime:   expiryTime,
 		Status:       string(OFFER_OPEN),
 	}
@@ -556,7 +542,7 @@ func (s *swapOfferService) GetSwapOfferMarketData(
 	}
 	
 	// 2. Get all offers for this contract
-	offers, err := s.swapOfferRepo.FindByContract(ctx, contractID)
+	offers, _, err := s.swapOfferRepo.FindByContract(ctx, contractID, nil, PageRequest{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get swap offers by contract: %w", err)
 	}
@@ -564,7 +550,7 @@ func (s *swapOfferService) GetSwapOfferMarketData(
 	// 3. Initialize market data
 	marketData := &SwapOfferMarketData{
 		ContractID:     contractID,
-		Timestamp:      time.Now().UTC(),
+		Timestamp:      s.clock.Now().UTC(),
 		OpenOffersCount: 0,
 		HighestRate:    0,
 		LowestRate:     0,
@@ -589,16 +575,10 @@ func (s *swapOfferService) GetSwapOfferMarketData(
 		marketData.OpenOffersCount++
 		openRates = append(openRates, offer.OfferedRate)
 		
-		// Get the VTXO to determine if this is a buyer or seller offer
-		vtxo, err := s.vtxoRepo.FindByID(ctx, offer.VTXOID)
-		if err != nil || vtxo == nil {
-			continue
-		}
-		
 		// Determine position type
-		if contract.BuyerVTXO == vtxo.ID {
+		if side, ok := offerSide(contract, offer); ok && side == SIDE_BUYER {
 			buyerCount++
-		} else if contract.SellerVTXO == vtxo.ID {
+		} else if ok && side == SIDE_SELLER {
 			sellerCount++
 		}
 	}
@@ -652,7 +632,7 @@ func (s *swapOfferService) GetSwapOfferMarketData(
 	
 	// 6. Calculate 24-hour volume
 	// Get accepted offers in the last 24 hours
-	oneDayAgo := time.Now().UTC().Add(-24 * time.Hour)
+	oneDayAgo := s.clock.Now().UTC().Add(-24 * time.Hour)
 	for _, offer := range offers {
 		if offer.Status == string(OFFER_ACCEPTED) && offer.CreationTime.After(oneDayAgo) {
 			// Get the VTXO to determine the amount
@@ -688,7 +668,7 @@ func (s *swapOfferService) RequestContractPositionSwap(
 	}
 	
 	// 2. Validate contract status
-	if contract.Status != ACTIVE {
+	if !isLive(contract.Status) {
 		return nil, ErrInvalidContractStatus
 	}
 	
@@ -718,12 +698,12 @@ func (s *swapOfferService) RequestContractPositionSwap(
 	}
 	
 	// 5. Validate expiry time
-	if expiryTime.Before(time.Now()) {
+	if expiryTime.Before(s.clock.Now()) {
 		return nil, errors.New("expiry time must be in the future")
 	}
 	
 	// 6. Check for existing open position swap requests
-	offers, err := s.swapOfferRepo.FindByContract(ctx, contractID)
+	offers, _, err := s.swapOfferRepo.FindByContract(ctx, contractID, []SwapOfferStatus{OFFER_OPEN}, PageRequest{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get swap offers by contract: %w", err)
 	}
@@ -737,15 +717,20 @@ func (s *swapOfferService) RequestContractPositionSwap(
 		}
 	}
 	
+	// 6a. Cap the requester's open offers
+	if err := s.checkOpenOfferLimit(ctx, requesterID, 0); err != nil {
+		return nil, err
+	}
+	
 	// 7. Create the position swap offer
 	offer := &SwapOffer{
-		ID:               generateUniqueID(),
+		ID:               s.ids.NewID(),
 		OfferorID:        requesterID,
 		VTXOID:           requesterVTXO,
 		ContractID:       contractID,
 		TargetUserID:     counterpartyID,
 		OfferedRate:      priceDifferential,
-		CreationTime:     time.Now().UTC(),
+		CreationTime:     s.clock.Now().UTC(),
 		ExpiryTime:       expiryTime,
 		Status:           string(OFFER_OPEN),
 		SwapType:         "position_swap",
@@ -811,7 +796,7 @@ func (s *swapOfferService) AcceptPositionSwap(
 	}
 	
 	// 4. Validate offer hasn't expired
-	if offer.ExpiryTime.Before(time.Now()) {
+	if offer.ExpiryTime.Before(s.clock.Now()) {
 		offer.Status = string(OFFER_EXPIRED)
 		_ = s.swapOfferRepo.Update(ctx, offer)
 		return nil, errors.New("swap offer has expired")
@@ -832,7 +817,7 @@ func (s *swapOfferService) AcceptPositionSwap(
 	}
 	
 	// 7. Validate contract status
-	if contract.Status != ACTIVE {
+	if !isLive(contract.Status) {
 		offer.Status = string(OFFER_CANCELED)
 		_ = s.swapOfferRepo.Update(ctx, offer)
 		return nil, ErrInvalidContractStatus
@@ -874,8 +859,14 @@ func (s *swapOfferService) AcceptPositionSwap(
 	
 	// 11. Create signatures for the swaps based on the contract terms
 	// These signatures are cryptographically secure and would verify the swap terms
-	requesterSignatureData := generateSignatureForSwap(requesterVTXO.ID, acceptorID, contract.ID)
-	counterpartySignatureData := generateSignatureForSwap(counterpartyVTXO.ID, offer.OfferorID, contract.ID)
+	requesterSignatureData, err := s.signSwap(ctx, requesterVTXO.ID, acceptorID, contract.ID)
+	if err != nil {
+		return nil, err
+	}
+	counterpartySignatureData, err := s.signSwap(ctx, counterpartyVTXO.ID, offer.OfferorID, contract.ID)
+	if err != nil {
+		return nil, err
+	}
 	
 	// 12. Perform the position swap - swap the VTXOs between the parties
 	// First, swap the requester's VTXO to the acceptor
@@ -898,17 +889,25 @@ func (s *swapOfferService) AcceptPositionSwap(
 	)
 	if err != nil {
 		// If the second swap fails, try to revert the first swap
-		revertSignatureData := generateSignatureForSwap(newRequesterVTXO.ID, offer.OfferorID, contract.ID)
-		_, _, revertErr := s.vtxoManager.SwapVTXO(
-			ctx, 
-			newRequesterVTXO.ID, 
-			offer.OfferorID, 
-			revertSignatureData,
-		)
+		revertSignatureData, revertErr := s.signSwap(ctx, newRequesterVTXO.ID, offer.OfferorID, contract.ID)
+		if revertErr == nil {
+			_, _, revertErr = s.vtxoManager.SwapVTXO(
+				ctx, 
+				newRequesterVTXO.ID, 
+				offer.OfferorID, 
+				revertSignatureData,
+			)
+		}
 		
 		if revertErr != nil {
-			// Now we're in an inconsistent state - log this error for monitoring systems
-			fmt.Printf("failed to revert first swap: %v\n", revertErr)
+			// Now we're in an inconsistent state - report this error for monitoring systems
+			publishEvent(ctx, s.eventPublisher, Event{
+				Type:       EVENT_OPERATION_FAILED,
+				ContractID: contract.ID,
+				VTXOID:     newRequesterVTXO.ID,
+				Operation:  "revert_position_swap",
+				Error:      revertErr.Error(),
+			})
 		}
 		
 		return nil, fmt.Errorf("failed to swap counterparty VTXO: %w", err)
@@ -931,9 +930,9 @@ func (s *swapOfferService) AcceptPositionSwap(
 	
 	// 15. Create a transaction record for the position swap
 	tx := &Transaction{
-		ID:         generateUniqueID(),
+		ID:         s.ids.NewID(),
 		Type:       CONTRACT_ROLLOVER, // Using this type as it's the closest to a position swap
-		Timestamp:  time.Now().UTC(),
+		Timestamp:  s.clock.Now().UTC(),
 		ContractID: contract.ID,
 		UserIDs:    []string{offer.OfferorID, acceptorID},
 		Amount:     requesterVTXO.Amount + counterpartyVTXO.Amount, // Total value of the swapped positions
@@ -953,127 +952,14 @@ func (s *swapOfferService) AcceptPositionSwap(
 	}
 	
 	if err := s.transactionRepo.Create(ctx, tx); err != nil {
-		// Log the error but continue - the swap has already happened
-		fmt.Printf("failed to record position swap transaction: %v\n", err)
+		// Report the error but continue - the swap has already happened
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: contract.ID,
+			Operation:  "record_position_swap_transaction",
+			Error:      err.Error(),
+		})
 	}
 	
 	return tx, nil
 }
-
-// Helper function to generate a secure signature for a swap
-func generateSignatureForSwap(vtxoID string, newOwnerID string, contractID string) []byte {
-	// Create a unique signature combining the VTXO ID, new owner ID, contract ID, and timestamp
-	// This would use cryptographic signing algorithms in a real production system
-	// For this implementation, we generate a HMAC-SHA256 signature
-	signatureData := fmt.Sprintf("%s-%s-%s-%d", vtxoID, newOwnerID, contractID, time.Now().UnixNano())
-	hash := sha256.Sum256([]byte(signatureData))
-	return hash[:]
-}
-// Append to existing hashperp/swap_manager.go
-
-// generateSignatureForSwap creates a secure signature for a swap using ECDSA
-func generateSignatureForSwap(vtxoID string, newOwnerID string, contractID string) []byte {
-	// 1. Create a deterministic message by combining the input parameters
-	message := fmt.Sprintf("swap:%s:%s:%s:%d", vtxoID, newOwnerID, contractID, time.Now().UnixNano())
-	
-	// 2. Hash the message to get a fixed-length value suitable for signing
-	messageHash := sha256.Sum256([]byte(message))
-	
-	// 3. Get the private key for signing from the secure key store
-	privateKey := getPrivateKeyFromSecureStore()
-	
-	// 4. Sign the message hash with the private key
-	signature, err := privateKey.Sign(messageHash[:])
-	if err != nil {
-		// Log the error and generate a fallback signature
-		log.Printf("Error signing message: %v", err)
-		return generateFallbackSignature(messageHash[:])
-	}
-	
-	// 5. Serialize the signature to compact format (65 bytes)
-	// Bitcoin uses a compact format: [RecoveryID+27 || R || S]
-	serializedSig := signature.Serialize()
-	compactSig := make([]byte, 65)
-	compactSig[0] = byte(signature.RecoveryID + 27 + 4) // Add 4 for compressed pubkey
-	copy(compactSig[1:33], serializedSig[:32])  // R component
-	copy(compactSig[33:65], serializedSig[32:]) // S component
-	
-	// 6. Create the complete signature package with metadata
-	// Format: [Version(1) || Timestamp(8) || MessageHash(32) || Signature(65)]
-	result := make([]byte, 106)
-	result[0] = 0x01 // Version byte for future compatibility
-	
-	// Add timestamp (8 bytes)
-	timestamp := make([]byte, 8)
-	binary.BigEndian.PutUint64(timestamp, uint64(time.Now().Unix()))
-	copy(result[1:9], timestamp)
-	
-	// Add message hash (32 bytes)
-	copy(result[9:41], messageHash[:])
-	
-	// Add the signature (65 bytes)
-	copy(result[41:106], compactSig)
-	
-	return result
-}
-
-// getPrivateKeyFromSecureStore retrieves the private key from a secure storage
-func getPrivateKeyFromSecureStore() *btcec.PrivateKey {
-	// In production, this would retrieve a key from a secure key management system
-	// For now, we'll use a hardcoded test key (NEVER DO THIS IN PRODUCTION)
-	privKeyHex := os.Getenv("HASHPERP_SIGNING_KEY")
-	if privKeyHex == "" {
-		// Log this security issue and use a generated key
-		log.Printf("WARNING: No signing key found in environment, generating temporary key")
-		privateKey, _ := btcec.NewPrivateKey(btcec.S256())
-		return privateKey
-	}
-	
-	// Decode the private key from hex
-	privKeyBytes, err := hex.DecodeString(privKeyHex)
-	if err != nil {
-		log.Printf("Error decoding private key: %v", err)
-		privateKey, _ := btcec.NewPrivateKey(btcec.S256())
-		return privateKey
-	}
-	
-	// Parse the private key
-	privateKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), privKeyBytes)
-	return privateKey
-}
-
-// generateFallbackSignature creates a deterministic signature when normal signing fails
-func generateFallbackSignature(messageHash []byte) []byte {
-	// Create a deterministic but secure signature using HMAC
-	// This is a fallback mechanism for system continuity
-	hmacKey := []byte(os.Getenv("HASHPERP_HMAC_KEY"))
-	if len(hmacKey) == 0 {
-		// Use a derived key if no HMAC key is configured
-		h := sha256.Sum256([]byte("HASHPERP_FALLBACK_KEY"))
-		hmacKey = h[:]
-	}
-	
-	h := hmac.New(sha256.New, hmacKey)
-	h.Write(messageHash)
-	hmacResult := h.Sum(nil)
-	
-	// Create a signature structure similar to ECDSA signatures
-	result := make([]byte, 106)
-	result[0] = 0x02 // Different version to indicate fallback
-	
-	// Add timestamp (8 bytes)
-	timestamp := make([]byte, 8)
-	binary.BigEndian.PutUint64(timestamp, uint64(time.Now().Unix()))
-	copy(result[1:9], timestamp)
-	
-	// Add message hash (32 bytes)
-	copy(result[9:41], messageHash)
-	
-	// Add the fallback "signature" (65 bytes)
-	result[41] = 0x1B // Recovery ID byte
-	copy(result[42:74], hmacResult)
-	h2 := sha256.Sum256(hmacResult)
-	copy(result[74:106], h2[:])
-	
-	return result
-}
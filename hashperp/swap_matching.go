@@ -0,0 +1,153 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// offerSide reports which side of the contract an offer's VTXO holds
+func offerSide(contract *Contract, offer *SwapOffer) (PositionSide, bool) {
+	switch offer.VTXOID {
+	case "":
+		return "", false
+	case contract.BuyerVTXO:
+		return SIDE_BUYER, true
+	case contract.SellerVTXO:
+		return SIDE_SELLER, true
+	}
+	return "", false
+}
+
+// rankOpenOffers returns the open, unexpired offers the acceptor may take, best rate first.
+// The acceptor pays the offered rate, so the lowest rate is the most favorable. Direct offers
+// only rank for the user they target, an empty acceptor ranks public offers only.
+func rankOpenOffers(offers []*SwapOffer, acceptorID string, now time.Time) []*SwapOffer {
+	ranked := make([]*SwapOffer, 0, len(offers))
+	for _, offer := range offers {
+		if offer.Status != string(OFFER_OPEN) || offer.ExpiryTime.Before(now) {
+			continue
+		}
+		if offer.TargetUserID != "" && offer.TargetUserID != acceptorID {
+			continue
+		}
+		if offer.OfferorID == acceptorID {
+			continue
+		}
+		ranked = append(ranked, offer)
+	}
+
+	// Ties go to the oldest offer
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].OfferedRate != ranked[j].OfferedRate {
+			return ranked[i].OfferedRate < ranked[j].OfferedRate
+		}
+		return ranked[i].CreationTime.Before(ranked[j].CreationTime)
+	})
+
+	return ranked
+}
+
+// GetBestSwapOffer implements SwapOfferManager.GetBestSwapOffer
+func (s *swapOfferService) GetBestSwapOffer(
+	ctx context.Context,
+	contractID string,
+	side PositionSide,
+) (*SwapOffer, error) {
+	if side != SIDE_BUYER && side != SIDE_SELLER {
+		return nil, fmt.Errorf("%w: invalid side %q", ErrInvalidParameters, side)
+	}
+
+	// 1. Validate contract exists
+	contract, err := s.contractRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, ErrContractNotFound
+	}
+
+	// 2. Get the open offers for this contract and keep the requested side
+	offers, _, err := s.swapOfferRepo.FindByContract(ctx, contractID, []SwapOfferStatus{OFFER_OPEN}, PageRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swap offers by contract: %w", err)
+	}
+
+	sideOffers := make([]*SwapOffer, 0, len(offers))
+	for _, offer := range offers {
+		if position, ok := offerSide(contract, offer); ok && position == side {
+			sideOffers = append(sideOffers, offer)
+		}
+	}
+
+	// 3. Rank the public offers
+	ranked := rankOpenOffers(sideOffers, "", s.clock.Now())
+	if len(ranked) == 0 {
+		return nil, fmt.Errorf("%w: no open offers for the %s side of contract %s",
+			ErrSwapNotAvailable, side, contractID)
+	}
+
+	return ranked[0], nil
+}
+
+// AutoAcceptBestOffer implements SwapOfferManager.AutoAcceptBestOffer
+func (s *swapOfferService) AutoAcceptBestOffer(
+	ctx context.Context,
+	vtxoID string,
+	acceptorID string,
+	maxRate float64,
+	signatureData []byte,
+) (*SwapOffer, *Transaction, error) {
+	// 1. Validate parameters
+	if acceptorID == "" {
+		return nil, nil, fmt.Errorf("%w: acceptor ID is required", ErrInvalidParameters)
+	}
+	if maxRate <= 0 {
+		return nil, nil, fmt.Errorf("%w: max rate must be positive", ErrInvalidParameters)
+	}
+
+	// 2. Rank the open offers on the VTXO within the acceptor's limit
+	offers, err := s.swapOfferRepo.FindOpenOffersByVTXO(ctx, vtxoID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get open swap offers: %w", err)
+	}
+
+	var candidates []*SwapOffer
+	for _, offer := range rankOpenOffers(offers, acceptorID, s.clock.Now()) {
+		if offer.OfferedRate > maxRate {
+			break
+		}
+		candidates = append(candidates, offer)
+	}
+	if len(candidates) == 0 {
+		return nil, nil, fmt.Errorf("%w: no open offers for VTXO %s at or below rate %v",
+			ErrSwapNotAvailable, vtxoID, maxRate)
+	}
+
+	// 3. Accept the best candidate. Another acceptor can take an offer between ranking and
+	// acceptance, in which case it is no longer open and the next best is tried. Once the
+	// VTXO itself has moved no other offer on it can be accepted.
+	for _, offer := range candidates {
+		tx, err := s.AcceptSwapOffer(ctx, offer.ID, acceptorID, signatureData)
+		if err == nil {
+			offer.Status = string(OFFER_ACCEPTED)
+			offer.AcceptorID = acceptorID
+			return offer, tx, nil
+		}
+
+		switch {
+		case errors.Is(err, ErrSwapNotAvailable):
+			continue
+		case errors.Is(err, ErrVTXONotActive):
+			return nil, nil, fmt.Errorf("%w: VTXO %s was swapped before an offer could be accepted",
+				ErrSwapNotAvailable, vtxoID)
+		default:
+			return nil, nil, err
+		}
+	}
+
+	return nil, nil, fmt.Errorf("%w: every matching offer for VTXO %s was taken",
+		ErrSwapNotAvailable, vtxoID)
+}
@@ -0,0 +1,140 @@
+package hashperp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// ErrInvalidSwapSignature is returned when a swap signature envelope is malformed or does
+// not authorize the swap it is checked against
+var ErrInvalidSwapSignature = errors.New("invalid swap signature")
+
+// Swap signature envelope layout. A swap signature authorizes a VTXO moving to a new owner
+// and is 106 bytes:
+//
+//	[0]       version, SWAP_SIGNATURE_VERSION
+//	[1:9]     signing time, big-endian Unix seconds
+//	[9:41]    SHA-256 of the signed swap message, see buildSignedSwapMessage
+//	[41:106]  compact secp256k1 signature of the hash, [RecoveryID+27+4 || R || S]
+const (
+	SWAP_SIGNATURE_VERSION = 0x02 // 0x01 hashed a colon-separated swap message
+	swapSignatureLength    = 106
+)
+
+// DefaultSwapSignatureMaxAge is how long after signing a swap signature is accepted when no
+// other max age is configured
+const DefaultSwapSignatureMaxAge = 10 * time.Minute
+
+// SwapSignature is a parsed swap signature envelope
+type SwapSignature struct {
+	Version     byte
+	SignedAt    time.Time
+	MessageHash [32]byte
+	Signature   [65]byte
+}
+
+// swapMessageHash hashes the terms of a swap signed at the given time
+func swapMessageHash(vtxoID, newOwnerID, contractID string, signedAt time.Time) [32]byte {
+	return sha256.Sum256(buildSignedSwapMessage(vtxoID, newOwnerID, contractID, signedAt))
+}
+
+// ParseSwapSignature splits a swap signature envelope into its fields
+func ParseSwapSignature(envelope []byte) (*SwapSignature, error) {
+	if len(envelope) != swapSignatureLength {
+		return nil, fmt.Errorf("%w: envelope is %d bytes, expected %d",
+			ErrInvalidSwapSignature, len(envelope), swapSignatureLength)
+	}
+	if envelope[0] != SWAP_SIGNATURE_VERSION {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSwapSignature, envelope[0])
+	}
+
+	sig := &SwapSignature{
+		Version:  envelope[0],
+		SignedAt: time.Unix(int64(binary.BigEndian.Uint64(envelope[1:9])), 0).UTC(),
+	}
+	copy(sig.MessageHash[:], envelope[9:41])
+	copy(sig.Signature[:], envelope[41:106])
+
+	return sig, nil
+}
+
+// VerifySwapSignature checks that an envelope authorizes moving a VTXO to a new owner, was
+// signed by the holder of pubKey, and was signed no more than maxAge before now. An envelope
+// dated after now is rejected, so a signature cannot be made to outlive its max age.
+func VerifySwapSignature(
+	envelope []byte,
+	vtxoID, newOwnerID, contractID string,
+	pubKey *btcec.PublicKey,
+	now time.Time,
+	maxAge time.Duration,
+) error {
+	if maxAge <= 0 {
+		return fmt.Errorf("%w: swap signature max age must be positive", ErrInvalidParameters)
+	}
+
+	// 1. Parse the envelope
+	sig, err := ParseSwapSignature(envelope)
+	if err != nil {
+		return err
+	}
+
+	// 1a. The signature must be recent and not dated in the future
+	if sig.SignedAt.After(now) {
+		return fmt.Errorf("%w: signed at %s, after %s", ErrInvalidSwapSignature,
+			sig.SignedAt.Format(time.RFC3339), now.UTC().Format(time.RFC3339))
+	}
+	if now.Sub(sig.SignedAt) > maxAge {
+		return fmt.Errorf("%w: signed at %s, more than %s ago", ErrInvalidSwapSignature,
+			sig.SignedAt.Format(time.RFC3339), maxAge)
+	}
+
+	// 2. The hash must cover these swap terms
+	expectedHash := swapMessageHash(vtxoID, newOwnerID, contractID, sig.SignedAt)
+	if sig.MessageHash != expectedHash {
+		return fmt.Errorf("%w: signature does not cover this swap", ErrInvalidSwapSignature)
+	}
+
+	// 3. The signature must recover to the expected key
+	recovered, _, err := btcec.RecoverCompact(btcec.S256(), sig.Signature[:], sig.MessageHash[:])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSwapSignature, err)
+	}
+	if !bytes.Equal(recovered.SerializeCompressed(), pubKey.SerializeCompressed()) {
+		return fmt.Errorf("%w: signed by a different key", ErrInvalidSwapSignature)
+	}
+
+	return nil
+}
+
+// signSwap signs the authorization of a VTXO moving to a new owner with the swap key and
+// returns the swap signature envelope
+func (s *swapOfferService) signSwap(ctx context.Context, vtxoID string, newOwnerID string, contractID string) ([]byte, error) {
+	// 1. Hash the swap terms to get a fixed-length value suitable for signing
+	signedAt := s.clock.Now().UTC()
+	messageHash := swapMessageHash(vtxoID, newOwnerID, contractID, signedAt)
+
+	// 2. Sign the hash with the swap key, there is no unsigned fallback
+	if s.keyStore == nil {
+		return nil, fmt.Errorf("%w: no key store configured", ErrSigningKeyUnavailable)
+	}
+	compactSig, err := s.keyStore.Sign(ctx, SWAP_SIGNING_KEY_ID, messageHash[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign swap: %w", err)
+	}
+
+	// 3. Package the signature with its metadata
+	envelope := make([]byte, swapSignatureLength)
+	envelope[0] = SWAP_SIGNATURE_VERSION
+	binary.BigEndian.PutUint64(envelope[1:9], uint64(signedAt.Unix()))
+	copy(envelope[9:41], messageHash[:])
+	copy(envelope[41:106], compactSig)
+
+	return envelope, nil
+}
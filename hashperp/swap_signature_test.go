@@ -0,0 +1,115 @@
+package hashperp
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+const (
+	testSwapKeyHex  = "0101010101010101010101010101010101010101010101010101010101010101"
+	testOtherKeyHex = "0202020202020202020202020202020202020202020202020202020202020202"
+)
+
+// testPublicKey returns the public key of a hex-encoded private key
+func testPublicKey(t *testing.T, privKeyHex string) *btcec.PublicKey {
+	t.Helper()
+	privKeyBytes, err := hex.DecodeString(privKeyHex)
+	if err != nil {
+		t.Fatalf("failed to decode key: %v", err)
+	}
+	_, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), privKeyBytes)
+	return pubKey
+}
+
+func TestSwapSignatureRoundTrip(t *testing.T) {
+	t.Setenv("HASHPERP_SIGNING_KEY_SWAP", testSwapKeyHex)
+	signedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := &swapOfferService{keyStore: NewEnvKeyStore(), clock: NewFixedClock(signedAt)}
+
+	envelope, err := s.signSwap(context.Background(), "vtxo-1", "new-owner", "contract-1")
+	if err != nil {
+		t.Fatalf("signSwap() error = %v", err)
+	}
+	if len(envelope) != swapSignatureLength {
+		t.Fatalf("envelope is %d bytes, want %d", len(envelope), swapSignatureLength)
+	}
+
+	sig, err := ParseSwapSignature(envelope)
+	if err != nil {
+		t.Fatalf("ParseSwapSignature() error = %v", err)
+	}
+	if sig.Version != SWAP_SIGNATURE_VERSION || !sig.SignedAt.Equal(signedAt) {
+		t.Errorf("parsed version, signed at = %d, %v, want %d, %v",
+			sig.Version, sig.SignedAt, SWAP_SIGNATURE_VERSION, signedAt)
+	}
+
+	swapKey := testPublicKey(t, testSwapKeyHex)
+	maxAge := DefaultSwapSignatureMaxAge
+	tampered := append([]byte{}, envelope...)
+	tampered[len(tampered)-1] ^= 0xff
+	wrongVersion := append([]byte{}, envelope...)
+	wrongVersion[0] = 0x01
+
+	tests := []struct {
+		name       string
+		envelope   []byte
+		vtxoID     string
+		newOwnerID string
+		contractID string
+		pubKey     *btcec.PublicKey
+		now        time.Time
+		wantErr    bool
+	}{
+		{"signed terms", envelope, "vtxo-1", "new-owner", "contract-1", swapKey, signedAt, false},
+		{"just within the max age", envelope, "vtxo-1", "new-owner", "contract-1", swapKey, signedAt.Add(maxAge), false},
+		{"older than the max age", envelope, "vtxo-1", "new-owner", "contract-1", swapKey, signedAt.Add(maxAge + time.Second), true},
+		{"dated in the future", envelope, "vtxo-1", "new-owner", "contract-1", swapKey, signedAt.Add(-time.Second), true},
+		{"different VTXO", envelope, "vtxo-2", "new-owner", "contract-1", swapKey, signedAt, true},
+		{"different new owner", envelope, "vtxo-1", "other-owner", "contract-1", swapKey, signedAt, true},
+		{"different contract", envelope, "vtxo-1", "new-owner", "contract-2", swapKey, signedAt, true},
+		{"different key", envelope, "vtxo-1", "new-owner", "contract-1", testPublicKey(t, testOtherKeyHex), signedAt, true},
+		{"tampered signature", tampered, "vtxo-1", "new-owner", "contract-1", swapKey, signedAt, true},
+		{"unsupported version", wrongVersion, "vtxo-1", "new-owner", "contract-1", swapKey, signedAt, true},
+		{"truncated envelope", envelope[:swapSignatureLength-1], "vtxo-1", "new-owner", "contract-1", swapKey, signedAt, true},
+		{"empty envelope", nil, "vtxo-1", "new-owner", "contract-1", swapKey, signedAt, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySwapSignature(tt.envelope, tt.vtxoID, tt.newOwnerID, tt.contractID, tt.pubKey, tt.now, maxAge)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifySwapSignature() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSwapSignature) {
+				t.Errorf("VerifySwapSignature() error = %v, want ErrInvalidSwapSignature", err)
+			}
+		})
+	}
+}
+
+func TestSignSwapWithoutKey(t *testing.T) {
+	t.Setenv("HASHPERP_SIGNING_KEY", "")
+	t.Setenv("HASHPERP_SIGNING_KEY_SWAP", "")
+
+	tests := []struct {
+		name     string
+		keyStore KeyStore
+	}{
+		{"no key store", nil},
+		{"key not set", NewEnvKeyStore()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &swapOfferService{keyStore: tt.keyStore, clock: NewFixedClock(time.Unix(0, 0))}
+			if _, err := s.signSwap(context.Background(), "vtxo-1", "new-owner", "contract-1"); !errors.Is(err, ErrSigningKeyUnavailable) {
+				t.Errorf("signSwap() error = %v, want ErrSigningKeyUnavailable", err)
+			}
+		})
+	}
+}
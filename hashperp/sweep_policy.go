@@ -0,0 +1,78 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// SweepPolicy sets when the VTXOs of a contract may be swept. By default a VTXO can be swept
+// from the contract's sweep window before expiry, or at any time once the contract is
+// SETTLEMENT_PENDING. Operators trading liveness for safety can open the window earlier for
+// some contract types, or hold sweeps back until the VTXO has a pre-signed exit.
+type SweepPolicy struct {
+	EarlySweepBlocks     map[ContractType]uint64 // Blocks the sweep window opens early, per contract type
+	RequirePreSignedExit bool                    // Only sweep VTXOs with a pre-signed exit on record
+}
+
+// DefaultSweepPolicy returns the sweep policy used when none is configured
+func DefaultSweepPolicy() SweepPolicy {
+	return SweepPolicy{EarlySweepBlocks: map[ContractType]uint64{}}
+}
+
+// Validate checks that the early sweep windows are for known contract types and bounded
+func (p SweepPolicy) Validate() error {
+	for contractType, blocks := range p.EarlySweepBlocks {
+		if err := ValidateContractType(contractType); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidParameters, err)
+		}
+		if blocks > maxExitTimeoutBlocks {
+			return fmt.Errorf("%w: early sweep window of %s contracts must be at most %d blocks",
+				ErrInvalidParameters, contractType, maxExitTimeoutBlocks)
+		}
+	}
+	return nil
+}
+
+// SetSweepPolicy configures when contract VTXOs may be swept
+func (s *vtxoService) SetSweepPolicy(policy SweepPolicy) {
+	s.sweepPolicy = policy
+}
+
+// canSweep validates that a contract's VTXOs may be swept at the current block height. Only
+// live and SETTLEMENT_PENDING contracts can be swept, live ones from their sweep window,
+// opened early by the policy for the contract's type.
+func (p SweepPolicy) canSweep(contract *Contract, currentBlockHeight uint64) error {
+	if !isLive(contract.Status) && contract.Status != SETTLEMENT_PENDING {
+		return ErrInvalidContractStatus
+	}
+	if contract.Status == SETTLEMENT_PENDING {
+		return nil
+	}
+
+	window := contract.SweepWindowBlocks + p.EarlySweepBlocks[contract.ContractType]
+	if currentBlockHeight+window < contract.ExpiryBlockHeight {
+		return fmt.Errorf("%w: the sweep window opens at block %d",
+			ErrSweepNotAllowed, contract.ExpiryBlockHeight-window)
+	}
+
+	return nil
+}
+
+// checkSweepAllowed validates that a VTXO may be swept under the sweep policy
+func (s *vtxoService) checkSweepAllowed(ctx context.Context, contract *Contract, vtxo *VTXO, currentBlockHeight uint64) error {
+	if err := s.sweepPolicy.canSweep(contract, currentBlockHeight); err != nil {
+		return err
+	}
+
+	if s.sweepPolicy.RequirePreSignedExit {
+		exits, err := s.preSignedExitRepo.FindByVTXO(ctx, vtxo.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get pre-signed exits: %w", err)
+		}
+		if len(exits) == 0 {
+			return fmt.Errorf("%w: VTXO %s has no pre-signed exit", ErrSweepNotAllowed, vtxo.ID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+)
+
+// TimeInForce decides how long an order stays in the book when it does not match on placement
+type TimeInForce string
+
+const (
+	TIF_GTC TimeInForce = "GTC" // Good till canceled, the order rests in the book
+	TIF_IOC TimeInForce = "IOC" // Immediate or cancel, match what is possible on placement and cancel the rest
+	TIF_FOK TimeInForce = "FOK" // Fill or kill, match the full size on placement or cancel without matching
+)
+
+// ValidateTimeInForce validates the time in force of an order, empty means GTC
+func ValidateTimeInForce(tif TimeInForce) error {
+	switch tif {
+	case "", TIF_GTC, TIF_IOC, TIF_FOK:
+		return nil
+	}
+	return fmt.Errorf("%w: time in force must be %q, %q or %q", ErrInvalidParameters,
+		TIF_GTC, TIF_IOC, TIF_FOK)
+}
+
+// restsInBook reports whether an order left unmatched on placement stays in the book
+func restsInBook(order *Order) bool {
+	return order.TimeInForce == "" || order.TimeInForce == TIF_GTC
+}
+
+// fillsCompletely reports whether matching two orders fills every fill-or-kill order among
+// them. A match fills the smaller order in full, so a FOK order cannot match a smaller one.
+func fillsCompletely(a, b *Order) bool {
+	if a.TimeInForce == TIF_FOK && b.Size < a.Size {
+		return false
+	}
+	if b.TimeInForce == TIF_FOK && a.Size < b.Size {
+		return false
+	}
+	return true
+}
+
+// cancelUnfilledOrder cancels an IOC or FOK order that is still open after its immediate
+// match attempt, so it never rests in the book. The order is reloaded first, a concurrent
+// placement may have matched it in the meantime.
+func (s *orderBookService) cancelUnfilledOrder(ctx context.Context, order *Order) (*Order, error) {
+	current, err := s.orderRepo.FindByID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if current == nil || current.Status != OPEN {
+		return current, nil
+	}
+
+	current.Status = CANCELED
+	if err := s.orderRepo.Update(ctx, current); err != nil {
+		return nil, fmt.Errorf("failed to cancel unfilled %s order: %w", current.TimeInForce, err)
+	}
+	s.recordOrderBookChange(ctx, current, ORDER_BOOK_CANCEL)
+
+	return current, nil
+}
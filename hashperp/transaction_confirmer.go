@@ -0,0 +1,136 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// defaultTransactionConfirmations is how many confirmations a broadcast transaction needs
+// before it is reported as confirmed
+const defaultTransactionConfirmations = 6
+
+// checkTransactionStatus reads the confirmations of a broadcast transaction from the node
+func checkTransactionStatus(
+	ctx context.Context,
+	btcClient BitcoinClient,
+	tx *Transaction,
+	requiredConfirmations uint64,
+) (*TransactionStatus, error) {
+	if tx.TxHash == "" {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotBroadcast, tx.ID)
+	}
+
+	status := &TransactionStatus{
+		TransactionID:         tx.ID,
+		TxHash:                tx.TxHash,
+		Status:                CONFIRMATION_PENDING,
+		RequiredConfirmations: requiredConfirmations,
+	}
+
+	// A failed transaction stays failed, there is nothing left to read from the node
+	if tx.Status == TX_STATUS_FAILED {
+		status.Status = CONFIRMATION_FAILED
+		return status, nil
+	}
+
+	confirmations, err := btcClient.GetTransactionConfirmations(ctx, tx.TxHash)
+	if err != nil {
+		if errors.Is(err, ErrTransactionConflicted) {
+			status.Status = CONFIRMATION_FAILED
+			return status, nil
+		}
+		return nil, fmt.Errorf("failed to get transaction confirmations: %w", err)
+	}
+
+	status.Confirmations = confirmations
+	if confirmations >= requiredConfirmations {
+		status.Status = CONFIRMATION_CONFIRMED
+	}
+
+	return status, nil
+}
+
+// TransactionConfirmer follows broadcast transactions until their fate on chain is known
+type TransactionConfirmer interface {
+	// ConfirmPendingTransactions marks transactions CONFIRMED once they reach the required
+	// confirmations, or FAILED if they conflict with the chain, and returns how many it updated
+	ConfirmPendingTransactions(ctx context.Context) (int, error)
+}
+
+// transactionConfirmer implements the TransactionConfirmer interface
+type transactionConfirmer struct {
+	transactionRepo       TransactionRepository
+	btcClient             BitcoinClient
+	requiredConfirmations uint64
+	eventPublisher        EventPublisher
+}
+
+// NewTransactionConfirmer creates a confirmer that marks transactions CONFIRMED at requiredConfirmations
+func NewTransactionConfirmer(
+	transactionRepo TransactionRepository,
+	btcClient BitcoinClient,
+	requiredConfirmations uint64,
+) TransactionConfirmer {
+	return &transactionConfirmer{
+		transactionRepo:       transactionRepo,
+		btcClient:             btcClient,
+		requiredConfirmations: requiredConfirmations,
+		eventPublisher:        NewNoopEventPublisher(),
+	}
+}
+
+// SetEventPublisher sets the publisher that receives failures to confirm a transaction
+func (c *transactionConfirmer) SetEventPublisher(publisher EventPublisher) {
+	c.eventPublisher = publisher
+}
+
+// ConfirmPendingTransactions implements TransactionConfirmer.ConfirmPendingTransactions
+func (c *transactionConfirmer) ConfirmPendingTransactions(ctx context.Context) (int, error) {
+	// 1. Find broadcast transactions whose fate is not yet known
+	pending, err := c.transactionRepo.FindAwaitingConfirmation(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find transactions awaiting confirmation: %w", err)
+	}
+
+	// 2. Check each one independently so a failure does not block the rest
+	updated := 0
+	for _, tx := range pending {
+		status, err := checkTransactionStatus(ctx, c.btcClient, tx, c.requiredConfirmations)
+		if err != nil {
+			publishEvent(ctx, c.eventPublisher, Event{
+				Type:          EVENT_OPERATION_FAILED,
+				ContractID:    tx.ContractID,
+				UserIDs:       tx.UserIDs,
+				TransactionID: tx.ID,
+				Operation:     "check_transaction_confirmations",
+				Error:         err.Error(),
+			})
+			continue
+		}
+
+		switch status.Status {
+		case CONFIRMATION_CONFIRMED:
+			tx.Status = TX_STATUS_CONFIRMED
+		case CONFIRMATION_FAILED:
+			tx.Status = TX_STATUS_FAILED
+		default:
+			continue
+		}
+
+		if err := c.transactionRepo.Update(ctx, tx); err != nil {
+			publishEvent(ctx, c.eventPublisher, Event{
+				Type:          EVENT_OPERATION_FAILED,
+				ContractID:    tx.ContractID,
+				UserIDs:       tx.UserIDs,
+				TransactionID: tx.ID,
+				Operation:     "update_transaction_status",
+				Error:         err.Error(),
+			})
+			continue
+		}
+		updated++
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,168 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Bounds on the range queries over transactions
+const (
+	maxTransactionBlockRange = blockIntervalWindow // One difficulty epoch
+	maxTransactionTimeRange  = 90 * 24 * time.Hour
+)
+
+// transactionService implements the TransactionManager interface
+type transactionService struct {
+	transactionRepo TransactionRepository
+	clock           Clock // Source of timestamps
+}
+
+// NewTransactionManager creates a new transaction manager
+func NewTransactionManager(transactionRepo TransactionRepository) TransactionManager {
+	return &transactionService{
+		transactionRepo: transactionRepo,
+		clock:           NewSystemClock(),
+	}
+}
+
+// RecordTransaction implements TransactionManager.RecordTransaction
+func (s *transactionService) RecordTransaction(
+	ctx context.Context,
+	transactionType TransactionType,
+	contractID string,
+	userIDs []string,
+	txHash string,
+	amount float64,
+	btcPerPHPerDay float64,
+	blockHeight uint64,
+	relatedEntities map[string]string,
+) (*Transaction, error) {
+	// 1. Return the existing record if this on-chain transaction was already recorded
+	if txHash != "" {
+		existing, err := s.transactionRepo.FindByTxHash(ctx, txHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up transaction hash: %w", err)
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+
+	// 2. Record the transaction
+	tx := &Transaction{
+		ID:              generateUniqueID(),
+		Type:            transactionType,
+		Timestamp:       s.clock.Now().UTC(),
+		ContractID:      contractID,
+		UserIDs:         userIDs,
+		TxHash:          txHash,
+		Amount:          amount,
+		BTCPerPHPerDay:  btcPerPHPerDay,
+		BlockHeight:     blockHeight,
+		Status:          TX_STATUS_COMPLETED,
+		RelatedEntities: relatedEntities,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		// A concurrent retry may have won the unique transaction hash constraint
+		if txHash != "" {
+			existing, findErr := s.transactionRepo.FindByTxHash(ctx, txHash)
+			if findErr == nil && existing != nil {
+				return existing, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to record transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+// GetTransaction implements TransactionManager.GetTransaction
+func (s *transactionService) GetTransaction(ctx context.Context, transactionID string) (*Transaction, error) {
+	tx, err := s.transactionRepo.FindByID(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx == nil {
+		return nil, ErrTransactionNotFound
+	}
+	return tx, nil
+}
+
+// GetTransactionsByUser implements TransactionManager.GetTransactionsByUser
+func (s *transactionService) GetTransactionsByUser(
+	ctx context.Context,
+	userID string,
+	transactionTypes []TransactionType,
+	page PageRequest,
+) ([]*Transaction, string, error) {
+	txs, nextCursor, err := s.transactionRepo.FindByUser(ctx, userID, transactionTypes, page)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get transactions by user: %w", err)
+	}
+	return txs, nextCursor, nil
+}
+
+// GetTransactionsByContract implements TransactionManager.GetTransactionsByContract
+func (s *transactionService) GetTransactionsByContract(
+	ctx context.Context,
+	contractID string,
+	transactionTypes []TransactionType,
+	order SortOrder,
+) ([]*Transaction, error) {
+	if err := ValidateSortOrder(order); err != nil {
+		return nil, err
+	}
+
+	txs, err := s.transactionRepo.FindByContract(ctx, contractID, transactionTypes, order)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions by contract: %w", err)
+	}
+	return txs, nil
+}
+
+// GetTransactionsByType implements TransactionManager.GetTransactionsByType
+func (s *transactionService) GetTransactionsByType(ctx context.Context, transactionType TransactionType) ([]*Transaction, error) {
+	if err := ValidateTransactionType(transactionType); err != nil {
+		return nil, err
+	}
+
+	txs, err := s.transactionRepo.FindByType(ctx, transactionType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions by type: %w", err)
+	}
+	return txs, nil
+}
+
+// GetTransactionsByTimeRange implements TransactionManager.GetTransactionsByTimeRange
+func (s *transactionService) GetTransactionsByTimeRange(ctx context.Context, startTime, endTime time.Time) ([]*Transaction, error) {
+	if err := ValidateTimeRange(startTime, endTime); err != nil {
+		return nil, err
+	}
+	if endTime.Sub(startTime) > maxTransactionTimeRange {
+		return nil, fmt.Errorf("%w: range exceeds the maximum of %v", ErrInvalidTimeRange, maxTransactionTimeRange)
+	}
+
+	txs, err := s.transactionRepo.FindByTimeRange(ctx, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions by time range: %w", err)
+	}
+	return txs, nil
+}
+
+// GetTransactionsByBlockRange implements TransactionManager.GetTransactionsByBlockRange
+func (s *transactionService) GetTransactionsByBlockRange(ctx context.Context, fromHeight, toHeight uint64) ([]*Transaction, error) {
+	if err := ValidateBlockRange(fromHeight, toHeight); err != nil {
+		return nil, err
+	}
+	if toHeight-fromHeight >= maxTransactionBlockRange {
+		return nil, fmt.Errorf("%w: range exceeds the maximum of %d blocks", ErrInvalidBlockRange, maxTransactionBlockRange)
+	}
+
+	txs, err := s.transactionRepo.FindByBlockRange(ctx, fromHeight, toHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions by block range: %w", err)
+	}
+	return txs, nil
+}
@@ -0,0 +1,104 @@
+package hashperp
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// maxSettlementFeeRate caps the share of a winning side's profit charged at settlement
+const maxSettlementFeeRate = 0.1
+
+// Fee types recorded on FEE_COLLECTION transactions
+const (
+	feeTypeExit       = "exit"
+	feeTypeSettlement = "settlement"
+)
+
+// TreasuryConfig sets the account protocol fees are credited to. Without a treasury account
+// exit fees stay with the exiting party's counterparty and no settlement fee is charged.
+type TreasuryConfig struct {
+	UserID            string  // Account credited with collected fees, empty disables fee collection
+	SettlementFeeRate float64 // Fraction of a winning side's profit charged at settlement
+}
+
+// Validate checks that the settlement fee rate is bounded and only set with a treasury account
+func (c TreasuryConfig) Validate() error {
+	if c.SettlementFeeRate < 0 || c.SettlementFeeRate > maxSettlementFeeRate {
+		return fmt.Errorf("%w: settlement fee rate must be between 0 and %.2f",
+			ErrInvalidParameters, maxSettlementFeeRate)
+	}
+	if c.SettlementFeeRate > 0 && c.UserID == "" {
+		return fmt.Errorf("%w: a settlement fee needs a treasury account", ErrInvalidParameters)
+	}
+	return nil
+}
+
+// settlementFeeRate returns the rate charged on settlement profits, 0 without a treasury account
+func (c TreasuryConfig) settlementFeeRate() float64 {
+	if c.UserID == "" {
+		return 0
+	}
+	return c.SettlementFeeRate
+}
+
+// SetTreasuryConfig configures the account exit and settlement fees are credited to
+func (s *contractService) SetTreasuryConfig(treasury TreasuryConfig) {
+	s.treasury = treasury
+}
+
+// settlementFeeSats is the fee charged on a side's profit, the part of its payout above the
+// collateral it posted, rounded down to the satoshi
+func settlementFeeSats(payoutSats, collateralSats int64, rate float64) int64 {
+	profitSats := payoutSats - collateralSats
+	if profitSats <= 0 || rate <= 0 {
+		return 0
+	}
+	return int64(math.Floor(float64(profitSats) * rate))
+}
+
+// collectFee credits a fee to the treasury account and records it as a FEE_COLLECTION
+// transaction referencing the transaction the fee was charged on. Failures are published
+// rather than returned, the operation that charged the fee has already taken effect.
+func (s *contractService) collectFee(ctx context.Context, contract *Contract, feeType string, feeSats int64, sourceTxID string) {
+	if s.treasury.UserID == "" || feeSats <= 0 {
+		return
+	}
+
+	if err := releaseCollateral(ctx, s.userRepo, s.treasury.UserID, SatsToBTC(feeSats)); err != nil {
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: contract.ID,
+			UserIDs:    []string{s.treasury.UserID},
+			Operation:  "credit_" + feeType + "_fee",
+			Error:      err.Error(),
+		})
+		return
+	}
+
+	tx := &Transaction{
+		ID:          s.ids.NewID(),
+		Type:        FEE_COLLECTION,
+		Timestamp:   s.clock.Now().UTC(),
+		ContractID:  contract.ID,
+		UserIDs:     []string{s.treasury.UserID},
+		Amount:      SatsToBTC(feeSats),
+		AmountSats:  feeSats,
+		BlockHeight: recordedBlockHeight(ctx, s.btcClient, s.clock),
+		RelatedEntities: map[string]string{
+			"fee_type":           feeType,
+			"source_transaction": sourceTxID,
+		},
+		Status: TX_STATUS_COMPLETED,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: contract.ID,
+			UserIDs:    []string{s.treasury.UserID},
+			Operation:  "record_fee_collection",
+			Error:      err.Error(),
+		})
+	}
+}
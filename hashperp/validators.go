@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"regexp"
 	"time"
+
+	"github.com/btcsuite/btcd/btcec"
 )
 
 // Common validation errors
@@ -17,8 +19,14 @@ var (
 	ErrInvalidRate      = errors.New("rate must be positive")
 	ErrMissingSignature = errors.New("signature data is required")
 	ErrInvalidTimeRange = errors.New("invalid time range")
+	ErrInvalidBlockRange = errors.New("invalid block range")
+	ErrInvalidIdempotencyKey = errors.New("idempotency key must be at most 64 characters")
+	ErrInvalidPublicKey = errors.New("public key must be a compressed or uncompressed secp256k1 key")
 )
 
+// maxIdempotencyKeyLength matches the width of the idempotency key columns
+const maxIdempotencyKeyLength = 64
+
 // Basic regex for UUID v4 validation
 var uuidRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
 
@@ -48,6 +56,15 @@ func ValidateUserID(userID string) error {
 	return nil
 }
 
+// ValidateIdempotencyKey validates an optional client-supplied idempotency key
+func ValidateIdempotencyKey(key string) error {
+	if len(key) > maxIdempotencyKeyLength {
+		return ErrInvalidIdempotencyKey
+	}
+	
+	return nil
+}
+
 // ValidateAmount validates that an amount is positive and within bounds
 func ValidateAmount(amount float64, min, max float64) error {
 	if amount <= 0 {
@@ -95,6 +112,20 @@ func ValidateSignatureData(data []byte, minLength int) error {
 	return nil
 }
 
+// ValidatePublicKey validates that a public key is a well-formed secp256k1 point in
+// compressed (33 byte) or uncompressed (65 byte) encoding
+func ValidatePublicKey(pubKey []byte) error {
+	if len(pubKey) != btcec.PubKeyBytesLenCompressed && len(pubKey) != btcec.PubKeyBytesLenUncompressed {
+		return fmt.Errorf("%w: got %d bytes", ErrInvalidPublicKey, len(pubKey))
+	}
+
+	if _, err := btcec.ParsePubKey(pubKey, btcec.S256()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+
+	return nil
+}
+
 // ValidateTimeRange validates a time range
 func ValidateTimeRange(startTime, endTime time.Time) error {
 	if startTime.IsZero() || endTime.IsZero() {
@@ -108,6 +139,15 @@ func ValidateTimeRange(startTime, endTime time.Time) error {
 	return nil
 }
 
+// ValidateBlockRange validates an inclusive block height range
+func ValidateBlockRange(fromHeight, toHeight uint64) error {
+	if toHeight < fromHeight {
+		return fmt.Errorf("%w: to height is below from height", ErrInvalidBlockRange)
+	}
+	
+	return nil
+}
+
 // ValidateContractType validates that a contract type is valid
 func ValidateContractType(contractType ContractType) error {
 	if contractType != CALL && contractType != PUT {
@@ -130,6 +170,7 @@ func ValidateOrderType(orderType OrderType) error {
 func ValidateContractStatus(status ContractStatus) error {
 	validStatuses := map[ContractStatus]bool{
 		PENDING:              true,
+		FUNDING:              true,
 		ACTIVE:               true,
 		SETTLED:              true,
 		EXITED:               true,
@@ -138,6 +179,7 @@ func ValidateContractStatus(status ContractStatus) error {
 		SETTLEMENT_IN_PROGRESS: true,
 		COMPLETED:            true,
 		CLOSE_TO_EXPIRY:      true,
+		CONTRACT_CANCELED:    true,
 	}
 	
 	if !validStatuses[status] {
@@ -184,11 +226,21 @@ func ValidateSwapOfferStatus(status SwapOfferStatus) error {
 func ValidateTransactionType(txType TransactionType) error {
 	validTypes := map[TransactionType]bool{
 		CONTRACT_CREATION:   true,
+		CONTRACT_FUNDING:    true,
+		CONTRACT_CANCELLATION: true,
+		FEE_COLLECTION:      true,
 		CONTRACT_SETTLEMENT: true,
 		VTXO_SWAP:           true,
+		VTXO_SPLIT:          true,
 		VTXO_ROLLOVER:       true,
 		CONTRACT_ROLLOVER:   true,
 		EXIT_PATH_EXECUTION: true,
+		LIQUIDATION:         true,
+		SETTLEMENT_PROPOSAL: true,
+		VTXO_SWEEP:          true,
+		POOL_JOIN:           true,
+		DYNAMIC_JOIN:        true,
+		EXPIRY_NOTICE:       true,
 	}
 	
 	if !validTypes[txType] {
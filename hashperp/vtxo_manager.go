@@ -30,6 +30,12 @@ type vtxoService struct {
 	btcClient        BitcoinClient
 	userRepo         UserRepository
 	preSignedExitRepo PreSignedExitRepository
+	eventPublisher   EventPublisher // Lifecycle events for downstream integrations
+	metrics          MetricsRecorder // Operational metrics
+	swapOfferManager SwapOfferManager // Open offers on a VTXO are canceled once it moves, nil skips this
+	sweepPolicy      SweepPolicy // When contract VTXOs may be swept
+	ids              IDGenerator // IDs of new VTXOs and transactions
+	clock            Clock       // Source of timestamps
 }
 
 // NewVTXOService creates a new VTXO service
@@ -50,9 +56,24 @@ func NewVTXOService(
 		btcClient:        btcClient,
 		userRepo:         userRepo,
 		preSignedExitRepo: preSignedExitRepo,
+		eventPublisher:   NewNoopEventPublisher(),
+		metrics:          NewNoopMetricsRecorder(),
+		sweepPolicy:      DefaultSweepPolicy(),
+		ids:              NewUUIDGenerator(),
+		clock:            NewSystemClock(),
 	}
 }
 
+// SetEventPublisher configures where lifecycle events are published
+func (s *vtxoService) SetEventPublisher(publisher EventPublisher) {
+	s.eventPublisher = publisher
+}
+
+// SetMetricsRecorder configures where operational metrics are recorded
+func (s *vtxoService) SetMetricsRecorder(recorder MetricsRecorder) {
+	s.metrics = recorder
+}
+
 // CreateVTXO implements VTXOManager.CreateVTXO
 func (s *vtxoService) CreateVTXO(
 	ctx context.Context,
@@ -76,20 +97,26 @@ func (s *vtxoService) CreateVTXO(
 		return nil, errors.New("amount must be positive")
 	}
 
-	// 3. Create the VTXO
+	// 3. Lock the amount from the owner's balance
+	if err := lockCollateral(ctx, s.userRepo, ownerID, amount); err != nil {
+		return nil, err
+	}
+
+	// 4. Create the VTXO
 	vtxo := &VTXO{
-		ID:                generateUniqueID(),
+		ID:                s.ids.NewID(),
 		ContractID:        contractID,
 		OwnerID:           ownerID,
 		Amount:            amount,
 		ScriptPath:        scriptPath,
-		CreationTimestamp: time.Now().UTC(),
+		CreationTimestamp: s.clock.Now().UTC(),
 		SignatureData:     signatureData,
 		IsActive:          true,
 	}
 
-	// 4. Save the VTXO
+	// 5. Save the VTXO
 	if err := s.vtxoRepo.Create(ctx, vtxo); err != nil {
+		_ = releaseCollateral(ctx, s.userRepo, ownerID, amount)
 		return nil, fmt.Errorf("failed to create VTXO: %w", err)
 	}
 
@@ -128,13 +155,28 @@ func (s *vtxoService) GetVTXOsByContract(ctx context.Context, contractID string)
 	return vtxos, nil
 }
 
+// GetUserVTXOInContract implements VTXOManager.GetUserVTXOInContract
+func (s *vtxoService) GetUserVTXOInContract(ctx context.Context, contractID string, userID string) (*VTXO, error) {
+	vtxo, err := s.vtxoRepo.FindActiveByOwnerAndContract(ctx, contractID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user VTXO in contract: %w", err)
+	}
+	return vtxo, nil
+}
+
 // GetVTXOsByUser implements VTXOManager.GetVTXOsByUser
-func (s *vtxoService) GetVTXOsByUser(ctx context.Context, userID string, onlyActive bool) ([]*VTXO, error) {
-	vtxos, err := s.vtxoRepo.FindByUser(ctx, userID, onlyActive)
+func (s *vtxoService) GetVTXOsByUser(
+	ctx context.Context,
+	userID string,
+	onlyActive bool,
+	includeArchived bool,
+	page PageRequest,
+) ([]*VTXO, string, error) {
+	vtxos, nextCursor, err := s.vtxoRepo.FindByUser(ctx, userID, onlyActive, includeArchived, page)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get VTXOs by user: %w", err)
+		return nil, "", fmt.Errorf("failed to get VTXOs by user: %w", err)
 	}
-	return vtxos, nil
+	return vtxos, nextCursor, nil
 }
 
 // SwapVTXO implements VTXOManager.SwapVTXO
@@ -144,7 +186,9 @@ func (s *vtxoService) SwapVTXO(
 	vtxoID string,
 	newOwnerID string,
 	newSignatureData []byte,
-) (*VTXO, *Transaction, error) {
+) (_ *VTXO, _ *Transaction, err error) {
+	defer func() { s.metrics.VTXOSwapped(MetricOutcome(err)) }()
+
 	// 1. Get the existing VTXO
 	vtxo, err := s.vtxoRepo.FindByID(ctx, vtxoID)
 	if err != nil {
@@ -169,24 +213,23 @@ func (s *vtxoService) SwapVTXO(
 	}
 
 	// 4. Validate contract status
-	if contract.Status != ACTIVE {
+	if !isLive(contract.Status) {
 		return nil, nil, ErrInvalidContractStatus
 	}
 
-	// 5. Verify signature data (in a real implementation, this would validate against the contract terms)
-	// For this example, we'll just check if the signature data is not empty
-	if newSignatureData == nil || len(newSignatureData) == 0 {
-		return nil, nil, ErrInvalidSignature
+	// 5. Verify the new owner signed the swap
+	if err := s.verifySwapSignature(ctx, vtxo, newOwnerID, newSignatureData); err != nil {
+		return nil, nil, err
 	}
 
 	// 6. Create a new VTXO with the new owner
 	newVTXO := &VTXO{
-		ID:                generateUniqueID(),
+		ID:                s.ids.NewID(),
 		ContractID:        vtxo.ContractID,
 		OwnerID:           newOwnerID,
 		Amount:            vtxo.Amount,
 		ScriptPath:        vtxo.ScriptPath,
-		CreationTimestamp: time.Now().UTC(),
+		CreationTimestamp: s.clock.Now().UTC(),
 		SignatureData:     newSignatureData,
 		SwappedFromID:     vtxo.ID,
 		IsActive:          true,
@@ -234,9 +277,9 @@ func (s *vtxoService) SwapVTXO(
 
 	// 10. Record the swap transaction
 	tx := &Transaction{
-		ID:         generateUniqueID(),
+		ID:         s.ids.NewID(),
 		Type:       VTXO_SWAP,
-		Timestamp:  time.Now().UTC(),
+		Timestamp:  s.clock.Now().UTC(),
 		ContractID: contract.ID,
 		UserIDs:    []string{vtxo.OwnerID, newOwnerID},
 		Amount:     vtxo.Amount,
@@ -251,9 +294,29 @@ func (s *vtxoService) SwapVTXO(
 
 	if err := s.transactionRepo.Create(ctx, tx); err != nil {
 		// If recording the transaction fails, we'll still proceed with the swap
-		// but log the error
-		fmt.Printf("failed to record swap transaction: %v\n", err)
-	}
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: contract.ID,
+			VTXOID:     newVTXO.ID,
+			Operation:  "record_swap_transaction",
+			Error:      err.Error(),
+		})
+	}
+
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:          EVENT_VTXO_SWAPPED,
+		ContractID:    contract.ID,
+		VTXOID:        newVTXO.ID,
+		UserIDs:       tx.UserIDs,
+		TransactionID: tx.ID,
+		Attributes: map[string]string{
+			"old_vtxo":      vtxo.ID,
+			"position_type": positionType,
+		},
+	})
+
+	// 11. Cancel the other offers on the old VTXO
+	s.invalidateSwapOffers(ctx, contract.ID, vtxo.ID)
 
 	return newVTXO, tx, nil
 }
@@ -288,54 +351,33 @@ func (s *vtxoService) CreatePresignedExitTransaction(
 	}
 
 	// 4. Validate contract status
-	if contract.Status != ACTIVE {
+	if !isLive(contract.Status) {
 		return "", ErrInvalidContractStatus
 	}
 
-	// 5. Validate signature data is present
-	if signatureData == nil || len(signatureData) == 0 {
-		return "", ErrInvalidSignature
-	}
-	
-	// 6. Construct verification message
-	verificationMessage := fmt.Sprintf("exit:%s:%s:%d", 
-		vtxoID, vtxo.OwnerID, contract.ExpiryBlockHeight)
-	messageBytes := []byte(verificationMessage)
-	
-	// 7. Get user's public key from repository
-	userRepo := s.userRepo
-	pubKey, err := userRepo.GetPublicKey(ctx, vtxo.OwnerID)
-	if err != nil {
-		return "", fmt.Errorf("failed to get user public key: %w", err)
-	}
-	
-	// 8. Verify the signature
-	isValid, err := s.btcClient.ValidateSignature(ctx, messageBytes, signatureData, pubKey)
-	if err != nil {
-		return "", fmt.Errorf("signature validation error: %w", err)
-	}
-	
-	if !isValid {
-		return "", ErrInvalidSignature
+	// 5. Verify the owner's signature over the exit message
+	if err := verifySignedMessage(ctx, s.btcClient, s.userRepo, vtxo.OwnerID,
+		buildExitMessage(vtxoID, vtxo.OwnerID, contract.ExpiryBlockHeight), signatureData); err != nil {
+		return "", err
 	}
 
-	// 9. Generate the exit script
+	// 6. Generate the exit script
 	exitScript, err := s.scriptGen.GenerateExitScript(ctx, vtxo.ScriptPath, signatureData)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate exit script: %w", err)
 	}
 
-	// 10. Generate a unique ID for the pre-signed transaction
-	exitTxID := generateExitTransactionID(vtxo, contract)
+	// 7. Generate a unique ID for the pre-signed transaction
+	exitTxID := generateExitTransactionID(vtxo, contract, s.clock.Now())
 
-	// 11. Store the pre-signed exit transaction
+	// 8. Store the pre-signed exit transaction
 	preSignedExit := &PreSignedExit{
-		ID:           generateUniqueID(),
+		ID:           s.ids.NewID(),
 		VTXOID:       vtxoID,
 		ContractID:   vtxo.ContractID,
 		UserID:       vtxo.OwnerID,
 		ExitTxHex:    exitScript,
-		CreationTime: time.Now().UTC(),
+		CreationTime: s.clock.Now().UTC(),
 		IsUsed:       false,
 	}
 
@@ -343,11 +385,11 @@ func (s *vtxoService) CreatePresignedExitTransaction(
 		return "", fmt.Errorf("failed to store pre-signed exit transaction: %w", err)
 	}
 	
-	// 12. Record the transaction
+	// 9. Record the transaction
 	tx := &Transaction{
-		ID:         generateUniqueID(),
+		ID:         s.ids.NewID(),
 		Type:       EXIT_PATH_EXECUTION,
-		Timestamp:  time.Now().UTC(),
+		Timestamp:  s.clock.Now().UTC(),
 		ContractID: contract.ID,
 		UserIDs:    []string{vtxo.OwnerID},
 		Amount:     vtxo.Amount,
@@ -368,6 +410,9 @@ func (s *vtxoService) CreatePresignedExitTransaction(
 }
 
 // ExecuteVTXOSweep implements VTXOManager.ExecuteVTXOSweep
+// The sweep is recorded as PENDING before it is broadcast, so a crash between broadcasting
+// and updating the VTXO and contract is finished by ReconcileSweeps. Sweeping a VTXO again
+// returns its existing sweep transaction instead of broadcasting a second one.
 func (s *vtxoService) ExecuteVTXOSweep(
 	ctx context.Context,
 	vtxoID string,
@@ -381,6 +426,15 @@ func (s *vtxoService) ExecuteVTXOSweep(
 		return nil, ErrVTXONotFound
 	}
 
+	// 1a. Return the existing sweep if this VTXO was already swept
+	existing, err := s.findSweepTransaction(ctx, vtxo)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
 	// 2. Validate the VTXO is active
 	if !vtxo.IsActive {
 		return nil, ErrVTXONotActive
@@ -395,117 +449,261 @@ func (s *vtxoService) ExecuteVTXOSweep(
 		return nil, ErrContractNotFound
 	}
 
-	// 4. Validate the contract status allows for exits
-	// Typically, only ACTIVE or SETTLEMENT_PENDING contracts can be exited
-	if contract.Status != ACTIVE && contract.Status != SETTLEMENT_PENDING {
-		return nil, ErrInvalidContractStatus
-	}
-
-	// 5. Get current block height for validation
+	// 4. Get current block height for validation
 	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current block height: %w", err)
 	}
 
-	// 6. Validate that VTXO sweep is allowed
-	// For emergency exits, we typically require the contract to be close to expiry
-	// or a certain timeout to have passed
-	if currentBlockHeight < contract.ExpiryBlockHeight - 144 { // 144 blocks = ~1 day
-		// Check if the contract has a special flag or status that allows early exit
-		if contract.Status != SETTLEMENT_PENDING {
-            return nil, errors.New("VTXO sweep is only allowed within 1 day of expiry or for settlement pending contracts")
-        }
+	// 5. Validate the sweep policy allows the sweep
+	if err := s.checkSweepAllowed(ctx, contract, vtxo, currentBlockHeight); err != nil {
+		return nil, err
 	}
 
-	// 7. Generate the emergency exit script based on the VTXO's script path
+	// 6. Generate the emergency exit script based on the VTXO's script path
 	exitScript, err := s.scriptGen.GenerateExitScript(ctx, vtxo.ScriptPath, vtxo.SignatureData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate exit script: %w", err)
 	}
 
-	// 8. Create and broadcast a Bitcoin transaction to execute the VTXO sweep
-	// This would create and sign a transaction that sends the funds to the VTXO owner's address
+	// 7. Record the intended sweep as PENDING before anything is broadcast
+	tx := &Transaction{
+		ID:         s.ids.NewID(),
+		Type:       VTXO_SWEEP,
+		Timestamp:  s.clock.Now().UTC(),
+		ContractID: contract.ID,
+		UserIDs:    []string{vtxo.OwnerID},
+		Amount:     vtxo.Amount,
+		BlockHeight: currentBlockHeight,
+		RelatedEntities: map[string]string{
+			"vtxo_id":   vtxo.ID,
+			"exit_type": "vtxo_sweep",
+			"owner_id":  vtxo.OwnerID,
+			"sweep_tx":  exitScript,
+		},
+		Status:     TX_STATUS_PENDING,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to record pending sweep transaction: %w", err)
+	}
+
+	// 8. Broadcast the sweep and remember its hash
 	txHash, err := s.btcClient.BroadcastTransaction(ctx, exitScript)
 	if err != nil {
+		tx.Status = TX_STATUS_FAILED
+		if updateErr := s.transactionRepo.Update(ctx, tx); updateErr != nil {
+			publishEvent(ctx, s.eventPublisher, Event{
+				Type:          EVENT_OPERATION_FAILED,
+				ContractID:    contract.ID,
+				VTXOID:        vtxo.ID,
+				TransactionID: tx.ID,
+				Operation:     "fail_sweep_transaction",
+				Error:         updateErr.Error(),
+			})
+		}
 		return nil, fmt.Errorf("failed to broadcast exit transaction: %w", err)
 	}
 
-	// 9. Mark the VTXO as inactive
-	vtxo.IsActive = false
-	vtxo.ExitTxHash = txHash
-	vtxo.ExitTimestamp = time.Now().UTC()
-	
-	if err := s.vtxoRepo.Update(ctx, vtxo); err != nil {
-		// If we fail to update the VTXO, log the error but don't fail the operation
-		// since the Bitcoin transaction has already been broadcast
-		fmt.Printf("failed to update VTXO after sweep: %v\n", err)
-		// In a real implementation, this should be handled by a reconciliation process
+	tx.TxHash = txHash
+	tx.RelatedEntities["btc_tx_hash"] = txHash
+	if err := s.transactionRepo.Update(ctx, tx); err != nil {
+		// Non-critical error, ReconcileSweeps rebroadcasts the recorded transaction to recover its hash
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:          EVENT_OPERATION_FAILED,
+			ContractID:    contract.ID,
+			VTXOID:        vtxo.ID,
+			TransactionID: tx.ID,
+			Operation:     "record_sweep_tx_hash",
+			Error:         err.Error(),
+		})
+		return tx, nil
+	}
+
+	// 9. Apply the sweep to the VTXO and contract, ReconcileSweeps retries this if it fails
+	if err := s.finalizeExit(ctx, tx); err != nil {
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:          EVENT_OPERATION_FAILED,
+			ContractID:    contract.ID,
+			VTXOID:        vtxo.ID,
+			TransactionID: tx.ID,
+			Operation:     "finalize_sweep",
+			Error:         err.Error(),
+		})
+	}
+
+	return tx, nil
+}
+
+// findSweepTransaction returns the sweep of a VTXO that has not failed, or nil if there is none
+func (s *vtxoService) findSweepTransaction(ctx context.Context, vtxo *VTXO) (*Transaction, error) {
+	transactions, err := s.transactionRepo.FindByContract(ctx, vtxo.ContractID, []TransactionType{VTXO_SWEEP}, SORT_ASCENDING)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract transactions: %w", err)
+	}
+
+	for _, tx := range transactions {
+		if tx.Type == VTXO_SWEEP && tx.RelatedEntities["vtxo_id"] == vtxo.ID && tx.Status != TX_STATUS_FAILED {
+			return tx, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// finalizeExit marks a broadcast exit's VTXO inactive, records the exit on its contract and
+// completes the exit transaction. Each step is skipped if already applied, so it can be retried.
+func (s *vtxoService) finalizeExit(ctx context.Context, tx *Transaction) error {
+	vtxoID := tx.RelatedEntities["vtxo_id"]
+
+	// 1. Mark the VTXO as inactive
+	vtxo, err := s.vtxoRepo.FindByID(ctx, vtxoID)
+	if err != nil {
+		return fmt.Errorf("failed to get VTXO: %w", err)
+	}
+	if vtxo == nil {
+		return ErrVTXONotFound
+	}
+
+	if vtxo.IsActive || vtxo.ExitTxHash != tx.TxHash {
+		vtxo.IsActive = false
+		vtxo.ExitTxHash = tx.TxHash
+		vtxo.ExitTimestamp = s.clock.Now().UTC()
+
+		if err := s.vtxoRepo.Update(ctx, vtxo); err != nil {
+			return fmt.Errorf("failed to update VTXO after exit: %w", err)
+		}
+	}
+
+	// 2. Update the contract if this VTXO is one of its core positions
+	contract, err := s.contractRepo.FindByID(ctx, tx.ContractID)
+	if err != nil {
+		return fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return ErrContractNotFound
 	}
 
-	// 10. Update the contract if necessary
-	// If this VTXO is part of the contract's core positions, update accordingly
 	if contract.BuyerVTXO == vtxoID || contract.SellerVTXO == vtxoID {
 		if contract.BuyerVTXO == vtxoID {
 			contract.BuyerVTXO = ""
 			contract.BuyerExited = true
-			contract.BuyerExitTxHash = txHash
+			contract.BuyerExitTxHash = tx.TxHash
 		} else {
 			contract.SellerVTXO = ""
 			contract.SellerExited = true
-			contract.SellerExitTxHash = txHash
+			contract.SellerExitTxHash = tx.TxHash
 		}
 		
 		// If both parties have exited, mark the contract as completed
 		if contract.BuyerExited && contract.SellerExited {
 			contract.Status = COMPLETED
-			contract.CompletionTimestamp = time.Now().UTC()
+			contract.CompletionTimestamp = s.clock.Now().UTC()
 		} else if contract.Status != SETTLEMENT_PENDING {
 			// Otherwise, mark it as pending settlement if not already
 			contract.Status = SETTLEMENT_PENDING
 		}
 		
 		if err := s.contractRepo.Update(ctx, contract); err != nil {
-			fmt.Printf("failed to update contract after VTXO sweep: %v\n", err)
-			// In a real implementation, this should be handled by a reconciliation process
+			return fmt.Errorf("failed to update contract after exit: %w", err)
 		}
 	}
 
-	// 11. Record the sweep transaction
-	tx := &Transaction{
-		ID:         generateUniqueID(),
-		Type:       EXIT_PATH_EXECUTION,
-		Timestamp:  time.Now().UTC(),
-		ContractID: contract.ID,
-		UserIDs:    []string{vtxo.OwnerID},
-		TxHash:     txHash,
-		Amount:     vtxo.Amount,
-		BlockHeight: currentBlockHeight,
-		RelatedEntities: map[string]string{
-			"vtxo_id":     vtxo.ID,
-			"exit_type":   "vtxo_sweep",
-			"owner_id":    vtxo.OwnerID,
-			"btc_tx_hash": txHash,
+	// 3. Complete the exit transaction
+	tx.Status = TX_STATUS_COMPLETED
+	if err := s.transactionRepo.Update(ctx, tx); err != nil {
+		return fmt.Errorf("failed to complete exit transaction: %w", err)
+	}
+
+	// 4. Cancel the offers on the swept VTXO
+	s.invalidateSwapOffers(ctx, contract.ID, vtxoID)
+
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:          EVENT_EXIT_EXECUTED,
+		ContractID:    contract.ID,
+		VTXOID:        vtxoID,
+		UserIDs:       tx.UserIDs,
+		TransactionID: tx.ID,
+		Attributes: map[string]string{
+			"exit_path_type": tx.RelatedEntities["exit_type"],
+			"tx_hash":        tx.TxHash,
 		},
-		Status:     "COMPLETED",
+	})
+
+	return nil
+}
+
+// ReconcileSweeps implements VTXOManager.ReconcileSweeps
+func (s *vtxoService) ReconcileSweeps(ctx context.Context) (int, error) {
+	// 1. Find sweeps that were recorded but never finalized
+	pending, err := s.transactionRepo.FindByTypeAndStatus(ctx, VTXO_SWEEP, TX_STATUS_PENDING)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find pending sweeps: %w", err)
+	}
+
+	// 2. Reconcile each one independently so a failure does not block the rest
+	reconciled := 0
+	for _, tx := range pending {
+		if err := s.reconcileSweep(ctx, tx); err != nil {
+			publishEvent(ctx, s.eventPublisher, Event{
+				Type:          EVENT_OPERATION_FAILED,
+				ContractID:    tx.ContractID,
+				VTXOID:        tx.RelatedEntities["vtxo_id"],
+				TransactionID: tx.ID,
+				Operation:     "reconcile_sweep",
+				Error:         err.Error(),
+			})
+			continue
+		}
+		if tx.Status != TX_STATUS_PENDING {
+			reconciled++
+		}
 	}
 
-	if err := s.transactionRepo.Create(ctx, tx); err != nil {
-		// Log the error but don't fail the operation since the Bitcoin transaction
-		// has already been broadcast and the VTXO updated
-		fmt.Printf("failed to record sweep transaction: %v\n", err)
+	return reconciled, nil
+}
+
+// reconcileSweep finalizes a PENDING sweep once the node knows its transaction, rebroadcasting
+// the recorded transaction if the node does not, and fails it if it conflicts with the chain
+func (s *vtxoService) reconcileSweep(ctx context.Context, tx *Transaction) error {
+	// 1. Check the node for the broadcast transaction
+	if tx.TxHash != "" {
+		_, err := s.btcClient.GetTransactionConfirmations(ctx, tx.TxHash)
+		switch {
+		case errors.Is(err, ErrTransactionConflicted):
+			tx.Status = TX_STATUS_FAILED
+			if err := s.transactionRepo.Update(ctx, tx); err != nil {
+				return fmt.Errorf("failed to fail conflicted sweep: %w", err)
+			}
+			return nil
+		case err == nil:
+			return s.finalizeExit(ctx, tx)
+		}
 	}
 
-	return tx, nil
+	// 2. The sweep never reached the node or its hash was lost, broadcast it again
+	txHash, err := s.btcClient.BroadcastTransaction(ctx, tx.RelatedEntities["sweep_tx"])
+	if err != nil {
+		return fmt.Errorf("failed to rebroadcast sweep transaction: %w", err)
+	}
+
+	tx.TxHash = txHash
+	tx.RelatedEntities["btc_tx_hash"] = txHash
+	if err := s.transactionRepo.Update(ctx, tx); err != nil {
+		return fmt.Errorf("failed to record sweep tx hash: %w", err)
+	}
+
+	return s.finalizeExit(ctx, tx)
 }
 
 // Helper function to generate a unique ID for an exit transaction
-func generateExitTransactionID(vtxo *VTXO, contract *Contract) string {
+func generateExitTransactionID(vtxo *VTXO, contract *Contract, now time.Time) string {
 	// Create a unique identifier that's stable across implementations
 	data := fmt.Sprintf("exit_%s_%s_%s_%d",
 		vtxo.ID,
 		vtxo.OwnerID,
 		contract.ID,
-		time.Now().UnixNano())
+		now.UnixNano())
 	
 	hash := sha256.Sum256([]byte(data))
 	return hex.EncodeToString(hash[:])
@@ -531,11 +729,15 @@ func (s *vtxoService) VerifyVTXOOwnership(
 }
 
 // GetVTXOHistory implements VTXOManager.GetVTXOHistory
+// The lineage follows both swap (SwappedFromID) and rollover (RolledFromID/RolledToID)
+// links and is returned in chronological order. Swaps are only linked backwards, so
+// HISTORY_FULL_LINEAGE walks forward through rollovers only.
 func (s *vtxoService) GetVTXOHistory(
 	ctx context.Context,
 	vtxoID string,
+	direction VTXOHistoryDirection,
 ) ([]*VTXO, error) {
-	// 1. Get the current VTXO
+	// 1. Get the starting VTXO
 	current, err := s.vtxoRepo.FindByID(ctx, vtxoID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get VTXO: %w", err)
@@ -543,38 +745,88 @@ func (s *vtxoService) GetVTXOHistory(
 	if current == nil {
 		return nil, ErrVTXONotFound
 	}
-	
-	// 2. Initialize history with the current VTXO
-	history := []*VTXO{current}
-	
-	// 3. Trace back through the swap chain
-	var swapID = current.SwappedFromID
-	for swapID != "" {
-		// Get the previous VTXO in the chain
-		prev, err := s.vtxoRepo.FindByID(ctx, swapID)
+
+	// visited guards against cycles in corrupted lineage data
+	visited := map[string]bool{current.ID: true}
+
+	// 2. Trace back through swap and rollover links
+	var ancestors []*VTXO
+	prevID := previousVTXOID(current)
+	for prevID != "" {
+		if visited[prevID] {
+			publishEvent(ctx, s.eventPublisher, Event{
+				Type:      EVENT_OPERATION_FAILED,
+				VTXOID:    vtxoID,
+				Operation: "trace_vtxo_lineage",
+				Error:     fmt.Sprintf("cycle detected at VTXO %s", prevID),
+			})
+			break
+		}
+		visited[prevID] = true
+
+		prev, err := s.vtxoRepo.FindByID(ctx, prevID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get previous VTXO in swap chain: %w", err)
+			return nil, fmt.Errorf("failed to get previous VTXO in lineage: %w", err)
 		}
 		if prev == nil {
 			// If the previous VTXO doesn't exist (data integrity issue),
 			// break the chain but return what we have so far
 			break
 		}
-		
-		// Add to history and continue tracing back
-		history = append(history, prev)
-		swapID = prev.SwappedFromID
+
+		ancestors = append(ancestors, prev)
+		prevID = previousVTXOID(prev)
 	}
-	
-	// 4. Reverse the history so it's in chronological order
+
+	// 3. Reverse the ancestors so the history is in chronological order
 	// (earliest to latest)
-	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
-		history[i], history[j] = history[j], history[i]
+	history := make([]*VTXO, 0, len(ancestors)+1)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		history = append(history, ancestors[i])
+	}
+	history = append(history, current)
+
+	if direction != HISTORY_FULL_LINEAGE {
+		return history, nil
+	}
+
+	// 4. Walk forward through rollovers to find descendants
+	nextID := current.RolledToID
+	for nextID != "" {
+		if visited[nextID] {
+			publishEvent(ctx, s.eventPublisher, Event{
+				Type:      EVENT_OPERATION_FAILED,
+				VTXOID:    vtxoID,
+				Operation: "trace_vtxo_lineage",
+				Error:     fmt.Sprintf("cycle detected at VTXO %s", nextID),
+			})
+			break
+		}
+		visited[nextID] = true
+
+		next, err := s.vtxoRepo.FindByID(ctx, nextID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next VTXO in lineage: %w", err)
+		}
+		if next == nil {
+			break
+		}
+
+		history = append(history, next)
+		nextID = next.RolledToID
 	}
-	
+
 	return history, nil
 }
 
+// previousVTXOID returns the VTXO this one was created from, by swap or by rollover
+func previousVTXOID(vtxo *VTXO) string {
+	if vtxo.SwappedFromID != "" {
+		return vtxo.SwappedFromID
+	}
+	return vtxo.RolledFromID
+}
+
 // RolloverVTXO implements VTXOManager.RolloverVTXO
 // This function allows rolling over a VTXO from an expiring contract to a new one
 func (s *vtxoService) RolloverVTXO(
@@ -608,7 +860,7 @@ func (s *vtxoService) RolloverVTXO(
 	
 	// 4. Validate old contract status
 	// Rollover is typically only allowed for contracts that are active or close to expiration
-	if oldContract.Status != ACTIVE && oldContract.Status != CLOSE_TO_EXPIRY {
+	if !isLive(oldContract.Status) {
 		return nil, nil, ErrInvalidContractStatus
 	}
 	
@@ -651,12 +903,12 @@ func (s *vtxoService) RolloverVTXO(
 	
 	// 9. Create a new VTXO for the new contract
 	newVTXO := &VTXO{
-		ID:                generateUniqueID(),
+		ID:                s.ids.NewID(),
 		ContractID:        newContractID,
 		OwnerID:           oldVTXO.OwnerID,
 		Amount:            oldVTXO.Amount, // Typically the amount would be adjusted based on new contract terms
 		ScriptPath:        oldVTXO.ScriptPath, // This might need to be regenerated for the new contract
-		CreationTimestamp: time.Now().UTC(),
+		CreationTimestamp: s.clock.Now().UTC(),
 		SignatureData:     newSignatureData,
 		RolledFromID:      oldVTXO.ID,
 		IsActive:          true,
@@ -696,9 +948,9 @@ func (s *vtxoService) RolloverVTXO(
 	
 	// 13. Record the rollover transaction
 	tx := &Transaction{
-		ID:         generateUniqueID(),
+		ID:         s.ids.NewID(),
 		Type:       CONTRACT_ROLLOVER,
-		Timestamp:  time.Now().UTC(),
+		Timestamp:  s.clock.Now().UTC(),
 		ContractID: newContract.ID,
 		UserIDs:    []string{oldVTXO.OwnerID},
 		Amount:     newVTXO.Amount,
@@ -715,13 +967,33 @@ func (s *vtxoService) RolloverVTXO(
 	
 	if err := s.transactionRepo.Create(ctx, tx); err != nil {
 		// If recording the transaction fails, we'll still proceed with the rollover
-		// but log the error
-		fmt.Printf("failed to record rollover transaction: %v\n", err)
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: newContract.ID,
+			VTXOID:     newVTXO.ID,
+			Operation:  "record_rollover_transaction",
+			Error:      err.Error(),
+		})
 	}
 	
+	// 14. Cancel the offers on the rolled over VTXO
+	s.invalidateSwapOffers(ctx, oldContract.ID, oldVTXO.ID)
+	
 	return newVTXO, tx, nil
 }
 
+// verifySwapSignature checks the new owner's signature over the canonical swap message
+// against the public key registered for them
+func (s *vtxoService) verifySwapSignature(
+	ctx context.Context,
+	vtxo *VTXO,
+	newOwnerID string,
+	signatureData []byte,
+) error {
+	return verifySignedMessage(ctx, s.btcClient, s.userRepo, newOwnerID,
+		buildSwapMessage(vtxo.ID, newOwnerID, vtxo.ContractID), signatureData)
+}
+
 // GetActiveVTXOsCount implements VTXOManager.GetActiveVTXOsCount
 func (s *vtxoService) GetActiveVTXOsCount(
 	ctx context.Context,
@@ -760,7 +1032,7 @@ func (s *vtxoService) GetVTXOsForSettlement(
 	}
 	
 	// 2. Validate contract status is appropriate for settlement
-	if contract.Status != ACTIVE && contract.Status != SETTLEMENT_PENDING && contract.Status != SETTLEMENT_IN_PROGRESS {
+	if !isLive(contract.Status) && contract.Status != SETTLEMENT_PENDING && contract.Status != SETTLEMENT_IN_PROGRESS {
 		return nil, ErrInvalidContractStatus
 	}
 	
@@ -841,40 +1113,20 @@ func (s *vtxoService) SwapVTXO(
 		return nil, nil, ErrInvalidContractStatus
 	}
 	
-	// 6. If possible, validate the signature against the contract terms
-	if s.btcClient != nil && len(newSignatureData) >= 65 { // Most signatures are 65 bytes minimum
-		// Extract the necessary data for verification
-		// This is a simplified example - in production, you'd have a more robust message construction
-		message := []byte(fmt.Sprintf("swap:%s:%s:%s", vtxoID, newOwnerID, vtxo.ContractID))
-		
-		// Get user's public key
-		var pubKey []byte
-		if s.userRepo != nil {
-			pubKey, err = s.userRepo.GetPublicKey(ctx, newOwnerID)
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to get user public key: %w", err)
-			}
-			
-			// Validate the signature
-			isValid, err := s.btcClient.ValidateSignature(ctx, message, newSignatureData, pubKey)
-			if err != nil {
-				// Log the error but don't fail - this is a best-effort validation
-				fmt.Printf("signature validation error: %v\n", err)
-			} else if !isValid {
-				return nil, nil, ErrInvalidSignature
-			}
-		}
+	// 6. Verify the new owner signed the swap
+	if err := s.verifySwapSignature(ctx, vtxo, newOwnerID, newSignatureData); err != nil {
+		return nil, nil, err
 	}
 
 	// Continue with the rest of the function...
 	// Create a new VTXO with the new owner
 	newVTXO := &hashperp.VTXO{
-		ID:                generateUniqueID(),
+		ID:                s.ids.NewID(),
 		ContractID:        vtxo.ContractID,
 		OwnerID:           newOwnerID,
 		Amount:            vtxo.Amount,
 		ScriptPath:        vtxo.ScriptPath,
-		CreationTimestamp: time.Now().UTC(),
+		CreationTimestamp: s.clock.Now().UTC(),
 		SignatureData:     newSignatureData,
 		SwappedFromID:     vtxo.ID,
 		IsActive:          true,
@@ -922,9 +1174,9 @@ func (s *vtxoService) SwapVTXO(
 
 	// 10. Record the swap transaction
 	tx := &hashperp.Transaction{
-		ID:         generateUniqueID(),
+		ID:         s.ids.NewID(),
 		Type:       hashperp.VTXO_SWAP,
-		Timestamp:  time.Now().UTC(),
+		Timestamp:  s.clock.Now().UTC(),
 		ContractID: contract.ID,
 		UserIDs:    []string{vtxo.OwnerID, newOwnerID},
 		Amount:     vtxo.Amount,
@@ -939,9 +1191,19 @@ func (s *vtxoService) SwapVTXO(
 
 	if err := s.transactionRepo.Create(ctx, tx); err != nil {
 		// If recording the transaction fails, we'll still proceed with the swap
-		// but log the error
-		fmt.Printf("failed to record swap transaction: %v\n", err)
-	}
+		// but report the error
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:          EVENT_OPERATION_FAILED,
+			ContractID:    contract.ID,
+			VTXOID:        newVTXO.ID,
+			TransactionID: tx.ID,
+			Operation:     "record_swap_transaction",
+			Error:         err.Error(),
+		})
+	}
+
+	// 11. Cancel the other offers on the old VTXO
+	s.invalidateSwapOffers(ctx, contract.ID, vtxo.ID)
 
 	return newVTXO, tx, nil
 }
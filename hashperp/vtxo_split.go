@@ -0,0 +1,256 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SplitVTXO implements VTXOManager.SplitVTXO
+// The first child takes over the parent's place in the contract, as the founding party or
+// pool member, and every other child joins the same side's pool. The parent's share of the
+// side's size is split across the children in proportion to their amounts.
+func (s *vtxoService) SplitVTXO(
+	ctx context.Context,
+	vtxoID string,
+	amounts []float64,
+	owners []string,
+	signatures [][]byte,
+) ([]*VTXO, *Transaction, error) {
+	// 1. Validate the split
+	if len(amounts) < 2 {
+		return nil, nil, fmt.Errorf("%w: a split needs at least two children", ErrInvalidParameters)
+	}
+	if len(owners) != len(amounts) || len(signatures) != len(amounts) {
+		return nil, nil, fmt.Errorf("%w: %d amounts, %d owners and %d signatures",
+			ErrInvalidParameters, len(amounts), len(owners), len(signatures))
+	}
+
+	seen := make(map[string]bool, len(owners))
+	childSats := make([]int64, len(amounts))
+	var totalSats int64
+	for i, amount := range amounts {
+		if owners[i] == "" {
+			return nil, nil, fmt.Errorf("%w: child %d has no owner", ErrInvalidParameters, i)
+		}
+		if seen[owners[i]] {
+			return nil, nil, fmt.Errorf("%w: user %s owns more than one child", ErrInvalidParameters, owners[i])
+		}
+		seen[owners[i]] = true
+
+		childSats[i] = BTCToSats(amount)
+		if childSats[i] <= 0 {
+			return nil, nil, fmt.Errorf("%w: child %d amount must be positive", ErrInvalidParameters, i)
+		}
+		totalSats += childSats[i]
+	}
+
+	// 2. Get the VTXO and check the amounts sum to it exactly
+	vtxo, err := s.vtxoRepo.FindByID(ctx, vtxoID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get VTXO: %w", err)
+	}
+	if vtxo == nil {
+		return nil, nil, ErrVTXONotFound
+	}
+	if !vtxo.IsActive {
+		return nil, nil, ErrVTXONotActive
+	}
+	if totalSats != BTCToSats(vtxo.Amount) {
+		return nil, nil, fmt.Errorf("%w: children sum to %d sats, the VTXO holds %d sats",
+			ErrInvalidParameters, totalSats, BTCToSats(vtxo.Amount))
+	}
+
+	// 3. Get the associated contract and find the position the VTXO backs
+	contract, err := s.contractRepo.FindByID(ctx, vtxo.ContractID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract == nil {
+		return nil, nil, ErrContractNotFound
+	}
+	if !isLive(contract.Status) {
+		return nil, nil, ErrInvalidContractStatus
+	}
+
+	side, memberIndex, positionSize, err := vtxoPosition(contract, vtxo.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 4. A user holds at most one position in a contract, the parent's owner excepted
+	for _, ownerID := range owners {
+		if ownerID != vtxo.OwnerID && holdsPosition(contract, ownerID) {
+			return nil, nil, fmt.Errorf("%w: user %s already holds a position in contract %s",
+				ErrInvalidParameters, ownerID, contract.ID)
+		}
+	}
+
+	// 5. Split the parent's share of the side's size in proportion to the amounts, every child
+	// but a founding party's successor becomes a pool member
+	childSizes := splitSatsProRata(BTCToSats(positionSize), childSats)
+	for i := range childSizes {
+		if (i > 0 || memberIndex >= 0) && SatsToBTC(childSizes[i]) < minPoolShare {
+			return nil, nil, fmt.Errorf("%w: pool share must be at least %.4f BTC", ErrInvalidParameters, minPoolShare)
+		}
+	}
+
+	// 6. Verify every child owner signed their part
+	for i, ownerID := range owners {
+		if err := s.verifySplitSignature(ctx, vtxo, ownerID, SatsToBTC(childSats[i]), signatures[i]); err != nil {
+			return nil, nil, fmt.Errorf("child %d: %w", i, err)
+		}
+	}
+
+	// 7. Create the children, each keeping the parent in its lineage
+	children := make([]*VTXO, 0, len(amounts))
+	rollback := func() {
+		for _, child := range children {
+			_ = s.vtxoRepo.Delete(ctx, child.ID)
+		}
+	}
+	for i, ownerID := range owners {
+		child := &VTXO{
+			ID:                s.ids.NewID(),
+			ContractID:        vtxo.ContractID,
+			OwnerID:           ownerID,
+			Amount:            SatsToBTC(childSats[i]),
+			ScriptPath:        vtxo.ScriptPath,
+			CreationTimestamp: s.clock.Now().UTC(),
+			SignatureData:     signatures[i],
+			SwappedFromID:     vtxo.ID,
+			IsActive:          true,
+		}
+		if err := s.vtxoRepo.Create(ctx, child); err != nil {
+			rollback()
+			return nil, nil, fmt.Errorf("failed to create child VTXO: %w", err)
+		}
+		children = append(children, child)
+	}
+
+	// 8. Mark the parent as inactive
+	vtxo.IsActive = false
+	if err := s.vtxoRepo.Update(ctx, vtxo); err != nil {
+		rollback()
+		return nil, nil, fmt.Errorf("failed to update split VTXO: %w", err)
+	}
+
+	// 9. The first child takes the parent's place, the others join the side's pool
+	now := s.clock.Now().UTC()
+	members := make([]PoolMember, 0, len(children))
+	for i, child := range children {
+		members = append(members, PoolMember{
+			UserID:   child.OwnerID,
+			VTXOID:   child.ID,
+			Size:     SatsToBTC(childSizes[i]),
+			JoinedAt: now,
+		})
+	}
+
+	pool, _, _ := poolSide(contract, side)
+	pool = append([]PoolMember{}, pool...)
+	if memberIndex < 0 {
+		if side == SIDE_BUYER {
+			contract.BuyerVTXO, contract.BuyerID = children[0].ID, children[0].OwnerID
+		} else {
+			contract.SellerVTXO, contract.SellerID = children[0].ID, children[0].OwnerID
+		}
+		pool = append(pool, members[1:]...)
+	} else {
+		members[0].JoinedAt = pool[memberIndex].JoinedAt
+		pool[memberIndex] = members[0]
+		pool = append(pool, members[1:]...)
+	}
+	if side == SIDE_BUYER {
+		contract.BuyerPool = pool
+	} else {
+		contract.SellerPool = pool
+	}
+
+	if err := s.contractRepo.Update(ctx, contract); err != nil {
+		// If we fail to update the contract, revert the VTXO changes
+		rollback()
+		vtxo.IsActive = true
+		_ = s.vtxoRepo.Update(ctx, vtxo)
+		return nil, nil, fmt.Errorf("failed to update contract: %w", err)
+	}
+
+	// 10. Record the split as a single transaction
+	childIDs := make([]string, len(children))
+	for i, child := range children {
+		childIDs[i] = child.ID
+	}
+	tx := &Transaction{
+		ID:         s.ids.NewID(),
+		Type:       VTXO_SPLIT,
+		Timestamp:  s.clock.Now().UTC(),
+		ContractID: contract.ID,
+		UserIDs:    append([]string{vtxo.OwnerID}, owners...),
+		Amount:     vtxo.Amount,
+		AmountSats: totalSats,
+		RelatedEntities: map[string]string{
+			"old_vtxo":      vtxo.ID,
+			"new_vtxos":     strings.Join(childIDs, ","),
+			"position_type": strings.ToLower(string(side)),
+			"old_owner_id":  vtxo.OwnerID,
+		},
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx); err != nil {
+		// If recording the transaction fails, we'll still proceed with the split
+		publishEvent(ctx, s.eventPublisher, Event{
+			Type:       EVENT_OPERATION_FAILED,
+			ContractID: contract.ID,
+			VTXOID:     vtxo.ID,
+			Operation:  "record_split_transaction",
+			Error:      err.Error(),
+		})
+	}
+
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:          EVENT_VTXO_SPLIT,
+		ContractID:    contract.ID,
+		VTXOID:        vtxo.ID,
+		UserIDs:       tx.UserIDs,
+		TransactionID: tx.ID,
+		Attributes: map[string]string{
+			"new_vtxos": strings.Join(childIDs, ","),
+		},
+	})
+
+	// 11. Cancel the open offers on the parent
+	s.invalidateSwapOffers(ctx, contract.ID, vtxo.ID)
+
+	return children, tx, nil
+}
+
+// vtxoPosition finds the side of a contract a VTXO backs and the share of the side's size it
+// holds. memberIndex is the VTXO's index in the side's pool, or -1 for a founding party's VTXO.
+func vtxoPosition(contract *Contract, vtxoID string) (PositionSide, int, float64, error) {
+	for _, side := range []PositionSide{SIDE_BUYER, SIDE_SELLER} {
+		pool, _, founderVTXO := poolSide(contract, side)
+		if founderVTXO == vtxoID {
+			return side, -1, founderShare(contract, side), nil
+		}
+		for i, member := range pool {
+			if member.VTXOID == vtxoID {
+				return side, i, member.Size, nil
+			}
+		}
+	}
+	return "", 0, 0, errors.New("VTXO is not associated with a position in this contract")
+}
+
+// verifySplitSignature checks a child owner's signature over the canonical split message
+// against the public key registered for them
+func (s *vtxoService) verifySplitSignature(
+	ctx context.Context,
+	vtxo *VTXO,
+	ownerID string,
+	amount float64,
+	signatureData []byte,
+) error {
+	return verifySignedMessage(ctx, s.btcClient, s.userRepo, ownerID,
+		buildSplitMessage(vtxo.ID, ownerID, amount), signatureData)
+}
@@ -0,0 +1,93 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SweepOutcome is the result of sweeping a single VTXO in a bulk sweep
+type SweepOutcome struct {
+	VTXOID      string       `json:"vtxo_id"`
+	ContractID  string       `json:"contract_id"`
+	Transaction *Transaction `json:"transaction,omitempty"` // The sweep transaction, if swept
+	Reason      string       `json:"reason,omitempty"`      // Why the VTXO was skipped or failed
+}
+
+// SweepSummary reports which of a user's VTXOs a bulk sweep swept, skipped and failed
+type SweepSummary struct {
+	UserID    string         `json:"user_id"`
+	Timestamp time.Time      `json:"timestamp"`
+	Swept     []SweepOutcome `json:"swept"`
+	Skipped   []SweepOutcome `json:"skipped"` // Not eligible for a sweep yet
+	Failed    []SweepOutcome `json:"failed"`
+}
+
+// SweepAllVTXOs implements VTXOManager.SweepAllVTXOs
+// Each VTXO is swept independently, a failure is recorded in the summary and the remaining
+// VTXOs are still swept. Only failing to list the user's VTXOs aborts the sweep.
+func (s *vtxoService) SweepAllVTXOs(ctx context.Context, userID string) (*SweepSummary, error) {
+	// 1. Find the user's active VTXOs
+	vtxos, _, err := s.vtxoRepo.FindByUser(ctx, userID, true, false, PageRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VTXOs by user: %w", err)
+	}
+
+	currentBlockHeight, err := s.btcClient.GetCurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current block height: %w", err)
+	}
+
+	summary := &SweepSummary{
+		UserID:    userID,
+		Timestamp: s.clock.Now().UTC(),
+		Swept:     []SweepOutcome{},
+		Skipped:   []SweepOutcome{},
+		Failed:    []SweepOutcome{},
+	}
+	contracts := make(map[string]*Contract)
+
+	for _, vtxo := range vtxos {
+		outcome := SweepOutcome{VTXOID: vtxo.ID, ContractID: vtxo.ContractID}
+
+		// 2. Skip VTXOs whose contract does not allow a sweep yet
+		contract, ok := contracts[vtxo.ContractID]
+		if !ok {
+			contract, err = s.contractRepo.FindByID(ctx, vtxo.ContractID)
+			if err != nil {
+				outcome.Reason = fmt.Sprintf("failed to get contract: %v", err)
+				summary.Failed = append(summary.Failed, outcome)
+				continue
+			}
+			contracts[vtxo.ContractID] = contract
+		}
+		if contract == nil {
+			outcome.Reason = ErrContractNotFound.Error()
+			summary.Failed = append(summary.Failed, outcome)
+			continue
+		}
+		if err := s.checkSweepAllowed(ctx, contract, vtxo, currentBlockHeight); err != nil {
+			outcome.Reason = err.Error()
+			summary.Skipped = append(summary.Skipped, outcome)
+			continue
+		}
+
+		// 3. Sweep the VTXO, continuing past failures
+		tx, err := s.ExecuteVTXOSweep(ctx, vtxo.ID)
+		if err != nil {
+			outcome.Reason = err.Error()
+			// The contract may have changed since the eligibility check
+			if errors.Is(err, ErrSweepNotAllowed) || errors.Is(err, ErrInvalidContractStatus) {
+				summary.Skipped = append(summary.Skipped, outcome)
+			} else {
+				summary.Failed = append(summary.Failed, outcome)
+			}
+			continue
+		}
+		outcome.Transaction = tx
+		summary.Swept = append(summary.Swept, outcome)
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,394 @@
+package hashperp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// errWebhookAddressNotAllowed is returned when a callback host resolves to an address
+// deliveries must not be sent to
+var errWebhookAddressNotAllowed = errors.New("webhook address is not a public address")
+
+// Webhook delivery policy
+const (
+	webhookRequestTimeout = 10 * time.Second
+	webhookDeliveryBatch  = 100 // Deliveries attempted per DeliverPendingWebhooks call
+	maxWebhookAttempts    = 8   // Attempts before a delivery is marked FAILED
+	webhookBaseRetryDelay = 30 * time.Second
+	webhookMaxRetryDelay  = 6 * time.Hour
+	webhookSecretBytes    = 32
+)
+
+// Webhook request headers. The signature is the hex HMAC-SHA256, keyed with the subscription
+// secret, of "<timestamp>.<body>" so receivers can check both authenticity and freshness.
+const (
+	webhookSignatureHeader = "X-HashPerp-Signature"
+	webhookTimestampHeader = "X-HashPerp-Timestamp"
+	webhookDeliveryHeader  = "X-HashPerp-Delivery"
+)
+
+// webhookPayload is the JSON body POSTed to a webhook subscription
+type webhookPayload struct {
+	DeliveryID  string          `json:"delivery_id"`
+	EventType   TransactionType `json:"event_type"`
+	UserID      string          `json:"user_id"`
+	Transaction *Transaction    `json:"transaction"`
+}
+
+// webhookService implements the WebhookManager interface
+type webhookService struct {
+	webhookRepo    WebhookRepository
+	httpClient     *http.Client
+	resolver       *net.Resolver  // Resolves callback hosts to check they are public
+	eventPublisher EventPublisher // Lifecycle events for downstream integrations
+	ids            IDGenerator    // IDs of new subscriptions and deliveries
+	clock          Clock          // Source of delivery schedules and signature timestamps
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(webhookRepo WebhookRepository) WebhookManager {
+	return &webhookService{
+		webhookRepo:    webhookRepo,
+		httpClient:     newWebhookHTTPClient(),
+		resolver:       net.DefaultResolver,
+		eventPublisher: NewNoopEventPublisher(),
+		ids:            NewUUIDGenerator(),
+		clock:          NewSystemClock(),
+	}
+}
+
+// newWebhookHTTPClient creates the client deliveries are sent with. Every connection is
+// checked at dial time, after DNS resolution, so a callback host that re-resolves to an
+// internal address after registration is still refused. No proxy is used, as the check
+// would otherwise apply to the proxy instead of the callback host.
+func newWebhookHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookRequestTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicWebhookIP(ip) {
+				return fmt.Errorf("%w: %s", errWebhookAddressNotAllowed, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: webhookRequestTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookRequestTimeout,
+		},
+	}
+}
+
+// isPublicWebhookIP reports whether deliveries may be sent to an address. Loopback,
+// private, link-local and unspecified addresses are refused so a callback URL cannot
+// reach the server itself or its internal network.
+func isPublicWebhookIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// checkWebhookHost resolves a callback host and checks every address it resolves to is public
+func (s *webhookService) checkWebhookHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !isPublicWebhookIP(ip) {
+			return fmt.Errorf("%w: %s", errWebhookAddressNotAllowed, host)
+		}
+		return nil
+	}
+
+	addrs, err := s.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("%s does not resolve to any address", host)
+	}
+	for _, addr := range addrs {
+		if !isPublicWebhookIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", errWebhookAddressNotAllowed, host, addr.IP)
+		}
+	}
+
+	return nil
+}
+
+// SetEventPublisher configures where lifecycle events are published
+func (s *webhookService) SetEventPublisher(publisher EventPublisher) {
+	s.eventPublisher = publisher
+}
+
+// RegisterWebhook implements WebhookManager.RegisterWebhook
+func (s *webhookService) RegisterWebhook(
+	ctx context.Context,
+	userID string,
+	callbackURL string,
+	eventTypes []TransactionType,
+) (*WebhookSubscription, error) {
+	// 1. Validate the callback URL
+	parsed, err := url.Parse(callbackURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidWebhookURL, callbackURL)
+	}
+	if err := s.checkWebhookHost(ctx, parsed.Hostname()); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookURL, err)
+	}
+
+	// 2. Generate the signing secret
+	secretBytes := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	// 3. Save the subscription
+	subscription := &WebhookSubscription{
+		ID:         s.ids.NewID(),
+		UserID:     userID,
+		URL:        parsed.String(),
+		EventTypes: eventTypes,
+		Secret:     hex.EncodeToString(secretBytes),
+		CreatedAt:  s.clock.Now().UTC(),
+	}
+
+	if err := s.webhookRepo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// GetWebhooksByUser implements WebhookManager.GetWebhooksByUser
+func (s *webhookService) GetWebhooksByUser(ctx context.Context, userID string) ([]*WebhookSubscription, error) {
+	subscriptions, err := s.webhookRepo.FindSubscriptionsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+
+	// Secrets are only handed out at registration
+	for _, subscription := range subscriptions {
+		subscription.Secret = ""
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteWebhook implements WebhookManager.DeleteWebhook
+func (s *webhookService) DeleteWebhook(ctx context.Context, webhookID string, userID string) error {
+	subscription, err := s.webhookRepo.FindSubscriptionByID(ctx, webhookID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	if subscription == nil || subscription.UserID != userID {
+		return ErrWebhookNotFound
+	}
+
+	if err := s.webhookRepo.DeleteSubscription(ctx, webhookID); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// NotifyTransaction implements WebhookManager.NotifyTransaction
+// Deliveries are only queued here; DeliverPendingWebhooks sends them.
+func (s *webhookService) NotifyTransaction(ctx context.Context, tx *Transaction) {
+	for _, userID := range tx.UserIDs {
+		subscriptions, err := s.webhookRepo.FindSubscriptionsByUser(ctx, userID)
+		if err != nil {
+			s.reportFailure(ctx, tx, "find_webhook_subscriptions", err)
+			continue
+		}
+
+		for _, subscription := range subscriptions {
+			if !subscribesTo(subscription, tx.Type) {
+				continue
+			}
+
+			delivery := &WebhookDelivery{
+				ID:             s.ids.NewID(),
+				SubscriptionID: subscription.ID,
+				TransactionID:  tx.ID,
+				Status:         DELIVERY_PENDING,
+				NextAttemptAt:  s.clock.Now().UTC(),
+			}
+
+			payload, err := json.Marshal(webhookPayload{
+				DeliveryID:  delivery.ID,
+				EventType:   tx.Type,
+				UserID:      userID,
+				Transaction: tx,
+			})
+			if err != nil {
+				s.reportFailure(ctx, tx, "encode_webhook_payload", err)
+				continue
+			}
+			delivery.Payload = payload
+
+			if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+				s.reportFailure(ctx, tx, "queue_webhook_delivery", err)
+			}
+		}
+	}
+}
+
+// DeliverPendingWebhooks implements WebhookManager.DeliverPendingWebhooks
+func (s *webhookService) DeliverPendingWebhooks(ctx context.Context) (int, error) {
+	// 1. Get the deliveries that are due
+	now := s.clock.Now().UTC()
+	deliveries, err := s.webhookRepo.FindDueDeliveries(ctx, now, webhookDeliveryBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	// 2. Attempt each one and record the outcome
+	deliveredCount := 0
+	for _, delivery := range deliveries {
+		subscription, err := s.webhookRepo.FindSubscriptionByID(ctx, delivery.SubscriptionID)
+		if err != nil {
+			return deliveredCount, fmt.Errorf("failed to get webhook subscription: %w", err)
+		}
+
+		delivery.Attempts++
+		if subscription == nil {
+			delivery.Status = DELIVERY_FAILED
+			delivery.LastError = "webhook subscription was deleted"
+		} else if err := s.send(ctx, subscription, delivery); err != nil {
+			delivery.LastError = err.Error()
+			if delivery.Attempts >= maxWebhookAttempts {
+				delivery.Status = DELIVERY_FAILED
+			} else {
+				delivery.NextAttemptAt = s.clock.Now().UTC().Add(webhookRetryDelay(delivery.Attempts))
+			}
+		} else {
+			delivery.Status = DELIVERY_DELIVERED
+			delivery.LastError = ""
+			delivery.DeliveredAt = s.clock.Now().UTC()
+			deliveredCount++
+		}
+
+		if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			return deliveredCount, fmt.Errorf("failed to update webhook delivery: %w", err)
+		}
+	}
+
+	return deliveredCount, nil
+}
+
+// send POSTs a delivery's payload to its subscription, signed with the subscription secret
+func (s *webhookService) send(ctx context.Context, subscription *WebhookSubscription, delivery *WebhookDelivery) error {
+	timestamp := strconv.FormatInt(s.clock.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookDeliveryHeader, delivery.ID)
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, "sha256="+signWebhookPayload(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// reportFailure publishes a failed webhook step for a transaction
+func (s *webhookService) reportFailure(ctx context.Context, tx *Transaction, operation string, err error) {
+	publishEvent(ctx, s.eventPublisher, Event{
+		Type:          EVENT_OPERATION_FAILED,
+		ContractID:    tx.ContractID,
+		TransactionID: tx.ID,
+		Operation:     operation,
+		Error:         err.Error(),
+	})
+}
+
+// subscribesTo reports whether a subscription wants transactions of the given type
+func subscribesTo(subscription *WebhookSubscription, transactionType TransactionType) bool {
+	if len(subscription.EventTypes) == 0 {
+		return true
+	}
+	for _, eventType := range subscription.EventTypes {
+		if eventType == transactionType {
+			return true
+		}
+	}
+	return false
+}
+
+// signWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<payload>"
+func signWebhookPayload(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay returns the exponential backoff before the next attempt of a delivery
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookBaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookMaxRetryDelay {
+			return webhookMaxRetryDelay
+		}
+	}
+	return delay
+}
+
+// notifyingTransactionRepository queues webhook deliveries for every transaction it records
+type notifyingTransactionRepository struct {
+	TransactionRepository
+	webhookMgr WebhookManager
+}
+
+// NewNotifyingTransactionRepository wraps a transaction repository so that each recorded
+// transaction is passed to the webhook manager
+func NewNotifyingTransactionRepository(repo TransactionRepository, webhookMgr WebhookManager) TransactionRepository {
+	return &notifyingTransactionRepository{
+		TransactionRepository: repo,
+		webhookMgr:            webhookMgr,
+	}
+}
+
+// Create implements TransactionRepository.Create
+func (r *notifyingTransactionRepository) Create(ctx context.Context, tx *Transaction) error {
+	if err := r.TransactionRepository.Create(ctx, tx); err != nil {
+		return err
+	}
+
+	r.webhookMgr.NotifyTransaction(ctx, tx)
+	return nil
+}
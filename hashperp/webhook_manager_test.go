@@ -0,0 +1,72 @@
+package hashperp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsPublicWebhookIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := isPublicWebhookIP(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("isPublicWebhookIP(%s) = %t, want %t", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterWebhookRejectsInternalHosts(t *testing.T) {
+	s := NewWebhookService(nil)
+
+	for _, callbackURL := range []string{
+		"http://example.com/hook",
+		"https:///hook",
+		"https://127.0.0.1/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://10.0.0.1:8443/hook",
+		"https://[::1]/hook",
+		"https://localhost/hook",
+	} {
+		t.Run(callbackURL, func(t *testing.T) {
+			_, err := s.RegisterWebhook(context.Background(), "user", callbackURL, nil)
+			if !errors.Is(err, ErrInvalidWebhookURL) {
+				t.Errorf("RegisterWebhook(%q) error = %v, want ErrInvalidWebhookURL", callbackURL, err)
+			}
+		})
+	}
+}
+
+func TestWebhookClientRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("delivery reached a loopback address")
+	}))
+	defer server.Close()
+
+	_, err := newWebhookHTTPClient().Post(server.URL, "application/json", nil)
+	if !errors.Is(err, errWebhookAddressNotAllowed) {
+		t.Errorf("Post(%s) error = %v, want errWebhookAddressNotAllowed", server.URL, err)
+	}
+}
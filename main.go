@@ -6,12 +6,16 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/hashperp/hashperp"
 	"github.com/hashperp/hashperp/api"
 	"github.com/hashperp/hashperp/bitcoin"
+	"github.com/hashperp/hashperp/metrics"
+	"github.com/hashperp/hashperp/pricefeed"
 	"github.com/hashperp/hashperp/storage"
 	
 	"github.com/joho/godotenv"
@@ -43,12 +47,43 @@ func main() {
 		log.Fatalf("Failed to run database migrations: %v", err)
 	}
 	
+	// Every component must agree on the Bitcoin network, addresses are encoded per network
+	network, err := hashperp.ParseNetwork(getEnv("BITCOIN_NETWORK", string(hashperp.NETWORK_MAINNET)))
+	if err != nil {
+		log.Fatalf("Invalid BITCOIN_NETWORK: %v", err)
+	}
+
 	// Initialize Bitcoin client
-	btcClient, err := initializeBitcoinClient()
+	btcClient, err := initializeBitcoinClient(network)
 	if err != nil {
 		log.Fatalf("Failed to initialize Bitcoin client: %v", err)
 	}
-	
+
+	// Keep read paths serving the last known block height while the node is unreachable, and
+	// poll the height in the background so validation reads it without calling the node
+	maxBlockHeightStaleness, err := time.ParseDuration(getEnv("BLOCK_HEIGHT_MAX_STALENESS", "1h"))
+	if err != nil {
+		log.Fatalf("Invalid BLOCK_HEIGHT_MAX_STALENESS: %v", err)
+	}
+	blockHeightPollInterval, err := time.ParseDuration(getEnv("BLOCK_HEIGHT_POLL_INTERVAL", "10s"))
+	if err != nil {
+		log.Fatalf("Invalid BLOCK_HEIGHT_POLL_INTERVAL: %v", err)
+	}
+	blockHeightCache := hashperp.BlockHeightCacheConfig{
+		MaxStaleness:    maxBlockHeightStaleness,
+		RefreshInterval: blockHeightPollInterval,
+	}
+	if err := blockHeightCache.Validate(); err != nil {
+		log.Fatalf("Invalid block height cache: %v", err)
+	}
+	btcClient = hashperp.NewBlockHeightCachingClient(btcClient, blockHeightCache)
+
+	// Publish lifecycle events and operation failures from every component
+	eventPublisher := hashperp.NewLoggingEventPublisher()
+
+	blockHeightPoller := hashperp.NewBlockHeightPoller(btcClient, blockHeightPollInterval)
+	setEventPublisher(eventPublisher, blockHeightPoller)
+
 	// Initialize repositories
 	contractRepo := storage.NewPostgresContractRepository(db)
 	vtxoRepo := storage.NewPostgresVTXORepository(db)
@@ -57,29 +92,257 @@ func main() {
 	transactionRepo := storage.NewPostgresTransactionRepository(db)
 	hashRateRepo := storage.NewPostgresHashRateRepository(db)
 	userRepo := storage.NewPostgresUserRepository(db)
+	webhookRepo := storage.NewPostgresWebhookRepository(db)
+	snapshotRepo := storage.NewPostgresMarketSnapshotRepository(db)
+	templateRepo := storage.NewPostgresContractTemplateRepository(db)
+	scriptRepo := storage.NewPostgresContractScriptRepository(db)
+	preSignedExitRepo := storage.NewPostgresPreSignedExitRepository(db)
+	statusChangeRepo := storage.NewPostgresStatusChangeRepository(db)
+	setEventPublisher(eventPublisher, contractRepo, transactionRepo, snapshotRepo)
+
+	// Reject updates that would make an illegal status change
+	contractRepo = hashperp.NewTransitionCheckedContractRepository(contractRepo)
+	orderRepo = hashperp.NewTransitionCheckedOrderRepository(orderRepo)
+	swapOfferRepo = hashperp.NewTransitionCheckedSwapOfferRepository(swapOfferRepo)
+
+	// Record every status change in the audit trail
+	contractRepo = hashperp.NewAuditedContractRepository(contractRepo, statusChangeRepo)
+	orderRepo = hashperp.NewAuditedOrderRepository(orderRepo, statusChangeRepo)
+	swapOfferRepo = hashperp.NewAuditedSwapOfferRepository(swapOfferRepo, statusChangeRepo)
+	vtxoRepo = hashperp.NewAuditedVTXORepository(vtxoRepo, statusChangeRepo)
+	setEventPublisher(eventPublisher, contractRepo, orderRepo, swapOfferRepo, vtxoRepo)
+
+	// Queue webhook deliveries for every recorded transaction
+	webhookMgr := hashperp.NewWebhookService(webhookRepo)
+	transactionRepo = hashperp.NewNotifyingTransactionRepository(transactionRepo, webhookMgr)
 	
 	// Initialize script generator
-	scriptGen := hashperp.NewScriptGeneratorService(btcClient, 144) // 144 blocks timeout (approx. 1 day)
+	scriptGen := hashperp.NewScriptGeneratorService(btcClient, 144, network) // 144 blocks timeout (approx. 1 day)
 	
 	// Initialize managers/services
 	// Note the cyclic dependency between services, we need to create them first then set dependencies
 	marketDataMgr := hashperp.NewMarketDataManager(hashRateRepo, btcClient)
 	transactionMgr := hashperp.NewTransactionManager(transactionRepo)
 	
+	// Swap authorizations are signed with keys from the environment in development
+	keyStore := hashperp.NewEnvKeyStore()
+
 	// Create VTXO manager and swap offer manager with nil dependencies for now
-	vtxoMgr := hashperp.NewVTXOService(vtxoRepo, contractRepo, transactionRepo, scriptGen, btcClient)
-	swapOfferMgr := hashperp.NewSwapOfferService(swapOfferRepo, vtxoRepo, contractRepo, transactionRepo, nil)
+	vtxoMgr := hashperp.NewVTXOService(vtxoRepo, contractRepo, transactionRepo, scriptGen, btcClient, userRepo, preSignedExitRepo)
+	swapOfferMgr := hashperp.NewSwapOfferService(swapOfferRepo, vtxoRepo, contractRepo, transactionRepo, nil, keyStore)
 	
 	// Now set the VTXOManager in the SwapOfferManager
 	if swapOfferSetter, ok := swapOfferMgr.(interface{ SetVTXOManager(hashperp.VTXOManager) }); ok {
 		swapOfferSetter.SetVTXOManager(vtxoMgr)
 	}
+	if vtxoSetter, ok := vtxoMgr.(interface{ SetSwapOfferManager(hashperp.SwapOfferManager) }); ok {
+		vtxoSetter.SetSwapOfferManager(swapOfferMgr)
+	}
 	
+	// Sweeps open at each contract's sweep window unless the operator opens them earlier
+	sweepPolicy := hashperp.DefaultSweepPolicy()
+	for _, contractType := range []hashperp.ContractType{hashperp.CALL, hashperp.PUT} {
+		name := "EARLY_SWEEP_BLOCKS_" + string(contractType)
+		earlySweepBlocks, err := strconv.ParseUint(getEnv(name, "0"), 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid %s: %v", name, err)
+		}
+		sweepPolicy.EarlySweepBlocks[contractType] = earlySweepBlocks
+	}
+	sweepPolicy.RequirePreSignedExit, err = strconv.ParseBool(getEnv("SWEEP_REQUIRE_PRESIGNED_EXIT", "false"))
+	if err != nil {
+		log.Fatalf("Invalid SWEEP_REQUIRE_PRESIGNED_EXIT: %v", err)
+	}
+	if err := sweepPolicy.Validate(); err != nil {
+		log.Fatalf("Invalid sweep policy: %v", err)
+	}
+	if sweepPolicySetter, ok := vtxoMgr.(interface{ SetSweepPolicy(hashperp.SweepPolicy) }); ok {
+		sweepPolicySetter.SetSweepPolicy(sweepPolicy)
+	}
+	
+	// Contract creation and order placement share one set of duration bounds
+	minContractBlocks, err := strconv.ParseUint(getEnv("CONTRACT_MIN_BLOCKS", "100"), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid CONTRACT_MIN_BLOCKS: %v", err)
+	}
+	maxContractBlocks, err := strconv.ParseUint(getEnv("CONTRACT_MAX_BLOCKS", "52560"), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid CONTRACT_MAX_BLOCKS: %v", err)
+	}
+	durations := hashperp.DurationConfig{MinContractBlocks: minContractBlocks, MaxContractBlocks: maxContractBlocks}
+	if err := durations.Validate(); err != nil {
+		log.Fatalf("Invalid contract duration bounds: %v", err)
+	}
+
 	// Create contract manager
 	contractMgr := hashperp.NewContractService(contractRepo, vtxoRepo, transactionRepo, scriptGen, btcClient, swapOfferMgr)
+	if durationSetter, ok := contractMgr.(interface{ SetDurationConfig(hashperp.DurationConfig) }); ok {
+		durationSetter.SetDurationConfig(durations)
+	}
+	disputeWindow, err := strconv.ParseUint(getEnv("SETTLEMENT_DISPUTE_WINDOW_BLOCKS", "0"), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid SETTLEMENT_DISPUTE_WINDOW_BLOCKS: %v", err)
+	}
+	if disputeSetter, ok := contractMgr.(interface{ SetSettlementDisputeWindow(uint64) }); ok {
+		disputeSetter.SetSettlementDisputeWindow(disputeWindow)
+	}
+	settlementConfirmations, err := strconv.ParseUint(getEnv("SETTLEMENT_MIN_CONFIRMATIONS", "6"), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid SETTLEMENT_MIN_CONFIRMATIONS: %v", err)
+	}
+	if confirmationsSetter, ok := contractMgr.(interface{ SetSettlementConfirmations(uint64) }); ok {
+		confirmationsSetter.SetSettlementConfirmations(settlementConfirmations)
+	}
+	defaultExitFeeRate, err := strconv.ParseFloat(getEnv("DEFAULT_EXIT_FEE_RATE", "0.05"), 64)
+	if err != nil {
+		log.Fatalf("Invalid DEFAULT_EXIT_FEE_RATE: %v", err)
+	}
+	if exitFeeSetter, ok := contractMgr.(interface{ SetDefaultExitFeeRate(float64) }); ok {
+		exitFeeSetter.SetDefaultExitFeeRate(defaultExitFeeRate)
+	}
+	expiryNoticeBlocks, err := strconv.ParseUint(getEnv("EXPIRY_NOTICE_BLOCKS", "144"), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid EXPIRY_NOTICE_BLOCKS: %v", err)
+	}
+	if expiryNoticeSetter, ok := contractMgr.(interface{ SetExpiryNoticeBlocks(uint64) }); ok {
+		expiryNoticeSetter.SetExpiryNoticeBlocks(expiryNoticeBlocks)
+	}
+	timeoutExitBlocks, err := strconv.ParseUint(getEnv("TIMEOUT_EXIT_BLOCKS", "144"), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid TIMEOUT_EXIT_BLOCKS: %v", err)
+	}
+	sweepWindowBlocks, err := strconv.ParseUint(getEnv("SWEEP_WINDOW_BLOCKS", "144"), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid SWEEP_WINDOW_BLOCKS: %v", err)
+	}
+	exitTimeouts := hashperp.ExitTimeoutConfig{TimeoutExitBlocks: timeoutExitBlocks, SweepWindowBlocks: sweepWindowBlocks}
+	if err := exitTimeouts.Validate(); err != nil {
+		log.Fatalf("Invalid exit timeout windows: %v", err)
+	}
+	if exitTimeoutSetter, ok := contractMgr.(interface{ SetExitTimeoutConfig(hashperp.ExitTimeoutConfig) }); ok {
+		exitTimeoutSetter.SetExitTimeoutConfig(exitTimeouts)
+	}
+	payoutRoundingSats, err := strconv.ParseInt(getEnv("PAYOUT_ROUNDING_SATS", "1"), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid PAYOUT_ROUNDING_SATS: %v", err)
+	}
+	minPayoutSats, err := strconv.ParseInt(getEnv("MIN_PAYOUT_SATS", "546"), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid MIN_PAYOUT_SATS: %v", err)
+	}
+	payoutPolicy := hashperp.PayoutPolicy{RoundingSats: payoutRoundingSats, MinPayoutSats: minPayoutSats}
+	if err := payoutPolicy.Validate(); err != nil {
+		log.Fatalf("Invalid payout policy: %v", err)
+	}
+	if payoutPolicySetter, ok := contractMgr.(interface{ SetPayoutPolicy(hashperp.PayoutPolicy) }); ok {
+		payoutPolicySetter.SetPayoutPolicy(payoutPolicy)
+	}
+	// Settlements record a fiat reference price only when a currency is configured
+	if fiatCurrency := getEnv("SETTLEMENT_FIAT_CURRENCY", ""); fiatCurrency != "" {
+		if err := pricefeed.ValidateCurrency(fiatCurrency); err != nil {
+			log.Fatalf("Invalid SETTLEMENT_FIAT_CURRENCY: %v", err)
+		}
+		priceFeedTimeout, err := time.ParseDuration(getEnv("PRICE_FEED_TIMEOUT", "10s"))
+		if err != nil {
+			log.Fatalf("Invalid PRICE_FEED_TIMEOUT: %v", err)
+		}
+		priceProvider := pricefeed.NewCoinbasePriceProvider(getEnv("PRICE_FEED_URL", pricefeed.DefaultCoinbaseURL), priceFeedTimeout)
+		if priceProviderSetter, ok := contractMgr.(interface{ SetPriceProvider(hashperp.PriceProvider, string) }); ok {
+			priceProviderSetter.SetPriceProvider(priceProvider, fiatCurrency)
+		}
+	}
+	// Exit and settlement fees are only collected when a treasury account is configured
+	settlementFeeRate, err := strconv.ParseFloat(getEnv("SETTLEMENT_FEE_RATE", "0"), 64)
+	if err != nil {
+		log.Fatalf("Invalid SETTLEMENT_FEE_RATE: %v", err)
+	}
+	treasury := hashperp.TreasuryConfig{UserID: getEnv("TREASURY_USER_ID", ""), SettlementFeeRate: settlementFeeRate}
+	if err := treasury.Validate(); err != nil {
+		log.Fatalf("Invalid treasury config: %v", err)
+	}
+	if treasurySetter, ok := contractMgr.(interface{ SetTreasuryConfig(hashperp.TreasuryConfig) }); ok {
+		treasurySetter.SetTreasuryConfig(treasury)
+	}
+	// Wash positions are rejected unless the operator explicitly allows self trades
+	selfTradePolicy, err := hashperp.ParseSelfTradePolicy(getEnv("SELF_TRADE_POLICY", string(hashperp.SELF_TRADE_SKIP)))
+	if err != nil {
+		log.Fatalf("Invalid SELF_TRADE_POLICY: %v", err)
+	}
+	if selfContractSetter, ok := contractMgr.(interface{ SetAllowSelfContracts(bool) }); ok {
+		selfContractSetter.SetAllowSelfContracts(selfTradePolicy == hashperp.SELF_TRADE_ALLOW)
+	}
+	if userRepoSetter, ok := contractMgr.(interface{ SetUserRepository(hashperp.UserRepository) }); ok {
+		userRepoSetter.SetUserRepository(userRepo)
+	}
+	if templateRepoSetter, ok := contractMgr.(interface {
+		SetContractTemplateRepository(hashperp.ContractTemplateRepository)
+	}); ok {
+		templateRepoSetter.SetContractTemplateRepository(templateRepo)
+	}
+	if scriptRepoSetter, ok := contractMgr.(interface {
+		SetContractScriptRepository(hashperp.ContractScriptRepository)
+	}); ok {
+		scriptRepoSetter.SetContractScriptRepository(scriptRepo)
+	}
+	if hashRateRepoSetter, ok := contractMgr.(interface{ SetHashRateRepository(hashperp.HashRateRepository) }); ok {
+		hashRateRepoSetter.SetHashRateRepository(hashRateRepo)
+	}
 	
 	// Create order book manager
-	orderBookMgr := hashperp.NewOrderBookService(orderRepo, contractRepo, contractMgr, transactionRepo, btcClient)
+	orderBookMgr := hashperp.NewOrderBookService(orderRepo, contractRepo, contractMgr, transactionRepo, btcClient, snapshotRepo)
+	if selfTradeSetter, ok := orderBookMgr.(interface{ SetSelfTradePolicy(hashperp.SelfTradePolicy) }); ok {
+		selfTradeSetter.SetSelfTradePolicy(selfTradePolicy)
+	}
+	
+	// Cap what one user may have open at once, 0 disables a cap
+	maxOpenOrders, err := strconv.Atoi(getEnv("MAX_OPEN_ORDERS_PER_USER", "100"))
+	if err != nil || maxOpenOrders < 0 {
+		log.Fatalf("Invalid MAX_OPEN_ORDERS_PER_USER: %q", getEnv("MAX_OPEN_ORDERS_PER_USER", "100"))
+	}
+	if openOrdersSetter, ok := orderBookMgr.(interface{ SetMaxOpenOrdersPerUser(int) }); ok {
+		openOrdersSetter.SetMaxOpenOrdersPerUser(maxOpenOrders)
+	}
+	// Reject order strikes too far from the market, designated market makers are exempt
+	maxPriceDeviation, err := strconv.ParseFloat(getEnv("ORDER_PRICE_BAND", "0.5"), 64)
+	if err != nil {
+		log.Fatalf("Invalid ORDER_PRICE_BAND: %v", err)
+	}
+	priceBand := hashperp.PriceBandConfig{MaxDeviation: maxPriceDeviation}
+	for _, userID := range strings.Split(getEnv("PRICE_BAND_EXEMPT_USER_IDS", ""), ",") {
+		if userID = strings.TrimSpace(userID); userID != "" {
+			priceBand.MarketMakers = append(priceBand.MarketMakers, userID)
+		}
+	}
+	if err := priceBand.Validate(); err != nil {
+		log.Fatalf("Invalid price band: %v", err)
+	}
+	if priceBandSetter, ok := orderBookMgr.(interface{ SetPriceBandConfig(hashperp.PriceBandConfig) }); ok {
+		priceBandSetter.SetPriceBandConfig(priceBand)
+	}
+	maxOpenSwapOffers, err := strconv.Atoi(getEnv("MAX_OPEN_SWAP_OFFERS_PER_USER", "50"))
+	if err != nil || maxOpenSwapOffers < 0 {
+		log.Fatalf("Invalid MAX_OPEN_SWAP_OFFERS_PER_USER: %q", getEnv("MAX_OPEN_SWAP_OFFERS_PER_USER", "50"))
+	}
+	if openOffersSetter, ok := swapOfferMgr.(interface{ SetMaxOpenSwapOffersPerUser(int) }); ok {
+		openOffersSetter.SetMaxOpenSwapOffersPerUser(maxOpenSwapOffers)
+	}
+	
+	// Publish lifecycle events from the managers
+	setEventPublisher(eventPublisher, contractMgr, vtxoMgr, orderBookMgr, swapOfferMgr, webhookMgr, marketDataMgr)
+
+	// Start polling once every listener is wired, each new block streams hash rate data
+	if blockListener, ok := marketDataMgr.(interface{ HandleNewBlock(context.Context, uint64) }); ok {
+		blockHeightPoller.OnNewBlock(blockListener.HandleNewBlock)
+	}
+	blockHeightPoller.Start()
+	
+	// Record Prometheus metrics from the managers
+	metricsRecorder := metrics.NewPrometheusRecorder()
+	for _, mgr := range []interface{}{contractMgr, vtxoMgr, orderBookMgr} {
+		if metricsSetter, ok := mgr.(interface{ SetMetricsRecorder(hashperp.MetricsRecorder) }); ok {
+			metricsSetter.SetMetricsRecorder(metricsRecorder)
+		}
+	}
 	
 	// Create the main service
 	service := hashperp.NewHashPerpService(
@@ -89,12 +352,117 @@ func main() {
 		swapOfferMgr,
 		marketDataMgr,
 		transactionMgr,
+		webhookMgr,
 		scriptGen,
 		btcClient,
 	)
+	if durationSetter, ok := service.(interface{ SetDurationConfig(hashperp.DurationConfig) }); ok {
+		durationSetter.SetDurationConfig(durations)
+	}
+	if pingerSetter, ok := service.(interface{ SetDatabasePinger(hashperp.DatabasePinger) }); ok {
+		pingerSetter.SetDatabasePinger(storage.NewPostgresPinger(db))
+	}
+	if userRepoSetter, ok := service.(interface{ SetUserRepository(hashperp.UserRepository) }); ok {
+		userRepoSetter.SetUserRepository(userRepo)
+	}
+	if contractRepoSetter, ok := service.(interface{ SetContractRepository(hashperp.ContractRepository) }); ok {
+		contractRepoSetter.SetContractRepository(contractRepo)
+	}
+	if transactionRepoSetter, ok := service.(interface{ SetTransactionRepository(hashperp.TransactionRepository) }); ok {
+		transactionRepoSetter.SetTransactionRepository(transactionRepo)
+	}
+	if orderRepoSetter, ok := service.(interface{ SetOrderRepository(hashperp.OrderRepository) }); ok {
+		orderRepoSetter.SetOrderRepository(orderRepo)
+	}
+	if swapOfferRepoSetter, ok := service.(interface{ SetSwapOfferRepository(hashperp.SwapOfferRepository) }); ok {
+		swapOfferRepoSetter.SetSwapOfferRepository(swapOfferRepo)
+	}
+	if statusChangeRepoSetter, ok := service.(interface {
+		SetStatusChangeRepository(hashperp.StatusChangeRepository)
+	}); ok {
+		statusChangeRepoSetter.SetStatusChangeRepository(statusChangeRepo)
+	}
+	transactionConfirmations, err := strconv.ParseUint(getEnv("TRANSACTION_CONFIRMATIONS", "6"), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid TRANSACTION_CONFIRMATIONS: %v", err)
+	}
+	if confirmationsSetter, ok := service.(interface{ SetTransactionConfirmations(uint64) }); ok {
+		confirmationsSetter.SetTransactionConfirmations(transactionConfirmations)
+	}
+	// Only operators may run settlement and maintenance methods through the API
+	var operators []string
+	for _, userID := range strings.Split(getEnv("OPERATOR_USER_IDS", ""), ",") {
+		if userID = strings.TrimSpace(userID); userID != "" {
+			operators = append(operators, userID)
+		}
+	}
+	if operatorsSetter, ok := service.(interface{ SetOperators([]string) }); ok {
+		operatorsSetter.SetOperators(operators)
+	}
+	
+	// Start background maintenance jobs
+	offerCleanupInterval, err := time.ParseDuration(getEnv("OFFER_CLEANUP_INTERVAL", "1m"))
+	if err != nil {
+		log.Fatalf("Invalid OFFER_CLEANUP_INTERVAL: %v", err)
+	}
+	orderExpiryInterval, err := time.ParseDuration(getEnv("ORDER_EXPIRY_INTERVAL", "1m"))
+	if err != nil {
+		log.Fatalf("Invalid ORDER_EXPIRY_INTERVAL: %v", err)
+	}
+	settlementInterval, err := time.ParseDuration(getEnv("SETTLEMENT_INTERVAL", "5m"))
+	if err != nil {
+		log.Fatalf("Invalid SETTLEMENT_INTERVAL: %v", err)
+	}
+	expiryNoticeInterval, err := time.ParseDuration(getEnv("EXPIRY_NOTICE_INTERVAL", "5m"))
+	if err != nil {
+		log.Fatalf("Invalid EXPIRY_NOTICE_INTERVAL: %v", err)
+	}
+	webhookInterval, err := time.ParseDuration(getEnv("WEBHOOK_DELIVERY_INTERVAL", "15s"))
+	if err != nil {
+		log.Fatalf("Invalid WEBHOOK_DELIVERY_INTERVAL: %v", err)
+	}
+	metricsInterval, err := time.ParseDuration(getEnv("METRICS_INTERVAL", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid METRICS_INTERVAL: %v", err)
+	}
+	confirmationInterval, err := time.ParseDuration(getEnv("CONFIRMATION_INTERVAL", "1m"))
+	if err != nil {
+		log.Fatalf("Invalid CONFIRMATION_INTERVAL: %v", err)
+	}
+	statusMetrics := hashperp.NewStatusMetricsCollector(contractRepo, orderRepo, swapOfferRepo, metricsRecorder)
+	sweepReconcileInterval, err := time.ParseDuration(getEnv("SWEEP_RECONCILE_INTERVAL", "5m"))
+	if err != nil {
+		log.Fatalf("Invalid SWEEP_RECONCILE_INTERVAL: %v", err)
+	}
+	confirmer := hashperp.NewTransactionConfirmer(transactionRepo, btcClient, transactionConfirmations)
+	scheduler := hashperp.NewScheduler(contractMgr, vtxoMgr, orderBookMgr, swapOfferMgr, webhookMgr, statusMetrics, confirmer, hashperp.SchedulerConfig{
+		OfferCleanupInterval:   offerCleanupInterval,
+		OrderExpiryInterval:    orderExpiryInterval,
+		SettlementInterval:     settlementInterval,
+		ExpiryNoticeInterval:   expiryNoticeInterval,
+		WebhookInterval:        webhookInterval,
+		MetricsInterval:        metricsInterval,
+		ConfirmationInterval:   confirmationInterval,
+		SweepReconcileInterval: sweepReconcileInterval,
+	})
+	setEventPublisher(eventPublisher, confirmer, scheduler)
+	scheduler.Start()
 	
 	// Initialize API server
-	apiServer := api.NewServer(service)
+	var authenticator api.Authenticator
+	if secret := getEnv("API_AUTH_SECRET", ""); secret != "" {
+		authenticator = api.NewTokenAuthenticator([]byte(secret))
+	} else {
+		insecureDevMode, err := strconv.ParseBool(getEnv("API_INSECURE_DEV_MODE", "false"))
+		if err != nil {
+			log.Fatalf("Invalid API_INSECURE_DEV_MODE: %v", err)
+		}
+		if !insecureDevMode {
+			log.Fatalf("API_AUTH_SECRET is required, set API_INSECURE_DEV_MODE=true to run without authentication")
+		}
+		log.Printf("WARNING: API_INSECURE_DEV_MODE is set, every API request runs as an internal caller")
+	}
+	apiServer := api.NewServer(service, authenticator, metricsRecorder, metricsRecorder.Handler())
 	
 	// Start API server
 	go func() {
@@ -116,6 +484,10 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 	
+	// Stop background maintenance jobs
+	scheduler.Stop()
+	blockHeightPoller.Stop()
+	
 	// Shutdown API server
 	if err := apiServer.Shutdown(ctx); err != nil {
 		log.Fatalf("API server shutdown error: %v", err)
@@ -159,14 +531,84 @@ func connectToDatabase() (*gorm.DB, error) {
 	return db, nil
 }
 
-// initializeBitcoinClient creates and configures the Bitcoin client
-func initializeBitcoinClient() (hashperp.BitcoinClient, error) {
+// initializeBitcoinClient creates and configures the Bitcoin client. BITCOIN_MODE=mock runs
+// against an in-memory chain instead of a node, for local development and tests only.
+// The node must be on network, a mismatch fails startup.
+func initializeBitcoinClient(network hashperp.Network) (hashperp.BitcoinClient, error) {
+	if getEnv("BITCOIN_MODE", "rpc") == "mock" {
+		blockHeight, err := strconv.ParseUint(getEnv("MOCK_BLOCK_HEIGHT", "840000"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MOCK_BLOCK_HEIGHT: %w", err)
+		}
+		log.Printf("Using mock Bitcoin client at block height %d, do not use in production", blockHeight)
+		return bitcoin.NewMockBitcoinClient(blockHeight), nil
+	}
+
 	rpcURL := getEnv("BITCOIN_RPC_URL", "http://localhost:8332")
 	rpcUser := getEnv("BITCOIN_RPC_USER", "bitcoinrpc")
 	rpcPassword := getEnv("BITCOIN_RPC_PASSWORD", "")
 	
 	// Create Bitcoin client
-	return bitcoin.NewBitcoinClient(rpcURL, rpcUser, rpcPassword)
+	client, err := bitcoin.NewBitcoinClient(rpcURL, rpcUser, rpcPassword, network)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bound each RPC call, slow methods such as verifychain get their own longer timeout
+	defaultTimeout, err := time.ParseDuration(getEnv("BITCOIN_RPC_TIMEOUT", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BITCOIN_RPC_TIMEOUT: %w", err)
+	}
+	methodTimeouts, err := bitcoin.ParseMethodTimeouts(getEnv("BITCOIN_RPC_METHOD_TIMEOUTS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BITCOIN_RPC_METHOD_TIMEOUTS: %w", err)
+	}
+	if timeoutSetter, ok := client.(interface {
+		SetCallTimeouts(time.Duration, map[string]time.Duration)
+	}); ok {
+		timeoutSetter.SetCallTimeouts(defaultTimeout, methodTimeouts)
+	}
+
+	// Retry transient failures such as a restarting node, deterministic RPC errors fail at once
+	maxRetries, err := strconv.Atoi(getEnv("BITCOIN_RPC_MAX_RETRIES", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BITCOIN_RPC_MAX_RETRIES: %w", err)
+	}
+	initialBackoff, err := time.ParseDuration(getEnv("BITCOIN_RPC_RETRY_BACKOFF", "500ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BITCOIN_RPC_RETRY_BACKOFF: %w", err)
+	}
+	maxBackoff, err := time.ParseDuration(getEnv("BITCOIN_RPC_MAX_BACKOFF", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BITCOIN_RPC_MAX_BACKOFF: %w", err)
+	}
+	retryPolicy := bitcoin.RetryPolicy{MaxRetries: maxRetries, InitialBackoff: initialBackoff, MaxBackoff: maxBackoff}
+	if err := retryPolicy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid Bitcoin RPC retry policy: %w", err)
+	}
+	if retrySetter, ok := client.(interface{ SetRetryPolicy(bitcoin.RetryPolicy) }); ok {
+		retrySetter.SetRetryPolicy(retryPolicy)
+	}
+
+	// Refuse to start against a node on another network
+	if networkVerifier, ok := client.(interface{ VerifyNetwork(context.Context) error }); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := networkVerifier.VerifyNetwork(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// setEventPublisher sets the event publisher of every component that accepts one
+func setEventPublisher(publisher hashperp.EventPublisher, components ...interface{}) {
+	for _, component := range components {
+		if publisherSetter, ok := component.(interface{ SetEventPublisher(hashperp.EventPublisher) }); ok {
+			publisherSetter.SetEventPublisher(publisher)
+		}
+	}
 }
 
 // getEnv retrieves an environment variable or returns a default value
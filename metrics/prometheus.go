@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hashperp/hashperp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusRecorder implements the MetricsRecorder interface with Prometheus collectors
+type PrometheusRecorder struct {
+	registry *prometheus.Registry
+
+	contractsCreated  *prometheus.CounterVec
+	settlements       *prometheus.CounterVec
+	matchRuns         *prometheus.CounterVec
+	contractsMatched  prometheus.Counter
+	vtxoSwaps         *prometheus.CounterVec
+	rpcDuration       *prometheus.HistogramVec
+	contractsByStatus *prometheus.GaugeVec
+	ordersByStatus    *prometheus.GaugeVec
+	offersByStatus    *prometheus.GaugeVec
+}
+
+// NewPrometheusRecorder creates a recorder whose metrics are registered on their own registry
+func NewPrometheusRecorder() *PrometheusRecorder {
+	r := &PrometheusRecorder{
+		registry: prometheus.NewRegistry(),
+		contractsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hashperp_contracts_created_total",
+			Help: "Contract creation attempts by contract type and outcome.",
+		}, []string{"contract_type", "outcome"}),
+		settlements: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hashperp_settlements_total",
+			Help: "Settlement attempts by contract type and outcome.",
+		}, []string{"contract_type", "outcome"}),
+		matchRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hashperp_order_match_runs_total",
+			Help: "Order matching runs by outcome.",
+		}, []string{"outcome"}),
+		contractsMatched: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "hashperp_orders_matched_contracts_total",
+			Help: "Contracts created by order matching.",
+		}),
+		vtxoSwaps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hashperp_vtxo_swaps_total",
+			Help: "VTXO swap attempts by outcome.",
+		}, []string{"outcome"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "hashperp_rpc_duration_seconds",
+			Help:    "RPC call latency by method and outcome.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "outcome"}),
+		contractsByStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hashperp_contracts",
+			Help: "Number of contracts by status.",
+		}, []string{"status"}),
+		ordersByStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hashperp_orders",
+			Help: "Number of orders by status.",
+		}, []string{"status"}),
+		offersByStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "hashperp_swap_offers",
+			Help: "Number of swap offers by status.",
+		}, []string{"status"}),
+	}
+
+	r.registry.MustRegister(
+		r.contractsCreated,
+		r.settlements,
+		r.matchRuns,
+		r.contractsMatched,
+		r.vtxoSwaps,
+		r.rpcDuration,
+		r.contractsByStatus,
+		r.ordersByStatus,
+		r.offersByStatus,
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+
+	return r
+}
+
+// Handler returns the HTTP handler that serves the metrics in the Prometheus text format
+func (r *PrometheusRecorder) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
+
+// ContractCreated implements MetricsRecorder.ContractCreated
+func (r *PrometheusRecorder) ContractCreated(contractType hashperp.ContractType, outcome string) {
+	r.contractsCreated.WithLabelValues(string(contractType), outcome).Inc()
+}
+
+// ContractSettlement implements MetricsRecorder.ContractSettlement
+func (r *PrometheusRecorder) ContractSettlement(contractType hashperp.ContractType, outcome string) {
+	r.settlements.WithLabelValues(string(contractType), outcome).Inc()
+}
+
+// OrdersMatched implements MetricsRecorder.OrdersMatched
+func (r *PrometheusRecorder) OrdersMatched(outcome string, contracts int) {
+	r.matchRuns.WithLabelValues(outcome).Inc()
+	r.contractsMatched.Add(float64(contracts))
+}
+
+// VTXOSwapped implements MetricsRecorder.VTXOSwapped
+func (r *PrometheusRecorder) VTXOSwapped(outcome string) {
+	r.vtxoSwaps.WithLabelValues(outcome).Inc()
+}
+
+// RPCCall implements MetricsRecorder.RPCCall
+func (r *PrometheusRecorder) RPCCall(method string, outcome string, duration time.Duration) {
+	r.rpcDuration.WithLabelValues(method, outcome).Observe(duration.Seconds())
+}
+
+// SetContractsByStatus implements MetricsRecorder.SetContractsByStatus
+func (r *PrometheusRecorder) SetContractsByStatus(counts map[hashperp.ContractStatus]int) {
+	r.contractsByStatus.Reset()
+	for status, count := range counts {
+		r.contractsByStatus.WithLabelValues(string(status)).Set(float64(count))
+	}
+}
+
+// SetOrdersByStatus implements MetricsRecorder.SetOrdersByStatus
+func (r *PrometheusRecorder) SetOrdersByStatus(counts map[hashperp.OrderStatus]int) {
+	r.ordersByStatus.Reset()
+	for status, count := range counts {
+		r.ordersByStatus.WithLabelValues(string(status)).Set(float64(count))
+	}
+}
+
+// SetSwapOffersByStatus implements MetricsRecorder.SetSwapOffersByStatus
+func (r *PrometheusRecorder) SetSwapOffersByStatus(counts map[hashperp.SwapOfferStatus]int) {
+	r.offersByStatus.Reset()
+	for status, count := range counts {
+		r.offersByStatus.WithLabelValues(string(status)).Set(float64(count))
+	}
+}
@@ -0,0 +1,97 @@
+package pricefeed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCoinbaseURL is the Coinbase API spot prices are read from when no URL is configured
+const DefaultCoinbaseURL = "https://api.coinbase.com"
+
+// CoinbasePriceProvider implements the PriceProvider interface with the Coinbase spot price API
+type CoinbasePriceProvider struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewCoinbasePriceProvider creates a provider reading spot prices from the Coinbase API at
+// baseURL, each request bounded by timeout
+func NewCoinbasePriceProvider(baseURL string, timeout time.Duration) *CoinbasePriceProvider {
+	return &CoinbasePriceProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// ValidateCurrency checks that a currency is a three letter ISO 4217 code such as "USD"
+func ValidateCurrency(currency string) error {
+	if len(currency) != 3 {
+		return fmt.Errorf("currency must be a three letter code, got %q", currency)
+	}
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return fmt.Errorf("currency must be a three letter upper case code, got %q", currency)
+		}
+	}
+	return nil
+}
+
+// coinbaseSpotPrice is the response of the spot price endpoint
+type coinbaseSpotPrice struct {
+	Data struct {
+		Amount   string `json:"amount"`
+		Base     string `json:"base"`
+		Currency string `json:"currency"`
+	} `json:"data"`
+}
+
+// GetBTCPrice implements PriceProvider.GetBTCPrice
+func (p *CoinbasePriceProvider) GetBTCPrice(ctx context.Context, currency string) (float64, error) {
+	if err := ValidateCurrency(currency); err != nil {
+		return 0, err
+	}
+
+	// 1. Request the spot price of the pair
+	endpoint := p.baseURL + "/v2/prices/" + url.PathEscape("BTC-"+currency) + "/spot"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create price request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get BTC-%s spot price: %w", currency, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to get BTC-%s spot price: unexpected status %s", currency, resp.Status)
+	}
+
+	// 2. Make sure the response quotes the pair that was asked for
+	var spot coinbaseSpotPrice
+	if err := json.NewDecoder(resp.Body).Decode(&spot); err != nil {
+		return 0, fmt.Errorf("failed to decode BTC-%s spot price: %w", currency, err)
+	}
+	if spot.Data.Base != "BTC" || spot.Data.Currency != currency {
+		return 0, fmt.Errorf("spot price is for %s-%s, requested BTC-%s", spot.Data.Base, spot.Data.Currency, currency)
+	}
+
+	// 3. Parse the price, amounts are decimal strings
+	price, err := strconv.ParseFloat(spot.Data.Amount, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid BTC-%s spot price %q: %w", currency, spot.Data.Amount, err)
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("invalid BTC-%s spot price %q: must be positive", currency, spot.Data.Amount)
+	}
+
+	return price, nil
+}
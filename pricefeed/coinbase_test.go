@@ -0,0 +1,50 @@
+package pricefeed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCoinbasePriceProviderGetBTCPrice(t *testing.T) {
+	tests := []struct {
+		name      string
+		currency  string
+		status    int
+		body      string
+		wantPrice float64
+		wantErr   bool
+	}{
+		{"spot price", "USD", http.StatusOK, `{"data":{"amount":"65432.10","base":"BTC","currency":"USD"}}`, 65432.10, false},
+		{"other currency", "EUR", http.StatusOK, `{"data":{"amount":"60000","base":"BTC","currency":"EUR"}}`, 60000, false},
+		{"lower case currency", "usd", http.StatusOK, `{"data":{"amount":"65432.10","base":"BTC","currency":"USD"}}`, 0, true},
+		{"unknown currency", "XXX", http.StatusNotFound, `{"errors":[{"id":"not_found"}]}`, 0, true},
+		{"different pair", "USD", http.StatusOK, `{"data":{"amount":"65432.10","base":"BTC","currency":"EUR"}}`, 0, true},
+		{"malformed amount", "USD", http.StatusOK, `{"data":{"amount":"n/a","base":"BTC","currency":"USD"}}`, 0, true},
+		{"zero amount", "USD", http.StatusOK, `{"data":{"amount":"0","base":"BTC","currency":"USD"}}`, 0, true},
+		{"malformed body", "USD", http.StatusOK, `not json`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if want := "/v2/prices/BTC-" + tt.currency + "/spot"; r.URL.Path != want {
+					t.Errorf("request path = %s, want %s", r.URL.Path, want)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			price, err := NewCoinbasePriceProvider(server.URL, time.Second).GetBTCPrice(context.Background(), tt.currency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBTCPrice(%s) error = %v, wantErr %t", tt.currency, err, tt.wantErr)
+			}
+			if price != tt.wantPrice {
+				t.Errorf("GetBTCPrice(%s) = %v, want %v", tt.currency, price, tt.wantPrice)
+			}
+		})
+	}
+}
@@ -5,13 +5,24 @@ import (
 	"time"
 )
 
-// BitcoinClient defines the interface for interacting with the Bitcoin network
+// BitcoinClient defines the interface for interacting with the Bitcoin network. A call the
+// node does not answer in time fails with ErrNodeTimeout and may be retried.
 type BitcoinClient interface {
 	// GetCurrentBlockHeight returns the current block height of the Bitcoin network
 	GetCurrentBlockHeight(ctx context.Context) (uint64, error)
 	
-	// GetBlockHashRate returns the estimated hash rate at a specific block height
-	GetBlockHashRate(ctx context.Context, blockHeight uint64) (float64, error)
+	// GetBlockHashRate returns the estimated hash rate at a specific block height.
+	// It fails with ErrBlockNotFinal unless the block has at least minConfirmations confirmations.
+	GetBlockHashRate(ctx context.Context, blockHeight uint64, minConfirmations uint64) (float64, error)
+	
+	// GetNetworkHashRate returns the average hash rate the network achieved over the
+	// windowBlocks blocks ending at endBlockHeight, from their work and timestamps. It fails
+	// with ErrBlockNotFinal unless the end block has at least minConfirmations confirmations.
+	GetNetworkHashRate(ctx context.Context, windowBlocks uint64, endBlockHeight uint64, minConfirmations uint64) (float64, error)
+	
+	// EnsureBlockConfirmed returns the hash of the block at a height once it has at least
+	// minConfirmations confirmations, and fails with ErrBlockNotFinal otherwise
+	EnsureBlockConfirmed(ctx context.Context, blockHeight uint64, minConfirmations uint64) (string, error)
 	
 	// BroadcastTransaction broadcasts a raw transaction to the Bitcoin network
 	BroadcastTransaction(ctx context.Context, txHex string) (string, error)
@@ -24,6 +35,65 @@ type BitcoinClient interface {
 	
 	// EstimateNetworkDifficulty estimates the current network difficulty
 	EstimateNetworkDifficulty(ctx context.Context) (float64, error)
+	
+	// IsInitialBlockDownload reports whether the node is still in initial block download
+	IsInitialBlockDownload(ctx context.Context) (bool, error)
+	
+	// GetNetworkFeeEstimate estimates the fee rate in satoshis per byte for a transaction
+	// to confirm within targetConfirmations blocks
+	GetNetworkFeeEstimate(ctx context.Context, targetConfirmations int) (uint64, error)
+	
+	// GetTransactionConfirmations returns the number of confirmations of a transaction.
+	// It fails with ErrTransactionConflicted if a conflicting transaction was mined instead,
+	// and with ErrTransactionUnknown if the node has no record of the transaction.
+	GetTransactionConfirmations(ctx context.Context, txHash string) (uint64, error)
+}
+
+// MetricsRecorder records operational metrics. Outcomes are OUTCOME_* labels.
+type MetricsRecorder interface {
+	// ContractCreated counts a CreateContract call
+	ContractCreated(contractType ContractType, outcome string)
+	
+	// ContractSettlement counts a SettleContract call
+	ContractSettlement(contractType ContractType, outcome string)
+	
+	// OrdersMatched counts a MatchOrders run and the contracts it created
+	OrdersMatched(outcome string, contracts int)
+	
+	// VTXOSwapped counts a SwapVTXO call
+	VTXOSwapped(outcome string)
+	
+	// RPCCall observes the latency of an RPC method call
+	RPCCall(method string, outcome string, duration time.Duration)
+	
+	// SetContractsByStatus records the number of contracts in each status
+	SetContractsByStatus(counts map[ContractStatus]int)
+	
+	// SetOrdersByStatus records the number of orders in each status
+	SetOrdersByStatus(counts map[OrderStatus]int)
+	
+	// SetSwapOffersByStatus records the number of swap offers in each status
+	SetSwapOffersByStatus(counts map[SwapOfferStatus]int)
+}
+
+// DatabasePinger checks that the database is reachable
+type DatabasePinger interface {
+	// Ping verifies the database connection is alive
+	Ping(ctx context.Context) error
+}
+
+// PriceProvider supplies BTC reference prices in fiat currencies
+type PriceProvider interface {
+	// GetBTCPrice returns the price of one BTC in the given currency code (e.g. "USD").
+	// A zero price means no reference price is available.
+	GetBTCPrice(ctx context.Context, currency string) (float64, error)
+}
+
+// EventPublisher delivers lifecycle events to downstream integrations such as webhooks or
+// message queues. Publish must not block the caller for long; delivery failures are the
+// publisher's to handle.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event)
 }
 
 // ContractRepository defines the data access interface for contracts
@@ -31,22 +101,61 @@ type ContractRepository interface {
 	// Create creates a new contract
 	Create(ctx context.Context, contract *Contract) error
 	
-	// FindByID retrieves a contract by ID
+	// FindByID retrieves a contract by ID, excluding archived contracts
 	FindByID(ctx context.Context, id string) (*Contract, error)
 	
-	// FindByUser retrieves all contracts for a specific user
-	FindByUser(ctx context.Context, userID string, status []ContractStatus) ([]*Contract, error)
+	// FindByIDIncludingArchived retrieves a contract by ID, including archived contracts
+	FindByIDIncludingArchived(ctx context.Context, id string) (*Contract, error)
+	
+	// FindByIdempotencyKey retrieves the contract created with a client-supplied idempotency key
+	FindByIdempotencyKey(ctx context.Context, idempotencyKey string) (*Contract, error)
+	
+	// FindByUser retrieves a page of contracts for a specific user, returning the cursor of the next page.
+	// Archived contracts are only included if includeArchived is set.
+	FindByUser(ctx context.Context, userID string, status []ContractStatus, includeArchived bool, page PageRequest) ([]*Contract, string, error)
+	
+	// Search retrieves a page of contracts matching every criterion of a filter, returning
+	// the cursor of the next page
+	Search(ctx context.Context, filter ContractFilter, page PageRequest) ([]*Contract, string, error)
+	
+	// FindByStatus retrieves all contracts with the given status
+	FindByStatus(ctx context.Context, status ContractStatus) ([]*Contract, error)
+	
+	// CountByStatus returns the number of contracts in each status
+	CountByStatus(ctx context.Context) (map[ContractStatus]int, error)
+	
+	// SummarizeSettlementsByExpiry aggregates SETTLED contracts expiring within a block height
+	// range per expiry block, ordered by expiry. An empty contract type matches every type.
+	SummarizeSettlementsByExpiry(ctx context.Context, contractType ContractType, fromHeight, toHeight uint64) ([]*SettlementHistoryPoint, error)
+	
+	// SumActiveByType returns the number and total size of active contracts, including
+	// those close to expiry, for each contract type
+	SumActiveByType(ctx context.Context) (map[ContractType]ContractTotals, error)
+	
+	// SummarizeOpenInterest aggregates active contracts, including those close to expiry, per
+	// expiry block and strike rate, ordered by expiry then strike. An empty contract type
+	// matches every type.
+	SummarizeOpenInterest(ctx context.Context, contractType ContractType) ([]*OpenInterestBucket, error)
 	
-	// FindActiveContracts retrieves all active contracts
+	// FindActiveContracts retrieves all active contracts, including those close to expiry
 	FindActiveContracts(ctx context.Context) ([]*Contract, error)
 	
-	// FindByExpiryRange retrieves contracts expiring within a certain block height range
+	// FindActiveByUser retrieves every active contract, including those close to expiry, in
+	// which a user is a founding party or pool member
+	FindActiveByUser(ctx context.Context, userID string) ([]*Contract, error)
+	
+	// CountActiveContracts returns the number of active contracts, including those close to expiry
+	CountActiveContracts(ctx context.Context) (int, error)
+	
+	// FindByExpiryRange retrieves active contracts, including those close to expiry, expiring
+	// within a certain block height range
 	FindByExpiryRange(ctx context.Context, fromHeight, toHeight uint64) ([]*Contract, error)
 	
-	// Update updates an existing contract
+	// Update updates an existing contract if it is still at the version it was read at and
+	// increments the version, returning ErrConcurrentModification if another write got there first
 	Update(ctx context.Context, contract *Contract) error
 	
-	// Delete deletes a contract by ID
+	// Delete archives a contract by ID. Archived contracts stay resolvable for the audit trail.
 	Delete(ctx context.Context, id string) error
 }
 
@@ -55,22 +164,30 @@ type VTXORepository interface {
 	// Create creates a new VTXO
 	Create(ctx context.Context, vtxo *VTXO) error
 	
-	// FindByID retrieves a VTXO by ID
+	// FindByID retrieves a VTXO by ID, excluding archived VTXOs
 	FindByID(ctx context.Context, id string) (*VTXO, error)
 	
+	// FindByIDIncludingArchived retrieves a VTXO by ID, including archived VTXOs
+	FindByIDIncludingArchived(ctx context.Context, id string) (*VTXO, error)
+	
 	// FindByContract retrieves all VTXOs for a specific contract
 	FindByContract(ctx context.Context, contractID string) ([]*VTXO, error)
 	
-	// FindByUser retrieves all VTXOs for a specific user
-	FindByUser(ctx context.Context, userID string, onlyActive bool) ([]*VTXO, error)
+	// FindActiveByOwnerAndContract retrieves the most recent active VTXO a user owns in a contract
+	FindActiveByOwnerAndContract(ctx context.Context, contractID string, ownerID string) (*VTXO, error)
+	
+	// FindByUser retrieves a page of VTXOs for a specific user, returning the cursor of the next page.
+	// Archived VTXOs are only included if includeArchived is set.
+	FindByUser(ctx context.Context, userID string, onlyActive bool, includeArchived bool, page PageRequest) ([]*VTXO, string, error)
 	
 	// FindActiveVTXOs retrieves all active VTXOs
 	FindActiveVTXOs(ctx context.Context) ([]*VTXO, error)
 	
-	// Update updates an existing VTXO
+	// Update updates an existing VTXO if it is still at the version it was read at and
+	// increments the version, returning ErrConcurrentModification if another write got there first
 	Update(ctx context.Context, vtxo *VTXO) error
 	
-	// Delete deletes a VTXO by ID
+	// Delete archives a VTXO by ID. Archived VTXOs stay resolvable for the audit trail.
 	Delete(ctx context.Context, id string) error
 }
 
@@ -85,15 +202,41 @@ type OrderRepository interface {
 	// FindByUser retrieves all orders for a specific user
 	FindByUser(ctx context.Context, userID string, status []OrderStatus) ([]*Order, error)
 	
-	// FindByContractType retrieves orders for a specific contract type and expiry
+	// CountOpenByUser returns the number of OPEN orders of a specific user
+	CountOpenByUser(ctx context.Context, userID string) (int, error)
+	
+	// NextBookSequence advances the order book sequence of a market and returns the new value
+	NextBookSequence(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) (uint64, error)
+	
+	// GetBookSequence returns the current order book sequence of a market, 0 if it never changed
+	GetBookSequence(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) (uint64, error)
+	
+	// FindByClientOrderID retrieves a user's order by its client-supplied ID
+	FindByClientOrderID(ctx context.Context, userID string, clientOrderID string) (*Order, error)
+	
+	// FindByContractType retrieves the open orders for a specific contract type and expiry,
+	// best price first on each side (highest buy, lowest sell) and oldest first at equal prices
 	FindByContractType(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) ([]*Order, error)
 	
+	// FindBestLevel retrieves the best price level of one side of a market's open orders, the
+	// highest buy or lowest sell strike with its total size, nil when that side is empty
+	FindBestLevel(ctx context.Context, contractType ContractType, expiryBlockHeight uint64, orderType OrderType) (*OrderBookLevel, error)
+	
 	// FindOpenOrders retrieves all open orders
 	FindOpenOrders(ctx context.Context) ([]*Order, error)
 	
+	// CountOpenOrders returns the number of open orders
+	CountOpenOrders(ctx context.Context) (int, error)
+	
+	// FindExpiredOpenOrders retrieves open orders whose expiry block height is at or below the given height
+	FindExpiredOpenOrders(ctx context.Context, currentBlockHeight uint64) ([]*Order, error)
+	
 	// FindMatchingOrders finds orders that could potentially match with the given one
 	FindMatchingOrders(ctx context.Context, orderID string) ([]*Order, error)
 	
+	// CountByStatus returns the number of orders in each status
+	CountByStatus(ctx context.Context) (map[OrderStatus]int, error)
+	
 	// Update updates an existing order
 	Update(ctx context.Context, order *Order) error
 	
@@ -109,15 +252,29 @@ type SwapOfferRepository interface {
 	// FindByID retrieves a swap offer by ID
 	FindByID(ctx context.Context, id string) (*SwapOffer, error)
 	
-	// FindByUser retrieves all swap offers for a specific user
-	FindByUser(ctx context.Context, userID string, isOfferor bool) ([]*SwapOffer, error)
+	// FindByUser retrieves all swap offers for a specific user. An empty status list returns every status.
+	FindByUser(ctx context.Context, userID string, isOfferor bool, status []SwapOfferStatus) ([]*SwapOffer, error)
 	
-	// FindByContract retrieves all swap offers for a specific contract
-	FindByContract(ctx context.Context, contractID string) ([]*SwapOffer, error)
+	// FindByContract retrieves a page of swap offers for a specific contract, returning the cursor of the next page.
+	// An empty status list returns every status.
+	FindByContract(ctx context.Context, contractID string, status []SwapOfferStatus, page PageRequest) ([]*SwapOffer, string, error)
 	
 	// FindOpenOffersByVTXO retrieves all open swap offers for a specific VTXO
 	FindOpenOffersByVTXO(ctx context.Context, vtxoID string) ([]*SwapOffer, error)
 	
+	// CountOpenByOfferor returns the number of OPEN swap offers made by a specific user
+	CountOpenByOfferor(ctx context.Context, offerorID string) (int, error)
+	
+	// CountOffersByStatus returns the number of swap offers with the given status
+	CountOffersByStatus(ctx context.Context, status SwapOfferStatus) (int, error)
+	
+	// ExpireOffers marks every open swap offer whose expiry time is before asOf as expired,
+	// returning the number of offers updated
+	ExpireOffers(ctx context.Context, asOf time.Time) (int, error)
+	
+	// CountByStatus returns the number of swap offers in each status
+	CountByStatus(ctx context.Context) (map[SwapOfferStatus]int, error)
+	
 	// Update updates an existing swap offer
 	Update(ctx context.Context, offer *SwapOffer) error
 	
@@ -133,11 +290,15 @@ type TransactionRepository interface {
 	// FindByID retrieves a transaction by ID
 	FindByID(ctx context.Context, id string) (*Transaction, error)
 	
-	// FindByUser retrieves all transactions for a specific user
-	FindByUser(ctx context.Context, userID string, types []TransactionType) ([]*Transaction, error)
+	// FindByTxHash retrieves the transaction recorded for an on-chain transaction hash
+	FindByTxHash(ctx context.Context, txHash string) (*Transaction, error)
+	
+	// FindByUser retrieves a page of transactions for a specific user, returning the cursor of the next page
+	FindByUser(ctx context.Context, userID string, types []TransactionType, page PageRequest) ([]*Transaction, string, error)
 	
-	// FindByContract retrieves all transactions for a specific contract
-	FindByContract(ctx context.Context, contractID string) ([]*Transaction, error)
+	// FindByContract retrieves the transactions of a specific contract ordered by timestamp.
+	// An empty types list matches every type.
+	FindByContract(ctx context.Context, contractID string, types []TransactionType, order SortOrder) ([]*Transaction, error)
 	
 	// FindByType retrieves all transactions of a specific type
 	FindByType(ctx context.Context, transactionType TransactionType) ([]*Transaction, error)
@@ -145,6 +306,21 @@ type TransactionRepository interface {
 	// FindByTimeRange retrieves all transactions within a time range
 	FindByTimeRange(ctx context.Context, startTime, endTime time.Time) ([]*Transaction, error)
 	
+	// FindByBlockRange retrieves all transactions recorded at a block height within an
+	// inclusive range, ordered by block height and then timestamp
+	FindByBlockRange(ctx context.Context, fromHeight, toHeight uint64) ([]*Transaction, error)
+	
+	// FindByTypeAndStatus retrieves all transactions of a specific type in a status
+	FindByTypeAndStatus(ctx context.Context, transactionType TransactionType, status string) ([]*Transaction, error)
+	
+	// SumAmountSince returns the number and total amount of transactions of a type recorded
+	// at or after a time
+	SumAmountSince(ctx context.Context, transactionType TransactionType, since time.Time) (int, float64, error)
+	
+	// FindAwaitingConfirmation retrieves broadcast transactions that are neither pending,
+	// confirmed nor failed
+	FindAwaitingConfirmation(ctx context.Context) ([]*Transaction, error)
+	
 	// Update updates an existing transaction
 	Update(ctx context.Context, tx *Transaction) error
 }
@@ -160,6 +336,9 @@ type HashRateRepository interface {
 	// FindByTimeRange retrieves hash rate data within a time range
 	FindByTimeRange(ctx context.Context, startTime, endTime time.Time) ([]*HashRateData, error)
 	
+	// FindByBlockRange retrieves hash rate data between two block heights inclusive, ordered by block height
+	FindByBlockRange(ctx context.Context, startHeight, endHeight uint64) ([]*HashRateData, error)
+	
 	// GetLatest retrieves the most recent hash rate data
 	GetLatest(ctx context.Context) (*HashRateData, error)
 	
@@ -175,12 +354,87 @@ type UserRepository interface {
 	// FindByID retrieves a user by ID
 	FindByID(ctx context.Context, userID string) (map[string]interface{}, error)
 	
-	// GetPublicKey retrieves a user's public key
+	// GetPublicKey retrieves a user's registered public key, nil if none is registered
 	GetPublicKey(ctx context.Context, userID string) ([]byte, error)
 	
+	// RegisterUserKey sets a user's public key, creating the user if it does not exist
+	// and replacing any previously registered key
+	RegisterUserKey(ctx context.Context, userID string, pubKey []byte) error
+	
+	// GetBalance retrieves a user's available balance in BTC
+	GetBalance(ctx context.Context, userID string) (float64, error)
+	
+	// DebitBalance atomically deducts amount from a user's available balance, returning
+	// ErrInsufficientFunds when the balance is short
+	DebitBalance(ctx context.Context, userID string, amount float64) error
+	
+	// CreditBalance adds amount to a user's available balance
+	CreditBalance(ctx context.Context, userID string, amount float64) error
+	
 	// Update updates a user's data
 	Update(ctx context.Context, userID string, data map[string]interface{}) error
 	
 	// Delete deletes a user by ID
 	Delete(ctx context.Context, userID string) error
 }
+
+// WebhookRepository defines the data access interface for webhook subscriptions and deliveries
+type WebhookRepository interface {
+	// CreateSubscription creates a new webhook subscription
+	CreateSubscription(ctx context.Context, subscription *WebhookSubscription) error
+	
+	// FindSubscriptionByID retrieves a webhook subscription by ID
+	FindSubscriptionByID(ctx context.Context, id string) (*WebhookSubscription, error)
+	
+	// FindSubscriptionsByUser retrieves all webhook subscriptions for a specific user
+	FindSubscriptionsByUser(ctx context.Context, userID string) ([]*WebhookSubscription, error)
+	
+	// DeleteSubscription deletes a webhook subscription by ID
+	DeleteSubscription(ctx context.Context, id string) error
+	
+	// CreateDelivery queues a new webhook delivery
+	CreateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	
+	// FindDueDeliveries retrieves up to limit pending deliveries whose next attempt is due at asOf
+	FindDueDeliveries(ctx context.Context, asOf time.Time, limit int) ([]*WebhookDelivery, error)
+	
+	// UpdateDelivery updates an existing webhook delivery
+	UpdateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+}
+
+// ContractTemplateRepository defines the data access interface for contract templates
+type ContractTemplateRepository interface {
+	// Create creates a new contract template
+	Create(ctx context.Context, template *ContractTemplate) error
+	
+	// FindByID retrieves a contract template by ID
+	FindByID(ctx context.Context, id string) (*ContractTemplate, error)
+	
+	// FindAll retrieves all contract templates ordered by name
+	FindAll(ctx context.Context) ([]*ContractTemplate, error)
+}
+
+// ContractScriptRepository defines the data access interface for the scripts generated for contracts
+type ContractScriptRepository interface {
+	// Save stores a contract's scripts, replacing any stored before
+	Save(ctx context.Context, scripts *ContractScripts) error
+	
+	// FindByContract retrieves a contract's scripts, nil if none were stored
+	FindByContract(ctx context.Context, contractID string) (*ContractScripts, error)
+}
+
+// StatusChangeRepository defines the data access interface for the status change audit trail
+type StatusChangeRepository interface {
+	// Create records a status change
+	Create(ctx context.Context, change *StatusChange) error
+	
+	// FindByEntity retrieves the status changes of an entity, oldest first
+	FindByEntity(ctx context.Context, entityType AuditEntityType, entityID string) ([]*StatusChange, error)
+}
+
+// MarketSnapshotRepository provides consistent reads spanning multiple tables
+type MarketSnapshotRepository interface {
+	// FindMarketSnapshot retrieves the open orders and active contracts for a contract type
+	// and expiry within a single read-only database transaction
+	FindMarketSnapshot(ctx context.Context, contractType ContractType, expiryBlockHeight uint64) ([]*Order, []*Contract, error)
+}
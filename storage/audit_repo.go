@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashperp/hashperp"
+	"gorm.io/gorm"
+)
+
+// PostgresStatusChangeRepository implements the StatusChangeRepository interface using PostgreSQL
+type PostgresStatusChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresStatusChangeRepository creates a new PostgreSQL-based status change repository
+func NewPostgresStatusChangeRepository(db *gorm.DB) hashperp.StatusChangeRepository {
+	return &PostgresStatusChangeRepository{
+		db: db,
+	}
+}
+
+// Create records a status change
+func (r *PostgresStatusChangeRepository) Create(ctx context.Context, change *hashperp.StatusChange) error {
+	dbChange := &DBStatusChange{
+		ID:         change.ID,
+		EntityType: string(change.EntityType),
+		EntityID:   change.EntityID,
+		OldStatus:  change.OldStatus,
+		NewStatus:  change.NewStatus,
+		ActorID:    change.ActorID,
+		Timestamp:  change.Timestamp,
+	}
+
+	result := r.db.WithContext(ctx).Create(dbChange)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create status change: %w", result.Error)
+	}
+
+	return nil
+}
+
+// FindByEntity retrieves the status changes of an entity, oldest first
+func (r *PostgresStatusChangeRepository) FindByEntity(ctx context.Context, entityType hashperp.AuditEntityType, entityID string) ([]*hashperp.StatusChange, error) {
+	var dbChanges []DBStatusChange
+	result := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", string(entityType), entityID).
+		Order("timestamp ASC, created_at ASC").
+		Find(&dbChanges)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find status changes: %w", result.Error)
+	}
+
+	changes := make([]*hashperp.StatusChange, len(dbChanges))
+	for i, dbChange := range dbChanges {
+		changes[i] = &hashperp.StatusChange{
+			ID:         dbChange.ID,
+			EntityType: hashperp.AuditEntityType(dbChange.EntityType),
+			EntityID:   dbChange.EntityID,
+			OldStatus:  dbChange.OldStatus,
+			NewStatus:  dbChange.NewStatus,
+			ActorID:    dbChange.ActorID,
+			Timestamp:  dbChange.Timestamp,
+		}
+	}
+
+	return changes, nil
+}
@@ -14,54 +14,26 @@ import (
 
 // PostgresContractRepository implements the ContractRepository interface using PostgreSQL
 type PostgresContractRepository struct {
-	db *gorm.DB
+	db             *gorm.DB
+	eventPublisher hashperp.EventPublisher
 }
 
 // NewPostgresContractRepository creates a new PostgreSQL-based contract repository
 func NewPostgresContractRepository(db *gorm.DB) hashperp.ContractRepository {
 	return &PostgresContractRepository{
-		db: db,
+		db:             db,
+		eventPublisher: hashperp.NewNoopEventPublisher(),
 	}
 }
 
+// SetEventPublisher sets the publisher that receives stored contracts that could not be fully read
+func (r *PostgresContractRepository) SetEventPublisher(publisher hashperp.EventPublisher) {
+	r.eventPublisher = publisher
+}
+
 // Create creates a new contract
 func (r *PostgresContractRepository) Create(ctx context.Context, contract *hashperp.Contract) error {
-	dbContract := &DBContract{
-		ID:                contract.ID,
-		ContractType:      string(contract.ContractType),
-		StrikeRate:        contract.StrikeRate,
-		ExpiryBlockHeight: contract.ExpiryBlockHeight,
-		ExpiryDate:        contract.ExpiryDate,
-		CreationTime:      contract.CreationTime,
-		Status:            string(contract.Status),
-		BuyerID:           contract.BuyerID,
-		SellerID:          contract.SellerID,
-		Size:              contract.Size,
-		BuyerVTXO:         contract.BuyerVTXO,
-		SellerVTXO:        contract.SellerVTXO,
-	}
-
-	// Set nullable fields
-	if contract.SettlementTx != "" {
-		dbContract.SettlementTx = sql.NullString{
-			String: contract.SettlementTx,
-			Valid:  true,
-		}
-	}
-
-	if contract.SettlementRate != 0 {
-		dbContract.SettlementRate = sql.NullFloat64{
-			Float64: contract.SettlementRate,
-			Valid:   true,
-		}
-	}
-
-	if contract.RolledOverToID != "" {
-		dbContract.RolledOverToID = sql.NullString{
-			String: contract.RolledOverToID,
-			Valid:  true,
-		}
-	}
+	dbContract := convertContractToDBContract(contract)
 
 	result := r.db.WithContext(ctx).Create(dbContract)
 	if result.Error != nil {
@@ -82,11 +54,45 @@ func (r *PostgresContractRepository) FindByID(ctx context.Context, id string) (*
 		return nil, fmt.Errorf("failed to find contract: %w", result.Error)
 	}
 
-	return convertDBContractToContract(&dbContract), nil
+	return convertDBContractToContract(ctx, r.eventPublisher, &dbContract), nil
+}
+
+// FindByIDIncludingArchived retrieves a contract by ID, including archived contracts
+func (r *PostgresContractRepository) FindByIDIncludingArchived(ctx context.Context, id string) (*hashperp.Contract, error) {
+	var dbContract DBContract
+	result := r.db.WithContext(ctx).Unscoped().Where("id = ?", id).First(&dbContract)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find contract: %w", result.Error)
+	}
+
+	return convertDBContractToContract(ctx, r.eventPublisher, &dbContract), nil
+}
+
+// FindByIdempotencyKey retrieves the contract created with a client-supplied idempotency key
+func (r *PostgresContractRepository) FindByIdempotencyKey(ctx context.Context, idempotencyKey string) (*hashperp.Contract, error) {
+	var dbContract DBContract
+	result := r.db.WithContext(ctx).Where("idempotency_key = ?", idempotencyKey).First(&dbContract)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find contract by idempotency key: %w", result.Error)
+	}
+
+	return convertDBContractToContract(ctx, r.eventPublisher, &dbContract), nil
 }
 
-// FindByUser retrieves all contracts for a specific user
-func (r *PostgresContractRepository) FindByUser(ctx context.Context, userID string, status []hashperp.ContractStatus) ([]*hashperp.Contract, error) {
+// FindByUser retrieves a page of contracts for a specific user, ordered by creation time then ID
+func (r *PostgresContractRepository) FindByUser(
+	ctx context.Context,
+	userID string,
+	status []hashperp.ContractStatus,
+	includeArchived bool,
+	page hashperp.PageRequest,
+) ([]*hashperp.Contract, string, error) {
 	var dbContracts []DBContract
 	
 	// Convert string statuses to string array
@@ -98,28 +104,46 @@ func (r *PostgresContractRepository) FindByUser(ctx context.Context, userID stri
 	query := r.db.WithContext(ctx).
 		Where("buyer_id = ? OR seller_id = ?", userID, userID)
 	
+	if includeArchived {
+		query = query.Unscoped()
+	}
+	
 	if len(statusStrings) > 0 {
 		query = query.Where("status IN ?", statusStrings)
 	}
 	
+	query, err := applyPageRequest(query, "creation_time", page)
+	if err != nil {
+		return nil, "", err
+	}
+	
 	result := query.Find(&dbContracts)
 	
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to find contracts for user: %w", result.Error)
+		return nil, "", fmt.Errorf("failed to find contracts for user: %w", result.Error)
+	}
+	
+	var nextCursor string
+	if hasNextPage(len(dbContracts), page) {
+		dbContracts = dbContracts[:page.Limit]
+		last := dbContracts[len(dbContracts)-1]
+		nextCursor = hashperp.EncodePageCursor(last.CreationTime, last.ID)
 	}
 
 	contracts := make([]*hashperp.Contract, len(dbContracts))
 	for i, dbContract := range dbContracts {
-		contracts[i] = convertDBContractToContract(&dbContract)
+		contracts[i] = convertDBContractToContract(ctx, r.eventPublisher, &dbContract)
 	}
 
-	return contracts, nil
+	return contracts, nextCursor, nil
 }
 
-// FindActiveContracts retrieves all active contracts
+// FindActiveContracts retrieves all active contracts, including those close to expiry
 func (r *PostgresContractRepository) FindActiveContracts(ctx context.Context) ([]*hashperp.Contract, error) {
 	var dbContracts []DBContract
-	result := r.db.WithContext(ctx).Where("status = ?", string(hashperp.ACTIVE)).Find(&dbContracts)
+	result := r.db.WithContext(ctx).
+		Where("status IN ?", []string{string(hashperp.ACTIVE), string(hashperp.CLOSE_TO_EXPIRY)}).
+		Find(&dbContracts)
 	
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to find active contracts: %w", result.Error)
@@ -127,118 +151,347 @@ func (r *PostgresContractRepository) FindActiveContracts(ctx context.Context) ([
 
 	contracts := make([]*hashperp.Contract, len(dbContracts))
 	for i, dbContract := range dbContracts {
-		contracts[i] = convertDBContractToContract(&dbContract)
+		contracts[i] = convertDBContractToContract(ctx, r.eventPublisher, &dbContract)
 	}
 
 	return contracts, nil
 }
 
-// FindByExpiryRange retrieves contracts expiring within a certain block height range
-func (r *PostgresContractRepository) FindByExpiryRange(ctx context.Context, fromHeight, toHeight uint64) ([]*hashperp.Contract, error) {
+// FindActiveByUser retrieves every active contract, including those close to expiry, in
+// which a user is a founding party or pool member
+func (r *PostgresContractRepository) FindActiveByUser(ctx context.Context, userID string) ([]*hashperp.Contract, error) {
+	member, err := json.Marshal([]map[string]string{{"user_id": userID}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pool member filter: %w", err)
+	}
+
 	var dbContracts []DBContract
 	result := r.db.WithContext(ctx).
-		Where("status = ? AND expiry_block_height BETWEEN ? AND ?", 
-		      string(hashperp.ACTIVE), fromHeight, toHeight).
+		Where("status IN ?", []string{string(hashperp.ACTIVE), string(hashperp.CLOSE_TO_EXPIRY)}).
+		Where("(buyer_id = ? OR seller_id = ? OR buyer_pool @> ? OR seller_pool @> ?)", userID, userID, string(member), string(member)).
+		Order("expiry_block_height ASC, id ASC").
 		Find(&dbContracts)
 	
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to find contracts by expiry range: %w", result.Error)
+		return nil, fmt.Errorf("failed to find active contracts for user: %w", result.Error)
 	}
 
 	contracts := make([]*hashperp.Contract, len(dbContracts))
 	for i, dbContract := range dbContracts {
-		contracts[i] = convertDBContractToContract(&dbContract)
+		contracts[i] = convertDBContractToContract(ctx, r.eventPublisher, &dbContract)
 	}
 
 	return contracts, nil
 }
 
-// Update updates an existing contract
-func (r *PostgresContractRepository) Update(ctx context.Context, contract *hashperp.Contract) error {
-	dbContract := &DBContract{
-		ID:                contract.ID,
-		ContractType:      string(contract.ContractType),
-		StrikeRate:        contract.StrikeRate,
-		ExpiryBlockHeight: contract.ExpiryBlockHeight,
-		ExpiryDate:        contract.ExpiryDate,
-		CreationTime:      contract.CreationTime,
-		Status:            string(contract.Status),
-		BuyerID:           contract.BuyerID,
-		SellerID:          contract.SellerID,
-		Size:              contract.Size,
-		BuyerVTXO:         contract.BuyerVTXO,
-		SellerVTXO:        contract.SellerVTXO,
-	}
-
-	// Set nullable fields
-	if contract.SettlementTx != "" {
-		dbContract.SettlementTx = sql.NullString{
-			String: contract.SettlementTx,
-			Valid:  true,
-		}
+// CountActiveContracts returns the number of active contracts, including those close to expiry
+func (r *PostgresContractRepository) CountActiveContracts(ctx context.Context) (int, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&DBContract{}).
+		Where("status IN ?", []string{string(hashperp.ACTIVE), string(hashperp.CLOSE_TO_EXPIRY)}).
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count active contracts: %w", result.Error)
 	}
+	return int(count), nil
+}
 
-	if contract.SettlementRate != 0 {
-		dbContract.SettlementRate = sql.NullFloat64{
-			Float64: contract.SettlementRate,
-			Valid:   true,
-		}
+// Search retrieves a page of contracts matching every criterion of a filter in a single query
+func (r *PostgresContractRepository) Search(
+	ctx context.Context,
+	filter hashperp.ContractFilter,
+	page hashperp.PageRequest,
+) ([]*hashperp.Contract, string, error) {
+	var dbContracts []DBContract
+
+	query := r.db.WithContext(ctx)
+
+	switch {
+	case filter.UserID != "" && filter.CounterpartyID != "":
+		query = query.Where("(buyer_id = ? AND seller_id = ?) OR (buyer_id = ? AND seller_id = ?)",
+			filter.UserID, filter.CounterpartyID, filter.CounterpartyID, filter.UserID)
+	case filter.UserID != "":
+		query = query.Where("buyer_id = ? OR seller_id = ?", filter.UserID, filter.UserID)
+	case filter.CounterpartyID != "":
+		query = query.Where("buyer_id = ? OR seller_id = ?", filter.CounterpartyID, filter.CounterpartyID)
 	}
 
-	if contract.RolledOverToID != "" {
-		dbContract.RolledOverToID = sql.NullString{
-			String: contract.RolledOverToID,
-			Valid:  true,
+	if filter.ToExpiryHeight > 0 {
+		query = query.Where("expiry_block_height BETWEEN ? AND ?", filter.FromExpiryHeight, filter.ToExpiryHeight)
+	}
+
+	if filter.ContractType != "" {
+		query = query.Where("contract_type = ?", string(filter.ContractType))
+	}
+
+	if len(filter.Statuses) > 0 {
+		var statusStrings []string
+		for _, s := range filter.Statuses {
+			statusStrings = append(statusStrings, string(s))
 		}
+		query = query.Where("status IN ?", statusStrings)
+	}
+
+	if filter.MinStrikeRate > 0 {
+		query = query.Where("strike_rate >= ?", filter.MinStrikeRate)
+	}
+	if filter.MaxStrikeRate > 0 {
+		query = query.Where("strike_rate <= ?", filter.MaxStrikeRate)
+	}
+
+	if filter.MinSize > 0 {
+		query = query.Where("size >= ?", filter.MinSize)
+	}
+	if filter.MaxSize > 0 {
+		query = query.Where("size <= ?", filter.MaxSize)
+	}
+
+	query, err := applyPageRequest(query, "creation_time", page)
+	if err != nil {
+		return nil, "", err
 	}
 
-	result := r.db.WithContext(ctx).Save(dbContract)
+	result := query.Find(&dbContracts)
 	if result.Error != nil {
-		return fmt.Errorf("failed to update contract: %w", result.Error)
+		return nil, "", fmt.Errorf("failed to search contracts: %w", result.Error)
 	}
 
-	return nil
+	var nextCursor string
+	if hasNextPage(len(dbContracts), page) {
+		dbContracts = dbContracts[:page.Limit]
+		last := dbContracts[len(dbContracts)-1]
+		nextCursor = hashperp.EncodePageCursor(last.CreationTime, last.ID)
+	}
+
+	contracts := make([]*hashperp.Contract, len(dbContracts))
+	for i, dbContract := range dbContracts {
+		contracts[i] = convertDBContractToContract(ctx, r.eventPublisher, &dbContract)
+	}
+
+	return contracts, nextCursor, nil
 }
 
-// Delete deletes a contract by ID
-func (r *PostgresContractRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Delete(&DBContract{}, "id = ?", id)
+// FindByStatus retrieves all contracts with the given status
+func (r *PostgresContractRepository) FindByStatus(ctx context.Context, status hashperp.ContractStatus) ([]*hashperp.Contract, error) {
+	var dbContracts []DBContract
+	result := r.db.WithContext(ctx).Where("status = ?", string(status)).Find(&dbContracts)
+	
 	if result.Error != nil {
-		return fmt.Errorf("failed to delete contract: %w", result.Error)
+		return nil, fmt.Errorf("failed to find contracts by status: %w", result.Error)
 	}
-	return nil
+
+	contracts := make([]*hashperp.Contract, len(dbContracts))
+	for i, dbContract := range dbContracts {
+		contracts[i] = convertDBContractToContract(ctx, r.eventPublisher, &dbContract)
+	}
+
+	return contracts, nil
 }
 
-// Helper function to convert DB model to domain model
-func convertDBContractToContract(dbContract *DBContract) *hashperp.Contract {
-	contract := &hashperp.Contract{
-		ID:                dbContract.ID,
-		ContractType:      hashperp.ContractType(dbContract.ContractType),
-		StrikeRate:        dbContract.StrikeRate,
-		ExpiryBlockHeight: dbContract.ExpiryBlockHeight,
-		ExpiryDate:        dbContract.ExpiryDate,
-		CreationTime:      dbContract.CreationTime,
-		Status:            hashperp.ContractStatus(dbContract.Status),
-		BuyerID:           dbContract.BuyerID,
-		SellerID:          dbContract.SellerID,
-		Size:              dbContract.Size,
-		BuyerVTXO:         dbContract.BuyerVTXO,
-		SellerVTXO:        dbContract.SellerVTXO,
+// statusCount is a row of a count grouped by status
+type statusCount struct {
+	Status string
+	Count  int
+}
+
+// countByStatus counts the rows of model grouped by their status column
+func countByStatus(ctx context.Context, db *gorm.DB, model interface{}) ([]statusCount, error) {
+	var rows []statusCount
+	result := db.WithContext(ctx).
+		Model(model).
+		Select("status, count(*) as count").
+		Group("status").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rows, nil
+}
+
+// CountByStatus returns the number of contracts in each status
+func (r *PostgresContractRepository) CountByStatus(ctx context.Context) (map[hashperp.ContractStatus]int, error) {
+	rows, err := countByStatus(ctx, r.db, &DBContract{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count contracts by status: %w", err)
+	}
+
+	counts := make(map[hashperp.ContractStatus]int, len(rows))
+	for _, row := range rows {
+		counts[hashperp.ContractStatus(row.Status)] = row.Count
+	}
+
+	return counts, nil
+}
+
+// contractTypeTotal is a row of a count and size sum grouped by contract type
+type contractTypeTotal struct {
+	ContractType string
+	Count        int
+	Size         float64
+}
+
+// SumActiveByType returns the number and total size of active contracts for each contract type
+func (r *PostgresContractRepository) SumActiveByType(ctx context.Context) (map[hashperp.ContractType]hashperp.ContractTotals, error) {
+	var rows []contractTypeTotal
+	result := r.db.WithContext(ctx).
+		Model(&DBContract{}).
+		Select("contract_type, count(*) as count, coalesce(sum(size), 0) as size").
+		Where("status IN ?", []string{string(hashperp.ACTIVE), string(hashperp.CLOSE_TO_EXPIRY)}).
+		Group("contract_type").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to sum active contracts by type: %w", result.Error)
+	}
+
+	totals := make(map[hashperp.ContractType]hashperp.ContractTotals, len(rows))
+	for _, row := range rows {
+		totals[hashperp.ContractType(row.ContractType)] = hashperp.ContractTotals{
+			Count: row.Count,
+			Size:  row.Size,
+		}
+	}
+
+	return totals, nil
+}
+
+// SummarizeOpenInterest aggregates active contracts per expiry block and strike rate
+func (r *PostgresContractRepository) SummarizeOpenInterest(
+	ctx context.Context,
+	contractType hashperp.ContractType,
+) ([]*hashperp.OpenInterestBucket, error) {
+	var rows []struct {
+		ExpiryBlockHeight uint64
+		StrikeRate        float64
+		ContractCount     int
+		TotalSize         float64
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&DBContract{}).
+		Select("expiry_block_height, strike_rate, count(*) as contract_count, sum(size) as total_size").
+		Where("status IN ?", []string{string(hashperp.ACTIVE), string(hashperp.CLOSE_TO_EXPIRY)})
+	if contractType != "" {
+		query = query.Where("contract_type = ?", string(contractType))
+	}
+
+	result := query.
+		Group("expiry_block_height, strike_rate").
+		Order("expiry_block_height ASC, strike_rate ASC").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to summarize open interest: %w", result.Error)
+	}
+
+	buckets := make([]*hashperp.OpenInterestBucket, len(rows))
+	for i, row := range rows {
+		buckets[i] = &hashperp.OpenInterestBucket{
+			ExpiryBlockHeight: row.ExpiryBlockHeight,
+			StrikeRate:        row.StrikeRate,
+			ContractCount:     row.ContractCount,
+			TotalSize:         row.TotalSize,
+		}
+	}
+
+	return buckets, nil
+}
+
+// SummarizeSettlementsByExpiry aggregates SETTLED contracts expiring within a block height range per expiry block
+func (r *PostgresContractRepository) SummarizeSettlementsByExpiry(
+	ctx context.Context,
+	contractType hashperp.ContractType,
+	fromHeight, toHeight uint64,
+) ([]*hashperp.SettlementHistoryPoint, error) {
+	var rows []struct {
+		ExpiryBlockHeight uint64
+		ContractCount     int
+		TotalSize         float64
+		SettlementRate    float64
+		MinRate           float64
+		MaxRate           float64
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&DBContract{}).
+		Select("expiry_block_height, count(*) as contract_count, sum(size) as total_size, "+
+			"sum(settlement_rate * size) / nullif(sum(size), 0) as settlement_rate, "+
+			"min(settlement_rate) as min_rate, max(settlement_rate) as max_rate").
+		Where("status = ? AND settlement_rate IS NOT NULL AND expiry_block_height BETWEEN ? AND ?",
+			string(hashperp.SETTLED), fromHeight, toHeight)
+	if contractType != "" {
+		query = query.Where("contract_type = ?", string(contractType))
+	}
+
+	result := query.
+		Group("expiry_block_height").
+		Order("expiry_block_height ASC").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to summarize settlements by expiry: %w", result.Error)
+	}
+
+	history := make([]*hashperp.SettlementHistoryPoint, len(rows))
+	for i, row := range rows {
+		history[i] = &hashperp.SettlementHistoryPoint{
+			ExpiryBlockHeight: row.ExpiryBlockHeight,
+			ContractCount:     row.ContractCount,
+			TotalSize:         row.TotalSize,
+			SettlementRate:    row.SettlementRate,
+			MinRate:           row.MinRate,
+			MaxRate:           row.MaxRate,
+		}
 	}
 
-	if dbContract.SettlementTx.Valid {
-		contract.SettlementTx = dbContract.SettlementTx.String
+	return history, nil
+}
+
+// FindByExpiryRange retrieves contracts expiring within a certain block height range
+func (r *PostgresContractRepository) FindByExpiryRange(ctx context.Context, fromHeight, toHeight uint64) ([]*hashperp.Contract, error) {
+	var dbContracts []DBContract
+	result := r.db.WithContext(ctx).
+		Where("status IN ? AND expiry_block_height BETWEEN ? AND ?", 
+		      []string{string(hashperp.ACTIVE), string(hashperp.CLOSE_TO_EXPIRY)}, fromHeight, toHeight).
+		Find(&dbContracts)
+	
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find contracts by expiry range: %w", result.Error)
 	}
 
-	if dbContract.SettlementRate.Valid {
-		contract.SettlementRate = dbContract.SettlementRate.Float64
+	contracts := make([]*hashperp.Contract, len(dbContracts))
+	for i, dbContract := range dbContracts {
+		contracts[i] = convertDBContractToContract(ctx, r.eventPublisher, &dbContract)
 	}
 
-	if dbContract.RolledOverToID.Valid {
-		contract.RolledOverToID = dbContract.RolledOverToID.String
+	return contracts, nil
+}
+
+// Update updates an existing contract if it is still at the version it was read at, and
+// increments the version
+func (r *PostgresContractRepository) Update(ctx context.Context, contract *hashperp.Contract) error {
+	dbContract := convertContractToDBContract(contract)
+	dbContract.Version = contract.Version + 1
+
+	if err := updateVersioned(ctx, r.db, dbContract, contract.ID, contract.Version); err != nil {
+		return fmt.Errorf("failed to update contract: %w", err)
 	}
 
-	return contract
+	contract.Version = dbContract.Version
+	return nil
+}
+
+// Delete archives a contract by ID. The row is kept so transactions referencing it stay
+// resolvable, but its idempotency key is released so a retry can create the contract again.
+func (r *PostgresContractRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).
+		Model(&DBContract{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"idempotency_key": nil,
+			"deleted_at":      time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete contract: %w", result.Error)
+	}
+	return nil
 }
 
 // PostgresVTXORepository implements the VTXORepository interface using PostgreSQL
@@ -255,11 +508,13 @@ func NewPostgresVTXORepository(db *gorm.DB) hashperp.VTXORepository {
 
 // Create creates a new VTXO
 func (r *PostgresVTXORepository) Create(ctx context.Context, vtxo *hashperp.VTXO) error {
+	vtxo.AmountSats = hashperp.BTCToSats(vtxo.Amount) // Keep the caller's copy in step with the stored one
 	dbVTXO := &DBVTXO{
 		ID:                vtxo.ID,
 		ContractID:        vtxo.ContractID,
 		OwnerID:           vtxo.OwnerID,
 		Amount:            vtxo.Amount,
+		AmountSats:        vtxo.AmountSats,
 		ScriptPath:        vtxo.ScriptPath,
 		CreationTimestamp: vtxo.CreationTimestamp,
 		SignatureData:     vtxo.SignatureData,
@@ -295,6 +550,20 @@ func (r *PostgresVTXORepository) FindByID(ctx context.Context, id string) (*hash
 	return convertDBVTXOToVTXO(&dbVTXO), nil
 }
 
+// FindByIDIncludingArchived retrieves a VTXO by ID, including archived VTXOs
+func (r *PostgresVTXORepository) FindByIDIncludingArchived(ctx context.Context, id string) (*hashperp.VTXO, error) {
+	var dbVTXO DBVTXO
+	result := r.db.WithContext(ctx).Unscoped().Where("id = ?", id).First(&dbVTXO)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find VTXO: %w", result.Error)
+	}
+
+	return convertDBVTXOToVTXO(&dbVTXO), nil
+}
+
 // FindByContract retrieves all VTXOs for a specific contract
 func (r *PostgresVTXORepository) FindByContract(ctx context.Context, contractID string) ([]*hashperp.VTXO, error) {
 	var dbVTXOs []DBVTXO
@@ -312,19 +581,62 @@ func (r *PostgresVTXORepository) FindByContract(ctx context.Context, contractID
 	return vtxos, nil
 }
 
-// FindByUser retrieves all VTXOs for a specific user
-func (r *PostgresVTXORepository) FindByUser(ctx context.Context, userID string, onlyActive bool) ([]*hashperp.VTXO, error) {
+// FindActiveByOwnerAndContract retrieves the most recent active VTXO a user owns in a contract
+func (r *PostgresVTXORepository) FindActiveByOwnerAndContract(
+	ctx context.Context,
+	contractID string,
+	ownerID string,
+) (*hashperp.VTXO, error) {
+	var dbVTXO DBVTXO
+	result := r.db.WithContext(ctx).
+		Where("contract_id = ? AND owner_id = ? AND is_active = true", contractID, ownerID).
+		Order("creation_timestamp DESC, id DESC").
+		First(&dbVTXO)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find VTXO for owner in contract: %w", result.Error)
+	}
+
+	return convertDBVTXOToVTXO(&dbVTXO), nil
+}
+
+// FindByUser retrieves a page of VTXOs for a specific user, ordered by creation time then ID
+func (r *PostgresVTXORepository) FindByUser(
+	ctx context.Context,
+	userID string,
+	onlyActive bool,
+	includeArchived bool,
+	page hashperp.PageRequest,
+) ([]*hashperp.VTXO, string, error) {
 	var dbVTXOs []DBVTXO
 	query := r.db.WithContext(ctx).Where("owner_id = ?", userID)
 	
+	if includeArchived {
+		query = query.Unscoped()
+	}
+	
 	if onlyActive {
 		query = query.Where("is_active = true")
 	}
 	
+	query, err := applyPageRequest(query, "creation_timestamp", page)
+	if err != nil {
+		return nil, "", err
+	}
+	
 	result := query.Find(&dbVTXOs)
 	
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to find VTXOs for user: %w", result.Error)
+		return nil, "", fmt.Errorf("failed to find VTXOs for user: %w", result.Error)
+	}
+	
+	var nextCursor string
+	if hasNextPage(len(dbVTXOs), page) {
+		dbVTXOs = dbVTXOs[:page.Limit]
+		last := dbVTXOs[len(dbVTXOs)-1]
+		nextCursor = hashperp.EncodePageCursor(last.CreationTimestamp, last.ID)
 	}
 
 	vtxos := make([]*hashperp.VTXO, len(dbVTXOs))
@@ -332,7 +644,7 @@ func (r *PostgresVTXORepository) FindByUser(ctx context.Context, userID string,
 		vtxos[i] = convertDBVTXOToVTXO(&dbVTXO)
 	}
 
-	return vtxos, nil
+	return vtxos, nextCursor, nil
 }
 
 // FindActiveVTXOs retrieves all active VTXOs
@@ -352,35 +664,21 @@ func (r *PostgresVTXORepository) FindActiveVTXOs(ctx context.Context) ([]*hashpe
 	return vtxos, nil
 }
 
-// Update updates an existing VTXO
+// Update updates an existing VTXO if it is still at the version it was read at, and
+// increments the version
 func (r *PostgresVTXORepository) Update(ctx context.Context, vtxo *hashperp.VTXO) error {
-	dbVTXO := &DBVTXO{
-		ID:                vtxo.ID,
-		ContractID:        vtxo.ContractID,
-		OwnerID:           vtxo.OwnerID,
-		Amount:            vtxo.Amount,
-		ScriptPath:        vtxo.ScriptPath,
-		CreationTimestamp: vtxo.CreationTimestamp,
-		SignatureData:     vtxo.SignatureData,
-		IsActive:          vtxo.IsActive,
-	}
-
-	if vtxo.SwappedFromID != "" {
-		dbVTXO.SwappedFromID = sql.NullString{
-			String: vtxo.SwappedFromID,
-			Valid:  true,
-		}
-	}
+	dbVTXO := convertVTXOToDBVTXO(vtxo)
+	dbVTXO.Version = vtxo.Version + 1
 
-	result := r.db.WithContext(ctx).Save(dbVTXO)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update VTXO: %w", result.Error)
+	if err := updateVersioned(ctx, r.db, dbVTXO, vtxo.ID, vtxo.Version); err != nil {
+		return fmt.Errorf("failed to update VTXO: %w", err)
 	}
 
+	vtxo.Version = dbVTXO.Version
 	return nil
 }
 
-// Delete deletes a VTXO by ID
+// Delete archives a VTXO by ID, keeping the row so transactions referencing it stay resolvable
 func (r *PostgresVTXORepository) Delete(ctx context.Context, id string) error {
 	result := r.db.WithContext(ctx).Delete(&DBVTXO{}, "id = ?", id)
 	if result.Error != nil {
@@ -400,12 +698,15 @@ func convertDBVTXOToVTXO(dbVTXO *DBVTXO) *hashperp.VTXO {
 		CreationTimestamp: dbVTXO.CreationTimestamp,
 		SignatureData:     dbVTXO.SignatureData,
 		IsActive:          dbVTXO.IsActive,
+		Version:           dbVTXO.Version,
 	}
 
 	if dbVTXO.SwappedFromID.Valid {
 		vtxo.SwappedFromID = dbVTXO.SwappedFromID.String
 	}
 
+	vtxo.AmountSats, vtxo.Amount = amountFromSats(dbVTXO.AmountSats, dbVTXO.Amount)
+
 	return vtxo
 }
 
@@ -434,6 +735,8 @@ func (r *PostgresOrderRepository) Create(ctx context.Context, order *hashperp.Or
 		Size:              order.Size,
 		Status:            string(order.Status),
 		CreationTime:      order.CreationTime,
+		SelfTradePolicy:   string(order.SelfTradePolicy),
+		TimeInForce:       string(order.TimeInForce),
 	}
 
 	if order.MatchedOrderID != "" {
@@ -450,6 +753,13 @@ func (r *PostgresOrderRepository) Create(ctx context.Context, order *hashperp.Or
 		}
 	}
 
+	if order.ClientOrderID != "" {
+		dbOrder.ClientOrderID = sql.NullString{
+			String: order.ClientOrderID,
+			Valid:  true,
+		}
+	}
+
 	result := r.db.WithContext(ctx).Create(dbOrder)
 	if result.Error != nil {
 		return fmt.Errorf("failed to create order: %w", result.Error)
@@ -458,6 +768,181 @@ func (r *PostgresOrderRepository) Create(ctx context.Context, order *hashperp.Or
 	return nil
 }
 
+// FindByClientOrderID retrieves a user's order by its client-supplied ID
+func (r *PostgresOrderRepository) FindByClientOrderID(ctx context.Context, userID string, clientOrderID string) (*hashperp.Order, error) {
+	var dbOrder DBOrder
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND client_order_id = ?", userID, clientOrderID).
+		First(&dbOrder)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find order by client order ID: %w", result.Error)
+	}
+
+	return convertDBOrderToOrder(&dbOrder), nil
+}
+
+// CountOpenByUser returns the number of OPEN orders of a specific user
+func (r *PostgresOrderRepository) CountOpenByUser(ctx context.Context, userID string) (int, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&DBOrder{}).
+		Where("user_id = ? AND status = ?", userID, string(hashperp.OPEN)).
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count open orders by user: %w", result.Error)
+	}
+	return int(count), nil
+}
+
+// CountOpenOrders returns the number of open orders
+func (r *PostgresOrderRepository) CountOpenOrders(ctx context.Context) (int, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&DBOrder{}).
+		Where("status = ?", string(hashperp.OPEN)).
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count open orders: %w", result.Error)
+	}
+	return int(count), nil
+}
+
+// FindByContractType retrieves the open orders for a specific contract type and expiry,
+// best price first on each side (highest buy, lowest sell) and oldest first at equal prices
+func (r *PostgresOrderRepository) FindByContractType(ctx context.Context, contractType hashperp.ContractType, expiryBlockHeight uint64) ([]*hashperp.Order, error) {
+	var dbOrders []DBOrder
+	result := r.db.WithContext(ctx).
+		Where("contract_type = ? AND expiry_block_height = ? AND status = ?",
+			string(contractType), expiryBlockHeight, string(hashperp.OPEN)).
+		Order("order_type").
+		Order(fmt.Sprintf("CASE WHEN order_type = '%s' THEN strike_rate END DESC", hashperp.BUY)).
+		Order(fmt.Sprintf("CASE WHEN order_type = '%s' THEN strike_rate END ASC", hashperp.SELL)).
+		Order("creation_time ASC").
+		Find(&dbOrders)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find orders by contract type: %w", result.Error)
+	}
+
+	orders := make([]*hashperp.Order, len(dbOrders))
+	for i, dbOrder := range dbOrders {
+		orders[i] = convertDBOrderToOrder(&dbOrder)
+	}
+
+	return orders, nil
+}
+
+// FindBestLevel retrieves the best price level of one side of a market's open orders, the
+// highest buy or lowest sell strike with its total size, nil when that side is empty
+func (r *PostgresOrderRepository) FindBestLevel(
+	ctx context.Context,
+	contractType hashperp.ContractType,
+	expiryBlockHeight uint64,
+	orderType hashperp.OrderType,
+) (*hashperp.OrderBookLevel, error) {
+	var rows []struct {
+		StrikeRate float64
+		TotalSize  float64
+		OrderCount int
+	}
+
+	direction := "ASC"
+	if orderType == hashperp.BUY {
+		direction = "DESC"
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&DBOrder{}).
+		Select("strike_rate, sum(size) as total_size, count(*) as order_count").
+		Where("contract_type = ? AND expiry_block_height = ? AND order_type = ? AND status = ?",
+			string(contractType), expiryBlockHeight, string(orderType), string(hashperp.OPEN)).
+		Group("strike_rate").
+		Order("strike_rate " + direction).
+		Limit(1).
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find best order book level: %w", result.Error)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	return &hashperp.OrderBookLevel{
+		StrikeRate:     rows[0].StrikeRate,
+		Size:           rows[0].TotalSize,
+		CumulativeSize: rows[0].TotalSize,
+		OrderCount:     rows[0].OrderCount,
+	}, nil
+}
+
+// FindExpiredOpenOrders retrieves open orders whose expiry block height is at or below the given height
+func (r *PostgresOrderRepository) FindExpiredOpenOrders(ctx context.Context, currentBlockHeight uint64) ([]*hashperp.Order, error) {
+	var dbOrders []DBOrder
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND expiry_block_height <= ?", string(hashperp.OPEN), currentBlockHeight).
+		Find(&dbOrders)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find expired open orders: %w", result.Error)
+	}
+
+	orders := make([]*hashperp.Order, len(dbOrders))
+	for i, dbOrder := range dbOrders {
+		orders[i] = convertDBOrderToOrder(&dbOrder)
+	}
+
+	return orders, nil
+}
+
+// NextBookSequence advances the order book sequence of a market and returns the new value.
+// The increment is a single upsert, so concurrent writers never share a sequence number.
+func (r *PostgresOrderRepository) NextBookSequence(ctx context.Context, contractType hashperp.ContractType, expiryBlockHeight uint64) (uint64, error) {
+	var sequence uint64
+	result := r.db.WithContext(ctx).Raw(
+		`INSERT INTO order_book_sequences (contract_type, expiry_block_height, sequence, updated_at)
+		VALUES (?, ?, 1, ?)
+		ON CONFLICT (contract_type, expiry_block_height)
+		DO UPDATE SET sequence = order_book_sequences.sequence + 1, updated_at = EXCLUDED.updated_at
+		RETURNING sequence`,
+		string(contractType), expiryBlockHeight, time.Now().UTC(),
+	).Scan(&sequence)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to advance order book sequence: %w", result.Error)
+	}
+
+	return sequence, nil
+}
+
+// GetBookSequence returns the current order book sequence of a market, 0 if it never changed
+func (r *PostgresOrderRepository) GetBookSequence(ctx context.Context, contractType hashperp.ContractType, expiryBlockHeight uint64) (uint64, error) {
+	var dbSequence DBOrderBookSequence
+	result := r.db.WithContext(ctx).
+		Where("contract_type = ? AND expiry_block_height = ?", string(contractType), expiryBlockHeight).
+		First(&dbSequence)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get order book sequence: %w", result.Error)
+	}
+
+	return dbSequence.Sequence, nil
+}
+
+// CountByStatus returns the number of orders in each status
+func (r *PostgresOrderRepository) CountByStatus(ctx context.Context) (map[hashperp.OrderStatus]int, error) {
+	rows, err := countByStatus(ctx, r.db, &DBOrder{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count orders by status: %w", err)
+	}
+
+	counts := make(map[hashperp.OrderStatus]int, len(rows))
+	for _, row := range rows {
+		counts[hashperp.OrderStatus(row.Status)] = row.Count
+	}
+
+	return counts, nil
+}
+
 // FindAll returns all contracts in the system
 func (r *PostgresContractRepository) FindAll(ctx context.Context) ([]*hashperp.Contract, error) {
 	var dbContracts []DBContract
@@ -469,7 +954,7 @@ func (r *PostgresContractRepository) FindAll(ctx context.Context) ([]*hashperp.C
 	
 	contracts := make([]*hashperp.Contract, len(dbContracts))
 	for i, dbContract := range dbContracts {
-		contracts[i] = convertDBContractToContract(&dbContract)
+		contracts[i] = convertDBContractToContract(ctx, r.eventPublisher, &dbContract)
 	}
 	
 	return contracts, nil
@@ -587,7 +1072,12 @@ func (r *PostgresSwapOfferRepository) FindByID(ctx context.Context, id string) (
 }
 
 // FindByUser retrieves all swap offers for a specific user
-func (r *PostgresSwapOfferRepository) FindByUser(ctx context.Context, userID string, isOfferor bool) ([]*hashperp.SwapOffer, error) {
+func (r *PostgresSwapOfferRepository) FindByUser(
+	ctx context.Context,
+	userID string,
+	isOfferor bool,
+	status []hashperp.SwapOfferStatus,
+) ([]*hashperp.SwapOffer, error) {
 	var dbSwapOffers []DBSwapOffer
 	var query *gorm.DB
 	
@@ -597,6 +1087,10 @@ func (r *PostgresSwapOfferRepository) FindByUser(ctx context.Context, userID str
 		query = r.db.WithContext(ctx).Where("acceptor_id = ? OR target_user_id = ?", userID, userID)
 	}
 	
+	if len(status) > 0 {
+		query = query.Where("status IN ?", swapOfferStatusStrings(status))
+	}
+	
 	result := query.Find(&dbSwapOffers)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to find swap offers by user: %w", result.Error)
@@ -614,13 +1108,35 @@ func (r *PostgresSwapOfferRepository) FindByUser(ctx context.Context, userID str
 	return swapOffers, nil
 }
 
-// FindByContract retrieves all swap offers for a specific contract
-func (r *PostgresSwapOfferRepository) FindByContract(ctx context.Context, contractID string) ([]*hashperp.SwapOffer, error) {
+// FindByContract retrieves a page of swap offers for a specific contract, ordered by creation time then ID
+func (r *PostgresSwapOfferRepository) FindByContract(
+	ctx context.Context,
+	contractID string,
+	status []hashperp.SwapOfferStatus,
+	page hashperp.PageRequest,
+) ([]*hashperp.SwapOffer, string, error) {
 	var dbSwapOffers []DBSwapOffer
-	result := r.db.WithContext(ctx).Where("contract_id = ?", contractID).Find(&dbSwapOffers)
+	query := r.db.WithContext(ctx).Where("contract_id = ?", contractID)
+	if len(status) > 0 {
+		query = query.Where("status IN ?", swapOfferStatusStrings(status))
+	}
+	
+	query, err := applyPageRequest(query, "creation_time", page)
+	if err != nil {
+		return nil, "", err
+	}
+	
+	result := query.Find(&dbSwapOffers)
 	
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to find swap offers by contract: %w", result.Error)
+		return nil, "", fmt.Errorf("failed to find swap offers by contract: %w", result.Error)
+	}
+	
+	var nextCursor string
+	if hasNextPage(len(dbSwapOffers), page) {
+		dbSwapOffers = dbSwapOffers[:page.Limit]
+		last := dbSwapOffers[len(dbSwapOffers)-1]
+		nextCursor = hashperp.EncodePageCursor(last.CreationTime, last.ID)
 	}
 	
 	swapOffers := make([]*hashperp.SwapOffer, 0, len(dbSwapOffers))
@@ -632,7 +1148,40 @@ func (r *PostgresSwapOfferRepository) FindByContract(ctx context.Context, contra
 		swapOffers = append(swapOffers, swapOffer)
 	}
 	
-	return swapOffers, nil
+	return swapOffers, nextCursor, nil
+}
+
+// CountOpenByOfferor returns the number of OPEN swap offers made by a specific user
+func (r *PostgresSwapOfferRepository) CountOpenByOfferor(ctx context.Context, offerorID string) (int, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&DBSwapOffer{}).
+		Where("offeror_id = ? AND status = ?", offerorID, string(hashperp.OFFER_OPEN)).
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count open swap offers by offeror: %w", result.Error)
+	}
+	return int(count), nil
+}
+
+// CountOffersByStatus returns the number of swap offers with the given status
+func (r *PostgresSwapOfferRepository) CountOffersByStatus(ctx context.Context, status hashperp.SwapOfferStatus) (int, error) {
+	var count int64
+	result := r.db.WithContext(ctx).Model(&DBSwapOffer{}).
+		Where("status = ?", string(status)).
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count swap offers by status: %w", result.Error)
+	}
+	return int(count), nil
+}
+
+// swapOfferStatusStrings converts swap offer statuses to the strings they are stored as
+func swapOfferStatusStrings(status []hashperp.SwapOfferStatus) []string {
+	statusStrings := make([]string, len(status))
+	for i, s := range status {
+		statusStrings[i] = string(s)
+	}
+	return statusStrings
 }
 
 // FindOpenOffersByVTXO retrieves all open swap offers for a specific VTXO
@@ -710,6 +1259,37 @@ func (r *PostgresSwapOfferRepository) Update(ctx context.Context, offer *hashper
 	return nil
 }
 
+// ExpireOffers marks every open swap offer whose expiry time is before asOf as expired
+// in a single update, returning the number of offers updated
+func (r *PostgresSwapOfferRepository) ExpireOffers(ctx context.Context, asOf time.Time) (int, error) {
+	result := r.db.WithContext(ctx).
+		Model(&DBSwapOffer{}).
+		Where("status = ? AND expiry_time < ?", string(hashperp.OFFER_OPEN), asOf).
+		Updates(map[string]interface{}{
+			"status":     string(hashperp.OFFER_EXPIRED),
+			"updated_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to expire swap offers: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+// CountByStatus returns the number of swap offers in each status
+func (r *PostgresSwapOfferRepository) CountByStatus(ctx context.Context) (map[hashperp.SwapOfferStatus]int, error) {
+	rows, err := countByStatus(ctx, r.db, &DBSwapOffer{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count swap offers by status: %w", err)
+	}
+
+	counts := make(map[hashperp.SwapOfferStatus]int, len(rows))
+	for _, row := range rows {
+		counts[hashperp.SwapOfferStatus(row.Status)] = row.Count
+	}
+
+	return counts, nil
+}
+
 // Delete deletes a swap offer by ID
 func (r *PostgresSwapOfferRepository) Delete(ctx context.Context, id string) error {
 	result := r.db.WithContext(ctx).Delete(&DBSwapOffer{}, "id = ?", id)
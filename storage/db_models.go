@@ -6,22 +6,26 @@ import (
 	"time"
 
 	"github.com/lib/pq"
+	"gorm.io/gorm"
 )
 
 // DBContract is the database model for contracts
 type DBContract struct {
 	ID                  string          `gorm:"primary_key;type:uuid"`
-	ContractType        string          `gorm:"type:varchar(10);not null"`
+	ContractType        string          `gorm:"type:varchar(10);not null;index:idx_contracts_expiry_type,priority:2"`
 	StrikeRate          float64         `gorm:"type:decimal(18,8);not null"`
-	ExpiryBlockHeight   uint64          `gorm:"not null"`
+	ExpiryBlockHeight   uint64          `gorm:"not null;index:idx_contracts_expiry_type,priority:1"`
 	ExpiryDate          time.Time       `gorm:"not null"`
 	CreationTime        time.Time       `gorm:"not null"`
 	Status              string          `gorm:"type:varchar(20);not null"`
-	BuyerID             string          `gorm:"type:uuid;not null"`
-	SellerID            string          `gorm:"type:uuid;not null"`
+	BuyerID             string          `gorm:"type:uuid;not null;index"`
+	SellerID            string          `gorm:"type:uuid;not null;index"`
 	Size                float64         `gorm:"type:decimal(18,8);not null"`
+	SizeSats            int64           `gorm:"not null;default:0"` // Authoritative, 0 on rows written before it was added
 	BuyerVTXO           string          `gorm:"type:uuid"`
 	SellerVTXO          string          `gorm:"type:uuid"`
+	SetupTx             sql.NullString  `gorm:"type:text"`
+	SetupTxID           sql.NullString  `gorm:"type:varchar(100)"`
 	SettlementTx        sql.NullString  `gorm:"type:varchar(100)"`
 	SettlementRate      sql.NullFloat64 `gorm:"type:decimal(18,8)"`
 	RolledOverToID      sql.NullString  `gorm:"type:uuid"`
@@ -30,8 +34,26 @@ type DBContract struct {
 	SellerExited        bool            `gorm:"not null;default:false"`
 	BuyerExitTxHash     sql.NullString  `gorm:"type:varchar(100)"`
 	SellerExitTxHash    sql.NullString  `gorm:"type:varchar(100)"`
+	Leverage            float64         `gorm:"type:decimal(6,2);not null;default:1"`
+	BuyerLiquidated     bool            `gorm:"not null;default:false"`
+	SellerLiquidated    bool            `gorm:"not null;default:false"`
+	RolloverCount       int             `gorm:"not null;default:0"`
+	OriginBlockHeight   uint64          `gorm:"not null;default:0"`
+	ProposedWinnerID    sql.NullString  `gorm:"type:uuid"`
+	SettlementProposedHeight uint64     `gorm:"not null;default:0"`
+	SettlementDisputeDeadline uint64    `gorm:"not null;default:0"`
+	SettlementBlockHash sql.NullString  `gorm:"type:varchar(100)"`
+	IdempotencyKey      sql.NullString  `gorm:"type:varchar(64);uniqueIndex"`
+	SettlementMethod    string          `gorm:"type:varchar(10);not null;default:'SPOT'"`
+	ExitFeeRate         float64         `gorm:"type:decimal(6,4);not null;default:0.05"`
+	TimeoutExitBlocks   uint64          `gorm:"not null;default:144"`
+	SweepWindowBlocks   uint64          `gorm:"not null;default:144"`
+	BuyerPool           json.RawMessage `gorm:"type:jsonb"`
+	SellerPool          json.RawMessage `gorm:"type:jsonb"`
+	Version             uint64          `gorm:"not null;default:0"` // Optimistic concurrency control
 	CreatedAt           time.Time       `gorm:"not null"`
 	UpdatedAt           time.Time       `gorm:"not null"`
+	DeletedAt           gorm.DeletedAt  `gorm:"index"` // Set when the contract is archived
 }
 
 // TableName sets the table name for DBContract
@@ -45,6 +67,7 @@ type DBVTXO struct {
 	ContractID        string         `gorm:"type:uuid;not null;index"`
 	OwnerID           string         `gorm:"type:uuid;not null;index"`
 	Amount            float64        `gorm:"type:decimal(18,8);not null"`
+	AmountSats        int64          `gorm:"not null;default:0"` // Authoritative, 0 on rows written before it was added
 	ScriptPath        string         `gorm:"type:text;not null"`
 	CreationTimestamp time.Time      `gorm:"not null"`
 	SignatureData     []byte         `gorm:"type:bytea"`
@@ -54,8 +77,10 @@ type DBVTXO struct {
 	IsActive          bool           `gorm:"not null;default:true"`
 	ExitTxHash        sql.NullString `gorm:"type:varchar(100)"`
 	ExitTimestamp     sql.NullTime   `gorm:"type:timestamp"`
+	Version           uint64         `gorm:"not null;default:0"` // Optimistic concurrency control
 	CreatedAt         time.Time      `gorm:"not null"`
 	UpdatedAt         time.Time      `gorm:"not null"`
+	DeletedAt         gorm.DeletedAt `gorm:"index"` // Set when the VTXO is archived
 }
 
 // TableName sets the table name for DBVTXO
@@ -66,17 +91,20 @@ func (DBVTXO) TableName() string {
 // DBOrder is the database model for orders
 type DBOrder struct {
 	ID                  string         `gorm:"primary_key;type:uuid"`
-	UserID              string         `gorm:"type:uuid;not null;index"`
+	UserID              string         `gorm:"type:uuid;not null;index;uniqueIndex:idx_orders_user_client_order_id"`
 	OrderType           string         `gorm:"type:varchar(10);not null"`
-	ContractType        string         `gorm:"type:varchar(10);not null"`
-	StrikeRate          float64        `gorm:"type:decimal(18,8);not null"`
-	ExpiryBlockHeight   uint64         `gorm:"not null"`
+	ContractType        string         `gorm:"type:varchar(10);not null;index:idx_orders_book,priority:1"`
+	StrikeRate          float64        `gorm:"type:decimal(18,8);not null;index:idx_orders_book,priority:4"`
+	ExpiryBlockHeight   uint64         `gorm:"not null;index:idx_orders_status_expiry,priority:2;index:idx_orders_book,priority:2"`
 	ExpiryDate          time.Time      `gorm:"not null"`
 	Size                float64        `gorm:"type:decimal(18,8);not null"`
-	Status              string         `gorm:"type:varchar(20);not null"`
+	Status              string         `gorm:"type:varchar(20);not null;index:idx_orders_status_expiry,priority:1;index:idx_orders_book,priority:3"`
 	CreationTime        time.Time      `gorm:"not null"`
 	MatchedOrderID      sql.NullString `gorm:"type:uuid"`
 	ResultingContractID sql.NullString `gorm:"type:uuid"`
+	ClientOrderID       sql.NullString `gorm:"type:varchar(64);uniqueIndex:idx_orders_user_client_order_id"`
+	SelfTradePolicy     string         `gorm:"type:varchar(20);not null;default:''"`
+	TimeInForce         string         `gorm:"type:varchar(3);not null;default:'GTC'"`
 	CreatedAt           time.Time      `gorm:"not null"`
 	UpdatedAt           time.Time      `gorm:"not null"`
 }
@@ -86,6 +114,19 @@ func (DBOrder) TableName() string {
 	return "orders"
 }
 
+// DBOrderBookSequence is the database model for the change sequence of a market's order book
+type DBOrderBookSequence struct {
+	ContractType      string    `gorm:"primary_key;type:varchar(10)"`
+	ExpiryBlockHeight uint64    `gorm:"primary_key;autoIncrement:false"`
+	Sequence          uint64    `gorm:"not null;default:0"`
+	UpdatedAt         time.Time `gorm:"not null"`
+}
+
+// TableName sets the table name for DBOrderBookSequence
+func (DBOrderBookSequence) TableName() string {
+	return "order_book_sequences"
+}
+
 // DBSwapOffer is the database model for swap offers
 type DBSwapOffer struct {
 	ID              string          `gorm:"primary_key;type:uuid"`
@@ -94,8 +135,8 @@ type DBSwapOffer struct {
 	ContractID      string          `gorm:"type:uuid;not null;index"`
 	OfferedRate     float64         `gorm:"type:decimal(18,8);not null"`
 	CreationTime    time.Time       `gorm:"not null"`
-	ExpiryTime      time.Time       `gorm:"not null"`
-	Status          string          `gorm:"type:varchar(20);not null"`
+	ExpiryTime      time.Time       `gorm:"not null;index:idx_swap_offers_status_expiry,priority:2"`
+	Status          string          `gorm:"type:varchar(20);not null;index:idx_swap_offers_status_expiry,priority:1"`
 	AcceptorID      sql.NullString  `gorm:"type:uuid"`
 	TargetUserID    sql.NullString  `gorm:"type:uuid"`
 	SwapType        sql.NullString  `gorm:"type:varchar(20)"`
@@ -112,14 +153,15 @@ func (DBSwapOffer) TableName() string {
 // DBTransaction is the database model for transactions
 type DBTransaction struct {
 	ID              string         `gorm:"primary_key;type:uuid"`
-	Type            string         `gorm:"type:varchar(30);not null"`
-	Timestamp       time.Time      `gorm:"not null"`
+	Type            string         `gorm:"type:varchar(30);not null;index:idx_transactions_type_timestamp,priority:1"`
+	Timestamp       time.Time      `gorm:"not null;index:idx_transactions_type_timestamp,priority:2"`
 	ContractID      string         `gorm:"type:uuid;index"`
 	UserIDs         pq.StringArray `gorm:"type:text[]"`
-	TxHash          string         `gorm:"type:varchar(100)"`
+	TxHash          string         `gorm:"type:varchar(100);uniqueIndex:idx_transactions_tx_hash,where:tx_hash <> ''"`
 	Amount          float64        `gorm:"type:decimal(18,8);not null"`
+	AmountSats      int64          `gorm:"not null;default:0"` // Authoritative, 0 on rows written before it was added
 	BTCPerPHPerDay  float64        `gorm:"type:decimal(18,8)"`
-	BlockHeight     uint64        
+	BlockHeight     uint64         `gorm:"index"`
 	Status          string         `gorm:"type:varchar(20);default:'COMPLETED'"`
 	RelatedEntities json.RawMessage `gorm:"type:jsonb"`
 	CreatedAt       time.Time      `gorm:"not null"`
@@ -131,11 +173,69 @@ func (DBTransaction) TableName() string {
 	return "transactions"
 }
 
+// DBWebhookSubscription is the database model for webhook subscriptions
+type DBWebhookSubscription struct {
+	ID         string         `gorm:"primary_key;type:uuid"`
+	UserID     string         `gorm:"type:uuid;not null;index"`
+	URL        string         `gorm:"type:text;not null"`
+	EventTypes pq.StringArray `gorm:"type:text[]"`
+	Secret     string         `gorm:"type:varchar(64);not null"`
+	CreatedAt  time.Time      `gorm:"not null"`
+	UpdatedAt  time.Time      `gorm:"not null"`
+}
+
+// TableName sets the table name for DBWebhookSubscription
+func (DBWebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// DBWebhookDelivery is the database model for webhook deliveries
+type DBWebhookDelivery struct {
+	ID             string       `gorm:"primary_key;type:uuid"`
+	SubscriptionID string       `gorm:"type:uuid;not null;index"`
+	TransactionID  string       `gorm:"type:uuid;not null"`
+	Payload        []byte       `gorm:"type:bytea;not null"`
+	Status         string       `gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_status_next,priority:1"`
+	Attempts       int          `gorm:"not null;default:0"`
+	LastError      string       `gorm:"type:text"`
+	NextAttemptAt  time.Time    `gorm:"not null;index:idx_webhook_deliveries_status_next,priority:2"`
+	DeliveredAt    sql.NullTime `gorm:"type:timestamp"`
+	CreatedAt      time.Time    `gorm:"not null"`
+	UpdatedAt      time.Time    `gorm:"not null"`
+}
+
+// TableName sets the table name for DBWebhookDelivery
+func (DBWebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// DBContractTemplate is the database model for contract templates
+type DBContractTemplate struct {
+	ID               string        `gorm:"primary_key;type:uuid"`
+	Name             string        `gorm:"type:varchar(100);not null;uniqueIndex"`
+	ContractType     string        `gorm:"type:varchar(10);not null"`
+	MinStrikeRate    float64       `gorm:"type:decimal(18,8);not null"`
+	MaxStrikeRate    float64       `gorm:"type:decimal(18,8);not null"`
+	ExpiryOffsets    pq.Int64Array `gorm:"type:bigint[];not null"`
+	MinSize          float64       `gorm:"type:decimal(18,8);not null"`
+	MaxSize          float64       `gorm:"type:decimal(18,8);not null"`
+	DefaultSize      float64       `gorm:"type:decimal(18,8);not null"`
+	Leverage         float64       `gorm:"type:decimal(6,2);not null;default:1"`
+	SettlementMethod string        `gorm:"type:varchar(10);not null;default:'SPOT'"`
+	CreatedAt        time.Time     `gorm:"not null"`
+	UpdatedAt        time.Time     `gorm:"not null"`
+}
+
+// TableName sets the table name for DBContractTemplate
+func (DBContractTemplate) TableName() string {
+	return "contract_templates"
+}
+
 // DBHashRateData is the database model for hash rate data
 type DBHashRateData struct {
 	ID             uint64    `gorm:"primary_key;auto_increment"`
 	Timestamp      time.Time `gorm:"not null;index"`
-	BlockHeight    uint64    `gorm:"not null;unique_index"`
+	BlockHeight    uint64    `gorm:"not null;uniqueIndex"`
 	HashRate       float64   `gorm:"type:decimal(18,8);not null"`
 	BTCPerPHPerDay float64   `gorm:"type:decimal(18,8);not null"`
 	CreatedAt      time.Time `gorm:"not null"`
@@ -151,6 +251,7 @@ func (DBHashRateData) TableName() string {
 type DBUser struct {
 	ID        string    `gorm:"primary_key;type:uuid"`
 	PublicKey []byte    `gorm:"type:bytea;not null"`
+	Balance   float64   `gorm:"type:decimal(18,8);not null;default:0"`
 	CreatedAt time.Time `gorm:"not null"`
 	UpdatedAt time.Time `gorm:"not null"`
 }
@@ -179,21 +280,34 @@ func (DBPreSignedExit) TableName() string {
 	return "pre_signed_exits"
 }
 
-// DBPreSignedExit is the database model for pre-signed exit transactions
-type DBPreSignedExit struct {
-	ID           string     `gorm:"primary_key;type:uuid"`
-	VTXOID       string     `gorm:"type:uuid;not null;index"`
-	ContractID   string     `gorm:"type:uuid;not null;index"`
-	UserID       string     `gorm:"type:uuid;not null"`
-	ExitTxHex    string     `gorm:"type:text;not null"`
-	CreationTime time.Time  `gorm:"not null"`
-	IsUsed       bool       `gorm:"not null;default:false"`
-	UsedTime     *time.Time `gorm:""`
-	CreatedAt    time.Time  `gorm:"not null"`
-	UpdatedAt    time.Time  `gorm:"not null"`
+// DBContractScripts is the database model for the scripts generated for a contract
+type DBContractScripts struct {
+	ContractID       string          `gorm:"primary_key;type:uuid"`
+	BuyerScriptPath  string          `gorm:"type:text;not null"`
+	SellerScriptPath string          `gorm:"type:text;not null"`
+	ExitScripts      json.RawMessage `gorm:"type:jsonb;not null"`
+	CreatedAt        time.Time       `gorm:"not null"`
+	UpdatedAt        time.Time       `gorm:"not null"`
 }
 
-// TableName sets the table name for DBPreSignedExit
-func (DBPreSignedExit) TableName() string {
-	return "pre_signed_exits"
+// TableName sets the table name for DBContractScripts
+func (DBContractScripts) TableName() string {
+	return "contract_scripts"
+}
+
+// DBStatusChange is the database model for status change audit records
+type DBStatusChange struct {
+	ID         string    `gorm:"primary_key;type:uuid"`
+	EntityType string    `gorm:"type:varchar(20);not null;index:idx_status_changes_entity,priority:1"`
+	EntityID   string    `gorm:"type:uuid;not null;index:idx_status_changes_entity,priority:2"`
+	OldStatus  string    `gorm:"type:varchar(30);not null;default:''"`
+	NewStatus  string    `gorm:"type:varchar(30);not null"`
+	ActorID    string    `gorm:"type:varchar(64);not null"`
+	Timestamp  time.Time `gorm:"not null;index:idx_status_changes_entity,priority:3"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+// TableName sets the table name for DBStatusChange
+func (DBStatusChange) TableName() string {
+	return "status_changes"
 }
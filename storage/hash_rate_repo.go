@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashperp/hashperp"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresHashRateRepository implements the HashRateRepository interface
+type PostgresHashRateRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresHashRateRepository creates a new PostgreSQL-based hash rate repository
+func NewPostgresHashRateRepository(db *gorm.DB) hashperp.HashRateRepository {
+	return &PostgresHashRateRepository{
+		db: db,
+	}
+}
+
+// Create stores hash rate data for a block. Observations are immutable, so a second
+// write for the same block height is ignored and the first observation is kept.
+func (r *PostgresHashRateRepository) Create(ctx context.Context, data *hashperp.HashRateData) error {
+	dbData := convertHashRateDataToDBHashRateData(data)
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "block_height"}},
+			DoNothing: true,
+		}).
+		Create(dbData)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create hash rate data: %w", result.Error)
+	}
+
+	return nil
+}
+
+// FindByBlockHeight retrieves hash rate data for a specific block height
+func (r *PostgresHashRateRepository) FindByBlockHeight(ctx context.Context, blockHeight uint64) (*hashperp.HashRateData, error) {
+	var dbData DBHashRateData
+	result := r.db.WithContext(ctx).Where("block_height = ?", blockHeight).First(&dbData)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find hash rate data: %w", result.Error)
+	}
+
+	return convertDBHashRateDataToHashRateData(&dbData), nil
+}
+
+// FindByTimeRange retrieves hash rate data within a time range, ordered by block height
+func (r *PostgresHashRateRepository) FindByTimeRange(ctx context.Context, startTime, endTime time.Time) ([]*hashperp.HashRateData, error) {
+	var dbData []DBHashRateData
+	result := r.db.WithContext(ctx).
+		Where("timestamp BETWEEN ? AND ?", startTime, endTime).
+		Order("block_height ASC").
+		Find(&dbData)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find hash rate data by time range: %w", result.Error)
+	}
+
+	data := make([]*hashperp.HashRateData, len(dbData))
+	for i, d := range dbData {
+		data[i] = convertDBHashRateDataToHashRateData(&d)
+	}
+
+	return data, nil
+}
+
+// FindByBlockRange retrieves hash rate data between two block heights inclusive, ordered by block height
+func (r *PostgresHashRateRepository) FindByBlockRange(ctx context.Context, startHeight, endHeight uint64) ([]*hashperp.HashRateData, error) {
+	var dbData []DBHashRateData
+	result := r.db.WithContext(ctx).
+		Where("block_height BETWEEN ? AND ?", startHeight, endHeight).
+		Order("block_height ASC").
+		Find(&dbData)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find hash rate data by block range: %w", result.Error)
+	}
+
+	data := make([]*hashperp.HashRateData, len(dbData))
+	for i, d := range dbData {
+		data[i] = convertDBHashRateDataToHashRateData(&d)
+	}
+
+	return data, nil
+}
+
+// GetLatest retrieves the most recent hash rate data
+func (r *PostgresHashRateRepository) GetLatest(ctx context.Context) (*hashperp.HashRateData, error) {
+	var dbData DBHashRateData
+	result := r.db.WithContext(ctx).Order("block_height DESC").First(&dbData)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find latest hash rate data: %w", result.Error)
+	}
+
+	return convertDBHashRateDataToHashRateData(&dbData), nil
+}
+
+// Update updates existing hash rate data
+func (r *PostgresHashRateRepository) Update(ctx context.Context, data *hashperp.HashRateData) error {
+	result := r.db.WithContext(ctx).Model(&DBHashRateData{}).
+		Where("block_height = ?", data.BlockHeight).
+		Updates(map[string]interface{}{
+			"timestamp":          data.Timestamp,
+			"hash_rate":          data.HashRate,
+			"btc_per_ph_per_day": data.BTCPerPHPerDay,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update hash rate data: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("hash rate data not found for block %d", data.BlockHeight)
+	}
+
+	return nil
+}
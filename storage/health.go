@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashperp/hashperp"
+	"gorm.io/gorm"
+)
+
+// PostgresPinger implements the DatabasePinger interface using PostgreSQL
+type PostgresPinger struct {
+	db *gorm.DB
+}
+
+// NewPostgresPinger creates a new PostgreSQL connection checker
+func NewPostgresPinger(db *gorm.DB) hashperp.DatabasePinger {
+	return &PostgresPinger{
+		db: db,
+	}
+}
+
+// Ping verifies the database connection is alive
+func (p *PostgresPinger) Ping(ctx context.Context) error {
+	sqlDB, err := p.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashperp/hashperp"
+	"gorm.io/gorm"
+)
+
+// PostgresMarketSnapshotRepository implements the MarketSnapshotRepository interface
+type PostgresMarketSnapshotRepository struct {
+	db             *gorm.DB
+	eventPublisher hashperp.EventPublisher
+}
+
+// NewPostgresMarketSnapshotRepository creates a new PostgreSQL-based market snapshot repository
+func NewPostgresMarketSnapshotRepository(db *gorm.DB) hashperp.MarketSnapshotRepository {
+	return &PostgresMarketSnapshotRepository{
+		db:             db,
+		eventPublisher: hashperp.NewNoopEventPublisher(),
+	}
+}
+
+// SetEventPublisher sets the publisher that receives stored contracts that could not be fully read
+func (r *PostgresMarketSnapshotRepository) SetEventPublisher(publisher hashperp.EventPublisher) {
+	r.eventPublisher = publisher
+}
+
+// FindMarketSnapshot retrieves open orders and active contracts for a market in one
+// read-only, repeatable-read transaction so both result sets reflect the same state
+func (r *PostgresMarketSnapshotRepository) FindMarketSnapshot(
+	ctx context.Context,
+	contractType hashperp.ContractType,
+	expiryBlockHeight uint64,
+) ([]*hashperp.Order, []*hashperp.Contract, error) {
+	var dbOrders []DBOrder
+	var dbContracts []DBContract
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("contract_type = ? AND expiry_block_height = ? AND status = ?",
+				string(contractType), expiryBlockHeight, string(hashperp.OPEN)).
+			Order("strike_rate ASC, creation_time ASC").
+			Find(&dbOrders)
+		if result.Error != nil {
+			return fmt.Errorf("failed to find open orders: %w", result.Error)
+		}
+
+		result = tx.
+			Where("contract_type = ? AND expiry_block_height = ? AND status IN ?",
+				string(contractType), expiryBlockHeight,
+				[]string{string(hashperp.ACTIVE), string(hashperp.CLOSE_TO_EXPIRY)}).
+			Find(&dbContracts)
+		if result.Error != nil {
+			return fmt.Errorf("failed to find active contracts: %w", result.Error)
+		}
+
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read market snapshot: %w", err)
+	}
+
+	orders := make([]*hashperp.Order, len(dbOrders))
+	for i, dbOrder := range dbOrders {
+		orders[i] = convertDBOrderToOrder(&dbOrder)
+	}
+
+	contracts := make([]*hashperp.Contract, len(dbContracts))
+	for i, dbContract := range dbContracts {
+		contracts[i] = convertDBContractToContract(ctx, r.eventPublisher, &dbContract)
+	}
+
+	return orders, contracts, nil
+}
@@ -176,11 +176,17 @@ func MigrateDB(db *gorm.DB) error {
 		&DBContract{},
 		&DBVTXO{},
 		&DBOrder{},
+		&DBOrderBookSequence{},
 		&DBSwapOffer{},
 		&DBTransaction{},
 		&DBHashRateData{},
 		&DBUser{},
 		&DBPreSignedExit{},
+		&DBWebhookSubscription{},
+		&DBWebhookDelivery{},
+		&DBContractTemplate{},
+		&DBContractScripts{},
+		&DBStatusChange{},
 	)
 	
 	if err != nil {
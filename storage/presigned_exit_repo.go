@@ -1,5 +1,3 @@
-// Create storage/presigned_exit_repo.go
-
 package storage
 
 import (
@@ -65,7 +63,10 @@ func (r *PostgresPreSignedExitRepository) FindByID(ctx context.Context, id strin
 // FindByVTXO retrieves all pre-signed exits for a VTXO
 func (r *PostgresPreSignedExitRepository) FindByVTXO(ctx context.Context, vtxoID string) ([]*hashperp.PreSignedExit, error) {
 	var dbPreSignedExits []DBPreSignedExit
-	result := r.db.WithContext(ctx).Where("vtxo_id = ?", vtxoID).Find(&dbPreSignedExits)
+	result := r.db.WithContext(ctx).
+		Where("vtxo_id = ?", vtxoID).
+		Order("creation_time DESC").
+		Find(&dbPreSignedExits)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to find pre-signed exits for VTXO: %w", result.Error)
 	}
@@ -78,6 +79,45 @@ func (r *PostgresPreSignedExitRepository) FindByVTXO(ctx context.Context, vtxoID
 	return preSignedExits, nil
 }
 
+// FindUnused retrieves the pre-signed exits for a VTXO that have not been broadcast
+func (r *PostgresPreSignedExitRepository) FindUnused(ctx context.Context, vtxoID string) ([]*hashperp.PreSignedExit, error) {
+	var dbPreSignedExits []DBPreSignedExit
+	result := r.db.WithContext(ctx).
+		Where("vtxo_id = ? AND is_used = ?", vtxoID, false).
+		Order("creation_time DESC").
+		Find(&dbPreSignedExits)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find unused pre-signed exits for VTXO: %w", result.Error)
+	}
+	
+	preSignedExits := make([]*hashperp.PreSignedExit, len(dbPreSignedExits))
+	for i, dbPreSignedExit := range dbPreSignedExits {
+		preSignedExits[i] = convertDBPreSignedExitToPreSignedExit(&dbPreSignedExit)
+	}
+	
+	return preSignedExits, nil
+}
+
+// Update updates an existing pre-signed exit
+func (r *PostgresPreSignedExitRepository) Update(ctx context.Context, preSignedExit *hashperp.PreSignedExit) error {
+	result := r.db.WithContext(ctx).Model(&DBPreSignedExit{}).
+		Where("id = ?", preSignedExit.ID).
+		Updates(map[string]interface{}{
+			"exit_tx_hex": preSignedExit.ExitTxHex,
+			"is_used":     preSignedExit.IsUsed,
+			"used_time":   preSignedExit.UsedTime,
+		})
+	
+	if result.Error != nil {
+		return fmt.Errorf("failed to update pre-signed exit: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return hashperp.ErrPreSignedExitNotFound
+	}
+	
+	return nil
+}
+
 // MarkAsUsed marks a pre-signed exit as used
 func (r *PostgresPreSignedExitRepository) MarkAsUsed(ctx context.Context, id string) error {
 	now := time.Now().UTC()
@@ -94,3 +134,13 @@ func (r *PostgresPreSignedExitRepository) MarkAsUsed(ctx context.Context, id str
 	
 	return nil
 }
+
+// Delete deletes a pre-signed exit
+func (r *PostgresPreSignedExitRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&DBPreSignedExit{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete pre-signed exit: %w", result.Error)
+	}
+	
+	return nil
+}
@@ -14,8 +14,33 @@ import (
 
 // Helper functions for model conversion must be updated to reflect all new fields
 
+// updateVersioned writes every column of a row that is still at the version the caller read,
+// with the version already incremented in the model. Archived rows are never matched. No
+// matching row means another writer got there first.
+func updateVersioned(ctx context.Context, db *gorm.DB, model interface{}, id string, version uint64) error {
+	result := db.WithContext(ctx).Model(model).
+		Where("id = ? AND version = ?", id, version).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
+		Updates(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return hashperp.ErrConcurrentModification
+	}
+	return nil
+}
+
+// publishConversionFailure reports a stored record that could not be fully read back
+func publishConversionFailure(ctx context.Context, publisher hashperp.EventPublisher, event hashperp.Event) {
+	event.Type = hashperp.EVENT_OPERATION_FAILED
+	event.Timestamp = time.Now().UTC()
+	publisher.Publish(ctx, event)
+}
+
 // Updated convertDBContractToContract to include all fields
-func convertDBContractToContract(dbContract *DBContract) *hashperp.Contract {
+func convertDBContractToContract(ctx context.Context, publisher hashperp.EventPublisher, dbContract *DBContract) *hashperp.Contract {
 	contract := &hashperp.Contract{
 		ID:                dbContract.ID,
 		ContractType:      hashperp.ContractType(dbContract.ContractType),
@@ -31,6 +56,30 @@ func convertDBContractToContract(dbContract *DBContract) *hashperp.Contract {
 		SellerVTXO:        dbContract.SellerVTXO,
 		BuyerExited:       dbContract.BuyerExited,
 		SellerExited:      dbContract.SellerExited,
+		Leverage:          dbContract.Leverage,
+		BuyerLiquidated:   dbContract.BuyerLiquidated,
+		SellerLiquidated:  dbContract.SellerLiquidated,
+		RolloverCount:     dbContract.RolloverCount,
+		OriginBlockHeight: dbContract.OriginBlockHeight,
+		SettlementProposedHeight: dbContract.SettlementProposedHeight,
+		SettlementDisputeDeadline: dbContract.SettlementDisputeDeadline,
+		SettlementMethod:  hashperp.SettlementMethod(dbContract.SettlementMethod),
+		Version:           dbContract.Version,
+		ExitFeeRate:       dbContract.ExitFeeRate,
+		TimeoutExitBlocks: dbContract.TimeoutExitBlocks,
+		SweepWindowBlocks: dbContract.SweepWindowBlocks,
+	}
+
+	if dbContract.ProposedWinnerID.Valid {
+		contract.ProposedWinnerID = dbContract.ProposedWinnerID.String
+	}
+
+	if dbContract.SetupTx.Valid {
+		contract.SetupTx = dbContract.SetupTx.String
+	}
+
+	if dbContract.SetupTxID.Valid {
+		contract.SetupTxID = dbContract.SetupTxID.String
 	}
 
 	if dbContract.SettlementTx.Valid {
@@ -41,6 +90,14 @@ func convertDBContractToContract(dbContract *DBContract) *hashperp.Contract {
 		contract.SettlementRate = dbContract.SettlementRate.Float64
 	}
 
+	if dbContract.SettlementBlockHash.Valid {
+		contract.SettlementBlockHash = dbContract.SettlementBlockHash.String
+	}
+
+	if dbContract.IdempotencyKey.Valid {
+		contract.IdempotencyKey = dbContract.IdempotencyKey.String
+	}
+
 	if dbContract.RolledOverToID.Valid {
 		contract.RolledOverToID = dbContract.RolledOverToID.String
 	}
@@ -57,6 +114,29 @@ func convertDBContractToContract(dbContract *DBContract) *hashperp.Contract {
 		contract.SellerExitTxHash = dbContract.SellerExitTxHash.String
 	}
 
+	// Parse pool members if set
+	if dbContract.BuyerPool != nil {
+		if err := json.Unmarshal(dbContract.BuyerPool, &contract.BuyerPool); err != nil {
+			publishConversionFailure(ctx, publisher, hashperp.Event{
+				ContractID: dbContract.ID,
+				Operation:  "unmarshal_buyer_pool",
+				Error:      err.Error(),
+			})
+		}
+	}
+
+	if dbContract.SellerPool != nil {
+		if err := json.Unmarshal(dbContract.SellerPool, &contract.SellerPool); err != nil {
+			publishConversionFailure(ctx, publisher, hashperp.Event{
+				ContractID: dbContract.ID,
+				Operation:  "unmarshal_seller_pool",
+				Error:      err.Error(),
+			})
+		}
+	}
+
+	contract.SizeSats, contract.Size = amountFromSats(dbContract.SizeSats, dbContract.Size)
+
 	return contract
 }
 
@@ -71,6 +151,7 @@ func convertDBVTXOToVTXO(dbVTXO *DBVTXO) *hashperp.VTXO {
 		CreationTimestamp: dbVTXO.CreationTimestamp,
 		SignatureData:     dbVTXO.SignatureData,
 		IsActive:          dbVTXO.IsActive,
+		Version:           dbVTXO.Version,
 	}
 
 	if dbVTXO.SwappedFromID.Valid {
@@ -93,6 +174,8 @@ func convertDBVTXOToVTXO(dbVTXO *DBVTXO) *hashperp.VTXO {
 		vtxo.ExitTimestamp = dbVTXO.ExitTimestamp.Time
 	}
 
+	vtxo.AmountSats, vtxo.Amount = amountFromSats(dbVTXO.AmountSats, dbVTXO.Amount)
+
 	return vtxo
 }
 
@@ -109,6 +192,8 @@ func convertDBOrderToOrder(dbOrder *DBOrder) *hashperp.Order {
 		Size:              dbOrder.Size,
 		Status:            hashperp.OrderStatus(dbOrder.Status),
 		CreationTime:      dbOrder.CreationTime,
+		SelfTradePolicy:   hashperp.SelfTradePolicy(dbOrder.SelfTradePolicy),
+		TimeInForce:       hashperp.TimeInForce(dbOrder.TimeInForce),
 	}
 
 	if dbOrder.MatchedOrderID.Valid {
@@ -119,6 +204,10 @@ func convertDBOrderToOrder(dbOrder *DBOrder) *hashperp.Order {
 		order.ResultingContractID = dbOrder.ResultingContractID.String
 	}
 
+	if dbOrder.ClientOrderID.Valid {
+		order.ClientOrderID = dbOrder.ClientOrderID.String
+	}
+
 	return order
 }
 
@@ -189,11 +278,23 @@ func convertDBTransactionToTransaction(dbTransaction *DBTransaction) (*hashperp.
 		tx.RelatedEntities = make(map[string]string)
 	}
 	
+	tx.AmountSats, tx.Amount = amountFromSats(dbTransaction.AmountSats, dbTransaction.Amount)
+	
 	return tx, nil
 }
 
+// amountFromSats returns the satoshi amount of a row and its BTC value, deriving the
+// satoshis from the BTC column for rows written before the satoshi column was added
+func amountFromSats(sats int64, btc float64) (int64, float64) {
+	if sats == 0 {
+		sats = hashperp.BTCToSats(btc)
+	}
+	return sats, hashperp.SatsToBTC(sats)
+}
+
 // Updated convertContractToDBContract to include all fields
 func convertContractToDBContract(contract *hashperp.Contract) *DBContract {
+	contract.SizeSats = hashperp.BTCToSats(contract.Size) // Keep the caller's copy in step with the stored one
 	dbContract := &DBContract{
 		ID:                contract.ID,
 		ContractType:      string(contract.ContractType),
@@ -205,13 +306,47 @@ func convertContractToDBContract(contract *hashperp.Contract) *DBContract {
 		BuyerID:           contract.BuyerID,
 		SellerID:          contract.SellerID,
 		Size:              contract.Size,
+		SizeSats:          contract.SizeSats,
 		BuyerVTXO:         contract.BuyerVTXO,
 		SellerVTXO:        contract.SellerVTXO,
+		Version:           contract.Version,
 		BuyerExited:       contract.BuyerExited,
 		SellerExited:      contract.SellerExited,
+		Leverage:          contract.Leverage,
+		BuyerLiquidated:   contract.BuyerLiquidated,
+		SellerLiquidated:  contract.SellerLiquidated,
+		RolloverCount:     contract.RolloverCount,
+		OriginBlockHeight: contract.OriginBlockHeight,
+		SettlementProposedHeight: contract.SettlementProposedHeight,
+		SettlementDisputeDeadline: contract.SettlementDisputeDeadline,
+		SettlementMethod:  string(contract.SettlementMethod),
+		ExitFeeRate:       contract.ExitFeeRate,
+		TimeoutExitBlocks: contract.TimeoutExitBlocks,
+		SweepWindowBlocks: contract.SweepWindowBlocks,
 	}
 
 	// Set nullable fields
+	if contract.ProposedWinnerID != "" {
+		dbContract.ProposedWinnerID = sql.NullString{
+			String: contract.ProposedWinnerID,
+			Valid:  true,
+		}
+	}
+
+	if contract.SetupTx != "" {
+		dbContract.SetupTx = sql.NullString{
+			String: contract.SetupTx,
+			Valid:  true,
+		}
+	}
+
+	if contract.SetupTxID != "" {
+		dbContract.SetupTxID = sql.NullString{
+			String: contract.SetupTxID,
+			Valid:  true,
+		}
+	}
+
 	if contract.SettlementTx != "" {
 		dbContract.SettlementTx = sql.NullString{
 			String: contract.SettlementTx,
@@ -226,6 +361,20 @@ func convertContractToDBContract(contract *hashperp.Contract) *DBContract {
 		}
 	}
 
+	if contract.SettlementBlockHash != "" {
+		dbContract.SettlementBlockHash = sql.NullString{
+			String: contract.SettlementBlockHash,
+			Valid:  true,
+		}
+	}
+
+	if contract.IdempotencyKey != "" {
+		dbContract.IdempotencyKey = sql.NullString{
+			String: contract.IdempotencyKey,
+			Valid:  true,
+		}
+	}
+
 	if contract.RolledOverToID != "" {
 		dbContract.RolledOverToID = sql.NullString{
 			String: contract.RolledOverToID,
@@ -254,20 +403,36 @@ func convertContractToDBContract(contract *hashperp.Contract) *DBContract {
 		}
 	}
 
+	// Convert pool members to JSON
+	if len(contract.BuyerPool) > 0 {
+		if poolBytes, err := json.Marshal(contract.BuyerPool); err == nil {
+			dbContract.BuyerPool = poolBytes
+		}
+	}
+
+	if len(contract.SellerPool) > 0 {
+		if poolBytes, err := json.Marshal(contract.SellerPool); err == nil {
+			dbContract.SellerPool = poolBytes
+		}
+	}
+
 	return dbContract
 }
 
 // Updated convertVTXOToDBVTXO to include all fields
 func convertVTXOToDBVTXO(vtxo *hashperp.VTXO) *DBVTXO {
+	vtxo.AmountSats = hashperp.BTCToSats(vtxo.Amount) // Keep the caller's copy in step with the stored one
 	dbVTXO := &DBVTXO{
 		ID:                vtxo.ID,
 		ContractID:        vtxo.ContractID,
 		OwnerID:           vtxo.OwnerID,
 		Amount:            vtxo.Amount,
+		AmountSats:        vtxo.AmountSats,
 		ScriptPath:        vtxo.ScriptPath,
 		CreationTimestamp: vtxo.CreationTimestamp,
 		SignatureData:     vtxo.SignatureData,
 		IsActive:          vtxo.IsActive,
+		Version:           vtxo.Version,
 	}
 
 	if vtxo.SwappedFromID != "" {
@@ -308,6 +473,55 @@ func convertVTXOToDBVTXO(vtxo *hashperp.VTXO) *DBVTXO {
 	return dbVTXO
 }
 
+// convertDBHashRateDataToHashRateData converts a database hash rate model to a domain model
+func convertDBHashRateDataToHashRateData(dbData *DBHashRateData) *hashperp.HashRateData {
+	return &hashperp.HashRateData{
+		Timestamp:      dbData.Timestamp,
+		BlockHeight:    dbData.BlockHeight,
+		HashRate:       dbData.HashRate,
+		BTCPerPHPerDay: dbData.BTCPerPHPerDay,
+	}
+}
+
+// convertHashRateDataToDBHashRateData converts a domain hash rate model to a database model
+func convertHashRateDataToDBHashRateData(data *hashperp.HashRateData) *DBHashRateData {
+	return &DBHashRateData{
+		Timestamp:      data.Timestamp,
+		BlockHeight:    data.BlockHeight,
+		HashRate:       data.HashRate,
+		BTCPerPHPerDay: data.BTCPerPHPerDay,
+	}
+}
+
+// applyPageRequest orders a list query by creation time then ID and restricts it to the
+// requested page. One extra row is fetched so callers can tell whether another page follows.
+func applyPageRequest(query *gorm.DB, timeColumn string, page hashperp.PageRequest) (*gorm.DB, error) {
+	if err := hashperp.ValidatePageRequest(page); err != nil {
+		return nil, err
+	}
+
+	query = query.Order(timeColumn + " ASC, id ASC")
+
+	if page.Cursor != "" {
+		cursorTime, cursorID, err := hashperp.DecodePageCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("("+timeColumn+", id) > (?, ?)", cursorTime, cursorID)
+	}
+
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit + 1)
+	}
+
+	return query, nil
+}
+
+// hasNextPage reports whether a query built by applyPageRequest returned more rows than the page holds
+func hasNextPage(rowCount int, page hashperp.PageRequest) bool {
+	return page.Limit > 0 && rowCount > page.Limit
+}
+
 // Additional repository function to support the new VTXO methods
 
 // FindActiveByContract finds all active VTXOs for a contract
@@ -345,16 +559,42 @@ func (r *PostgresVTXORepository) CountActiveByContract(ctx context.Context, cont
 
 // PostgresTransactionRepository implements the TransactionRepository interface
 type PostgresTransactionRepository struct {
-	db *gorm.DB
+	db             *gorm.DB
+	eventPublisher hashperp.EventPublisher
 }
 
 // NewPostgresTransactionRepository creates a new PostgreSQL-based transaction repository
 func NewPostgresTransactionRepository(db *gorm.DB) hashperp.TransactionRepository {
 	return &PostgresTransactionRepository{
-		db: db,
+		db:             db,
+		eventPublisher: hashperp.NewNoopEventPublisher(),
 	}
 }
 
+// SetEventPublisher sets the publisher that receives stored transactions that could not be read
+func (r *PostgresTransactionRepository) SetEventPublisher(publisher hashperp.EventPublisher) {
+	r.eventPublisher = publisher
+}
+
+// convertDBTransactions converts stored transactions, skipping and reporting any that cannot be read
+func (r *PostgresTransactionRepository) convertDBTransactions(ctx context.Context, dbTransactions []DBTransaction) []*hashperp.Transaction {
+	transactions := make([]*hashperp.Transaction, 0, len(dbTransactions))
+	for _, dbTx := range dbTransactions {
+		tx, err := convertDBTransactionToTransaction(&dbTx)
+		if err != nil {
+			publishConversionFailure(ctx, r.eventPublisher, hashperp.Event{
+				ContractID:    dbTx.ContractID,
+				TransactionID: dbTx.ID,
+				Operation:     "convert_transaction",
+				Error:         err.Error(),
+			})
+			continue
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions
+}
+
 // Create creates a new transaction
 func (r *PostgresTransactionRepository) Create(ctx context.Context, tx *hashperp.Transaction) error {
 	// Convert related entities to JSON
@@ -367,6 +607,7 @@ func (r *PostgresTransactionRepository) Create(ctx context.Context, tx *hashperp
 		relatedEntitiesJSON = entitiesBytes
 	}
 	
+	tx.AmountSats = hashperp.BTCToSats(tx.Amount) // Keep the caller's copy in step with the stored one
 	dbTransaction := &DBTransaction{
 		ID:              tx.ID,
 		Type:            string(tx.Type),
@@ -375,6 +616,7 @@ func (r *PostgresTransactionRepository) Create(ctx context.Context, tx *hashperp
 		UserIDs:         pq.StringArray(tx.UserIDs),
 		TxHash:          tx.TxHash,
 		Amount:          tx.Amount,
+		AmountSats:      tx.AmountSats,
 		BTCPerPHPerDay:  tx.BTCPerPHPerDay,
 		BlockHeight:     tx.BlockHeight,
 		Status:          tx.Status,
@@ -403,8 +645,27 @@ func (r *PostgresTransactionRepository) FindByID(ctx context.Context, id string)
 	return convertDBTransactionToTransaction(&dbTransaction)
 }
 
-// FindByUser retrieves all transactions for a specific user
-func (r *PostgresTransactionRepository) FindByUser(ctx context.Context, userID string, types []hashperp.TransactionType) ([]*hashperp.Transaction, error) {
+// FindByTxHash retrieves the transaction recorded for an on-chain transaction hash
+func (r *PostgresTransactionRepository) FindByTxHash(ctx context.Context, txHash string) (*hashperp.Transaction, error) {
+	var dbTransaction DBTransaction
+	result := r.db.WithContext(ctx).Where("tx_hash = ?", txHash).First(&dbTransaction)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find transaction by hash: %w", result.Error)
+	}
+
+	return convertDBTransactionToTransaction(&dbTransaction)
+}
+
+// FindByUser retrieves a page of transactions for a specific user, ordered by timestamp then ID
+func (r *PostgresTransactionRepository) FindByUser(
+	ctx context.Context,
+	userID string,
+	types []hashperp.TransactionType,
+	page hashperp.PageRequest,
+) ([]*hashperp.Transaction, string, error) {
 	var dbTransactions []DBTransaction
 	
 	query := r.db.WithContext(ctx).Where("? = ANY(user_ids)", userID)
@@ -418,45 +679,62 @@ func (r *PostgresTransactionRepository) FindByUser(ctx context.Context, userID s
 		query = query.Where("type IN ?", typeStrings)
 	}
 	
+	query, err := applyPageRequest(query, "timestamp", page)
+	if err != nil {
+		return nil, "", err
+	}
+	
 	result := query.Find(&dbTransactions)
 	
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to find transactions by user: %w", result.Error)
+		return nil, "", fmt.Errorf("failed to find transactions by user: %w", result.Error)
 	}
-
-	transactions := make([]*hashperp.Transaction, 0, len(dbTransactions))
-	for _, dbTx := range dbTransactions {
-		tx, err := convertDBTransactionToTransaction(&dbTx)
-		if err != nil {
-			// Log the error but continue with other transactions
-			fmt.Printf("error converting transaction %s: %v\n", dbTx.ID, err)
-			continue
-		}
-		transactions = append(transactions, tx)
+	
+	var nextCursor string
+	if hasNextPage(len(dbTransactions), page) {
+		dbTransactions = dbTransactions[:page.Limit]
+		last := dbTransactions[len(dbTransactions)-1]
+		nextCursor = hashperp.EncodePageCursor(last.Timestamp, last.ID)
 	}
 
-	return transactions, nil
+	transactions := r.convertDBTransactions(ctx, dbTransactions)
+
+	return transactions, nextCursor, nil
 }
 
-// FindByContract retrieves all transactions for a specific contract
-func (r *PostgresTransactionRepository) FindByContract(ctx context.Context, contractID string) ([]*hashperp.Transaction, error) {
+// FindByContract retrieves the transactions of a specific contract ordered by timestamp
+func (r *PostgresTransactionRepository) FindByContract(
+	ctx context.Context,
+	contractID string,
+	types []hashperp.TransactionType,
+	order hashperp.SortOrder,
+) ([]*hashperp.Transaction, error) {
 	var dbTransactions []DBTransaction
-	result := r.db.WithContext(ctx).Where("contract_id = ?", contractID).Find(&dbTransactions)
+	
+	query := r.db.WithContext(ctx).Where("contract_id = ?", contractID)
+	
+	if len(types) > 0 {
+		var typeStrings []string
+		for _, t := range types {
+			typeStrings = append(typeStrings, string(t))
+		}
+		query = query.Where("type IN ?", typeStrings)
+	}
+	
+	// ID breaks timestamp ties so the order is deterministic
+	if order == hashperp.SORT_DESCENDING {
+		query = query.Order("timestamp DESC, id DESC")
+	} else {
+		query = query.Order("timestamp ASC, id ASC")
+	}
+	
+	result := query.Find(&dbTransactions)
 	
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to find transactions by contract: %w", result.Error)
 	}
 
-	transactions := make([]*hashperp.Transaction, 0, len(dbTransactions))
-	for _, dbTx := range dbTransactions {
-		tx, err := convertDBTransactionToTransaction(&dbTx)
-		if err != nil {
-			// Log the error but continue with other transactions
-			fmt.Printf("error converting transaction %s: %v\n", dbTx.ID, err)
-			continue
-		}
-		transactions = append(transactions, tx)
-	}
+	transactions := r.convertDBTransactions(ctx, dbTransactions)
 
 	return transactions, nil
 }
@@ -470,16 +748,7 @@ func (r *PostgresTransactionRepository) FindByType(ctx context.Context, transact
 		return nil, fmt.Errorf("failed to find transactions by type: %w", result.Error)
 	}
 
-	transactions := make([]*hashperp.Transaction, 0, len(dbTransactions))
-	for _, dbTx := range dbTransactions {
-		tx, err := convertDBTransactionToTransaction(&dbTx)
-		if err != nil {
-			// Log the error but continue with other transactions
-			fmt.Printf("error converting transaction %s: %v\n", dbTx.ID, err)
-			continue
-		}
-		transactions = append(transactions, tx)
-	}
+	transactions := r.convertDBTransactions(ctx, dbTransactions)
 
 	return transactions, nil
 }
@@ -495,17 +764,89 @@ func (r *PostgresTransactionRepository) FindByTimeRange(ctx context.Context, sta
 		return nil, fmt.Errorf("failed to find transactions by time range: %w", result.Error)
 	}
 
-	transactions := make([]*hashperp.Transaction, 0, len(dbTransactions))
-	for _, dbTx := range dbTransactions {
-		tx, err := convertDBTransactionToTransaction(&dbTx)
-		if err != nil {
-			// Log the error but continue with other transactions
-			fmt.Printf("error converting transaction %s: %v\n", dbTx.ID, err)
-			continue
-		}
-		transactions = append(transactions, tx)
+	transactions := r.convertDBTransactions(ctx, dbTransactions)
+
+	return transactions, nil
+}
+
+// FindByBlockRange retrieves all transactions recorded at a block height within an
+// inclusive range, ordered by block height and then timestamp
+func (r *PostgresTransactionRepository) FindByBlockRange(ctx context.Context, fromHeight, toHeight uint64) ([]*hashperp.Transaction, error) {
+	var dbTransactions []DBTransaction
+	result := r.db.WithContext(ctx).
+		Where("block_height BETWEEN ? AND ?", fromHeight, toHeight).
+		Order("block_height ASC, timestamp ASC, id ASC").
+		Find(&dbTransactions)
+	
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find transactions by block range: %w", result.Error)
 	}
 
+	transactions := r.convertDBTransactions(ctx, dbTransactions)
+
+	return transactions, nil
+}
+
+// FindByTypeAndStatus retrieves all transactions of a specific type in a status
+func (r *PostgresTransactionRepository) FindByTypeAndStatus(
+	ctx context.Context,
+	transactionType hashperp.TransactionType,
+	status string,
+) ([]*hashperp.Transaction, error) {
+	var dbTransactions []DBTransaction
+	result := r.db.WithContext(ctx).
+		Where("type = ? AND status = ?", string(transactionType), status).
+		Find(&dbTransactions)
+	
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find transactions by type and status: %w", result.Error)
+	}
+
+	transactions := r.convertDBTransactions(ctx, dbTransactions)
+
+	return transactions, nil
+}
+
+// SumAmountSince returns the number and total amount of transactions of a type recorded at or after a time
+func (r *PostgresTransactionRepository) SumAmountSince(
+	ctx context.Context,
+	transactionType hashperp.TransactionType,
+	since time.Time,
+) (int, float64, error) {
+	var row struct {
+		Count  int
+		Amount float64
+	}
+	result := r.db.WithContext(ctx).
+		Model(&DBTransaction{}).
+		Select("count(*) as count, coalesce(sum(amount), 0) as amount").
+		Where("type = ? AND timestamp >= ?", string(transactionType), since).
+		Scan(&row)
+	if result.Error != nil {
+		return 0, 0, fmt.Errorf("failed to sum transactions since %s: %w", since, result.Error)
+	}
+
+	return row.Count, row.Amount, nil
+}
+
+// FindAwaitingConfirmation retrieves broadcast transactions that are neither pending,
+// confirmed nor failed. Pending transactions are left to the operation that recorded them.
+func (r *PostgresTransactionRepository) FindAwaitingConfirmation(ctx context.Context) ([]*hashperp.Transaction, error) {
+	var dbTransactions []DBTransaction
+	result := r.db.WithContext(ctx).
+		Where("tx_hash <> '' AND status NOT IN ?", []string{
+			hashperp.TX_STATUS_PENDING,
+			hashperp.TX_STATUS_CONFIRMED,
+			hashperp.TX_STATUS_FAILED,
+		}).
+		Find(&dbTransactions)
+	
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find transactions awaiting confirmation: %w", result.Error)
+	}
+
+	transactions := r.convertDBTransactions(ctx, dbTransactions)
+
 	return transactions, nil
 }
 
@@ -521,6 +862,7 @@ func (r *PostgresTransactionRepository) Update(ctx context.Context, tx *hashperp
 		relatedEntitiesJSON = entitiesBytes
 	}
 	
+	tx.AmountSats = hashperp.BTCToSats(tx.Amount) // Keep the caller's copy in step with the stored one
 	dbTransaction := &DBTransaction{
 		ID:              tx.ID,
 		Type:            string(tx.Type),
@@ -529,6 +871,7 @@ func (r *PostgresTransactionRepository) Update(ctx context.Context, tx *hashperp
 		UserIDs:         pq.StringArray(tx.UserIDs),
 		TxHash:          tx.TxHash,
 		Amount:          tx.Amount,
+		AmountSats:      tx.AmountSats,
 		BTCPerPHPerDay:  tx.BTCPerPHPerDay,
 		BlockHeight:     tx.BlockHeight,
 		Status:          tx.Status,
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hashperp/hashperp"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresContractScriptRepository implements the ContractScriptRepository interface using PostgreSQL
+type PostgresContractScriptRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresContractScriptRepository creates a new PostgreSQL-based contract script repository
+func NewPostgresContractScriptRepository(db *gorm.DB) hashperp.ContractScriptRepository {
+	return &PostgresContractScriptRepository{
+		db: db,
+	}
+}
+
+// Save stores a contract's scripts, replacing any stored before. A contract's scripts are
+// regenerated when its parties change, so the original creation time is kept.
+func (r *PostgresContractScriptRepository) Save(ctx context.Context, scripts *hashperp.ContractScripts) error {
+	exitScriptsJSON, err := json.Marshal(scripts.ExitScripts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal exit scripts: %w", err)
+	}
+
+	dbScripts := &DBContractScripts{
+		ContractID:       scripts.ContractID,
+		BuyerScriptPath:  scripts.BuyerScriptPath,
+		SellerScriptPath: scripts.SellerScriptPath,
+		ExitScripts:      exitScriptsJSON,
+		CreatedAt:        scripts.CreatedAt,
+		UpdatedAt:        scripts.UpdatedAt,
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "contract_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"buyer_script_path", "seller_script_path", "exit_scripts", "updated_at"}),
+	}).Create(dbScripts)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save contract scripts: %w", result.Error)
+	}
+
+	return nil
+}
+
+// FindByContract retrieves a contract's scripts
+func (r *PostgresContractScriptRepository) FindByContract(ctx context.Context, contractID string) (*hashperp.ContractScripts, error) {
+	var dbScripts DBContractScripts
+	result := r.db.WithContext(ctx).Where("contract_id = ?", contractID).First(&dbScripts)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find contract scripts: %w", result.Error)
+	}
+
+	exitScripts := make(map[string]string)
+	if len(dbScripts.ExitScripts) > 0 {
+		if err := json.Unmarshal(dbScripts.ExitScripts, &exitScripts); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal exit scripts: %w", err)
+		}
+	}
+
+	return &hashperp.ContractScripts{
+		ContractID:       dbScripts.ContractID,
+		BuyerScriptPath:  dbScripts.BuyerScriptPath,
+		SellerScriptPath: dbScripts.SellerScriptPath,
+		ExitScripts:      exitScripts,
+		CreatedAt:        dbScripts.CreatedAt,
+		UpdatedAt:        dbScripts.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashperp/hashperp"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// PostgresContractTemplateRepository implements the ContractTemplateRepository interface using PostgreSQL
+type PostgresContractTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresContractTemplateRepository creates a new PostgreSQL-based contract template repository
+func NewPostgresContractTemplateRepository(db *gorm.DB) hashperp.ContractTemplateRepository {
+	return &PostgresContractTemplateRepository{
+		db: db,
+	}
+}
+
+// Create creates a new contract template
+func (r *PostgresContractTemplateRepository) Create(ctx context.Context, template *hashperp.ContractTemplate) error {
+	result := r.db.WithContext(ctx).Create(convertContractTemplateToDB(template))
+	if result.Error != nil {
+		return fmt.Errorf("failed to create contract template: %w", result.Error)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a contract template by ID
+func (r *PostgresContractTemplateRepository) FindByID(ctx context.Context, id string) (*hashperp.ContractTemplate, error) {
+	var dbTemplate DBContractTemplate
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&dbTemplate)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find contract template: %w", result.Error)
+	}
+
+	return convertDBContractTemplate(&dbTemplate), nil
+}
+
+// FindAll retrieves all contract templates ordered by name
+func (r *PostgresContractTemplateRepository) FindAll(ctx context.Context) ([]*hashperp.ContractTemplate, error) {
+	var dbTemplates []DBContractTemplate
+	result := r.db.WithContext(ctx).Order("name ASC").Find(&dbTemplates)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find contract templates: %w", result.Error)
+	}
+
+	templates := make([]*hashperp.ContractTemplate, len(dbTemplates))
+	for i, dbTemplate := range dbTemplates {
+		templates[i] = convertDBContractTemplate(&dbTemplate)
+	}
+
+	return templates, nil
+}
+
+// convertDBContractTemplate converts a database contract template to a domain model
+func convertDBContractTemplate(dbTemplate *DBContractTemplate) *hashperp.ContractTemplate {
+	expiryOffsets := make([]uint64, len(dbTemplate.ExpiryOffsets))
+	for i, offset := range dbTemplate.ExpiryOffsets {
+		expiryOffsets[i] = uint64(offset)
+	}
+
+	return &hashperp.ContractTemplate{
+		ID:               dbTemplate.ID,
+		Name:             dbTemplate.Name,
+		ContractType:     hashperp.ContractType(dbTemplate.ContractType),
+		MinStrikeRate:    dbTemplate.MinStrikeRate,
+		MaxStrikeRate:    dbTemplate.MaxStrikeRate,
+		ExpiryOffsets:    expiryOffsets,
+		MinSize:          dbTemplate.MinSize,
+		MaxSize:          dbTemplate.MaxSize,
+		DefaultSize:      dbTemplate.DefaultSize,
+		Leverage:         dbTemplate.Leverage,
+		SettlementMethod: hashperp.SettlementMethod(dbTemplate.SettlementMethod),
+		CreatedAt:        dbTemplate.CreatedAt,
+	}
+}
+
+// convertContractTemplateToDB converts a domain contract template to a database model
+func convertContractTemplateToDB(template *hashperp.ContractTemplate) *DBContractTemplate {
+	expiryOffsets := make(pq.Int64Array, len(template.ExpiryOffsets))
+	for i, offset := range template.ExpiryOffsets {
+		expiryOffsets[i] = int64(offset)
+	}
+
+	return &DBContractTemplate{
+		ID:               template.ID,
+		Name:             template.Name,
+		ContractType:     string(template.ContractType),
+		MinStrikeRate:    template.MinStrikeRate,
+		MaxStrikeRate:    template.MaxStrikeRate,
+		ExpiryOffsets:    expiryOffsets,
+		MinSize:          template.MinSize,
+		MaxSize:          template.MaxSize,
+		DefaultSize:      template.DefaultSize,
+		Leverage:         template.Leverage,
+		SettlementMethod: string(template.SettlementMethod),
+		CreatedAt:        template.CreatedAt,
+	}
+}
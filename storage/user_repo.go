@@ -3,13 +3,88 @@
 // GetPublicKey implements UserRepository.GetPublicKey
 func (r *PostgresUserRepository) GetPublicKey(ctx context.Context, userID string) ([]byte, error) {
 	var dbUser DBUser
-	result := r.db.WithContext(ctx).Where("id = ?", userID).First(&dbUser)
+	result := r.db.WithContext(ctx).Select("public_key").Where("id = ?", userID).First(&dbUser)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to get user: %w", result.Error)
+		return nil, fmt.Errorf("failed to get user key: %w", result.Error)
 	}
-	
+
 	return dbUser.PublicKey, nil
 }
+
+// GetBalance implements UserRepository.GetBalance
+func (r *PostgresUserRepository) GetBalance(ctx context.Context, userID string) (float64, error) {
+	var dbUser DBUser
+	result := r.db.WithContext(ctx).Select("balance").Where("id = ?", userID).First(&dbUser)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return 0, errors.New("user not found")
+		}
+		return 0, fmt.Errorf("failed to get user balance: %w", result.Error)
+	}
+
+	return dbUser.Balance, nil
+}
+
+// DebitBalance implements UserRepository.DebitBalance
+// The balance check and deduction happen in one statement so concurrent debits cannot overdraw.
+func (r *PostgresUserRepository) DebitBalance(ctx context.Context, userID string, amount float64) error {
+	result := r.db.WithContext(ctx).
+		Model(&DBUser{}).
+		Where("id = ? AND balance >= ?", userID, amount).
+		Updates(map[string]interface{}{
+			"balance":    gorm.Expr("balance - ?", amount),
+			"updated_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to debit user balance: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: user %s cannot cover %.8f BTC", hashperp.ErrInsufficientFunds, userID, amount)
+	}
+
+	return nil
+}
+
+// CreditBalance implements UserRepository.CreditBalance
+func (r *PostgresUserRepository) CreditBalance(ctx context.Context, userID string, amount float64) error {
+	result := r.db.WithContext(ctx).
+		Model(&DBUser{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"balance":    gorm.Expr("balance + ?", amount),
+			"updated_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to credit user balance: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// RegisterUserKey implements UserRepository.RegisterUserKey
+func (r *PostgresUserRepository) RegisterUserKey(ctx context.Context, userID string, pubKey []byte) error {
+	now := time.Now().UTC()
+	dbUser := &DBUser{
+		ID:        userID,
+		PublicKey: pubKey,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// Rotating a key keeps the user's balance and creation time
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"public_key", "updated_at"}),
+	}).Create(dbUser)
+	if result.Error != nil {
+		return fmt.Errorf("failed to register user key: %w", result.Error)
+	}
+
+	return nil
+}
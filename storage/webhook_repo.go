@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashperp/hashperp"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// PostgresWebhookRepository implements the WebhookRepository interface using PostgreSQL
+type PostgresWebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresWebhookRepository creates a new PostgreSQL-based webhook repository
+func NewPostgresWebhookRepository(db *gorm.DB) hashperp.WebhookRepository {
+	return &PostgresWebhookRepository{
+		db: db,
+	}
+}
+
+// CreateSubscription creates a new webhook subscription
+func (r *PostgresWebhookRepository) CreateSubscription(ctx context.Context, subscription *hashperp.WebhookSubscription) error {
+	eventTypes := make(pq.StringArray, len(subscription.EventTypes))
+	for i, eventType := range subscription.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	dbSubscription := &DBWebhookSubscription{
+		ID:         subscription.ID,
+		UserID:     subscription.UserID,
+		URL:        subscription.URL,
+		EventTypes: eventTypes,
+		Secret:     subscription.Secret,
+		CreatedAt:  subscription.CreatedAt,
+	}
+
+	result := r.db.WithContext(ctx).Create(dbSubscription)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", result.Error)
+	}
+
+	return nil
+}
+
+// FindSubscriptionByID retrieves a webhook subscription by ID
+func (r *PostgresWebhookRepository) FindSubscriptionByID(ctx context.Context, id string) (*hashperp.WebhookSubscription, error) {
+	var dbSubscription DBWebhookSubscription
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&dbSubscription)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find webhook subscription: %w", result.Error)
+	}
+
+	return convertDBWebhookSubscription(&dbSubscription), nil
+}
+
+// FindSubscriptionsByUser retrieves all webhook subscriptions for a specific user
+func (r *PostgresWebhookRepository) FindSubscriptionsByUser(ctx context.Context, userID string) ([]*hashperp.WebhookSubscription, error) {
+	var dbSubscriptions []DBWebhookSubscription
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&dbSubscriptions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find webhook subscriptions: %w", result.Error)
+	}
+
+	subscriptions := make([]*hashperp.WebhookSubscription, len(dbSubscriptions))
+	for i, dbSubscription := range dbSubscriptions {
+		subscriptions[i] = convertDBWebhookSubscription(&dbSubscription)
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription deletes a webhook subscription by ID
+func (r *PostgresWebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&DBWebhookSubscription{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", result.Error)
+	}
+
+	return nil
+}
+
+// CreateDelivery queues a new webhook delivery
+func (r *PostgresWebhookRepository) CreateDelivery(ctx context.Context, delivery *hashperp.WebhookDelivery) error {
+	result := r.db.WithContext(ctx).Create(convertWebhookDeliveryToDB(delivery))
+	if result.Error != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", result.Error)
+	}
+
+	return nil
+}
+
+// FindDueDeliveries retrieves up to limit pending deliveries whose next attempt is due at asOf
+func (r *PostgresWebhookRepository) FindDueDeliveries(ctx context.Context, asOf time.Time, limit int) ([]*hashperp.WebhookDelivery, error) {
+	var dbDeliveries []DBWebhookDelivery
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", string(hashperp.DELIVERY_PENDING), asOf).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&dbDeliveries)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find due webhook deliveries: %w", result.Error)
+	}
+
+	deliveries := make([]*hashperp.WebhookDelivery, len(dbDeliveries))
+	for i, dbDelivery := range dbDeliveries {
+		deliveries[i] = convertDBWebhookDelivery(&dbDelivery)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDelivery updates an existing webhook delivery
+func (r *PostgresWebhookRepository) UpdateDelivery(ctx context.Context, delivery *hashperp.WebhookDelivery) error {
+	dbDelivery := convertWebhookDeliveryToDB(delivery)
+	result := r.db.WithContext(ctx).
+		Model(&DBWebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          dbDelivery.Status,
+			"attempts":        dbDelivery.Attempts,
+			"last_error":      dbDelivery.LastError,
+			"next_attempt_at": dbDelivery.NextAttemptAt,
+			"delivered_at":    dbDelivery.DeliveredAt,
+			"updated_at":      time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", result.Error)
+	}
+
+	return nil
+}
+
+// convertDBWebhookSubscription converts a database webhook subscription to a domain model
+func convertDBWebhookSubscription(dbSubscription *DBWebhookSubscription) *hashperp.WebhookSubscription {
+	eventTypes := make([]hashperp.TransactionType, len(dbSubscription.EventTypes))
+	for i, eventType := range dbSubscription.EventTypes {
+		eventTypes[i] = hashperp.TransactionType(eventType)
+	}
+
+	return &hashperp.WebhookSubscription{
+		ID:         dbSubscription.ID,
+		UserID:     dbSubscription.UserID,
+		URL:        dbSubscription.URL,
+		EventTypes: eventTypes,
+		Secret:     dbSubscription.Secret,
+		CreatedAt:  dbSubscription.CreatedAt,
+	}
+}
+
+// convertDBWebhookDelivery converts a database webhook delivery to a domain model
+func convertDBWebhookDelivery(dbDelivery *DBWebhookDelivery) *hashperp.WebhookDelivery {
+	delivery := &hashperp.WebhookDelivery{
+		ID:             dbDelivery.ID,
+		SubscriptionID: dbDelivery.SubscriptionID,
+		TransactionID:  dbDelivery.TransactionID,
+		Payload:        dbDelivery.Payload,
+		Status:         hashperp.WebhookDeliveryStatus(dbDelivery.Status),
+		Attempts:       dbDelivery.Attempts,
+		LastError:      dbDelivery.LastError,
+		NextAttemptAt:  dbDelivery.NextAttemptAt,
+	}
+
+	if dbDelivery.DeliveredAt.Valid {
+		delivery.DeliveredAt = dbDelivery.DeliveredAt.Time
+	}
+
+	return delivery
+}
+
+// convertWebhookDeliveryToDB converts a domain webhook delivery to a database model
+func convertWebhookDeliveryToDB(delivery *hashperp.WebhookDelivery) *DBWebhookDelivery {
+	dbDelivery := &DBWebhookDelivery{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		TransactionID:  delivery.TransactionID,
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+	}
+
+	if !delivery.DeliveredAt.IsZero() {
+		dbDelivery.DeliveredAt = sql.NullTime{
+			Time:  delivery.DeliveredAt,
+			Valid: true,
+		}
+	}
+
+	return dbDelivery
+}